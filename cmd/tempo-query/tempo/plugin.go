@@ -48,7 +48,59 @@ func New(cfg *Config) *Backend {
 }
 
 func (b *Backend) GetDependencies(ctx context.Context, endTs time.Time, lookback time.Duration) ([]jaeger.DependencyLink, error) {
-	return nil, nil
+	span, ctx := opentracing.StartSpanFromContext(ctx, "tempo-query.GetDependencies")
+	defer span.Finish()
+
+	url := fmt.Sprintf("http://%s/api/dependencies?start=%d&end=%d", b.tempoBackend, endTs.Add(-lookback).Unix(), endTs.Unix())
+
+	req, err := b.newGetRequest(ctx, url, span)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed GET to tempo %w", err)
+	}
+	defer resp.Body.Close()
+
+	// if the dependencies endpoint returns 404, the metrics-generator is most likely not enabled
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error reading response from Tempo: got %s", resp.Status)
+		}
+		return nil, fmt.Errorf("%s", body)
+	}
+
+	var serviceGraph tempopb.ServiceGraphResponse
+	err = jsonpb.Unmarshal(resp.Body, &serviceGraph)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshaling Tempo response: %w", err)
+	}
+
+	// edges of different connection types between the same services are a single dependency in Jaeger
+	links := make([]jaeger.DependencyLink, 0, len(serviceGraph.Edges))
+	linkIdx := map[[2]string]int{}
+	for _, e := range serviceGraph.Edges {
+		k := [2]string{e.Client, e.Server}
+		if i, ok := linkIdx[k]; ok {
+			links[i].CallCount += e.Requests
+			continue
+		}
+		linkIdx[k] = len(links)
+		links = append(links, jaeger.DependencyLink{
+			Parent:    e.Client,
+			Child:     e.Server,
+			CallCount: e.Requests,
+		})
+	}
+
+	return links, nil
 }
 
 func (b *Backend) GetTrace(ctx context.Context, traceID jaeger.TraceID) (*jaeger.Trace, error) {
@@ -29,6 +29,7 @@ import (
 	tempo_ring "github.com/grafana/tempo/pkg/ring"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb"
 )
 
 // The various modules that make up tempo.
@@ -118,6 +119,8 @@ func (t *App) initOverrides() (services.Service, error) {
 }
 
 func (t *App) initDistributor() (services.Service, error) {
+	t.cfg.Distributor.Ingest = t.cfg.Ingest
+
 	// todo: make ingester client a module instead of passing the config everywhere
 	distributor, err := distributor.New(t.cfg.Distributor, t.cfg.IngesterClient, t.ring, t.cfg.GeneratorClient, t.generatorRing, t.overrides, t.TracesConsumerMiddleware, t.cfg.Server.LogLevel, t.cfg.SearchEnabled, t.cfg.MetricsGeneratorEnabled, prometheus.DefaultRegisterer)
 	if err != nil {
@@ -134,6 +137,7 @@ func (t *App) initDistributor() (services.Service, error) {
 
 func (t *App) initIngester() (services.Service, error) {
 	t.cfg.Ingester.LifecyclerConfig.ListenPort = t.cfg.Server.GRPCListenPort
+	t.cfg.Ingester.Ingest = t.cfg.Ingest
 	ingester, err := ingester.New(t.cfg.Ingester, t.store, t.overrides, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ingester: %w", err)
@@ -149,13 +153,31 @@ func (t *App) initIngester() (services.Service, error) {
 
 func (t *App) initGenerator() (services.Service, error) {
 	t.cfg.Generator.Ring.ListenPort = t.cfg.Server.GRPCListenPort
-	generator, err := generator.New(&t.cfg.Generator, t.overrides, prometheus.DefaultRegisterer, log.Logger)
+
+	// the backend is only read to backfill metrics
+	var store tempodb.Reader
+	if t.cfg.Generator.Backfill.Enabled {
+		store = t.store
+		// do not enable polling if this is the single binary. in that case the compactor will take care of polling
+		if t.cfg.Target == MetricsGenerator {
+			t.store.EnablePolling(nil)
+		}
+	}
+
+	generator, err := generator.New(&t.cfg.Generator, t.overrides, store, prometheus.DefaultRegisterer, log.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create metrics-generator %w", err)
 	}
 	t.generator = generator
 
 	tempopb.RegisterMetricsGeneratorServer(t.Server.GRPC, t.generator)
+
+	cardinalityHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.generator.CardinalityHandler))
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, "/metrics-generator/cardinality"), cardinalityHandler)
+
+	backfillHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.generator.BackfillHandler))
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, "/metrics-generator/backfill"), backfillHandler)
+
 	return t.generator, nil
 }
 
@@ -177,8 +199,14 @@ func (t *App) initQuerier() (services.Service, error) {
 		t.store.EnablePolling(nil)
 	}
 
+	// the service graph is queried from the metrics-generators
+	var generatorRing ring.ReadRing
+	if t.cfg.MetricsGeneratorEnabled {
+		generatorRing = t.generatorRing
+	}
+
 	// todo: make ingester client a module instead of passing config everywhere
-	querier, err := querier.New(t.cfg.Querier, t.cfg.IngesterClient, t.ring, t.store, t.overrides)
+	querier, err := querier.New(t.cfg.Querier, t.cfg.IngesterClient, t.ring, t.cfg.GeneratorClient, generatorRing, t.store, t.overrides)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create querier %w", err)
 	}
@@ -191,6 +219,9 @@ func (t *App) initQuerier() (services.Service, error) {
 	tracesHandler := middleware.Wrap(http.HandlerFunc(t.querier.TraceByIDHandler))
 	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathTraces)), tracesHandler)
 
+	tracesBatchHandler := middleware.Wrap(http.HandlerFunc(t.querier.TracesByIDHandler))
+	t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathTracesBatch)), tracesBatchHandler)
+
 	if t.cfg.SearchEnabled {
 		searchHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.SearchHandler))
 		t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearch)), searchHandler)
@@ -202,20 +233,31 @@ func (t *App) initQuerier() (services.Service, error) {
 		t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValues)), searchTagValuesHandler)
 	}
 
+	if t.cfg.MetricsGeneratorEnabled {
+		serviceGraphHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.ServiceGraphHandler))
+		t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathDependencies)), serviceGraphHandler)
+
+		queryRangeHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.QueryRangeHandler))
+		t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathQueryRange)), queryRangeHandler)
+
+		cardinalityHandler := t.HTTPAuthMiddleware.Wrap(http.HandlerFunc(t.querier.CardinalityHandler))
+		t.Server.HTTP.Handle(path.Join(api.PathPrefixQuerier, addHTTPAPIPrefix(&t.cfg, api.PathCardinality)), cardinalityHandler)
+	}
+
 	return t.querier, t.querier.CreateAndRegisterWorker(t.Server.HTTPServer.Handler)
 }
 
 func (t *App) initQueryFrontend() (services.Service, error) {
 	// cortexTripper is a bridge between http and httpgrpc. it does the job of passing data to the cortex
 	// frontend code
-	cortexTripper, v1, _, err := frontend.InitFrontend(t.cfg.Frontend.Config, frontend.CortexNoQuerierLimits{}, 0, log.Logger, prometheus.DefaultRegisterer)
+	cortexTripper, v1, _, err := frontend.InitFrontend(t.cfg.Frontend.Config, t.overrides, 0, log.Logger, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, err
 	}
 	t.frontend = v1
 
 	// create query frontend
-	queryFrontend, err := frontend.New(t.cfg.Frontend, cortexTripper, t.store, log.Logger, prometheus.DefaultRegisterer)
+	queryFrontend, err := frontend.New(t.cfg.Frontend, cortexTripper, t.store, t.overrides, log.Logger, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, err
 	}
@@ -227,13 +269,24 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	)
 
 	traceByIDHandler := middleware.Wrap(queryFrontend.TraceByID)
+	tracesByIDHandler := middleware.Wrap(queryFrontend.TracesByID)
+	traceComparisonHandler := middleware.Wrap(queryFrontend.TraceComparison)
+	traceSummaryHandler := middleware.Wrap(queryFrontend.TraceSummary)
 	searchHandler := middleware.Wrap(queryFrontend.Search)
+	serviceGraphHandler := middleware.Wrap(queryFrontend.ServiceGraph)
+	queryRangeHandler := middleware.Wrap(queryFrontend.QueryRange)
+	cardinalityHandler := middleware.Wrap(queryFrontend.Cardinality)
+	queriesHandler := middleware.Wrap(http.HandlerFunc(queryFrontend.QueriesHandler))
+	cancelQueryHandler := middleware.Wrap(http.HandlerFunc(queryFrontend.CancelQueryHandler))
 
 	// register grpc server for queriers to connect to
 	frontend_v1pb.RegisterFrontendServer(t.Server.GRPC, t.frontend)
 
-	// http trace by id endpoint
+	// http trace by id endpoint, the comparison endpoint is registered first to not be matched as a trace ID
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathTracesCompare), traceComparisonHandler)
 	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraces), traceByIDHandler)
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathTracesBatch), tracesByIDHandler)
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraceSummary), traceSummaryHandler)
 
 	// http search endpoints
 	if t.cfg.SearchEnabled {
@@ -244,6 +297,17 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 		t.store.EnablePolling(nil) // the query frontend does not need to have knowledge of the backend unless it is building jobs for backend search
 	}
 
+	// http metrics-generator endpoints
+	if t.cfg.MetricsGeneratorEnabled {
+		t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathDependencies), serviceGraphHandler)
+		t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathQueryRange), queryRangeHandler)
+		t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathCardinality), cardinalityHandler)
+	}
+
+	// http endpoints listing and canceling queries in progress
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathQueries), queriesHandler)
+	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathQuery), cancelQueryHandler)
+
 	// http query echo endpoint
 	t.Server.HTTP.Handle(addHTTPAPIPrefix(&t.cfg, api.PathEcho), echoHandler())
 
@@ -266,6 +330,11 @@ func (t *App) initCompactor() (services.Service, error) {
 }
 
 func (t *App) initStore() (services.Service, error) {
+	// only queriers read ranges of blocks through the disk cache
+	if t.cfg.Target != Querier && t.cfg.Target != SingleBinary && t.cfg.Target != ScalableSingleBinary {
+		t.cfg.StorageConfig.Trace.DiskCache = nil
+	}
+
 	store, err := tempo_storage.NewStore(t.cfg.StorageConfig, log.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create store %w", err)
@@ -327,7 +396,7 @@ func (t *App) setupModuleManager() error {
 		// Store:        nil,
 		Overrides:            {Server},
 		MemberlistKV:         {Server},
-		QueryFrontend:        {Store, Server},
+		QueryFrontend:        {Store, Server, Overrides},
 		Ring:                 {Server, MemberlistKV},
 		MetricsGeneratorRing: {Server, MemberlistKV},
 		Distributor:          {Ring, Server, Overrides},
@@ -342,10 +411,17 @@ func (t *App) setupModuleManager() error {
 	if t.cfg.MetricsGeneratorEnabled {
 		// If metrics-generator is enabled, the distributor needs the metrics-generator ring
 		deps[Distributor] = append(deps[Distributor], MetricsGeneratorRing)
+		// The querier queries the service graph from the metrics-generators
+		deps[Querier] = append(deps[Querier], MetricsGeneratorRing)
 		// Add the metrics generator as dependency for when target is {,scalable-}single-binary
 		deps[SingleBinary] = append(deps[SingleBinary], MetricsGenerator)
 	}
 
+	if t.cfg.Generator.Backfill.Enabled {
+		// The metrics-generator reads blocks from the backend to backfill metrics
+		deps[MetricsGenerator] = append(deps[MetricsGenerator], Store)
+	}
+
 	for mod, targets := range deps {
 		if err := mm.AddDependency(mod, targets...); err != nil {
 			return err
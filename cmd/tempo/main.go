@@ -33,6 +33,9 @@ import (
 	"github.com/grafana/tempo/cmd/tempo/app"
 	"github.com/grafana/tempo/cmd/tempo/build"
 	"github.com/grafana/tempo/pkg/util/log"
+
+	// metrics-generator processors registered as plugins
+	_ "github.com/grafana/tempo/modules/generator/processor/attributecount"
 )
 
 const appName = "tempo"
@@ -0,0 +1,116 @@
+package distributor
+
+import (
+	"time"
+
+	"github.com/grafana/tempo/modules/overrides"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// otherAttributeValue is the attribute value label used for discarded spans of values without an explicit nested
+// rate limit. It keeps the cardinality of tempo_discarded_spans_by_attribute_total bounded by the configured limits.
+const otherAttributeValue = "other"
+
+// attributeUsage is the size and span count of a push per value of the tenant's rate limit attribute.
+type attributeUsage struct {
+	values []string // attribute value per batch
+	sizes  map[string]int
+	spans  map[string]int
+}
+
+// attributeUsage returns the usage of the batches per value of the tenant's rate limit attribute (service.name by
+// default). It returns nil if the tenant has no nested rate limits.
+func (d *Distributor) attributeUsage(userID string, batches []*v1.ResourceSpans) *attributeUsage {
+	if len(d.overrides.IngestionAttributeRateLimits(userID)) == 0 {
+		return nil
+	}
+	attribute := d.overrides.IngestionRateLimitAttribute(userID)
+
+	u := &attributeUsage{
+		values: make([]string, len(batches)),
+		sizes:  map[string]int{},
+		spans:  map[string]int{},
+	}
+	for i, b := range batches {
+		v := resourceAttributeValue(b, attribute)
+		u.values[i] = v
+		u.sizes[v] += b.Size()
+		u.spans[v] += countSpans(b)
+	}
+	return u
+}
+
+// applyAttributeRateLimits enforces the nested ingestion rate limits of a tenant. Each value of the tenant's rate
+// limit attribute that has a limit is rate limited separately and batches whose value exceeded its limit are dropped.
+// It is called before the tenant rate limit, so that the tenant limit is only charged for the batches that are kept.
+// The sizes and span counts of dropped values are removed from the usage.
+func (d *Distributor) applyAttributeRateLimits(now time.Time, userID string, batches []*v1.ResourceSpans, u *attributeUsage) []*v1.ResourceSpans {
+	if u == nil {
+		return batches
+	}
+
+	var denied map[string]struct{}
+	for v, size := range u.sizes {
+		if _, ok := d.overrides.IngestionAttributeRateLimit(userID, v); !ok {
+			continue
+		}
+		if d.attributeRateLimiter.AllowN(now, attributeRateLimiterKey(userID, v), size) {
+			continue
+		}
+
+		if denied == nil {
+			denied = map[string]struct{}{}
+		}
+		denied[v] = struct{}{}
+
+		overrides.RecordDiscardedSpans(u.spans[v], reasonRateLimited, userID)
+		d.recordDiscardedSpansByAttribute(map[string]int{v: u.spans[v]}, reasonRateLimited, userID)
+		delete(u.spans, v)
+		delete(u.sizes, v)
+	}
+
+	if len(denied) == 0 {
+		return batches
+	}
+
+	allowed := make([]*v1.ResourceSpans, 0, len(batches))
+	for i, b := range batches {
+		if _, ok := denied[u.values[i]]; !ok {
+			allowed = append(allowed, b)
+		}
+	}
+
+	return allowed
+}
+
+// attributeValueLabel returns the label recorded for discarded spans of the given attribute value. Values without an
+// explicit nested rate limit, including those limited by the "*" wildcard, are recorded as otherAttributeValue.
+func (d *Distributor) attributeValueLabel(userID string, value string) string {
+	if _, ok := d.overrides.IngestionAttributeRateLimits(userID)[value]; ok {
+		return value
+	}
+	return otherAttributeValue
+}
+
+// resourceAttributeValue returns the value of the given resource attribute as a string or an empty string if the
+// attribute is not present.
+func resourceAttributeValue(b *v1.ResourceSpans, attribute string) string {
+	if b.Resource == nil {
+		return ""
+	}
+	for _, kv := range b.Resource.Attributes {
+		if kv.Key == attribute && kv.Value != nil {
+			return util.StringifyAnyValue(kv.Value)
+		}
+	}
+	return ""
+}
+
+func countSpans(b *v1.ResourceSpans) int {
+	count := 0
+	for _, ils := range b.InstrumentationLibrarySpans {
+		count += len(ils.Spans)
+	}
+	return count
+}
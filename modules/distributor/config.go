@@ -6,6 +6,8 @@ import (
 
 	"github.com/grafana/dskit/flagext"
 	ring_client "github.com/grafana/dskit/ring/client"
+
+	"github.com/grafana/tempo/pkg/ingest"
 )
 
 var defaultReceivers = map[string]interface{}{
@@ -39,8 +41,12 @@ type Config struct {
 
 	SearchTagsDenyList []string `yaml:"search_tags_deny_list"`
 
+	// Kafka ingest path. Set from the top level ingest config.
+	Ingest ingest.Config `yaml:"-"`
+
 	// For testing.
-	factory func(addr string) (ring_client.PoolClient, error) `yaml:"-"`
+	factory      func(addr string) (ring_client.PoolClient, error) `yaml:"-"`
+	ingestClient ingest.Client                                     `yaml:"-"`
 }
 
 // RegisterFlagsAndApplyDefaults registers flags and applies defaults
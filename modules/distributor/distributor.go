@@ -30,6 +30,7 @@ import (
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
 	_ "github.com/grafana/tempo/pkg/gogocodec" // force gogo codec registration
+	"github.com/grafana/tempo/pkg/ingest"
 	"github.com/grafana/tempo/pkg/model"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
@@ -130,8 +131,13 @@ type Distributor struct {
 	generatorsRing          ring.ReadRing
 	generatorsPool          *ring_client.Pool
 
+	// Kafka ingest path
+	ingestClient ingest.Client
+
 	// Per-user rate limiter.
 	ingestionRateLimiter *limiter.RateLimiter
+	// Per-user and resource attribute value rate limiter.
+	attributeRateLimiter *limiter.RateLimiter
 
 	// Manager for subservices
 	subservices        *services.Manager
@@ -150,7 +156,7 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 	subservices := []services.Service(nil)
 
 	// Create the configured ingestion rate limit strategy (local or global).
-	var ingestionRateStrategy limiter.RateLimiterStrategy
+	var ingestionRateStrategy, attributeRateStrategy limiter.RateLimiterStrategy
 	var distributorRing *ring.Ring
 
 	if o.IngestionRateStrategy() == overrides.GlobalIngestionRateStrategy {
@@ -161,6 +167,7 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		}
 		subservices = append(subservices, lifecycler)
 		ingestionRateStrategy = newGlobalIngestionRateStrategy(o, lifecycler)
+		attributeRateStrategy = newGlobalAttributeRateStrategy(o, lifecycler)
 
 		ring, err := ring.New(lifecyclerCfg.RingConfig, "distributor", cfg.OverrideRingKey, log.Logger, prometheus.WrapRegistererWithPrefix("cortex_", reg))
 		if err != nil {
@@ -170,6 +177,7 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		subservices = append(subservices, distributorRing)
 	} else {
 		ingestionRateStrategy = newLocalIngestionRateStrategy(o)
+		attributeRateStrategy = newLocalAttributeRateStrategy(o)
 	}
 
 	pool := ring_client.NewPool("distributor_pool",
@@ -197,6 +205,18 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		subservices = append(subservices, generatorsPool)
 	}
 
+	var ingestClient ingest.Client
+	if cfg.Ingest.Enabled {
+		ingestClient = cfg.ingestClient
+		if ingestClient == nil {
+			var err error
+			ingestClient, err = ingest.NewKafkaClient(cfg.Ingest, log.Logger)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	// turn list into map for efficient checking
 	tagsToDrop := map[string]struct{}{}
 	for _, tag := range cfg.SearchTagsDenyList {
@@ -208,8 +228,10 @@ func New(cfg Config, clientCfg ingester_client.Config, ingestersRing ring.ReadRi
 		clientCfg:               clientCfg,
 		ingestersRing:           ingestersRing,
 		pool:                    pool,
+		ingestClient:            ingestClient,
 		DistributorRing:         distributorRing,
 		ingestionRateLimiter:    limiter.NewRateLimiter(ingestionRateStrategy, 10*time.Second),
+		attributeRateLimiter:    limiter.NewRateLimiter(attributeRateStrategy, 10*time.Second),
 		searchEnabled:           searchEnabled,
 		metricsGeneratorEnabled: metricsGeneratorEnabled,
 		generatorClientCfg:      generatorClientCfg,
@@ -263,7 +285,15 @@ func (d *Distributor) running(ctx context.Context) error {
 
 // Called after distributor is asked to stop via StopAsync.
 func (d *Distributor) stopping(_ error) error {
-	return services.StopManagerAndAwaitStopped(context.Background(), d.subservices)
+	err := services.StopManagerAndAwaitStopped(context.Background(), d.subservices)
+
+	if d.ingestClient != nil {
+		if closeErr := d.ingestClient.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+
+	return err
 }
 
 // PushBatches pushes a batch of traces
@@ -281,6 +311,11 @@ func (d *Distributor) PushBatches(ctx context.Context, batches []*v1.ResourceSpa
 		logTraces(batches)
 	}
 
+	// truncate spans exceeding the per span limits before they are measured for rate limiting
+	if l := d.spanLimits(userID); l.enabled() {
+		recordTruncatedSpans(truncateSpans(batches, l), userID)
+	}
+
 	// metric size
 	size := 0
 	spanCount := 0
@@ -298,8 +333,34 @@ func (d *Distributor) PushBatches(ctx context.Context, batches []*v1.ResourceSpa
 
 	// check limits
 	now := time.Now()
+	usage := d.attributeUsage(userID, batches)
+	var spansByAttribute map[string]int
+	if usage != nil {
+		spansByAttribute = usage.spans
+	}
+
+	// the nested limits are applied first, the bytes of the batches they drop are not charged to the tenant limit
+	batches = d.applyAttributeRateLimits(now, userID, batches, usage)
+	if len(batches) == 0 {
+		return nil, status.Errorf(codes.ResourceExhausted,
+			"%s ingestion rate limit exceeded for all values of %s",
+			overrides.ErrorPrefixRateLimited,
+			d.overrides.IngestionRateLimitAttribute(userID))
+	}
+	if usage != nil {
+		// recount what's left after the attribute rate limits dropped batches
+		size, spanCount = 0, 0
+		for _, s := range usage.sizes {
+			size += s
+		}
+		for _, c := range usage.spans {
+			spanCount += c
+		}
+	}
+
 	if !d.ingestionRateLimiter.AllowN(now, userID, size) {
 		overrides.RecordDiscardedSpans(spanCount, reasonRateLimited, userID)
+		d.recordDiscardedSpansByAttribute(spansByAttribute, reasonRateLimited, userID)
 		return nil, status.Errorf(codes.ResourceExhausted,
 			"%s ingestion rate limit (%d bytes) exceeded while adding %d bytes",
 			overrides.ErrorPrefixRateLimited,
@@ -310,6 +371,7 @@ func (d *Distributor) PushBatches(ctx context.Context, batches []*v1.ResourceSpa
 	keys, rebatchedTraces, err := requestsByTraceID(batches, userID, spanCount)
 	if err != nil {
 		overrides.RecordDiscardedSpans(spanCount, reasonInternalError, userID)
+		d.recordDiscardedSpansByAttribute(spansByAttribute, reasonInternalError, userID)
 		return nil, err
 	}
 
@@ -330,9 +392,14 @@ func (d *Distributor) PushBatches(ctx context.Context, batches []*v1.ResourceSpa
 		})
 	}
 
-	err = d.sendToIngestersViaBytes(ctx, userID, rebatchedTraces, searchData, keys)
+	if d.ingestClient != nil {
+		err = d.sendToIngestTopic(ctx, userID, rebatchedTraces, searchData, keys)
+	} else {
+		err = d.sendToIngestersViaBytes(ctx, userID, rebatchedTraces, searchData, keys)
+	}
 	if err != nil {
-		recordDiscaredSpans(err, userID, spanCount)
+		reason := recordDiscaredSpans(err, userID, spanCount)
+		d.recordDiscardedSpansByAttribute(spansByAttribute, reason, userID)
 	}
 
 	if d.metricsGeneratorEnabled && len(d.overrides.MetricsGeneratorProcessors(userID)) > 0 && err == nil {
@@ -519,19 +586,35 @@ func requestsByTraceID(batches []*v1.ResourceSpans, userID string, spanCount int
 	return keys, traces, nil
 }
 
-func recordDiscaredSpans(err error, userID string, spanCount int) {
+// recordDiscaredSpans records the spans of a failed push and returns the reason they were discarded for.
+func recordDiscaredSpans(err error, userID string, spanCount int) string {
 	s := status.Convert(err)
 	if s == nil {
-		return
+		return ""
 	}
 	desc := s.Message()
 
+	reason := reasonInternalError
 	if strings.HasPrefix(desc, overrides.ErrorPrefixLiveTracesExceeded) {
-		overrides.RecordDiscardedSpans(spanCount, reasonLiveTracesExceeded, userID)
+		reason = reasonLiveTracesExceeded
 	} else if strings.HasPrefix(desc, overrides.ErrorPrefixTraceTooLarge) {
-		overrides.RecordDiscardedSpans(spanCount, reasonTraceTooLarge, userID)
-	} else {
-		overrides.RecordDiscardedSpans(spanCount, reasonInternalError, userID)
+		reason = reasonTraceTooLarge
+	}
+
+	overrides.RecordDiscardedSpans(spanCount, reason, userID)
+	return reason
+}
+
+// recordDiscardedSpansByAttribute records discarded spans per value of the tenant's rate limit attribute. It is a
+// noop for tenants without nested rate limits.
+func (d *Distributor) recordDiscardedSpansByAttribute(spansByAttribute map[string]int, reason string, userID string) {
+	if len(spansByAttribute) == 0 || reason == "" {
+		return
+	}
+
+	attribute := d.overrides.IngestionRateLimitAttribute(userID)
+	for value, count := range spansByAttribute {
+		overrides.RecordDiscardedSpansByAttribute(count, reason, userID, attribute, d.attributeValueLabel(userID, value))
 	}
 }
 
@@ -27,6 +27,7 @@ import (
 	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/ingest"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
@@ -659,11 +660,114 @@ func TestDistributor(t *testing.T) {
 	}
 }
 
+func TestDistributorAttributeRateLimits(t *testing.T) {
+	makeServiceBatch := func(service string, spans int) *v1.ResourceSpans {
+		b := test.MakeBatch(spans, nil)
+		b.Resource.Attributes[0].Value = &v1_common.AnyValue{
+			Value: &v1_common.AnyValue_StringValue{StringValue: service},
+		}
+		return b
+	}
+
+	limits := &overrides.Limits{}
+	flagext.DefaultValues(limits)
+	limits.IngestionAttributeRateLimits = map[string]overrides.AttributeRateLimit{
+		"noisy": {RateLimitBytes: 1, BurstSizeBytes: 1},
+	}
+
+	d := prepare(t, limits, nil)
+
+	noisy := makeServiceBatch("noisy", 5)
+	quiet := makeServiceBatch("quiet", 3)
+	unlimited := makeServiceBatch("", 2)
+	unlimited.Resource.Attributes = nil
+
+	batches := []*v1.ResourceSpans{noisy, quiet, unlimited}
+	usage := d.attributeUsage("test", batches)
+	allowed := d.applyAttributeRateLimits(time.Now(), "test", batches, usage)
+	assert.Equal(t, []*v1.ResourceSpans{quiet, unlimited}, allowed)
+	assert.Equal(t, map[string]int{"quiet": 3, "": 2}, usage.spans)
+	assert.Equal(t, map[string]int{"quiet": quiet.Size(), "": unlimited.Size()}, usage.sizes)
+
+	// values without an explicit limit are recorded as other
+	assert.Equal(t, "noisy", d.attributeValueLabel("test", "noisy"))
+	assert.Equal(t, otherAttributeValue, d.attributeValueLabel("test", "quiet"))
+
+	// a request with only rate limited services is rejected
+	_, err := d.PushBatches(ctx, []*v1.ResourceSpans{makeServiceBatch("noisy", 5)})
+	require.Error(t, err)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	// other services are unaffected
+	_, err = d.PushBatches(ctx, []*v1.ResourceSpans{makeServiceBatch("noisy", 5), makeServiceBatch("quiet", 5)})
+	require.NoError(t, err)
+
+	// the bytes of dropped batches are not charged to the tenant limit
+	noisy, quiet = makeServiceBatch("noisy", 5), makeServiceBatch("quiet", 3)
+	limits.IngestionBurstSizeBytes = quiet.Size()
+	d = prepare(t, limits, nil)
+	_, err = d.PushBatches(ctx, []*v1.ResourceSpans{noisy, quiet})
+	require.NoError(t, err)
+
+	// tenants without nested limits are not counted per attribute
+	limits.IngestionAttributeRateLimits = nil
+	d = prepare(t, limits, nil)
+	assert.Nil(t, d.attributeUsage("test", []*v1.ResourceSpans{noisy}))
+}
+
+func TestDistributorIngestTopic(t *testing.T) {
+	limits := &overrides.Limits{}
+	flagext.DefaultValues(limits)
+
+	broker := ingest.NewLocalBroker()
+	distributorConfig := Config{}
+	distributorConfig.Ingest.Enabled = true
+	distributorConfig.Ingest.Partitions = 4
+	distributorConfig.Ingest.WriteTimeout = time.Second
+	distributorConfig.ingestClient = broker
+
+	d := prepareWithConfig(t, distributorConfig, limits, nil)
+
+	traceIDs := map[string]struct{}{}
+	batches := make([]*v1.ResourceSpans, 0, 10)
+	for i := 0; i < 10; i++ {
+		traceID := test.ValidTraceID(nil)
+		traceIDs[string(traceID)] = struct{}{}
+		batches = append(batches, test.MakeBatch(5, traceID))
+	}
+
+	_, err := d.PushBatches(ctx, batches)
+	require.NoError(t, err)
+
+	found := 0
+	for partition := int32(0); int(partition) < distributorConfig.Ingest.Partitions; partition++ {
+		if broker.Len(partition) == 0 {
+			continue
+		}
+
+		c, err := broker.Consume(partition, 0)
+		require.NoError(t, err)
+
+		r := <-c.Records()
+		require.NoError(t, c.Close())
+
+		assert.Equal(t, "test", r.TenantID)
+		assert.Equal(t, len(r.Request.Ids), len(r.Request.Traces))
+		for _, id := range r.Request.Ids {
+			assert.Contains(t, traceIDs, string(id.Slice))
+			assert.Equal(t, partition, ingest.PartitionForToken(util.TokenFor("test", id.Slice), int32(distributorConfig.Ingest.Partitions)))
+			found++
+		}
+	}
+	assert.Equal(t, len(traceIDs), found)
+}
+
 func prepare(t *testing.T, limits *overrides.Limits, kvStore kv.Client) *Distributor {
-	var (
-		distributorConfig Config
-		clientConfig      ingester_client.Config
-	)
+	return prepareWithConfig(t, Config{}, limits, kvStore)
+}
+
+func prepareWithConfig(t *testing.T, distributorConfig Config, limits *overrides.Limits, kvStore kv.Client) *Distributor {
+	var clientConfig ingester_client.Config
 	flagext.DefaultValues(&clientConfig)
 
 	overrides, err := overrides.NewOverrides(*limits)
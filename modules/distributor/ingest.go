@@ -0,0 +1,74 @@
+package distributor
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/ingest"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+var (
+	metricIngestRecordsWritten = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_ingest_records_written_total",
+		Help:      "The total number of records written to the ingest topic.",
+	}, []string{"partition"})
+	metricIngestRecordsWriteFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_ingest_records_write_failures_total",
+		Help:      "The total number of records that failed to be written to the ingest topic.",
+	}, []string{"partition"})
+)
+
+// sendToIngestTopic writes the rebatched traces to the ingest topic. Traces are partitioned by their
+// ring token and all traces for a partition are written as a single record.
+func (d *Distributor) sendToIngestTopic(ctx context.Context, userID string, traces []*rebatchedTrace, searchData [][]byte, keys []uint32) error {
+	ctx, cancel := context.WithTimeout(ctx, d.cfg.Ingest.WriteTimeout)
+	defer cancel()
+
+	reqs := map[int32]*tempopb.PushBytesRequest{}
+	for i, t := range traces {
+		b, err := d.traceEncoder.PrepareForWrite(t.trace, t.start, t.end)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal PushRequest")
+		}
+
+		partition := ingest.PartitionForToken(keys[i], int32(d.cfg.Ingest.Partitions))
+		req, ok := reqs[partition]
+		if !ok {
+			req = &tempopb.PushBytesRequest{}
+			reqs[partition] = req
+		}
+
+		req.Traces = append(req.Traces, tempopb.PreallocBytes{Slice: b})
+		req.Ids = append(req.Ids, tempopb.PreallocBytes{Slice: t.id})
+
+		// Search data optional
+		var s []byte
+		if len(searchData) > i {
+			s = searchData[i]
+		}
+		req.SearchData = append(req.SearchData, tempopb.PreallocBytes{Slice: s})
+	}
+
+	for partition, req := range reqs {
+		label := strconv.Itoa(int(partition))
+
+		err := d.ingestClient.Produce(ctx, partition, []*ingest.Record{{
+			TenantID: userID,
+			Request:  req,
+		}})
+		if err != nil {
+			metricIngestRecordsWriteFailures.WithLabelValues(label).Inc()
+			return err
+		}
+		metricIngestRecordsWritten.WithLabelValues(label).Inc()
+	}
+
+	return nil
+}
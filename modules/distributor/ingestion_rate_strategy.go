@@ -1,10 +1,15 @@
 package distributor
 
 import (
+	"strings"
+
 	"github.com/grafana/dskit/limiter"
 	"github.com/grafana/tempo/modules/overrides"
 )
 
+// attributeKeySeparator separates the tenant and the attribute value in the keys of the attribute rate limiter.
+const attributeKeySeparator = "\x00"
+
 // ReadLifecycler represents the read interface to the lifecycler.
 type ReadLifecycler interface {
 	HealthyInstancesCount() int
@@ -55,3 +60,66 @@ func (s *globalStrategy) Burst(userID string) int {
 	// to keep it easier to understand for users / operators.
 	return s.limits.IngestionBurstSizeBytes(userID)
 }
+
+// attributeRateLimiterKey returns the key used by the attribute rate limiter for the given tenant and attribute value.
+func attributeRateLimiterKey(userID, value string) string {
+	return userID + attributeKeySeparator + value
+}
+
+func splitAttributeRateLimiterKey(key string) (string, string) {
+	parts := strings.SplitN(key, attributeKeySeparator, 2)
+	if len(parts) != 2 {
+		return key, ""
+	}
+	return parts[0], parts[1]
+}
+
+type localAttributeStrategy struct {
+	limits *overrides.Overrides
+}
+
+// newLocalAttributeRateStrategy returns a strategy for the nested rate limits keyed by resource attribute value.
+// The keys passed to the strategy must be created with attributeRateLimiterKey.
+func newLocalAttributeRateStrategy(limits *overrides.Overrides) limiter.RateLimiterStrategy {
+	return &localAttributeStrategy{
+		limits: limits,
+	}
+}
+
+func (s *localAttributeStrategy) Limit(key string) float64 {
+	l, _ := s.limits.IngestionAttributeRateLimit(splitAttributeRateLimiterKey(key))
+	return float64(l.RateLimitBytes)
+}
+
+func (s *localAttributeStrategy) Burst(key string) int {
+	l, _ := s.limits.IngestionAttributeRateLimit(splitAttributeRateLimiterKey(key))
+	return l.Burst()
+}
+
+type globalAttributeStrategy struct {
+	limits *overrides.Overrides
+	ring   ReadLifecycler
+}
+
+func newGlobalAttributeRateStrategy(limits *overrides.Overrides, ring ReadLifecycler) limiter.RateLimiterStrategy {
+	return &globalAttributeStrategy{
+		limits: limits,
+		ring:   ring,
+	}
+}
+
+func (s *globalAttributeStrategy) Limit(key string) float64 {
+	l, _ := s.limits.IngestionAttributeRateLimit(splitAttributeRateLimiterKey(key))
+	numDistributors := s.ring.HealthyInstancesCount()
+
+	if numDistributors == 0 {
+		return float64(l.RateLimitBytes)
+	}
+
+	return float64(l.RateLimitBytes) / float64(numDistributors)
+}
+
+func (s *globalAttributeStrategy) Burst(key string) int {
+	l, _ := s.limits.IngestionAttributeRateLimit(splitAttributeRateLimiterKey(key))
+	return l.Burst()
+}
@@ -70,6 +70,53 @@ func TestIngestionRateStrategy(t *testing.T) {
 	}
 }
 
+func TestAttributeRateStrategy(t *testing.T) {
+	limits := overrides.Limits{
+		IngestionAttributeRateLimits: map[string]overrides.AttributeRateLimit{
+			"noisy": {RateLimitBytes: 10, BurstSizeBytes: 20},
+			"*":     {RateLimitBytes: 4, BurstSizeBytes: 8},
+			"quiet": {RateLimitBytes: 6},
+		},
+	}
+
+	o, err := overrides.NewOverrides(limits)
+	require.NoError(t, err)
+
+	ring := newReadLifecyclerMock()
+	ring.On("HealthyInstancesCount").Return(2)
+
+	local := newLocalAttributeRateStrategy(o)
+	global := newGlobalAttributeRateStrategy(o, ring)
+
+	noisy := attributeRateLimiterKey("test", "noisy")
+	other := attributeRateLimiterKey("test", "other")
+
+	assert.Equal(t, float64(10), local.Limit(noisy))
+	assert.Equal(t, 20, local.Burst(noisy))
+	assert.Equal(t, float64(4), local.Limit(other))
+	assert.Equal(t, 8, local.Burst(other))
+
+	assert.Equal(t, float64(5), global.Limit(noisy))
+	assert.Equal(t, 20, global.Burst(noisy))
+	assert.Equal(t, float64(2), global.Limit(other))
+	assert.Equal(t, 8, global.Burst(other))
+
+	// the burst defaults to the rate limit
+	quiet := attributeRateLimiterKey("test", "quiet")
+	assert.Equal(t, 6, local.Burst(quiet))
+	assert.Equal(t, 6, global.Burst(quiet))
+}
+
+func TestAttributeRateLimiterKey(t *testing.T) {
+	tenant, value := splitAttributeRateLimiterKey(attributeRateLimiterKey("test", "svc"))
+	assert.Equal(t, "test", tenant)
+	assert.Equal(t, "svc", value)
+
+	tenant, value = splitAttributeRateLimiterKey(attributeRateLimiterKey("test", ""))
+	assert.Equal(t, "test", tenant)
+	assert.Equal(t, "", value)
+}
+
 type readLifecyclerMock struct {
 	mock.Mock
 }
@@ -0,0 +1,145 @@
+package distributor
+
+import (
+	"unicode/utf8"
+
+	"github.com/grafana/tempo/modules/overrides"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const (
+	// reasonAttributesPerSpanExceeded indicates that a span had more attributes than allowed
+	reasonAttributesPerSpanExceeded = "attributes_per_span_exceeded"
+	// reasonAttributeValueTooLong indicates that a span had an attribute value longer than allowed
+	reasonAttributeValueTooLong = "attribute_value_too_long"
+	// reasonEventsPerSpanExceeded indicates that a span had more events than allowed
+	reasonEventsPerSpanExceeded = "events_per_span_exceeded"
+	// reasonLinksPerSpanExceeded indicates that a span had more links than allowed
+	reasonLinksPerSpanExceeded = "links_per_span_exceeded"
+)
+
+// spanLimits are the per-tenant limits applied to the individual spans of a push. A value of 0 disables a limit.
+type spanLimits struct {
+	maxAttributesPerSpan    int
+	maxAttributeValueLength int
+	maxEventsPerSpan        int
+	maxLinksPerSpan         int
+}
+
+func (d *Distributor) spanLimits(userID string) spanLimits {
+	return spanLimits{
+		maxAttributesPerSpan:    d.overrides.MaxAttributesPerSpan(userID),
+		maxAttributeValueLength: d.overrides.MaxAttributeValueLength(userID),
+		maxEventsPerSpan:        d.overrides.MaxEventsPerSpan(userID),
+		maxLinksPerSpan:         d.overrides.MaxLinksPerSpan(userID),
+	}
+}
+
+func (l spanLimits) enabled() bool {
+	return l.maxAttributesPerSpan > 0 || l.maxAttributeValueLength > 0 || l.maxEventsPerSpan > 0 || l.maxLinksPerSpan > 0
+}
+
+// truncateSpans applies the span limits to all spans in place. Data exceeding the limits is dropped and recorded in
+// the dropped counts of the span. It returns the number of truncated spans per reason.
+func truncateSpans(batches []*v1.ResourceSpans, l spanLimits) map[string]int {
+	truncated := map[string]int{}
+
+	for _, b := range batches {
+		if b.Resource != nil && l.maxAttributeValueLength > 0 {
+			truncateAttributeValues(b.Resource.Attributes, l.maxAttributeValueLength)
+		}
+
+		for _, ils := range b.InstrumentationLibrarySpans {
+			for _, s := range ils.Spans {
+				if l.maxAttributesPerSpan > 0 && len(s.Attributes) > l.maxAttributesPerSpan {
+					s.DroppedAttributesCount += uint32(len(s.Attributes) - l.maxAttributesPerSpan)
+					s.Attributes = s.Attributes[:l.maxAttributesPerSpan]
+					truncated[reasonAttributesPerSpanExceeded]++
+				}
+
+				if l.maxEventsPerSpan > 0 && len(s.Events) > l.maxEventsPerSpan {
+					s.DroppedEventsCount += uint32(len(s.Events) - l.maxEventsPerSpan)
+					s.Events = s.Events[:l.maxEventsPerSpan]
+					truncated[reasonEventsPerSpanExceeded]++
+				}
+
+				if l.maxLinksPerSpan > 0 && len(s.Links) > l.maxLinksPerSpan {
+					s.DroppedLinksCount += uint32(len(s.Links) - l.maxLinksPerSpan)
+					s.Links = s.Links[:l.maxLinksPerSpan]
+					truncated[reasonLinksPerSpanExceeded]++
+				}
+
+				if l.maxAttributeValueLength > 0 {
+					n := truncateAttributeValues(s.Attributes, l.maxAttributeValueLength)
+					for _, e := range s.Events {
+						n += truncateAttributeValues(e.Attributes, l.maxAttributeValueLength)
+					}
+					for _, link := range s.Links {
+						n += truncateAttributeValues(link.Attributes, l.maxAttributeValueLength)
+					}
+					if n > 0 {
+						truncated[reasonAttributeValueTooLong]++
+					}
+				}
+			}
+		}
+	}
+
+	return truncated
+}
+
+// truncateAttributeValues truncates all string values longer than maxLength and returns the number of truncated values.
+func truncateAttributeValues(attributes []*v1_common.KeyValue, maxLength int) int {
+	truncated := 0
+	for _, kv := range attributes {
+		truncated += truncateAnyValue(kv.Value, maxLength)
+	}
+	return truncated
+}
+
+func truncateAnyValue(v *v1_common.AnyValue, maxLength int) int {
+	if v == nil {
+		return 0
+	}
+
+	switch val := v.Value.(type) {
+	case *v1_common.AnyValue_StringValue:
+		if len(val.StringValue) > maxLength {
+			val.StringValue = truncateString(val.StringValue, maxLength)
+			return 1
+		}
+	case *v1_common.AnyValue_ArrayValue:
+		truncated := 0
+		if val.ArrayValue != nil {
+			for _, elem := range val.ArrayValue.Values {
+				truncated += truncateAnyValue(elem, maxLength)
+			}
+		}
+		return truncated
+	case *v1_common.AnyValue_KvlistValue:
+		if val.KvlistValue != nil {
+			return truncateAttributeValues(val.KvlistValue.Values, maxLength)
+		}
+	}
+
+	return 0
+}
+
+// truncateString truncates s to at most maxLength bytes without splitting a multi-byte character.
+func truncateString(s string, maxLength int) string {
+	if len(s) <= maxLength {
+		return s
+	}
+	for maxLength > 0 && !utf8.RuneStart(s[maxLength]) {
+		maxLength--
+	}
+	return s[:maxLength]
+}
+
+// recordTruncatedSpans records the truncated spans per reason.
+func recordTruncatedSpans(truncated map[string]int, userID string) {
+	for reason, count := range truncated {
+		overrides.RecordTruncatedSpans(count, reason, userID)
+	}
+}
@@ -0,0 +1,116 @@
+package distributor
+
+import (
+	"testing"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		maxLength int
+		expected  string
+	}{
+		{name: "shorter", s: "abc", maxLength: 5, expected: "abc"},
+		{name: "equal", s: "abcde", maxLength: 5, expected: "abcde"},
+		{name: "longer", s: "abcdef", maxLength: 3, expected: "abc"},
+		{name: "multi-byte boundary", s: "aéb", maxLength: 3, expected: "aé"},
+		{name: "multi-byte split", s: "aéb", maxLength: 2, expected: "a"},
+		{name: "multi-byte only", s: "日本", maxLength: 2, expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, truncateString(tc.s, tc.maxLength))
+		})
+	}
+}
+
+func TestTruncateSpans(t *testing.T) {
+	stringKV := func(key, value string) *v1_common.KeyValue {
+		return &v1_common.KeyValue{Key: key, Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: value}}}
+	}
+	makeSpan := func() *v1.Span {
+		return &v1.Span{
+			Attributes: []*v1_common.KeyValue{
+				stringKV("a", "aaaaaa"),
+				stringKV("b", "b"),
+				{Key: "c", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_ArrayValue{ArrayValue: &v1_common.ArrayValue{
+					Values: []*v1_common.AnyValue{{Value: &v1_common.AnyValue_StringValue{StringValue: "cccccc"}}},
+				}}}},
+			},
+			Events: []*v1.Span_Event{
+				{Attributes: []*v1_common.KeyValue{stringKV("e", "eeeeee")}},
+				{},
+			},
+			Links: []*v1.Span_Link{{}, {}, {}},
+		}
+	}
+
+	tests := []struct {
+		name              string
+		limits            spanLimits
+		expectedTruncated map[string]int
+		expectedSpan      func() *v1.Span
+	}{
+		{
+			name:              "no limits",
+			limits:            spanLimits{},
+			expectedTruncated: map[string]int{},
+			expectedSpan:      makeSpan,
+		},
+		{
+			name:              "attributes per span",
+			limits:            spanLimits{maxAttributesPerSpan: 1},
+			expectedTruncated: map[string]int{reasonAttributesPerSpanExceeded: 1},
+			expectedSpan: func() *v1.Span {
+				s := makeSpan()
+				s.Attributes = s.Attributes[:1]
+				s.DroppedAttributesCount = 2
+				return s
+			},
+		},
+		{
+			name:              "events and links per span",
+			limits:            spanLimits{maxEventsPerSpan: 1, maxLinksPerSpan: 2},
+			expectedTruncated: map[string]int{reasonEventsPerSpanExceeded: 1, reasonLinksPerSpanExceeded: 1},
+			expectedSpan: func() *v1.Span {
+				s := makeSpan()
+				s.Events = s.Events[:1]
+				s.DroppedEventsCount = 1
+				s.Links = s.Links[:2]
+				s.DroppedLinksCount = 1
+				return s
+			},
+		},
+		{
+			name:              "attribute value length",
+			limits:            spanLimits{maxAttributeValueLength: 2},
+			expectedTruncated: map[string]int{reasonAttributeValueTooLong: 1},
+			expectedSpan: func() *v1.Span {
+				s := makeSpan()
+				s.Attributes[0] = stringKV("a", "aa")
+				s.Attributes[2].Value.GetArrayValue().Values[0].Value = &v1_common.AnyValue_StringValue{StringValue: "cc"}
+				s.Events[0].Attributes[0] = stringKV("e", "ee")
+				return s
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			span := makeSpan()
+			batches := []*v1.ResourceSpans{{
+				InstrumentationLibrarySpans: []*v1.InstrumentationLibrarySpans{{Spans: []*v1.Span{span}}},
+			}}
+
+			truncated := truncateSpans(batches, tc.limits)
+			assert.Equal(t, tc.expectedTruncated, truncated)
+			assert.Equal(t, tc.expectedSpan(), span)
+		})
+	}
+}
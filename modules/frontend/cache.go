@@ -0,0 +1,263 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/golang/protobuf/proto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/cache/memcached"
+	"github.com/grafana/tempo/tempodb/backend/cache/redis"
+)
+
+const (
+	resultsCacheName = "frontend-results"
+
+	cacheKeyPrefixTraces = "traces:"
+	cacheKeyPrefixSearch = "search:"
+)
+
+// ResultsCacheConfig configures the cache of query results in the query-frontend.
+type ResultsCacheConfig struct {
+	// Cache is the backend of the cache, either lru, memcached or redis. Results are not cached if empty.
+	Cache           string                 `yaml:"cache"`
+	BackgroundCache cache.BackgroundConfig `yaml:"background_cache"`
+	LRU             cache.LRUConfig        `yaml:"lru"`
+	Memcached       memcached.Config       `yaml:"memcached"`
+	Redis           redis.Config           `yaml:"redis"`
+
+	// TraceByIDCacheAfter is how long after its last span ended a trace is cached. It must cover the time traces are
+	// kept in the ingesters, max_block_duration plus complete_block_timeout, so that cached traces are complete.
+	TraceByIDCacheAfter time.Duration `yaml:"trace_by_id_cache_after"`
+}
+
+func (cfg *ResultsCacheConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	cfg.BackgroundCache.WriteBackBuffer = 10000
+	cfg.BackgroundCache.WriteBackGoroutines = 10
+	cfg.LRU.MaxSizeBytes = 128 * 1024 * 1024
+	cfg.LRU.TTL = time.Hour
+	cfg.Memcached.TTL = time.Hour
+	cfg.Redis.TTL = time.Hour
+	cfg.TraceByIDCacheAfter = 2 * time.Hour
+}
+
+// ttl returns the ttl of the configured cache backend.
+func (cfg *ResultsCacheConfig) ttl() time.Duration {
+	switch cfg.Cache {
+	case "lru":
+		return cfg.LRU.TTL
+	case "memcached":
+		return cfg.Memcached.TTL
+	case "redis":
+		return cfg.Redis.TTL
+	}
+	return 0
+}
+
+// resultsCache caches results of the query-frontend. A nil resultsCache caches nothing.
+type resultsCache struct {
+	cache cache.Cache
+
+	requests *prometheus.CounterVec
+	hits     *prometheus.CounterVec
+}
+
+func newResultsCache(cfg ResultsCacheConfig, registerer prometheus.Registerer, logger log.Logger) (*resultsCache, error) {
+	var c cache.Cache
+
+	switch cfg.Cache {
+	case "":
+		return nil, nil
+	case "lru":
+		c = cache.NewLRU(cfg.LRU, resultsCacheName, registerer)
+	case "memcached":
+		c = memcached.NewClient(&cfg.Memcached, &cfg.BackgroundCache, resultsCacheName, registerer, logger)
+	case "redis":
+		c = redis.NewClient(&cfg.Redis, &cfg.BackgroundCache, resultsCacheName, registerer, logger)
+	default:
+		return nil, fmt.Errorf("unknown results cache %s, expected lru, memcached or redis", cfg.Cache)
+	}
+
+	return &resultsCache{
+		cache: c,
+		requests: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "tempo",
+			Name:      "query_frontend_results_cache_requests_total",
+			Help:      "Total lookups in the results cache.",
+		}, []string{"op"}),
+		hits: promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "tempo",
+			Name:      "query_frontend_results_cache_hits_total",
+			Help:      "Total lookups in the results cache that returned a result.",
+		}, []string{"op"}),
+	}, nil
+}
+
+func (c *resultsCache) fetch(ctx context.Context, op, key string) ([]byte, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	c.requests.WithLabelValues(op).Inc()
+
+	found, bufs, _ := c.cache.Fetch(ctx, []string{key})
+	if len(found) == 0 {
+		return nil, false
+	}
+
+	c.hits.WithLabelValues(op).Inc()
+	return bufs[0], true
+}
+
+func (c *resultsCache) store(ctx context.Context, key string, buf []byte) {
+	if c == nil {
+		return
+	}
+	c.cache.Store(ctx, []string{key}, [][]byte{buf})
+}
+
+// newTraceByIDCache creates a middleware caching combined traces. Traces are only cached once all their spans ended
+// more than cacheAfter ago, by then the ingesters have flushed them to the backend. Spans arriving later, e.g.
+// accepted by the late span policy of the ingesters, are missing from a cached trace until it expires with the
+// ttl of the cache.
+func newTraceByIDCache(c *resultsCache, cacheAfter time.Duration, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			// only plain requests are cached, other params can restrict the blocks that are searched
+			if c == nil || r.URL.RawQuery != "" {
+				return next.RoundTrip(r)
+			}
+
+			tenantID, err := user.ExtractOrgID(r.Context())
+			if err != nil {
+				return next.RoundTrip(r)
+			}
+			traceID, err := api.ParseTraceID(r)
+			if err != nil {
+				return next.RoundTrip(r)
+			}
+			key := cacheKeyPrefixTraces + tenantID + ":" + hex.EncodeToString(traceID)
+
+			if buf, ok := c.fetch(r.Context(), traceByIDOp, key); ok {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header: http.Header{
+						api.HeaderContentType: {api.HeaderAcceptProtobuf},
+					},
+					Body:          io.NopCloser(bytes.NewReader(buf)),
+					ContentLength: int64(len(buf)),
+				}, nil
+			}
+
+			resp, err := next.RoundTrip(r)
+			if err != nil || resp == nil || resp.StatusCode != http.StatusOK {
+				return resp, err
+			}
+
+			buf, err := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(buf))
+
+			traceResp := &tempopb.TraceByIDResponse{}
+			err = proto.Unmarshal(buf, traceResp)
+			if err != nil {
+				level.Error(logger).Log("msg", "error unmarshalling response to cache", "err", err)
+				return resp, nil
+			}
+
+			// partial results are not cached
+			if traceResp.Metrics != nil && traceResp.Metrics.FailedBlocks > 0 {
+				return resp, nil
+			}
+			if !traceEndedBefore(traceResp.Trace, time.Now().Add(-cacheAfter)) {
+				return resp, nil
+			}
+
+			c.store(r.Context(), key, buf)
+			return resp, nil
+		})
+	})
+}
+
+// traceEndedBefore returns true if all spans of the trace ended before t.
+func traceEndedBefore(trace *tempopb.Trace, t time.Time) bool {
+	if trace == nil {
+		return false
+	}
+
+	var end uint64
+	for _, b := range trace.Batches {
+		for _, ils := range b.InstrumentationLibrarySpans {
+			for _, s := range ils.Spans {
+				if s.EndTimeUnixNano > end {
+					end = s.EndTimeUnixNano
+				}
+			}
+		}
+	}
+	return end != 0 && end < uint64(t.UnixNano())
+}
+
+// searchBlockCacheKey returns the key of the results of a search block request. The query is
+// normalized so equivalent requests share the same key: tags are sorted and the time range is
+// limited to the range of the block. An empty key is returned if the request can not be cached.
+func searchBlockCacheKey(tenantID string, r *http.Request, metas map[string]*backend.BlockMeta) string {
+	req, err := api.ParseSearchBlockRequest(r)
+	if err != nil {
+		return ""
+	}
+	meta, ok := metas[req.BlockID]
+	if !ok {
+		return ""
+	}
+
+	start, end := req.SearchReq.Start, req.SearchReq.End
+	if blockStart := uint32(meta.StartTime.Unix()); start < blockStart {
+		start = blockStart
+	}
+	if blockEnd := uint32(meta.EndTime.Unix()); end > blockEnd {
+		end = blockEnd
+	}
+
+	// url.Values are encoded sorted by key
+	tags := url.Values{}
+	for k, v := range req.SearchReq.Tags {
+		tags.Set(k, v)
+	}
+
+	query := strings.Join([]string{
+		strconv.FormatUint(uint64(req.StartPage), 10),
+		strconv.FormatUint(uint64(req.PagesToSearch), 10),
+		strconv.FormatUint(uint64(start), 10),
+		strconv.FormatUint(uint64(end), 10),
+		strconv.FormatUint(uint64(req.SearchReq.Limit), 10),
+		strconv.FormatUint(uint64(req.SearchReq.MinDurationMs), 10),
+		strconv.FormatUint(uint64(req.SearchReq.MaxDurationMs), 10),
+		tags.Encode(),
+	}, "&")
+	hash := sha256.Sum256([]byte(query))
+
+	return cacheKeyPrefixSearch + tenantID + ":" + req.BlockID + ":" + hex.EncodeToString(hash[:])
+}
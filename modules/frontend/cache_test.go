@@ -0,0 +1,208 @@
+package frontend
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestTraceByIDCache(t *testing.T) {
+	tests := []struct {
+		name         string
+		endTime      time.Time
+		failedBlocks uint32
+		url          string
+		cached       bool
+	}{
+		{
+			name:    "old trace",
+			endTime: time.Now().Add(-2 * time.Hour),
+			url:     "/api/traces/1234",
+			cached:  true,
+		},
+		{
+			name:    "recent trace",
+			endTime: time.Now(),
+			url:     "/api/traces/1234",
+		},
+		{
+			name:         "failed blocks",
+			endTime:      time.Now().Add(-2 * time.Hour),
+			failedBlocks: 1,
+			url:          "/api/traces/1234",
+		},
+		{
+			name:    "request with params",
+			endTime: time.Now().Add(-2 * time.Hour),
+			url:     "/api/traces/1234?mode=blocks",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trace := test.MakeTrace(1, []byte{0x12, 0x34})
+			for _, b := range trace.Batches {
+				for _, ils := range b.InstrumentationLibrarySpans {
+					for _, s := range ils.Spans {
+						s.EndTimeUnixNano = uint64(tc.endTime.UnixNano())
+					}
+				}
+			}
+			buf, err := proto.Marshal(&tempopb.TraceByIDResponse{
+				Trace:   trace,
+				Metrics: &tempopb.TraceByIDMetrics{FailedBlocks: tc.failedBlocks},
+			})
+			require.NoError(t, err)
+
+			calls := atomic.NewInt32(0)
+			next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				calls.Inc()
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader(buf)),
+				}, nil
+			})
+
+			c := newTestResultsCache()
+			rt := NewRoundTripper(next, newTraceByIDCache(c, time.Hour, log.NewNopLogger()))
+
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest("GET", tc.url, nil)
+				req = mux.SetURLVars(req, map[string]string{"traceID": "1234"})
+				req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+
+				resp, err := rt.RoundTrip(req)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+				actual, err := io.ReadAll(resp.Body)
+				require.NoError(t, err)
+				assert.Equal(t, buf, actual)
+			}
+
+			expectedCalls := int32(2)
+			if tc.cached {
+				expectedCalls = 1
+			}
+			assert.Equal(t, expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestSearchBlockCacheKey(t *testing.T) {
+	blockID := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	metas := map[string]*backend.BlockMeta{
+		blockID.String(): {
+			BlockID:   blockID,
+			StartTime: time.Unix(1100, 0),
+			EndTime:   time.Unix(1200, 0),
+		},
+	}
+
+	key := func(query string) string {
+		r := httptest.NewRequest("GET", "/?blockID="+blockID.String()+"&startPage=0&pagesToSearch=10&encoding=none&indexPageSize=1&totalRecords=10&dataEncoding=v2&version=v2&"+query, nil)
+		return searchBlockCacheKey("test", r, metas)
+	}
+
+	base := key("start=1000&end=1500&tags=a%3Db+c%3Dd")
+	require.NotEmpty(t, base)
+	assert.True(t, strings.HasPrefix(base, "search:test:"+blockID.String()+":"))
+
+	// tag order and time ranges covering the block are normalized
+	assert.Equal(t, base, key("start=1000&end=1500&tags=c%3Dd+a%3Db"))
+	assert.Equal(t, base, key("start=900&end=1300&tags=a%3Db+c%3Dd"))
+
+	// everything else changes the results
+	assert.NotEqual(t, base, key("start=1150&end=1500&tags=a%3Db+c%3Dd"))
+	assert.NotEqual(t, base, key("start=1000&end=1500&tags=a%3Db"))
+	assert.NotEqual(t, base, key("start=1000&end=1500&tags=a%3Db+c%3Dd&limit=5"))
+	assert.NotEqual(t, base, key("start=1000&end=1500&tags=a%3Db+c%3Dd&minDuration=1s"))
+
+	// unknown blocks and invalid requests are not cached
+	assert.Empty(t, key("start=0&end=0"))
+	assert.Empty(t, searchBlockCacheKey("test", httptest.NewRequest("GET", "/?start=1000&end=1500&blockID="+uuid.New().String(), nil), metas))
+}
+
+func TestSearchSharderCache(t *testing.T) {
+	calls := atomic.NewInt32(0)
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		calls.Inc()
+
+		resString, err := (&jsonpb.Marshaler{}).MarshalToString(&tempopb.SearchResponse{
+			Traces: []*tempopb.TraceSearchMetadata{{TraceID: r.URL.Query().Get("startPage")}},
+			Metrics: &tempopb.SearchMetrics{
+				InspectedTraces: 1,
+			},
+		})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(strings.NewReader(resString)),
+			StatusCode: http.StatusOK,
+		}, nil
+	})
+
+	sharder := newSearchSharder(&mockReader{
+		metas: []*backend.BlockMeta{
+			{
+				StartTime:     time.Unix(1100, 0),
+				EndTime:       time.Unix(1200, 0),
+				Size:          defaultTargetBytesPerRequest * 2,
+				TotalRecords:  2,
+				BlockID:       uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+				IndexPageSize: 1,
+				DataEncoding:  "v2",
+				Version:       "v2",
+			},
+		},
+	}, SearchSharderConfig{
+		ConcurrentRequests:    1,
+		TargetBytesPerRequest: defaultTargetBytesPerRequest,
+	}, newTestResultsCache(), log.NewNopLogger())
+	testRT := NewRoundTripper(next, sharder)
+
+	for _, query := range []string{"/?start=1000&end=1500", "/?start=1050&end=1250"} {
+		req := httptest.NewRequest("GET", query, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+
+		resp, err := testRT.RoundTrip(req)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		actual := &tempopb.SearchResponse{}
+		require.NoError(t, jsonpb.Unmarshal(resp.Body, actual))
+		assert.Len(t, actual.Traces, 2)
+		assert.Equal(t, uint32(2), actual.Metrics.InspectedTraces)
+	}
+
+	// both queries cover the entire block, the second one is served from the cache
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func newTestResultsCache() *resultsCache {
+	return &resultsCache{
+		cache:    cache.NewMockCache(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{Name: "requests"}, []string{"op"}),
+		hits:     prometheus.NewCounterVec(prometheus.CounterOpts{Name: "hits"}, []string{"op"}),
+	}
+}
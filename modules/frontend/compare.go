@@ -0,0 +1,157 @@
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/opentracing/opentracing-go"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// comparedTrace is one of the two traces of a comparison, fetched through the trace by ID path.
+type comparedTrace struct {
+	name  string
+	trace *tempopb.Trace
+	// resp is set if the trace couldn't be fetched, it's returned as is to the client
+	resp    *http.Response
+	partial bool
+	err     error
+}
+
+// newTraceComparisonRoundTripper creates a RoundTripper comparing two traces. Both traces are fetched
+// concurrently through traces, the trace by ID RoundTripper, so they are sharded and cached like any
+// other trace by ID request.
+func newTraceComparisonRoundTripper(traces http.RoundTripper, logger log.Logger) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		span, ctx := opentracing.StartSpanFromContext(r.Context(), "frontend.TraceComparison")
+		defer span.Finish()
+		r = r.WithContext(ctx)
+
+		idA, idB, err := api.ParseTraceComparisonRequest(r)
+		if err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+				Header:     http.Header{},
+			}, nil
+		}
+
+		compared := []*comparedTrace{{name: "a"}, {name: "b"}}
+		wg := sync.WaitGroup{}
+		for i, id := range [][]byte{idA, idB} {
+			wg.Add(1)
+			go func(c *comparedTrace, id []byte) {
+				defer wg.Done()
+				fetchComparedTrace(traces, r, id, c)
+			}(compared[i], id)
+		}
+		wg.Wait()
+
+		partial := false
+		for _, c := range compared {
+			if c.err != nil {
+				level.Error(logger).Log("msg", "error fetching trace to compare", "trace", c.name, "err", c.err)
+				return nil, c.err
+			}
+			if c.resp != nil {
+				return c.resp, nil
+			}
+			partial = partial || c.partial
+		}
+
+		body, err := json.Marshal(trace.Compare(compared[0].trace, compared[1].trace))
+		if err != nil {
+			return nil, err
+		}
+
+		statusCode := http.StatusOK
+		if partial {
+			statusCode = http.StatusPartialContent
+		}
+		return &http.Response{
+			StatusCode: statusCode,
+			Header: http.Header{
+				api.HeaderContentType: {api.HeaderAcceptJSON},
+			},
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+		}, nil
+	})
+}
+
+// fetchComparedTrace fetches the trace id through the trace by ID RoundTripper. The time range hints of
+// the comparison request are passed on.
+func fetchComparedTrace(traces http.RoundTripper, parent *http.Request, id []byte, c *comparedTrace) {
+	traceID := util.TraceIDToHexString(id)
+	req := newTraceByIDRequest(parent, strings.TrimSuffix(parent.URL.Path, "compare")+traceID, traceID)
+
+	resp, err := traces.RoundTrip(req)
+	if err != nil {
+		c.err = err
+		return
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		c.err = err
+		return
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent:
+	case http.StatusNotFound:
+		c.resp = &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("trace %s (%s) not found", c.name, traceID))),
+			Header:     http.Header{},
+		}
+		return
+	default:
+		c.resp = &http.Response{
+			StatusCode: resp.StatusCode,
+			Body:       io.NopCloser(bytes.NewReader(body)),
+			Header:     http.Header{},
+		}
+		return
+	}
+
+	c.trace = &tempopb.Trace{}
+	c.err = proto.Unmarshal(body, c.trace)
+	c.partial = resp.StatusCode == http.StatusPartialContent
+}
+
+// newTraceByIDRequest derives a protobuf trace by ID request of traceID at path from parent. The time range
+// hints of parent are passed on.
+func newTraceByIDRequest(parent *http.Request, path string, traceID string) *http.Request {
+	req := parent.Clone(parent.Context())
+	req.Method = http.MethodGet
+	req.Body = http.NoBody
+	req.ContentLength = 0
+	req.URL.Path = path
+
+	params := url.Values{}
+	for _, param := range []string{"start", "end"} {
+		if v := parent.URL.Query().Get(param); v != "" {
+			params.Set(param, v)
+		}
+	}
+	req.URL.RawQuery = params.Encode()
+	req.RequestURI = req.URL.RequestURI()
+	req.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+	return mux.SetURLVars(req, map[string]string{api.URLParamTraceID: traceID})
+}
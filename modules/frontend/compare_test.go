@@ -0,0 +1,129 @@
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestTraceComparison(t *testing.T) {
+	traceA := test.MakeTrace(2, []byte{0x01})
+	traceB := test.MakeTrace(3, []byte{0x02})
+
+	tests := []struct {
+		name           string
+		url            string
+		statusA        int
+		statusB        int
+		expectedStatus int
+		expectedBody   string
+		expectedQuery  string
+	}{
+		{
+			name:           "compared",
+			url:            "/api/traces/compare?a=1&b=2",
+			statusA:        200,
+			statusB:        200,
+			expectedStatus: 200,
+		},
+		{
+			name:           "time range hints are passed on",
+			url:            "/api/traces/compare?a=1&b=2&start=10&end=20",
+			statusA:        200,
+			statusB:        200,
+			expectedStatus: 200,
+			expectedQuery:  "end=20&start=10",
+		},
+		{
+			name:           "partial",
+			url:            "/api/traces/compare?a=1&b=2",
+			statusA:        206,
+			statusB:        200,
+			expectedStatus: 206,
+		},
+		{
+			name:           "not found",
+			url:            "/api/traces/compare?a=1&b=2",
+			statusA:        200,
+			statusB:        404,
+			expectedStatus: 404,
+			expectedBody:   "trace b (2) not found",
+		},
+		{
+			name:           "error",
+			url:            "/api/traces/compare?a=1&b=2",
+			statusA:        500,
+			statusB:        200,
+			expectedStatus: 500,
+			expectedBody:   "error occurred",
+		},
+		{
+			name:           "bad request",
+			url:            "/api/traces/compare?a=1",
+			expectedStatus: 400,
+			expectedBody:   "please provide the traceIDs to compare as a and b",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			traces := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				traceID, err := api.ParseTraceID(r)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedQuery, r.URL.RawQuery)
+				assert.Equal(t, api.HeaderAcceptProtobuf, r.Header.Get(api.HeaderAccept))
+
+				tr, statusCode := traceA, tc.statusA
+				expectedPath := "/api/traces/1"
+				if util.TraceIDToHexString(traceID) == "2" {
+					tr, statusCode = traceB, tc.statusB
+					expectedPath = "/api/traces/2"
+				}
+				assert.Equal(t, expectedPath, r.URL.Path)
+
+				body := []byte("error occurred")
+				if statusCode/100 == 2 {
+					body, err = proto.Marshal(tr)
+					require.NoError(t, err)
+				}
+
+				return &http.Response{
+					StatusCode: statusCode,
+					Body:       io.NopCloser(bytes.NewReader(body)),
+					Header:     http.Header{},
+				}, nil
+			})
+
+			rt := newTraceComparisonRoundTripper(traces, log.NewNopLogger())
+
+			resp, err := rt.RoundTrip(httptest.NewRequest("GET", tc.url, nil))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, strings.TrimSpace(string(body)))
+				return
+			}
+
+			expected, err := json.Marshal(trace.Compare(traceA, traceB))
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(body))
+		})
+	}
+}
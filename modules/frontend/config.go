@@ -22,6 +22,7 @@ type Config struct {
 	QueryShards          int                    `yaml:"query_shards,omitempty"`
 	TolerateFailedBlocks int                    `yaml:"tolerate_failed_blocks,omitempty"`
 	Search               SearchConfig           `yaml:"search"`
+	ResultsCache         ResultsCacheConfig     `yaml:"results_cache"`
 }
 
 type SearchConfig struct {
@@ -46,14 +47,9 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 			TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		},
 	}
+	cfg.ResultsCache.RegisterFlagsAndApplyDefaults(prefix, f)
 }
 
-type CortexNoQuerierLimits struct{}
-
-var _ v1.Limits = (*CortexNoQuerierLimits)(nil)
-
-func (CortexNoQuerierLimits) MaxQueriersPerUser(user string) int { return 0 }
-
 // This struct combines several configuration options together to preserve backwards compatibility.
 type CombinedFrontendConfig struct {
 	Handler    transport.HandlerConfig `yaml:",inline"`
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"strings"
 
@@ -19,26 +20,45 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/weaveworks/common/user"
 
+	"github.com/grafana/tempo/modules/frontend/transport"
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/scheduler/queue"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/tempodb"
 )
 
 const (
-	traceByIDOp = "traces"
-	searchOp    = "search"
+	traceByIDOp    = "traces"
+	tracesByIDOp   = "traces_batch"
+	compareOp      = "traces_compare"
+	summaryOp      = "traces_summary"
+	searchOp       = "search"
+	serviceGraphOp = "dependencies"
+	queryRangeOp   = "query_range"
+	cardinalityOp  = "cardinality"
 )
 
 type QueryFrontend struct {
-	TraceByID, Search http.Handler
-	logger            log.Logger
-	queriesPerTenant  *prometheus.CounterVec
-	store             storage.Store
+	TraceByID, TracesByID, TraceComparison, TraceSummary, Search, ServiceGraph, QueryRange, Cardinality http.Handler
+	logger                                                                                              log.Logger
+	queriesPerTenant                                                                                    *prometheus.CounterVec
+	store                                                                                               storage.Store
+	queries                                                                                             *activeQueries
+}
+
+// Limits are the per-tenant limits enforced by the QueryFrontend.
+type Limits interface {
+	// MaxTraceIDsPerBatch returns the maximum number of trace IDs of a batch trace by ID request, 0 for no limit.
+	MaxTraceIDsPerBatch(user string) int
+	// MaxBytesPerBatchQuery returns the maximum size of the traces of a batch trace by ID request, 0 for no limit.
+	MaxBytesPerBatchQuery(user string) int
+	// MaxBytesPerTrace returns the maximum size of a trace, 0 for no limit. It's enforced when summarizing traces.
+	MaxBytesPerTrace(user string) int
 }
 
 // New returns a new QueryFrontend
-func New(cfg Config, next http.RoundTripper, store storage.Store, logger log.Logger, registerer prometheus.Registerer) (*QueryFrontend, error) {
+func New(cfg Config, next http.RoundTripper, store storage.Store, limits Limits, logger log.Logger, registerer prometheus.Registerer) (*QueryFrontend, error) {
 	level.Info(logger).Log("msg", "creating middleware in query frontend")
 
 	if cfg.QueryShards < minQueryShards || cfg.QueryShards > maxQueryShards {
@@ -57,44 +77,111 @@ func New(cfg Config, next http.RoundTripper, store storage.Store, logger log.Log
 		return nil, fmt.Errorf("query backend after should be less than or equal to query ingester until")
 	}
 
+	// spans can arrive for traces that were already cached. the ttl bounds how long they are missing from the cache.
+	if cfg.ResultsCache.Cache != "" && cfg.ResultsCache.ttl() <= 0 {
+		return nil, fmt.Errorf("frontend results cache ttl should be greater than 0")
+	}
+
 	queriesPerTenant := promauto.With(registerer).NewCounterVec(prometheus.CounterOpts{
 		Namespace: "tempo",
 		Name:      "query_frontend_queries_total",
 		Help:      "Total queries received per tenant.",
 	}, []string{"tenant", "op"})
 
+	resultsCache, err := newResultsCache(cfg.ResultsCache, registerer, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	retryWare := newRetryWare(cfg.MaxRetries, registerer)
 
+	// requests without an estimated cost are queued as if they scanned the target bytes of a search request
+	next = newDefaultCostMiddleware(int64(cfg.Search.Sharder.TargetBytesPerRequest)).Wrap(next)
+
 	// tracebyid middleware
-	traceByIDMiddleware := MergeMiddlewares(newTraceByIDMiddleware(cfg, logger), retryWare)
-	searchMiddleware := MergeMiddlewares(newSearchMiddleware(cfg, store, logger), retryWare)
+	traceByIDMiddleware := MergeMiddlewares(newTraceByIDMiddleware(cfg, resultsCache, logger), retryWare)
+	tracesByIDMiddleware := MergeMiddlewares(newTracesByIDMiddleware(cfg, limits, logger), retryWare)
+	searchMiddleware := MergeMiddlewares(newSearchMiddleware(cfg, store, resultsCache, logger), retryWare)
+	metricsGeneratorMiddleware := MergeMiddlewares(newMetricsGeneratorMiddleware(), retryWare)
 
 	traceByIDCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{
 		"op": traceByIDOp,
 	})
+	tracesByIDCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{
+		"op": tracesByIDOp,
+	})
+	compareCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{
+		"op": compareOp,
+	})
+	summaryCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{
+		"op": summaryOp,
+	})
 	searchCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{
 		"op": searchOp,
 	})
+	serviceGraphCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{
+		"op": serviceGraphOp,
+	})
+	queryRangeCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{
+		"op": queryRangeOp,
+	})
+	cardinalityCounter := queriesPerTenant.MustCurryWith(prometheus.Labels{
+		"op": cardinalityOp,
+	})
+
+	// the hostname identifies the replica executing a query in the queries API
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get hostname for the query IDs")
+	}
+
+	queries := newActiveQueries(hostname)
+	slowQueries := transport.NewSlowQueryLogger(cfg.Config.Handler.LogQueriesLongerThan, logger)
 
 	traces := traceByIDMiddleware.Wrap(next)
+	tracesBatch := tracesByIDMiddleware.Wrap(next)
+	compare := newTraceComparisonRoundTripper(traces, logger)
+	summary := newTraceSummaryRoundTripper(traces, limits, logger)
 	search := searchMiddleware.Wrap(next)
+	metricsGenerator := metricsGeneratorMiddleware.Wrap(next)
 	return &QueryFrontend{
-		TraceByID:        newHandler(traces, traceByIDCounter, logger),
-		Search:           newHandler(search, searchCounter, logger),
+		TraceByID:        newHandler(traces, traceByIDOp, traceByIDCounter, queries, slowQueries, logger),
+		TracesByID:       newHandler(tracesBatch, tracesByIDOp, tracesByIDCounter, queries, slowQueries, logger),
+		TraceComparison:  newHandler(compare, compareOp, compareCounter, queries, slowQueries, logger),
+		TraceSummary:     newHandler(summary, summaryOp, summaryCounter, queries, slowQueries, logger),
+		Search:           newHandler(search, searchOp, searchCounter, queries, slowQueries, logger),
+		ServiceGraph:     newHandler(metricsGenerator, serviceGraphOp, serviceGraphCounter, queries, slowQueries, logger),
+		QueryRange:       newHandler(metricsGenerator, queryRangeOp, queryRangeCounter, queries, slowQueries, logger),
+		Cardinality:      newHandler(metricsGenerator, cardinalityOp, cardinalityCounter, queries, slowQueries, logger),
 		logger:           logger,
 		queriesPerTenant: queriesPerTenant,
 		store:            store,
+		queries:          queries,
 	}, nil
 }
 
+// newDefaultCostMiddleware attaches defaultCost to requests which don't have an estimated cost yet,
+// like trace by ID requests and searches of the ingesters.
+func newDefaultCostMiddleware(defaultCost int64) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if queue.CostFromContext(r.Context()) == 0 {
+				r = r.WithContext(queue.ContextWithCost(r.Context(), defaultCost))
+			}
+			return next.RoundTrip(r)
+		})
+	})
+}
+
 // newTraceByIDMiddleware creates a new frontend middleware responsible for handling get traces requests.
-func newTraceByIDMiddleware(cfg Config, logger log.Logger) Middleware {
+func newTraceByIDMiddleware(cfg Config, c *resultsCache, logger log.Logger) Middleware {
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
 		// We're constructing middleware in this statement, each middleware wraps the next one from left-to-right
 		// - the Deduper dedupes Span IDs for Zipkin support
+		// - the TraceByIDCache returns traces that have been combined before
 		// - the ShardingWare shards queries by splitting the block ID space
 		// - the RetryWare retries requests that have failed (error or http status 500)
-		rt := NewRoundTripper(next, newDeduper(logger), newTraceByIDSharder(cfg.QueryShards, cfg.TolerateFailedBlocks, logger))
+		rt := NewRoundTripper(next, newDeduper(logger), newTraceByIDCache(c, cfg.ResultsCache.TraceByIDCacheAfter, logger), newTraceByIDSharder(cfg.QueryShards, cfg.TolerateFailedBlocks, logger))
 
 		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
 			// validate traceID
@@ -107,6 +194,16 @@ func newTraceByIDMiddleware(cfg Config, logger log.Logger) Middleware {
 				}, nil
 			}
 
+			// validate the time range hints, they are passed on to the queriers with the other params
+			_, _, err = api.ParseTraceByIDTimeRange(r)
+			if err != nil {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       io.NopCloser(strings.NewReader(err.Error())),
+					Header:     http.Header{},
+				}, nil
+			}
+
 			// check marshalling format
 			marshallingFormat := api.HeaderAcceptJSON
 			if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
@@ -163,11 +260,102 @@ func newTraceByIDMiddleware(cfg Config, logger log.Logger) Middleware {
 	})
 }
 
+// newTracesByIDMiddleware creates a new frontend middleware responsible for handling batch trace by ID requests.
+// Every shard of a batch costs as many trace by ID requests as the batch has trace IDs.
+func newTracesByIDMiddleware(cfg Config, limits Limits, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		// - the ShardingWare shards queries by splitting the block ID space, every shard looks up all the IDs
+		// - the RetryWare retries requests that have failed (error or http status 500)
+		rt := NewRoundTripper(next, newTracesByIDSharder(cfg.QueryShards, cfg.TolerateFailedBlocks, limits, logger))
+
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method != http.MethodPost {
+				return &http.Response{
+					StatusCode: http.StatusMethodNotAllowed,
+					Body:       io.NopCloser(strings.NewReader("batch trace lookups must be POST requests")),
+					Header:     http.Header{},
+				}, nil
+			}
+
+			// validate the traceIDs, the body is restored to be passed on to the queriers
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				return nil, errors.Wrap(err, "error reading request body at query frontend")
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			orgID, _ := user.ExtractOrgID(r.Context())
+			ids, err := api.ParseTraceIDs(r, limits.MaxTraceIDsPerBatch(orgID))
+			if err != nil {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       io.NopCloser(strings.NewReader(err.Error())),
+					Header:     http.Header{},
+				}, nil
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			r = r.WithContext(queue.ContextWithCost(r.Context(), int64(len(ids))*int64(cfg.Search.Sharder.TargetBytesPerRequest)))
+
+			// validate the time range hints, they are passed on to the queriers with the other params
+			_, _, err = api.ParseTraceByIDTimeRange(r)
+			if err != nil {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       io.NopCloser(strings.NewReader(err.Error())),
+					Header:     http.Header{},
+				}, nil
+			}
+
+			// check marshalling format
+			marshallingFormat := api.HeaderAcceptJSON
+			if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
+				marshallingFormat = api.HeaderAcceptProtobuf
+			}
+
+			// enforce all communication internal to Tempo to be in protobuf bytes
+			r.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+
+			resp, err := rt.RoundTrip(r)
+
+			if resp != nil && resp.StatusCode == http.StatusOK && marshallingFormat == api.HeaderAcceptJSON {
+				body, err := io.ReadAll(resp.Body)
+				resp.Body.Close()
+				if err != nil {
+					return nil, errors.Wrap(err, "error reading response body at query frontend")
+				}
+				responseObject := &tempopb.TracesByIDResponse{}
+				err = proto.Unmarshal(body, responseObject)
+				if err != nil {
+					return nil, err
+				}
+
+				var jsonTraces bytes.Buffer
+				marshaller := &jsonpb.Marshaler{}
+				err = marshaller.Marshal(&jsonTraces, responseObject)
+				if err != nil {
+					return nil, err
+				}
+				resp.Body = io.NopCloser(bytes.NewReader(jsonTraces.Bytes()))
+				resp.ContentLength = int64(jsonTraces.Len())
+			}
+			span := opentracing.SpanFromContext(r.Context())
+			if span != nil {
+				span.SetTag("contentType", marshallingFormat)
+			}
+
+			if resp != nil {
+				resp.Header.Set(api.HeaderContentType, marshallingFormat)
+			}
+
+			return resp, err
+		})
+	})
+}
+
 // newSearchMiddleware creates a new frontend middleware to handle search and search tags requests.
-func newSearchMiddleware(cfg Config, reader tempodb.Reader, logger log.Logger) Middleware {
+func newSearchMiddleware(cfg Config, reader tempodb.Reader, c *resultsCache, logger log.Logger) Middleware {
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
 		ingesterSearchRT := next
-		backendSearchRT := NewRoundTripper(next, newSearchSharder(reader, cfg.Search.Sharder, logger))
+		backendSearchRT := NewRoundTripper(next, newSearchSharder(reader, cfg.Search.Sharder, c, logger))
 
 		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
 			// backend search queries require sharding so we pass through a special roundtripper
@@ -186,6 +374,22 @@ func newSearchMiddleware(cfg Config, reader tempodb.Reader, logger log.Logger) M
 	})
 }
 
+// newMetricsGeneratorMiddleware creates a new frontend middleware to handle requests served by the
+// metrics-generators, i.e. service graph and query range requests. The metrics-generators are
+// queried by a single querier.
+func newMetricsGeneratorMiddleware() Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			orgID, _ := user.ExtractOrgID(r.Context())
+
+			r.Header.Set(user.OrgIDHeaderName, orgID)
+			r.RequestURI = buildUpstreamRequestURI(r.RequestURI, nil)
+
+			return next.RoundTrip(r)
+		})
+	})
+}
+
 // buildUpstreamRequestURI returns a uri based on the passed parameters
 // we do this because weaveworks/common uses the RequestURI field to translate from http.Request to httpgrpc.Request
 // https://github.com/weaveworks/common/blob/47e357f4e1badb7da17ad74bae63e228bdd76e8f/httpgrpc/server/server.go#L48
@@ -31,7 +31,7 @@ func TestFrontendRoundTripsSearch(t *testing.T) {
 				TargetBytesPerRequest: defaultTargetBytesPerRequest,
 			},
 		},
-	}, next, nil, log.NewNopLogger(), nil)
+	}, next, nil, nil, log.NewNopLogger(), nil)
 	require.NoError(t, err)
 
 	req := httptest.NewRequest("GET", "/", nil)
@@ -50,7 +50,7 @@ func TestFrontendBadConfigFails(t *testing.T) {
 				TargetBytesPerRequest: defaultTargetBytesPerRequest,
 			},
 		},
-	}, nil, nil, log.NewNopLogger(), nil)
+	}, nil, nil, nil, log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "frontend query shards should be between 2 and 256 (both inclusive)")
 	assert.Nil(t, f)
 
@@ -61,7 +61,7 @@ func TestFrontendBadConfigFails(t *testing.T) {
 				TargetBytesPerRequest: defaultTargetBytesPerRequest,
 			},
 		},
-	}, nil, nil, log.NewNopLogger(), nil)
+	}, nil, nil, nil, log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "frontend query shards should be between 2 and 256 (both inclusive)")
 	assert.Nil(t, f)
 
@@ -72,7 +72,7 @@ func TestFrontendBadConfigFails(t *testing.T) {
 				TargetBytesPerRequest: defaultTargetBytesPerRequest,
 			},
 		},
-	}, nil, nil, log.NewNopLogger(), nil)
+	}, nil, nil, nil, log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "frontend search concurrent requests should be greater than 0")
 	assert.Nil(t, f)
 
@@ -83,7 +83,7 @@ func TestFrontendBadConfigFails(t *testing.T) {
 				TargetBytesPerRequest: 0,
 			},
 		},
-	}, nil, nil, log.NewNopLogger(), nil)
+	}, nil, nil, nil, log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "frontend search target bytes per request should be greater than 0")
 	assert.Nil(t, f)
 
@@ -96,7 +96,21 @@ func TestFrontendBadConfigFails(t *testing.T) {
 				QueryBackendAfter:     time.Hour,
 			},
 		},
-	}, nil, nil, log.NewNopLogger(), nil)
+	}, nil, nil, nil, log.NewNopLogger(), nil)
 	assert.EqualError(t, err, "query backend after should be less than or equal to query ingester until")
 	assert.Nil(t, f)
+
+	f, err = New(Config{QueryShards: maxQueryShards,
+		Search: SearchConfig{
+			Sharder: SearchSharderConfig{
+				ConcurrentRequests:    defaultConcurrentRequests,
+				TargetBytesPerRequest: defaultTargetBytesPerRequest,
+			},
+		},
+		ResultsCache: ResultsCacheConfig{
+			Cache: "lru",
+		},
+	}, nil, nil, nil, log.NewNopLogger(), nil)
+	assert.EqualError(t, err, "frontend results cache ttl should be greater than 0")
+	assert.Nil(t, f)
 }
@@ -16,6 +16,8 @@ import (
 	"github.com/weaveworks/common/httpgrpc/server"
 	"github.com/weaveworks/common/tracing"
 	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/frontend/transport"
 )
 
 const (
@@ -35,16 +37,22 @@ var (
 // frontend endpoints and should only contain functionality that is common to all.
 type handler struct {
 	roundTripper     http.RoundTripper
+	op               string
 	logger           log.Logger
 	queriesPerTenant *prometheus.CounterVec
+	queries          *activeQueries
+	slowQueries      *transport.SlowQueryLogger
 }
 
 // newHandler creates a handler
-func newHandler(rt http.RoundTripper, queriesPerTenant *prometheus.CounterVec, logger log.Logger) http.Handler {
+func newHandler(rt http.RoundTripper, op string, queriesPerTenant *prometheus.CounterVec, queries *activeQueries, slowQueries *transport.SlowQueryLogger, logger log.Logger) http.Handler {
 	return &handler{
 		roundTripper:     rt,
+		op:               op,
 		logger:           logger,
 		queriesPerTenant: queriesPerTenant,
+		queries:          queries,
+		slowQueries:      slowQueries,
 	}
 }
 
@@ -61,23 +69,32 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	f.queriesPerTenant.WithLabelValues(orgID).Inc()
 
+	// track the query so it can be listed and canceled
+	ctx, query := f.queries.start(ctx, orgID, f.op, r)
+	defer f.queries.finish(query)
+	r = r.WithContext(ctx)
+
 	// add orgid to existing spans
 	span := opentracing.SpanFromContext(r.Context())
 	if span != nil {
 		span.SetTag("orgID", orgID)
+		span.SetTag("queryID", query.id)
 	}
 
 	resp, err := f.roundTripper.RoundTrip(r)
 	if err != nil {
 		err = writeError(w, err)
+		statusCode := errorStatusCode(err)
+		f.slowQueries.Log(r, orgID, query.id, statusCode, time.Since(start), query.queryStats())
 		level.Info(f.logger).Log(
 			"tenant", orgID,
+			"queryID", query.id,
 			"method", r.Method,
 			"traceID", traceID,
 			"url", r.URL.RequestURI(),
 			"duration", time.Since(start).String(),
 			"response_size", 0,
-			"status", http.StatusInternalServerError,
+			"status", statusCode,
 			"err", err.Error(),
 		)
 		return
@@ -85,14 +102,17 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	if resp == nil {
 		err = writeError(w, errors.New(NilResponseError))
+		statusCode := errorStatusCode(err)
+		f.slowQueries.Log(r, orgID, query.id, statusCode, time.Since(start), query.queryStats())
 		level.Info(f.logger).Log(
 			"tenant", orgID,
+			"queryID", query.id,
 			"method", r.Method,
 			"traceID", traceID,
 			"url", r.URL.RequestURI(),
 			"duration", time.Since(start).String(),
 			"response_size", 0,
-			"status", http.StatusInternalServerError,
+			"status", statusCode,
 			"err", err.Error(),
 		)
 		return
@@ -100,6 +120,7 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// write headers, status code and body
 	copyHeader(w.Header(), resp.Header)
+	w.Header().Set(HeaderQueryID, query.id)
 	w.WriteHeader(resp.StatusCode)
 	if resp.Body != nil {
 		_, _ = io.Copy(w, resp.Body)
@@ -116,8 +137,10 @@ func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		contentLength = resp.ContentLength
 	}
 
+	f.slowQueries.Log(r, orgID, query.id, statusCode, time.Since(start), query.queryStats())
 	level.Info(f.logger).Log(
 		"tenant", orgID,
+		"queryID", query.id,
 		"method", r.Method,
 		"traceID", traceID,
 		"url", r.URL.RequestURI(),
@@ -155,6 +178,14 @@ func writeError(w http.ResponseWriter, err error) error {
 	return err
 }
 
+// errorStatusCode returns the status code writeError responds with for err.
+func errorStatusCode(err error) int {
+	if resp, ok := httpgrpc.HTTPResponseFromError(err); ok {
+		return int(resp.Code)
+	}
+	return http.StatusInternalServerError
+}
+
 // isRequestBodyTooLarge returns true if the error is "http: request body too large".
 func isRequestBodyTooLarge(err error) bool {
 	return err != nil && strings.Contains(err.Error(), "http: request body too large")
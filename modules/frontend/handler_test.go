@@ -0,0 +1,32 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorStatusCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{err: context.Canceled, expected: StatusClientClosedRequest},
+		{err: context.DeadlineExceeded, expected: http.StatusGatewayTimeout},
+		{err: errors.New("http: request body too large"), expected: http.StatusRequestEntityTooLarge},
+		{err: errors.New("foo"), expected: http.StatusInternalServerError},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.err.Error(), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := writeError(w, tc.err)
+			assert.Equal(t, tc.expected, errorStatusCode(err))
+			assert.Equal(t, tc.expected, w.Code)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/weaveworks/common/user"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/querystats"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+const (
+	// HeaderQueryID is the response header the ID of a query is returned in.
+	HeaderQueryID = "X-Tempo-Query-ID"
+
+	muxVarQueryID = "queryID"
+
+	// queryIDSeparator separates the replica from the unique part of a query ID.
+	queryIDSeparator = ":"
+)
+
+type activeQueryContextKey struct{}
+
+// activeQuery is a query in progress in the query-frontend. It can be canceled through the queries API.
+type activeQuery struct {
+	id     string
+	tenant string
+	op     string
+	url    string
+	start  time.Time
+	cancel context.CancelFunc
+
+	inspectedBytes *atomic.Uint64
+
+	statsMtx sync.Mutex
+	stats    *tempopb.QueryStats
+}
+
+// ActiveQuery describes a query in progress in the query-frontend.
+type ActiveQuery struct {
+	ID             string    `json:"id"`
+	Replica        string    `json:"replica"`
+	Tenant         string    `json:"tenant"`
+	Op             string    `json:"op"`
+	URL            string    `json:"url"`
+	Start          time.Time `json:"start"`
+	Elapsed        string    `json:"elapsed"`
+	InspectedBytes uint64    `json:"inspectedBytes"`
+}
+
+// activeQueries tracks the queries in progress in the query-frontend. Queries are only tracked by the
+// replica executing them, their IDs are prefixed with the replica so requests sent to another replica
+// can be pointed to it.
+type activeQueries struct {
+	replica string
+
+	mtx     sync.Mutex
+	queries map[string]*activeQuery
+}
+
+func newActiveQueries(replica string) *activeQueries {
+	return &activeQueries{
+		replica: replica,
+		queries: map[string]*activeQuery{},
+	}
+}
+
+// start registers a new query. The returned context is canceled when the query is canceled, finish
+// must be called with the query once it's done.
+func (q *activeQueries) start(ctx context.Context, tenant, op string, r *http.Request) (context.Context, *activeQuery) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	query := &activeQuery{
+		id:             q.replica + queryIDSeparator + uuid.New().String(),
+		tenant:         tenant,
+		op:             op,
+		url:            r.URL.RequestURI(),
+		start:          time.Now(),
+		cancel:         cancel,
+		inspectedBytes: atomic.NewUint64(0),
+	}
+
+	q.mtx.Lock()
+	q.queries[query.id] = query
+	q.mtx.Unlock()
+
+	return context.WithValue(ctx, activeQueryContextKey{}, query), query
+}
+
+func (q *activeQueries) finish(query *activeQuery) {
+	q.mtx.Lock()
+	delete(q.queries, query.id)
+	q.mtx.Unlock()
+
+	query.cancel()
+}
+
+// list returns the queries of the tenant in progress, the oldest first.
+func (q *activeQueries) list(tenant string) []ActiveQuery {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	now := time.Now()
+	queries := []ActiveQuery{}
+	for _, query := range q.queries {
+		if query.tenant != tenant {
+			continue
+		}
+		queries = append(queries, ActiveQuery{
+			ID:             query.id,
+			Replica:        q.replica,
+			Tenant:         query.tenant,
+			Op:             query.op,
+			URL:            query.url,
+			Start:          query.start,
+			Elapsed:        now.Sub(query.start).String(),
+			InspectedBytes: query.inspectedBytes.Load(),
+		})
+	}
+
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].Start.Before(queries[j].Start)
+	})
+
+	return queries
+}
+
+// cancel cancels the query of the tenant with the given id. It returns false if there is no such query.
+func (q *activeQueries) cancel(tenant, id string) bool {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	query, ok := q.queries[id]
+	if !ok || query.tenant != tenant {
+		return false
+	}
+
+	// canceling the context stops the sharders, the queued jobs and the jobs running on the queriers
+	query.cancel()
+	return true
+}
+
+// queryReplica returns the replica of the query-frontend executing the query with the given id.
+func queryReplica(id string) string {
+	i := strings.LastIndex(id, queryIDSeparator)
+	if i < 0 {
+		return ""
+	}
+	return id[:i]
+}
+
+// addStats adds the statistics of a job of the query.
+func (q *activeQuery) addStats(stats *tempopb.QueryStats) {
+	q.statsMtx.Lock()
+	defer q.statsMtx.Unlock()
+
+	q.stats = querystats.Merge(q.stats, stats)
+}
+
+// queryStats returns the statistics of the jobs of the query finished so far.
+func (q *activeQuery) queryStats() *tempopb.QueryStats {
+	q.statsMtx.Lock()
+	defer q.statsMtx.Unlock()
+
+	if q.stats == nil {
+		return nil
+	}
+	stats := *q.stats
+	return &stats
+}
+
+// activeQueryFromContext returns the query a request belongs to, or nil if it's not tracked.
+func activeQueryFromContext(ctx context.Context) *activeQuery {
+	query, _ := ctx.Value(activeQueryContextKey{}).(*activeQuery)
+	return query
+}
+
+// QueriesHandler lists the queries of the tenant in progress.
+func (q *QueryFrontend) QueriesHandler(w http.ResponseWriter, r *http.Request) {
+	tenant, err := user.ExtractOrgID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	_ = json.NewEncoder(w).Encode(struct {
+		Queries []ActiveQuery `json:"queries"`
+	}{
+		Queries: q.queries.list(tenant),
+	})
+}
+
+// CancelQueryHandler cancels a query of the tenant in progress. Queries executed by another replica
+// of the query-frontend are rejected with the name of that replica.
+func (q *QueryFrontend) CancelQueryHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		http.Error(w, "only DELETE is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	tenant, err := user.ExtractOrgID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	id := mux.Vars(r)[muxVarQueryID]
+	if replica := queryReplica(id); replica != "" && replica != q.queries.replica {
+		http.Error(w, fmt.Sprintf("query is executed by query-frontend %s, send the request to it", replica), http.StatusMisdirectedRequest)
+		return
+	}
+	if !q.queries.cancel(tenant, id) {
+		http.Error(w, "query not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,110 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/frontend/transport"
+)
+
+func TestActiveQueries(t *testing.T) {
+	queries := newActiveQueries("frontend-1")
+
+	ctx, query := queries.start(context.Background(), "test", searchOp, httptest.NewRequest("GET", "/api/search?tags=a%3Db", nil))
+	assert.Equal(t, query, activeQueryFromContext(ctx))
+	query.inspectedBytes.Add(10)
+
+	actual := queries.list("test")
+	require.Len(t, actual, 1)
+	assert.Equal(t, query.id, actual[0].ID)
+	assert.True(t, strings.HasPrefix(query.id, "frontend-1:"))
+	assert.Equal(t, "frontend-1", actual[0].Replica)
+	assert.Equal(t, searchOp, actual[0].Op)
+	assert.Equal(t, "/api/search?tags=a%3Db", actual[0].URL)
+	assert.Equal(t, uint64(10), actual[0].InspectedBytes)
+
+	// queries of other tenants are not visible
+	assert.Empty(t, queries.list("other"))
+	assert.False(t, queries.cancel("other", query.id))
+	assert.NoError(t, ctx.Err())
+
+	assert.True(t, queries.cancel("test", query.id))
+	assert.Error(t, ctx.Err())
+
+	queries.finish(query)
+	assert.Empty(t, queries.list("test"))
+	assert.False(t, queries.cancel("test", query.id))
+}
+
+func TestCancelQuery(t *testing.T) {
+	queries := newActiveQueries("frontend-1")
+	started := make(chan struct{})
+
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		close(started)
+		<-r.Context().Done()
+		return nil, r.Context().Err()
+	})
+	q := &QueryFrontend{
+		Search:  newHandler(next, searchOp, prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"tenant"}), queries, transport.NewSlowQueryLogger(0, log.NewNopLogger()), log.NewNopLogger()),
+		queries: queries,
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		req := httptest.NewRequest("GET", "/api/search", nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+		w := httptest.NewRecorder()
+		q.Search.ServeHTTP(w, req)
+		done <- w
+	}()
+	<-started
+
+	req := httptest.NewRequest("GET", "/api/queries", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+	w := httptest.NewRecorder()
+	q.QueriesHandler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	list := struct {
+		Queries []ActiveQuery `json:"queries"`
+	}{}
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&list))
+	require.Len(t, list.Queries, 1)
+	id := list.Queries[0].ID
+
+	cancel := func(method, tenant, id string) int {
+		req := httptest.NewRequest(method, "/api/queries/"+id, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), tenant))
+		req = mux.SetURLVars(req, map[string]string{muxVarQueryID: id})
+		w := httptest.NewRecorder()
+		q.CancelQueryHandler(w, req)
+		return w.Code
+	}
+	assert.Equal(t, http.StatusMethodNotAllowed, cancel("GET", "test", id))
+	assert.Equal(t, http.StatusNotFound, cancel("DELETE", "other", id))
+	assert.Equal(t, http.StatusNotFound, cancel("DELETE", "test", "unknown"))
+	assert.Equal(t, http.StatusNotFound, cancel("DELETE", "test", "frontend-1:unknown"))
+	assert.Equal(t, http.StatusMisdirectedRequest, cancel("DELETE", "test", "frontend-2:"+strings.TrimPrefix(id, "frontend-1:")))
+	assert.Equal(t, http.StatusNoContent, cancel("DELETE", "test", id))
+
+	select {
+	case w := <-done:
+		assert.Equal(t, StatusClientClosedRequest, w.Code)
+	case <-time.After(time.Second):
+		t.Fatal("query was not canceled")
+	}
+	assert.Empty(t, queries.list("test"))
+}
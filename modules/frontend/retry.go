@@ -90,6 +90,15 @@ func (r retryWare) RoundTrip(req *http.Request) (*http.Response, error) {
 			ot_log.Int("status_code", statusCode),
 			ot_log.String("errMsg", errMsg),
 		)
+
+		// the body was consumed by the previous try, requests with a body must be able to rewind it
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
 	}
 }
 
@@ -1,6 +1,7 @@
 package frontend
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -15,6 +16,8 @@ import (
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/boundedwaitgroup"
+	"github.com/grafana/tempo/pkg/querystats"
+	"github.com/grafana/tempo/pkg/scheduler/queue"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -83,6 +86,12 @@ func (r *searchResponse) addResponse(res *tempopb.SearchResponse) {
 	r.resultsMetrics.InspectedTraces += res.Metrics.InspectedTraces
 	r.resultsMetrics.SkippedBlocks += res.Metrics.SkippedBlocks
 	r.resultsMetrics.SkippedTraces += res.Metrics.SkippedTraces
+	r.resultsMetrics.Stats = querystats.Merge(r.resultsMetrics.Stats, res.Metrics.Stats)
+
+	if query := activeQueryFromContext(r.ctx); query != nil {
+		query.inspectedBytes.Add(res.Metrics.InspectedBytes)
+		query.addStats(res.Metrics.Stats)
+	}
 }
 
 func (r *searchResponse) shouldQuit() bool {
@@ -126,6 +135,7 @@ func (r *searchResponse) result() *tempopb.SearchResponse {
 type searchSharder struct {
 	next   http.RoundTripper
 	reader tempodb.Reader
+	cache  *resultsCache
 
 	cfg    SearchSharderConfig
 	logger log.Logger
@@ -141,12 +151,14 @@ type SearchSharderConfig struct {
 	QueryIngestersUntil   time.Duration `yaml:"query_ingesters_until,omitempty"`
 }
 
-// newSearchSharder creates a sharding middleware for search
-func newSearchSharder(reader tempodb.Reader, cfg SearchSharderConfig, logger log.Logger) Middleware {
+// newSearchSharder creates a sharding middleware for search. The responses of block requests are
+// cached in c if it is not nil.
+func newSearchSharder(reader tempodb.Reader, cfg SearchSharderConfig, c *resultsCache, logger log.Logger) Middleware {
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
 		return searchSharder{
 			next:   next,
 			reader: reader,
+			cache:  c,
 			logger: logger,
 			cfg:    cfg,
 		}
@@ -215,6 +227,14 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 	}
 	span.SetTag("request-count", len(reqs))
 
+	var metasByID map[string]*backend.BlockMeta
+	if s.cache != nil {
+		metasByID = make(map[string]*backend.BlockMeta, len(blocks))
+		for _, m := range blocks {
+			metasByID[m.BlockID.String()] = m
+		}
+	}
+
 	// execute requests
 	wg := boundedwaitgroup.New(uint(s.cfg.ConcurrentRequests))
 	overallResponse := newSearchResponse(ctx, int(searchReq.Limit))
@@ -233,6 +253,22 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 				return
 			}
 
+			var cacheKey string
+			if s.cache != nil && innerR != ingesterReq {
+				cacheKey = searchBlockCacheKey(tenantID, innerR, metasByID)
+			}
+			if cacheKey != "" {
+				if buf, ok := s.cache.fetch(ctx, searchOp, cacheKey); ok {
+					results := &tempopb.SearchResponse{}
+					if err := jsonpb.Unmarshal(bytes.NewReader(buf), results); err == nil && results.Metrics != nil {
+						// the cached stats describe the work done to fill the cache
+						results.Metrics.Stats = &tempopb.QueryStats{CacheHits: 1}
+						overallResponse.addResponse(results)
+						return
+					}
+				}
+			}
+
 			resp, err := s.next.RoundTrip(innerR)
 			if err != nil {
 				_ = level.Error(s.logger).Log("msg", "error executing sharded query", "url", innerR.RequestURI, "err", err)
@@ -259,14 +295,24 @@ func (s searchSharder) RoundTrip(r *http.Request) (*http.Response, error) {
 			}
 
 			// successful query, read the body
+			buf, err := io.ReadAll(resp.Body)
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error reading response body status == ok", "url", innerR.RequestURI, "err", err)
+				overallResponse.setError(err)
+				return
+			}
 			results := &tempopb.SearchResponse{}
-			err = jsonpb.Unmarshal(resp.Body, results)
+			err = jsonpb.Unmarshal(bytes.NewReader(buf), results)
 			if err != nil {
 				_ = level.Error(s.logger).Log("msg", "error reading response body status == ok", "url", innerR.RequestURI, "err", err)
 				overallResponse.setError(err)
 				return
 			}
 
+			if cacheKey != "" {
+				s.cache.store(ctx, cacheKey, buf)
+			}
+
 			// happy path
 			overallResponse.addResponse(results)
 		}(req)
@@ -340,7 +386,12 @@ func (s *searchSharder) backendRequests(ctx context.Context, tenantID string, pa
 
 		blockID := m.BlockID.String()
 		for startPage := 0; startPage < int(m.TotalRecords); startPage += pagesPerQuery {
-			subR := parent.Clone(ctx)
+			// the queue schedules requests by the estimated bytes they scan
+			pages := pagesPerQuery
+			if remaining := int(m.TotalRecords) - startPage; remaining < pages {
+				pages = remaining
+			}
+			subR := parent.Clone(queue.ContextWithCost(ctx, int64(pages)*int64(bytesPerPage)))
 			subR.Header.Set(user.OrgIDHeaderName, tenantID)
 
 			subR, err := api.BuildSearchBlockRequest(subR, &tempopb.SearchBlockRequest{
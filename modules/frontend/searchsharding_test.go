@@ -17,7 +17,9 @@ import (
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/google/uuid"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/scheduler/queue"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/blocklist"
 	"github.com/grafana/tempo/tempodb/encoding/common"
@@ -31,7 +33,11 @@ type mockReader struct {
 	metas []*backend.BlockMeta
 }
 
-func (m *mockReader) Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string) ([]*tempopb.Trace, []error, error) {
+func (m *mockReader) Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64) ([]*tempopb.Trace, []error, error) {
+	return nil, nil, nil
+}
+
+func (m *mockReader) FindMany(ctx context.Context, tenantID string, ids []common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64) ([][]*tempopb.Trace, [][]error, error) {
 	return nil, nil, nil
 }
 
@@ -41,6 +47,9 @@ func (m *mockReader) BlockMetas(tenantID string) []*backend.BlockMeta {
 func (m *mockReader) Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error) {
 	return nil, nil
 }
+func (m *mockReader) IterateObjects(ctx context.Context, metas []*backend.BlockMeta, chunkSizeBytes uint32, callback tempodb.IterateObjectCallback) error {
+	return nil
+}
 func (m *mockReader) EnablePolling(sharder blocklist.JobSharder) {}
 func (m *mockReader) Shutdown()                                  {}
 
@@ -253,6 +262,31 @@ func TestBackendRequests(t *testing.T) {
 	}
 }
 
+func TestBackendRequestsCost(t *testing.T) {
+	s := &searchSharder{
+		cfg: SearchSharderConfig{
+			TargetBytesPerRequest: 300,
+		},
+	}
+	req := httptest.NewRequest("GET", "/?start=10&end=20", nil)
+
+	reqs, err := s.backendRequests(context.Background(), "test", req, []*backend.BlockMeta{
+		{
+			Size:         1000,
+			TotalRecords: 100,
+			BlockID:      uuid.MustParse("00000000-0000-0000-0000-000000000000"),
+		},
+	})
+	require.NoError(t, err)
+
+	// the last request only covers the remaining pages
+	actual := []int64{}
+	for _, r := range reqs {
+		actual = append(actual, queue.CostFromContext(r.Context()))
+	}
+	assert.Equal(t, []int64{300, 300, 300, 100}, actual)
+}
+
 func TestIngesterRequest(t *testing.T) {
 	now := int(time.Now().Unix())
 	tenMinutesAgo := int(time.Now().Add(-10 * time.Minute).Unix())
@@ -493,6 +527,7 @@ func TestSearchSharderRoundTrip(t *testing.T) {
 					InspectedBytes:  3,
 					SkippedBlocks:   4,
 					SkippedTraces:   9,
+					Stats:           &tempopb.QueryStats{BlocksFetched: 1, BytesRead: 100, PagesDecoded: 2},
 				}},
 			status2: 200,
 			response2: &tempopb.SearchResponse{
@@ -508,6 +543,7 @@ func TestSearchSharderRoundTrip(t *testing.T) {
 					InspectedBytes:  7,
 					SkippedBlocks:   8,
 					SkippedTraces:   10,
+					Stats:           &tempopb.QueryStats{BlocksFetched: 1, BytesRead: 50, CacheHits: 1},
 				}},
 			expectedStatus: 200,
 			expectedResponse: &tempopb.SearchResponse{
@@ -527,6 +563,7 @@ func TestSearchSharderRoundTrip(t *testing.T) {
 					InspectedBytes:  10,
 					SkippedBlocks:   12,
 					SkippedTraces:   19,
+					Stats:           &tempopb.QueryStats{BlocksFetched: 2, BytesRead: 150, CacheHits: 1, PagesDecoded: 2},
 				}},
 		},
 		{
@@ -605,7 +642,7 @@ func TestSearchSharderRoundTrip(t *testing.T) {
 			}, SearchSharderConfig{
 				ConcurrentRequests:    1, // 1 concurrent request to force order
 				TargetBytesPerRequest: defaultTargetBytesPerRequest,
-			}, log.NewNopLogger())
+			}, nil, log.NewNopLogger())
 			testRT := NewRoundTripper(next, sharder)
 
 			req := httptest.NewRequest("GET", "/?start=1000&end=1500", nil)
@@ -645,7 +682,7 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 		ConcurrentRequests:    defaultConcurrentRequests,
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		MaxDuration:           5 * time.Minute,
-	}, log.NewNopLogger())
+	}, nil, log.NewNopLogger())
 	testRT := NewRoundTripper(next, sharder)
 
 	// no org id
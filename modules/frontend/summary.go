@@ -0,0 +1,136 @@
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/opentracing/opentracing-go"
+	ot_log "github.com/opentracing/opentracing-go/log"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// traceSummaryResponse is the response of the trace summary endpoint. Trace is the pruned trace in
+// OpenTelemetry JSON, if requested.
+type traceSummaryResponse struct {
+	*trace.Summary
+	Trace json.RawMessage `json:"trace,omitempty"`
+}
+
+// newTraceSummaryRoundTripper creates a RoundTripper summarizing a trace. The trace is fetched through
+// traces, the trace by ID RoundTripper, so it's sharded across the queriers and cached like any other
+// trace by ID request. It's summarized once combined. Traces larger than the tenant's MaxBytesPerTrace
+// are refused, the sharder stops combining them once the limit is exceeded.
+func newTraceSummaryRoundTripper(traces http.RoundTripper, limits Limits, logger log.Logger) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		span, ctx := opentracing.StartSpanFromContext(r.Context(), "frontend.TraceSummary")
+		defer span.Finish()
+		r = r.WithContext(ctx)
+
+		id, err := api.ParseTraceID(r)
+		var prune, pruneSpans int
+		if err == nil {
+			prune, pruneSpans, err = api.ParseTraceSummaryPrune(r)
+		}
+		if err == nil {
+			_, _, err = api.ParseTraceByIDTimeRange(r)
+		}
+		if err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+				Header:     http.Header{},
+			}, nil
+		}
+
+		orgID, err := user.ExtractOrgID(ctx)
+		if err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+				Header:     http.Header{},
+			}, nil
+		}
+		maxBytes := limits.MaxBytesPerTrace(orgID)
+		r = r.WithContext(contextWithMaxTraceBytes(ctx, maxBytes))
+
+		traceID := util.TraceIDToHexString(id)
+		resp, err := traces.RoundTrip(newTraceByIDRequest(r, strings.TrimSuffix(r.URL.Path, "/summary"), traceID))
+		if err != nil {
+			level.Error(logger).Log("msg", "error fetching trace to summarize", "traceID", traceID, "err", err)
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK, http.StatusPartialContent:
+		default:
+			return &http.Response{
+				StatusCode: resp.StatusCode,
+				Body:       io.NopCloser(bytes.NewReader(body)),
+				Header:     http.Header{},
+			}, nil
+		}
+
+		// cached traces are not combined by the sharder
+		if maxBytes > 0 && len(body) > maxBytes {
+			return &http.Response{
+				StatusCode: http.StatusRequestEntityTooLarge,
+				Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("trace exceeds the maximum of %d bytes", maxBytes))),
+				Header:     http.Header{},
+			}, nil
+		}
+
+		t := &tempopb.Trace{}
+		err = proto.Unmarshal(body, t)
+		if err != nil {
+			return nil, err
+		}
+
+		summary := &traceSummaryResponse{
+			Summary: trace.Summarize(t),
+		}
+		span.LogFields(ot_log.Int("spans", summary.SpanCount))
+
+		if prune > 0 {
+			var buf bytes.Buffer
+			marshaller := &jsonpb.Marshaler{}
+			err = marshaller.Marshal(&buf, trace.Prune(t, prune, pruneSpans))
+			if err != nil {
+				return nil, err
+			}
+			summary.Trace = buf.Bytes()
+		}
+
+		b, err := json.Marshal(summary)
+		if err != nil {
+			return nil, err
+		}
+
+		return &http.Response{
+			StatusCode: resp.StatusCode,
+			Header: http.Header{
+				api.HeaderContentType: {api.HeaderAcceptJSON},
+			},
+			Body:          io.NopCloser(bytes.NewReader(b)),
+			ContentLength: int64(len(b)),
+		}, nil
+	})
+}
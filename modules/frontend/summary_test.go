@@ -0,0 +1,129 @@
+package frontend
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestTraceSummary(t *testing.T) {
+	tr := test.MakeTrace(2, []byte{0x01})
+
+	tests := []struct {
+		name           string
+		url            string
+		status         int
+		maxBytes       int
+		expectedStatus int
+		expectedBody   string
+		expectedQuery  string
+		expectedTrace  bool
+	}{
+		{
+			name:           "summarized",
+			url:            "/api/traces/1/summary",
+			status:         200,
+			expectedStatus: 200,
+		},
+		{
+			name:           "time range hints are passed on",
+			url:            "/api/traces/1/summary?start=10&end=20&prune=1",
+			status:         200,
+			expectedStatus: 200,
+			expectedQuery:  "end=20&start=10",
+			expectedTrace:  true,
+		},
+		{
+			name:           "partial",
+			url:            "/api/traces/1/summary",
+			status:         206,
+			expectedStatus: 206,
+		},
+		{
+			name:           "not found",
+			url:            "/api/traces/1/summary",
+			status:         404,
+			expectedStatus: 404,
+			expectedBody:   "error occurred",
+		},
+		{
+			name:           "too large",
+			url:            "/api/traces/1/summary",
+			status:         200,
+			maxBytes:       10,
+			expectedStatus: 413,
+			expectedBody:   "trace exceeds the maximum of 10 bytes",
+		},
+		{
+			name:           "bad request",
+			url:            "/api/traces/1/summary?prune=foo",
+			expectedStatus: 400,
+			expectedBody:   "invalid prune: strconv.Atoi: parsing \"foo\": invalid syntax",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			traces := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				// the trace is fetched through the trace by ID path
+				assert.Equal(t, "/api/traces/1", r.URL.Path)
+				assert.Equal(t, tc.expectedQuery, r.URL.RawQuery)
+				assert.Equal(t, api.HeaderAcceptProtobuf, r.Header.Get(api.HeaderAccept))
+				// the sharder stops combining the trace once it exceeds the limit
+				assert.Equal(t, tc.maxBytes, maxTraceBytesFromContext(r.Context()))
+
+				body := []byte("error occurred")
+				if tc.status/100 == 2 {
+					var err error
+					body, err = proto.Marshal(tr)
+					require.NoError(t, err)
+				}
+
+				return &http.Response{
+					StatusCode: tc.status,
+					Body:       io.NopCloser(bytes.NewReader(body)),
+					Header:     http.Header{},
+				}, nil
+			})
+
+			rt := newTraceSummaryRoundTripper(traces, &mockLimits{maxBytesPerTrace: tc.maxBytes}, log.NewNopLogger())
+
+			req := httptest.NewRequest("GET", tc.url, nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), "test"))
+			req = mux.SetURLVars(req, map[string]string{api.URLParamTraceID: "1"})
+			resp, err := rt.RoundTrip(req)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			if tc.expectedBody != "" {
+				assert.Equal(t, tc.expectedBody, strings.TrimSpace(string(body)))
+				return
+			}
+
+			actual := struct {
+				*trace.Summary
+				Trace json.RawMessage `json:"trace"`
+			}{}
+			require.NoError(t, json.Unmarshal(body, &actual))
+			assert.Equal(t, trace.Summarize(tr), actual.Summary)
+			assert.Equal(t, tc.expectedTrace, len(actual.Trace) > 0)
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/grafana/tempo/modules/querier"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/querystats"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/opentracing/opentracing-go"
 	"github.com/weaveworks/common/user"
@@ -27,6 +28,20 @@ const (
 	maxQueryShards = 256
 )
 
+type maxTraceBytesContextKey struct{}
+
+// contextWithMaxTraceBytes returns a context limiting the size of the trace combined by the trace by ID sharder to
+// maxBytes, 0 for no limit. The sharder stops combining shards once their responses exceed the limit and responds
+// with 413.
+func contextWithMaxTraceBytes(ctx context.Context, maxBytes int) context.Context {
+	return context.WithValue(ctx, maxTraceBytesContextKey{}, maxBytes)
+}
+
+func maxTraceBytesFromContext(ctx context.Context) int {
+	maxBytes, _ := ctx.Value(maxTraceBytesContextKey{}).(int)
+	return maxBytes
+}
+
 func newTraceByIDSharder(queryShards, maxFailedBlocks int, logger log.Logger) Middleware {
 	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
 		return shardQuery{
@@ -66,6 +81,9 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 
 	var overallError error
 	var totalFailedBlocks uint32
+	var totalStats *tempopb.QueryStats
+	maxBytes := maxTraceBytesFromContext(ctx)
+	responseBytes := 0
 	combiner := trace.NewCombiner()
 	combiner.Consume(&tempopb.Trace{}) // The query path returns a non-nil result even if no inputs (which is different than other paths which return nil for no inputs)
 	statusCode := http.StatusNotFound
@@ -84,7 +102,7 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 				overallError = err
 			}
 
-			if shouldQuit(r.Context(), statusCode, overallError) {
+			if shouldQuit(r.Context(), statusCode, overallError) || statusCode == http.StatusRequestEntityTooLarge {
 				return
 			}
 
@@ -115,6 +133,14 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 				return
 			}
 
+			// the size of the responses bounds the size of the combined trace
+			responseBytes += len(buff)
+			if maxBytes > 0 && responseBytes > maxBytes {
+				statusCode = http.StatusRequestEntityTooLarge
+				statusMsg = fmt.Sprintf("trace exceeds the maximum of %d bytes", maxBytes)
+				return
+			}
+
 			// marshal into a trace to combine.
 			// todo: better define responsibilities between middleware. the parent middleware in frontend.go actually sets the header
 			//  which forces the body here to be a proto encoded tempopb.Trace{}
@@ -127,6 +153,11 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 			}
 
 			if traceResp.Metrics != nil {
+				totalStats = querystats.Merge(totalStats, traceResp.Metrics.Stats)
+				if query := activeQueryFromContext(r.Context()); query != nil {
+					query.addStats(traceResp.Metrics.Stats)
+				}
+
 				totalFailedBlocks += traceResp.Metrics.FailedBlocks
 				if totalFailedBlocks > s.maxFailedBlocks {
 					overallError = fmt.Errorf("too many failed block queries %d (max %d)", totalFailedBlocks, s.maxFailedBlocks)
@@ -155,7 +186,7 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 		// translate non-404s into 500s. if, for instance, we get a 400 back from an internal component
 		// it means that we created a bad request. 400 should not be propagated back to the user b/c
 		// the bad request was due to a bug on our side, so return 500 instead.
-		if statusCode != http.StatusNotFound {
+		if statusCode != http.StatusNotFound && statusCode != http.StatusRequestEntityTooLarge {
 			statusCode = 500
 		}
 
@@ -170,6 +201,7 @@ func (s shardQuery) RoundTrip(r *http.Request) (*http.Response, error) {
 		Trace: overallTrace,
 		Metrics: &tempopb.TraceByIDMetrics{
 			FailedBlocks: totalFailedBlocks,
+			Stats:        totalStats,
 		},
 	})
 	if err != nil {
@@ -75,6 +75,15 @@ func TestBuildShardedRequests(t *testing.T) {
 
 	require.Equal(t, "/querier?mode=ingesters", shardedReqs[0].RequestURI)
 	require.Equal(t, "/querier?blockEnd=ffffffffffffffffffffffffffffffff&blockStart=00000000000000000000000000000000&mode=blocks", shardedReqs[1].RequestURI)
+
+	// time range hints are passed on to the queriers
+	req = httptest.NewRequest("GET", "/?start=10&end=20", nil).WithContext(ctx)
+
+	shardedReqs, err = sharder.buildShardedRequests(req)
+	require.NoError(t, err)
+	require.Len(t, shardedReqs, queryShards)
+
+	require.Equal(t, "/querier?blockEnd=ffffffffffffffffffffffffffffffff&blockStart=00000000000000000000000000000000&end=20&mode=blocks&start=10", shardedReqs[1].RequestURI)
 }
 
 func TestShardingWareDoRequest(t *testing.T) {
@@ -101,6 +110,7 @@ func TestShardingWareDoRequest(t *testing.T) {
 		err2                error
 		failedBlockQueries1 int
 		failedBlockQueries2 int
+		maxBytes            int
 		expectedStatus      int
 		expectedTrace       *tempopb.Trace
 		expectedError       error
@@ -260,6 +270,25 @@ func TestShardingWareDoRequest(t *testing.T) {
 			failedBlockQueries2: 5,
 			expectedError:       errors.New("too many failed block queries 5 (max 2)"),
 		},
+		{
+			name:           "max bytes within: 200+200",
+			status1:        200,
+			trace1:         trace1,
+			status2:        200,
+			trace2:         trace2,
+			maxBytes:       1_000_000,
+			expectedStatus: 200,
+			expectedTrace:  splitTrace,
+		},
+		{
+			name:           "max bytes exceeded: 200+200",
+			status1:        200,
+			trace1:         trace1,
+			status2:        200,
+			trace2:         trace2,
+			maxBytes:       10,
+			expectedStatus: 413,
+		},
 	}
 
 	for _, tc := range tests {
@@ -318,6 +347,9 @@ func TestShardingWareDoRequest(t *testing.T) {
 			req := httptest.NewRequest("GET", "/api/traces/1234", nil)
 			ctx := req.Context()
 			ctx = user.InjectOrgID(ctx, "blerg")
+			if tc.maxBytes > 0 {
+				ctx = contextWithMaxTraceBytes(ctx, tc.maxBytes)
+			}
 			req = req.WithContext(ctx)
 
 			resp, err := testRT.RoundTrip(req)
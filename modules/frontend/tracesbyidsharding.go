@@ -0,0 +1,246 @@
+package frontend
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/golang/protobuf/proto"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/querystats"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/opentracing/opentracing-go"
+	"github.com/weaveworks/common/user"
+)
+
+func newTracesByIDSharder(queryShards, maxFailedBlocks int, limits Limits, logger log.Logger) Middleware {
+	return MiddlewareFunc(func(next http.RoundTripper) http.RoundTripper {
+		return shardTracesQuery{
+			shardQuery: shardQuery{
+				next:            next,
+				queryShards:     queryShards,
+				logger:          logger,
+				blockBoundaries: createBlockBoundaries(queryShards - 1), // one shard will be used to query ingesters
+				maxFailedBlocks: uint32(maxFailedBlocks),
+			},
+			limits: limits,
+		}
+	})
+}
+
+// shardTracesQuery shards a batch trace by ID request on the same block boundaries as a single trace
+// by ID request. Every shard carries all the trace IDs so each block is searched once for the batch.
+// The responses of the shards are combined in memory, the request fails once they exceed the maximum
+// bytes per batch query of the tenant.
+type shardTracesQuery struct {
+	shardQuery
+	limits Limits
+}
+
+// RoundTrip implements http.RoundTripper
+func (s shardTracesQuery) RoundTrip(r *http.Request) (*http.Response, error) {
+	ctx := r.Context()
+	span, ctx := opentracing.StartSpanFromContext(ctx, "frontend.ShardTracesQuery")
+	defer span.Finish()
+
+	// the body holding the trace IDs is read once and replayed for every shard
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	orgID, _ := user.ExtractOrgID(ctx)
+	maxBytes := s.limits.MaxBytesPerBatchQuery(orgID)
+
+	// context propagation
+	r = r.WithContext(ctx)
+	reqs, err := s.buildShardedRequests(r)
+	if err != nil {
+		return nil, err
+	}
+	for _, req := range reqs {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+		req.ContentLength = int64(len(body))
+	}
+
+	// execute requests
+	wg := sync.WaitGroup{}
+	mtx := sync.Mutex{}
+
+	var overallError error
+	var totalStats *tempopb.QueryStats
+	combiners := map[string]*trace.Combiner{}
+	failedBlocks := map[string]uint32{}
+	statusCode := http.StatusOK
+	statusMsg := ""
+	responseBytes := 0
+	tooLarge := false
+
+	for _, req := range reqs {
+		wg.Add(1)
+		go func(innerR *http.Request) {
+			defer wg.Done()
+
+			resp, err := s.next.RoundTrip(innerR)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				overallError = err
+			}
+
+			if tooLarge || shouldQuit(r.Context(), statusCode, overallError) {
+				return
+			}
+
+			// check http error
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error querying proxy target", "url", innerR.RequestURI, "err", err)
+				overallError = err
+				return
+			}
+
+			// if the status code is anything but happy, save the error and pass it down the line
+			if resp.StatusCode != http.StatusOK {
+				statusCode = resp.StatusCode
+				bytesMsg, err := io.ReadAll(resp.Body)
+				if err != nil {
+					_ = level.Error(s.logger).Log("msg", "error reading response body status != ok", "url", innerR.RequestURI, "err", err)
+				}
+				statusMsg = string(bytesMsg)
+				return
+			}
+
+			// read the body
+			buff, err := io.ReadAll(resp.Body)
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error reading response body status == ok", "url", innerR.RequestURI, "err", err)
+				overallError = err
+				return
+			}
+
+			// the size of the responses bounds the size of the combined traces
+			responseBytes += len(buff)
+			if maxBytes > 0 && responseBytes > maxBytes {
+				tooLarge = true
+				combiners = nil
+				return
+			}
+
+			tracesResp := &tempopb.TracesByIDResponse{}
+			err = proto.Unmarshal(buff, tracesResp)
+			if err != nil {
+				_ = level.Error(s.logger).Log("msg", "error unmarshalling response", "url", innerR.RequestURI, "err", err, "body", string(buff))
+				overallError = err
+				return
+			}
+
+			if tracesResp.Metrics != nil {
+				totalStats = querystats.Merge(totalStats, tracesResp.Metrics.Stats)
+				if query := activeQueryFromContext(r.Context()); query != nil {
+					query.addStats(tracesResp.Metrics.Stats)
+				}
+			}
+
+			for traceID, result := range tracesResp.Traces {
+				if result.FailedBlocks > 0 {
+					failedBlocks[traceID] += result.FailedBlocks
+					_ = level.Warn(s.logger).Log("msg", "failed to query blocks for trace", "traceID", traceID, "failedBlocks", result.FailedBlocks, "err", result.Error)
+				}
+				if result.Trace == nil {
+					continue
+				}
+
+				c, ok := combiners[traceID]
+				if !ok {
+					c = trace.NewCombiner()
+					combiners[traceID] = c
+				}
+				c.Consume(result.Trace)
+			}
+		}(req)
+	}
+	wg.Wait()
+
+	if overallError != nil {
+		return nil, overallError
+	}
+
+	if tooLarge {
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("the traces of the batch exceed the maximum of %d bytes, request fewer traceIDs", maxBytes))),
+			Header:     http.Header{},
+		}, nil
+	}
+
+	if statusCode != http.StatusOK {
+		// translate non-500s into 500s. if, for instance, we get a 400 back from an internal component
+		// it means that we created a bad request. 400 should not be propagated back to the user b/c
+		// the bad request was due to a bug on our side, so return 500 instead.
+		return &http.Response{
+			StatusCode: http.StatusInternalServerError,
+			Body:       io.NopCloser(strings.NewReader(statusMsg)),
+			Header:     http.Header{},
+		}, nil
+	}
+
+	buff, err := proto.Marshal(s.combineResults(combiners, failedBlocks, totalStats))
+	if err != nil {
+		_ = level.Error(s.logger).Log("msg", "error marshalling response to proto", "err", err)
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header: http.Header{
+			api.HeaderContentType: {api.HeaderAcceptProtobuf},
+		},
+		Body:          io.NopCloser(bytes.NewReader(buff)),
+		ContentLength: int64(len(buff)),
+	}, nil
+}
+
+// combineResults builds the response of the batch from the traces combined across the shards. A trace
+// whose lookup failed in more blocks than tolerated is reported as an error instead of a partial trace.
+func (s shardTracesQuery) combineResults(combiners map[string]*trace.Combiner, failedBlocks map[string]uint32, stats *tempopb.QueryStats) *tempopb.TracesByIDResponse {
+	resp := &tempopb.TracesByIDResponse{
+		Traces: make(map[string]*tempopb.TraceByIDResult, len(combiners)),
+		Metrics: &tempopb.TraceByIDMetrics{
+			Stats: stats,
+		},
+	}
+
+	for traceID, c := range combiners {
+		t, _ := c.Result()
+		resp.Traces[traceID] = &tempopb.TraceByIDResult{
+			Trace: t,
+		}
+	}
+
+	for traceID, failed := range failedBlocks {
+		result, ok := resp.Traces[traceID]
+		if !ok {
+			result = &tempopb.TraceByIDResult{}
+			resp.Traces[traceID] = result
+		}
+
+		result.FailedBlocks = failed
+		if failed > s.maxFailedBlocks {
+			result.Trace = nil
+			result.Error = fmt.Sprintf("too many failed block queries %d (max %d)", failed, s.maxFailedBlocks)
+		}
+		resp.Metrics.FailedBlocks += failed
+	}
+
+	return resp
+}
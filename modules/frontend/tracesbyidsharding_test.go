@@ -0,0 +1,291 @@
+package frontend
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/scheduler/queue"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+)
+
+func TestShardingTracesDoRequest(t *testing.T) {
+	const body = `{"traceIDs": ["1", "2"]}`
+
+	// create and split a splitTrace
+	splitTrace := test.MakeTrace(10, []byte{0x01})
+	trace1 := &tempopb.Trace{}
+	trace2 := &tempopb.Trace{}
+
+	for _, b := range splitTrace.Batches {
+		if rand.Int()%2 == 0 {
+			trace1.Batches = append(trace1.Batches, b)
+		} else {
+			trace2.Batches = append(trace2.Batches, b)
+		}
+	}
+	otherTrace := test.MakeTrace(5, []byte{0x02})
+
+	tests := []struct {
+		name           string
+		status1        int
+		status2        int
+		resp1          map[string]*tempopb.TraceByIDResult
+		resp2          map[string]*tempopb.TraceByIDResult
+		err1           error
+		err2           error
+		maxBytes       int
+		expectedStatus int
+		expected       map[string]*tempopb.TraceByIDResult
+		expectedError  error
+	}{
+		{
+			name:           "empty returns",
+			status1:        200,
+			status2:        200,
+			expectedStatus: 200,
+			expected:       map[string]*tempopb.TraceByIDResult{},
+		},
+		{
+			name:    "traces combined across shards",
+			status1: 200,
+			status2: 200,
+			resp1: map[string]*tempopb.TraceByIDResult{
+				"1": {Trace: trace1},
+			},
+			resp2: map[string]*tempopb.TraceByIDResult{
+				"1": {Trace: trace2},
+				"2": {Trace: otherTrace},
+			},
+			expectedStatus: 200,
+			expected: map[string]*tempopb.TraceByIDResult{
+				"1": {Trace: splitTrace},
+				"2": {Trace: otherTrace},
+			},
+		},
+		{
+			name:    "failed blocks tolerated",
+			status1: 200,
+			status2: 200,
+			resp1: map[string]*tempopb.TraceByIDResult{
+				"1": {Trace: trace1},
+			},
+			resp2: map[string]*tempopb.TraceByIDResult{
+				"1": {Trace: trace2, FailedBlocks: 2, Error: "blerg"},
+			},
+			expectedStatus: 200,
+			expected: map[string]*tempopb.TraceByIDResult{
+				"1": {Trace: splitTrace, FailedBlocks: 2},
+			},
+		},
+		{
+			name:    "too many failed blocks",
+			status1: 200,
+			status2: 200,
+			resp1: map[string]*tempopb.TraceByIDResult{
+				"1": {Trace: trace1},
+				"2": {Trace: otherTrace},
+			},
+			resp2: map[string]*tempopb.TraceByIDResult{
+				"1": {FailedBlocks: 3, Error: "blerg"},
+			},
+			expectedStatus: 200,
+			expected: map[string]*tempopb.TraceByIDResult{
+				"1": {FailedBlocks: 3, Error: "too many failed block queries 3 (max 2)"},
+				"2": {Trace: otherTrace},
+			},
+		},
+		{
+			name:    "response too large",
+			status1: 200,
+			status2: 200,
+			resp1: map[string]*tempopb.TraceByIDResult{
+				"1": {Trace: trace1},
+			},
+			resp2: map[string]*tempopb.TraceByIDResult{
+				"1": {Trace: trace2},
+				"2": {Trace: otherTrace},
+			},
+			maxBytes:       10,
+			expectedStatus: 400,
+		},
+		{
+			name:           "400",
+			status1:        200,
+			status2:        400,
+			expectedStatus: 500,
+		},
+		{
+			name:           "500",
+			status1:        500,
+			status2:        200,
+			expectedStatus: 500,
+		},
+		{
+			name:          "error",
+			status1:       200,
+			err2:          errors.New("booo"),
+			expectedError: errors.New("booo"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sharder := newTracesByIDSharder(2, 2, &mockLimits{maxBytesPerBatchQuery: tc.maxBytes}, log.NewNopLogger())
+
+			next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				// every shard carries all the trace IDs
+				reqBody, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				require.Equal(t, body, string(reqBody))
+
+				var traces map[string]*tempopb.TraceByIDResult
+				var statusCode int
+				if r.RequestURI == "/querier/api/traces?mode=ingesters" {
+					traces = tc.resp1
+					statusCode = tc.status1
+					err = tc.err1
+				} else {
+					traces = tc.resp2
+					statusCode = tc.status2
+					err = tc.err2
+				}
+
+				if err != nil {
+					return nil, err
+				}
+
+				resBytes := []byte("error occurred")
+				if statusCode == 200 {
+					resBytes, err = proto.Marshal(&tempopb.TracesByIDResponse{
+						Traces:  traces,
+						Metrics: &tempopb.TraceByIDMetrics{},
+					})
+					require.NoError(t, err)
+				}
+
+				return &http.Response{
+					Body:       io.NopCloser(bytes.NewReader(resBytes)),
+					StatusCode: statusCode,
+				}, nil
+			})
+
+			testRT := NewRoundTripper(next, sharder)
+
+			req := httptest.NewRequest("POST", "/api/traces", strings.NewReader(body))
+			ctx := req.Context()
+			ctx = user.InjectOrgID(ctx, "blerg")
+			req = req.WithContext(ctx)
+
+			resp, err := testRT.RoundTrip(req)
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			assert.Equal(t, "application/protobuf", resp.Header.Get("Content-Type"))
+			actualResp := &tempopb.TracesByIDResponse{}
+			bytesResp, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			err = proto.Unmarshal(bytesResp, actualResp)
+			require.NoError(t, err)
+
+			require.Len(t, actualResp.Traces, len(tc.expected))
+			for traceID, expected := range tc.expected {
+				actual := actualResp.Traces[traceID]
+				require.NotNil(t, actual, traceID)
+				assert.Equal(t, expected.FailedBlocks, actual.FailedBlocks)
+				assert.Equal(t, expected.Error, actual.Error)
+
+				if expected.Trace == nil {
+					assert.Nil(t, actual.Trace)
+					continue
+				}
+				trace.SortTrace(expected.Trace)
+				trace.SortTrace(actual.Trace)
+				assert.True(t, proto.Equal(expected.Trace, actual.Trace))
+			}
+		})
+	}
+}
+
+func TestTracesByIDMiddleware(t *testing.T) {
+	cfg := Config{
+		QueryShards: 2,
+		Search: SearchConfig{
+			Sharder: SearchSharderConfig{
+				TargetBytesPerRequest: 100,
+			},
+		},
+	}
+
+	var mtx sync.Mutex
+	var costs []int64
+	next := RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		mtx.Lock()
+		costs = append(costs, queue.CostFromContext(r.Context()))
+		mtx.Unlock()
+
+		resBytes, err := proto.Marshal(&tempopb.TracesByIDResponse{Metrics: &tempopb.TraceByIDMetrics{}})
+		require.NoError(t, err)
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+			Header:     http.Header{},
+		}, nil
+	})
+	rt := newTracesByIDMiddleware(cfg, &mockLimits{maxTraceIDsPerBatch: 3}, log.NewNopLogger()).Wrap(next)
+
+	// every shard costs as many trace by ID requests as there are trace IDs
+	req := httptest.NewRequest("POST", "/api/traces", strings.NewReader(`{"traceIDs": ["1", "2", "3"]}`))
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []int64{300, 300}, costs)
+
+	// batches are limited per tenant
+	costs = nil
+	req = httptest.NewRequest("POST", "/api/traces", strings.NewReader(`{"traceIDs": ["1", "2", "3", "4"]}`))
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Empty(t, costs)
+}
+
+type mockLimits struct {
+	maxTraceIDsPerBatch   int
+	maxBytesPerBatchQuery int
+	maxBytesPerTrace      int
+}
+
+func (m *mockLimits) MaxTraceIDsPerBatch(string) int {
+	return m.maxTraceIDsPerBatch
+}
+
+func (m *mockLimits) MaxBytesPerBatchQuery(string) int {
+	return m.maxBytesPerBatchQuery
+}
+
+func (m *mockLimits) MaxBytesPerTrace(string) int {
+	return m.maxBytesPerTrace
+}
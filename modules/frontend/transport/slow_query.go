@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	util_log "github.com/grafana/tempo/pkg/util/log"
+)
+
+// SlowQueryLogger logs the queries slower than a threshold along with the statistics of the work
+// done to execute them.
+type SlowQueryLogger struct {
+	threshold time.Duration
+	log       log.Logger
+}
+
+// NewSlowQueryLogger returns a SlowQueryLogger logging queries slower than threshold. A threshold
+// of 0 disables logging, a negative threshold logs all queries.
+func NewSlowQueryLogger(threshold time.Duration, log log.Logger) *SlowQueryLogger {
+	return &SlowQueryLogger{
+		threshold: threshold,
+		log:       log,
+	}
+}
+
+// Log logs the query if it took longer than the threshold.
+func (l *SlowQueryLogger) Log(r *http.Request, tenant, queryID string, status int, queryResponseTime time.Duration, stats *tempopb.QueryStats) {
+	if l.threshold == 0 || queryResponseTime <= l.threshold {
+		return
+	}
+
+	if stats == nil {
+		stats = &tempopb.QueryStats{}
+	}
+
+	level.Info(util_log.WithContext(r.Context(), l.log)).Log(
+		"msg", "slow query detected",
+		"tenant", tenant,
+		"queryID", queryID,
+		"method", r.Method,
+		"path", r.URL.Path,
+		"query", r.URL.RawQuery,
+		"status", status,
+		"time_taken", queryResponseTime.String(),
+		"blocks_fetched", stats.BlocksFetched,
+		"bytes_read", stats.BytesRead,
+		"cache_hits", stats.CacheHits,
+		"bloom_tests", stats.BloomTests,
+		"pages_decoded", stats.PagesDecoded,
+		"io_time", time.Duration(stats.IoDurationNanos).String(),
+		"decode_time", time.Duration(stats.DecodeDurationNanos).String(),
+	)
+}
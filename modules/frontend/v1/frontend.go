@@ -44,6 +44,8 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 type Limits interface {
 	// Returns max queriers to use per tenant, or 0 if shuffle sharding is disabled.
 	MaxQueriersPerUser(user string) int
+	// Returns the max estimated bytes of requests of the tenant executed at the same time, or 0 if unlimited.
+	MaxConcurrentQueryBytes(user string) int
 }
 
 // Frontend queues HTTP requests, dispatches them to backends, and handles retries
@@ -74,6 +76,9 @@ type request struct {
 	queueSpan   opentracing.Span
 	originalCtx context.Context
 
+	userID string
+	cost   int64
+
 	request  *httpgrpc.HTTPRequest
 	err      chan error
 	response chan *httpgrpc.HTTPResponse
@@ -232,6 +237,7 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 		  it's possible that it's own queue would perpetually contain only expired requests.
 		*/
 		if req.originalCtx.Err() != nil {
+			f.requestQueue.FinishRequest(req.userID, req.cost)
 			lastUserIndex = lastUserIndex.ReuseLastUser()
 			continue
 		}
@@ -265,16 +271,20 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 		// downstream req.  Only way we can do that is to close the stream.
 		// The worker client is expecting this semantics.
 		case <-req.originalCtx.Done():
+			f.requestQueue.FinishRequest(req.userID, req.cost)
 			return req.originalCtx.Err()
 
 		// Is there was an error handling this request due to network IO,
 		// then error out this upstream request _and_ stream.
 		case err := <-errs:
+			f.requestQueue.FinishRequest(req.userID, req.cost)
 			req.err <- err
 			return err
 
 		// Happy path: merge the stats and propagate the response.
 		case resp := <-resps:
+			f.requestQueue.FinishRequest(req.userID, req.cost)
+
 			if stats.ShouldTrackHTTPGRPCResponse(resp.HttpResponse) {
 				stats := stats.FromContext(req.originalCtx)
 				stats.Merge(resp.Stats) // Safe if stats is nil.
@@ -325,13 +335,18 @@ func (f *Frontend) queueRequest(ctx context.Context, req *request) error {
 	req.enqueueTime = now
 	req.queueSpan, _ = opentracing.StartSpanFromContext(ctx, "queued")
 
-	// aggregate the max queriers limit in the case of a multi tenant query
+	// aggregate the max queriers and cost limits in the case of a multi tenant query
 	maxQueriers := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, f.limits.MaxQueriersPerUser)
+	maxConcurrentCost := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, f.limits.MaxConcurrentQueryBytes)
 
 	joinedTenantID := tenant.JoinTenantIDs(tenantIDs)
 	f.activeUsers.UpdateUserTimestamp(joinedTenantID, now)
 
-	err = f.requestQueue.EnqueueRequest(joinedTenantID, req, maxQueriers, nil)
+	// the cost is estimated by the middlewares creating the request, in bytes to scan
+	req.userID = joinedTenantID
+	req.cost = queue.CostFromContext(ctx)
+
+	err = f.requestQueue.EnqueueRequest(joinedTenantID, req, req.cost, maxQueriers, int64(maxConcurrentCost), nil)
 	if err == queue.ErrTooManyRequests {
 		return errTooManyRequest
 	}
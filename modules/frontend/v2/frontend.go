@@ -24,6 +24,7 @@ import (
 
 	"github.com/grafana/tempo/modules/frontend/v2/frontendv2pb"
 	"github.com/grafana/tempo/modules/querier/stats"
+	"github.com/grafana/tempo/pkg/scheduler/queue"
 	"github.com/grafana/tempo/pkg/util/httpgrpcutil"
 )
 
@@ -77,6 +78,8 @@ type frontendRequest struct {
 	request      *httpgrpc.HTTPRequest
 	userID       string
 	statsEnabled bool
+	// estimated cost of the request, in bytes to scan
+	cost int64
 
 	cancel context.CancelFunc
 
@@ -176,6 +179,7 @@ func (f *Frontend) RoundTripGRPC(ctx context.Context, req *httpgrpc.HTTPRequest)
 		request:      req,
 		userID:       userID,
 		statsEnabled: stats.IsEnabled(ctx),
+		cost:         queue.CostFromContext(ctx),
 
 		cancel: cancel,
 
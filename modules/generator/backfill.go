@@ -0,0 +1,552 @@
+package generator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	prometheus_storage "github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb"
+	tsdb_errors "github.com/prometheus/prometheus/tsdb/errors"
+
+	"github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	tempo_util "github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	BackfillStatusRunning   = "running"
+	BackfillStatusCompleted = "completed"
+	BackfillStatusFailed    = "failed"
+)
+
+var (
+	errBackfillDisabled = errors.New("backfill is not enabled")
+	errBackfillRunning  = errors.New("a backfill is already running for this tenant")
+)
+
+// BackfillJob is the state of a backfill of a tenant.
+type BackfillJob struct {
+	ID       string    `json:"id"`
+	Start    time.Time `json:"start"`
+	End      time.Time `json:"end"`
+	Status   string    `json:"status"`
+	Error    string    `json:"error,omitempty"`
+	Spans    uint64    `json:"spans"`
+	Samples  uint64    `json:"samples"`
+	Progress time.Time `json:"progress"`
+	// Blocks are the IDs of the TSDB blocks written.
+	Blocks []string `json:"blocks"`
+	// SkippedProcessors are enabled processors which can't be backfilled.
+	SkippedProcessors []string `json:"skippedProcessors,omitempty"`
+}
+
+// backfiller runs the backfills of all tenants. A tenant can only run one backfill at the time.
+type backfiller struct {
+	cfg       *Config
+	overrides metricsGeneratorOverrides
+	store     tempodb.Reader
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mtx  sync.Mutex
+	jobs map[string]*BackfillJob
+
+	logger log.Logger
+}
+
+func newBackfiller(cfg *Config, overrides metricsGeneratorOverrides, store tempodb.Reader, logger log.Logger) *backfiller {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &backfiller{
+		cfg:       cfg,
+		overrides: overrides,
+		store:     store,
+		ctx:       ctx,
+		cancel:    cancel,
+		jobs:      make(map[string]*BackfillJob),
+		logger:    log.With(logger, "component", "backfill"),
+	}
+}
+
+// start starts a backfill of the tenant between start and end in the background.
+func (b *backfiller) start(tenant string, start, end time.Time) (BackfillJob, error) {
+	if !b.cfg.Backfill.Enabled || b.store == nil {
+		return BackfillJob{}, errBackfillDisabled
+	}
+	if !end.After(start) {
+		return BackfillJob{}, errors.New("end must be after start")
+	}
+	if end.Sub(start) > b.cfg.Backfill.MaxDuration {
+		return BackfillJob{}, fmt.Errorf("range must not be longer than %s", b.cfg.Backfill.MaxDuration)
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if job, ok := b.jobs[tenant]; ok && job.Status == BackfillStatusRunning {
+		return BackfillJob{}, errBackfillRunning
+	}
+
+	job := &BackfillJob{
+		ID:       uuid.New().String(),
+		Start:    start,
+		End:      end,
+		Status:   BackfillStatusRunning,
+		Progress: start,
+		Blocks:   []string{},
+	}
+	b.jobs[tenant] = job
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+
+		logger := log.With(b.logger, "tenant", tenant, "job", job.ID)
+		level.Info(logger).Log("msg", "starting backfill", "start", start, "end", end)
+
+		err := b.run(b.ctx, tenant, job, logger)
+
+		b.mtx.Lock()
+		defer b.mtx.Unlock()
+		if err != nil {
+			level.Error(logger).Log("msg", "backfill failed", "err", err)
+			job.Status = BackfillStatusFailed
+			job.Error = err.Error()
+			return
+		}
+		level.Info(logger).Log("msg", "backfill completed", "spans", job.Spans, "blocks", len(job.Blocks))
+		job.Status = BackfillStatusCompleted
+	}()
+
+	return b.copyJob(job), nil
+}
+
+// get returns the last backfill of the tenant.
+func (b *backfiller) get(tenant string) (BackfillJob, bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	job, ok := b.jobs[tenant]
+	if !ok {
+		return BackfillJob{}, false
+	}
+	return b.copyJob(job), true
+}
+
+// copyJob returns a copy of the job. Must be called under lock.
+func (b *backfiller) copyJob(job *BackfillJob) BackfillJob {
+	c := *job
+	c.Blocks = append([]string{}, job.Blocks...)
+	c.SkippedProcessors = append([]string(nil), job.SkippedProcessors...)
+	return c
+}
+
+// stop cancels the running backfills and waits for them to return.
+func (b *backfiller) stop() {
+	b.cancel()
+	b.wg.Wait()
+}
+
+// run pushes the spans of the tenant through the processors enabled for the tenant. Spans are read per
+// block duration, every block duration results in one TSDB block. Traces are streamed from the blocks, the
+// spans of a trace are pushed to the processors of the collection interval they ended in. At the end of
+// the block duration the series of every interval are added to the series of the previous ones and
+// written with the timestamp of the interval, so the counters keep increasing like those of a generator
+// collecting every interval.
+func (b *backfiller) run(ctx context.Context, tenant string, job *BackfillJob, logger log.Logger) error {
+	names, skipped := b.processorNames(tenant)
+	if len(skipped) > 0 {
+		level.Warn(logger).Log("msg", "skipping processors which can't be backfilled", "processors", strings.Join(skipped, ","))
+		b.mtx.Lock()
+		job.SkippedProcessors = skipped
+		b.mtx.Unlock()
+	}
+	if len(names) == 0 {
+		return errors.New("no processors which can be backfilled are enabled for this tenant")
+	}
+
+	interval := b.overrides.MetricsGeneratorCollectionInterval(tenant)
+	if interval == 0 {
+		interval = b.cfg.Registry.CollectionInterval
+	}
+	dir := filepath.Join(b.cfg.Backfill.Path, tenant)
+
+	// the sum of all intervals so far, it's carried over the block durations
+	acc := newBackfillAccumulator()
+
+	for windowStart := job.Start; windowStart.Before(job.End); windowStart = windowStart.Add(b.cfg.Backfill.BlockDuration) {
+		windowEnd := windowStart.Add(b.cfg.Backfill.BlockDuration)
+		if windowEnd.After(job.End) {
+			windowEnd = job.End
+		}
+
+		w := &backfillWindow{
+			start:     windowStart,
+			end:       windowEnd,
+			interval:  interval,
+			intervals: make([]*backfillInterval, (windowEnd.Sub(windowStart)+interval-1)/interval),
+		}
+
+		spans, err := b.processWindow(ctx, tenant, names, w, logger)
+		if err == nil {
+			var blockID string
+			var samples uint64
+			blockID, samples, err = b.writeBlock(ctx, dir, w, acc, logger)
+			if err == nil {
+				b.mtx.Lock()
+				job.Spans += spans
+				job.Samples += samples
+				job.Progress = windowEnd
+				if blockID != "" {
+					job.Blocks = append(job.Blocks, blockID)
+				}
+				b.mtx.Unlock()
+			}
+		}
+		w.close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// processorNames returns the processors enabled for the tenant which can be backfilled, and those
+// which can't.
+func (b *backfiller) processorNames(tenant string) (names []string, skipped []string) {
+	for name := range b.overrides.MetricsGeneratorProcessors(tenant) {
+		if _, ok := notBackfillableProcessors[name]; ok {
+			skipped = append(skipped, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sort.Strings(skipped)
+	return names, skipped
+}
+
+// notBackfillableProcessors are processors depending on the wall clock. The service graphs processor pairs
+// client and server spans as they arrive and expires unpaired edges after a wait, spans replayed from
+// the backend all arrive at once.
+var notBackfillableProcessors = map[string]struct{}{
+	servicegraphs.Name: {},
+}
+
+// newProcessors creates the processors with the current config of the tenant.
+func (b *backfiller) newProcessors(tenant string, names []string, reg registry.Registry, logger log.Logger) ([]processor.Processor, error) {
+	cfg := tenantProcessorConfig(b.cfg.Processor, b.overrides, tenant)
+
+	processors := make([]processor.Processor, 0, len(names))
+	for _, name := range names {
+		p, err := newProcessor(name, cfg, tenant, reg, logger)
+		if err != nil {
+			for _, p := range processors {
+				p.Shutdown(context.Background())
+			}
+			return nil, err
+		}
+		processors = append(processors, p)
+	}
+	return processors, nil
+}
+
+// backfillWindow is a block duration of a backfill, split into collection intervals.
+type backfillWindow struct {
+	start, end time.Time
+	interval   time.Duration
+	// intervals are created when the first span ending in them is pushed.
+	intervals []*backfillInterval
+}
+
+// backfillInterval holds the series of the spans which ended in a collection interval.
+type backfillInterval struct {
+	reg        *registry.ManagedRegistry
+	processors []processor.Processor
+}
+
+// collectionTime returns the time the interval i is collected at.
+func (w *backfillWindow) collectionTime(i int) time.Time {
+	t := w.start.Add(time.Duration(i+1) * w.interval)
+	if t.After(w.end) {
+		return w.end
+	}
+	return t
+}
+
+func (w *backfillWindow) close() {
+	for _, i := range w.intervals {
+		if i == nil {
+			continue
+		}
+		for _, p := range i.processors {
+			p.Shutdown(context.Background())
+		}
+		i.reg.Close()
+	}
+	w.intervals = nil
+}
+
+// processWindow streams the traces overlapping the window and pushes the spans which ended in the window to
+// the processors of their collection interval. Traces found in multiple blocks are combined, so their spans
+// are only pushed once. It returns the number of spans pushed.
+func (b *backfiller) processWindow(ctx context.Context, tenant string, names []string, w *backfillWindow, logger log.Logger) (uint64, error) {
+	startNanos, endNanos := uint64(w.start.UnixNano()), uint64(w.end.UnixNano())
+
+	// blocks are combined per data encoding, a trace stored with different encodings isn't deduplicated
+	metasByEncoding := map[string][]*backend.BlockMeta{}
+	var encodings []string
+	for _, meta := range b.store.BlockMetas(tenant) {
+		if !overlaps(meta, w.start, w.end) {
+			continue
+		}
+		if _, ok := metasByEncoding[meta.DataEncoding]; !ok {
+			encodings = append(encodings, meta.DataEncoding)
+		}
+		metasByEncoding[meta.DataEncoding] = append(metasByEncoding[meta.DataEncoding], meta)
+	}
+	sort.Strings(encodings)
+
+	var spans uint64
+	for _, encoding := range encodings {
+		decoder, err := model.NewObjectDecoder(encoding)
+		if err != nil {
+			return 0, err
+		}
+
+		var pushErr error
+		err = b.store.IterateObjects(ctx, metasByEncoding[encoding], tempodb.DefaultSearchChunkSizeBytes, func(id common.ID, obj []byte) bool {
+			// skip traces outside of the range without decoding them, the range is in seconds
+			if traceStart, traceEnd, err := decoder.FastRange(obj); err == nil {
+				if int64(traceEnd) < w.start.Unix() || int64(traceStart) > w.end.Unix() {
+					return true
+				}
+			}
+
+			trace, err := decoder.PrepareForRead(obj)
+			if err != nil {
+				pushErr = fmt.Errorf("error decoding trace %s: %w", tempo_util.TraceIDToHexString(id), err)
+				return false
+			}
+
+			// group the spans of the trace per collection interval
+			reqs := map[int]*tempopb.PushSpansRequest{}
+			for _, rs := range trace.Batches {
+				for _, ils := range rs.InstrumentationLibrarySpans {
+					for _, span := range ils.Spans {
+						if span.EndTimeUnixNano < startNanos || span.EndTimeUnixNano >= endNanos {
+							continue
+						}
+						i := int((span.EndTimeUnixNano - startNanos) / uint64(w.interval))
+						reqs[i] = appendSpan(reqs[i], rs, ils, span)
+						spans++
+					}
+				}
+			}
+
+			for i, req := range reqs {
+				if w.intervals[i] == nil {
+					reg := registry.NewBackfill(&b.cfg.Registry, b.overrides, tenant, logger)
+					processors, err := b.newProcessors(tenant, names, reg, logger)
+					if err != nil {
+						reg.Close()
+						pushErr = err
+						return false
+					}
+					w.intervals[i] = &backfillInterval{reg: reg, processors: processors}
+				}
+				for _, p := range w.intervals[i].processors {
+					p.PushSpans(ctx, req)
+				}
+			}
+
+			return ctx.Err() == nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		if pushErr != nil {
+			return 0, pushErr
+		}
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+	}
+
+	return spans, nil
+}
+
+// appendSpan adds the span to req, spans of the same resource and instrumentation library are grouped
+// if they are appended consecutively.
+func appendSpan(req *tempopb.PushSpansRequest, rs *v1_trace.ResourceSpans, ils *v1_trace.InstrumentationLibrarySpans, span *v1_trace.Span) *tempopb.PushSpansRequest {
+	if req == nil {
+		req = &tempopb.PushSpansRequest{}
+	}
+
+	if n := len(req.Batches); n > 0 {
+		last := req.Batches[n-1]
+		lastILS := last.InstrumentationLibrarySpans[0]
+		if last.Resource == rs.Resource && lastILS.InstrumentationLibrary == ils.InstrumentationLibrary {
+			lastILS.Spans = append(lastILS.Spans, span)
+			return req
+		}
+	}
+
+	req.Batches = append(req.Batches, &v1_trace.ResourceSpans{
+		Resource: rs.Resource,
+		InstrumentationLibrarySpans: []*v1_trace.InstrumentationLibrarySpans{{
+			InstrumentationLibrary: ils.InstrumentationLibrary,
+			Spans:                  []*v1_trace.Span{span},
+		}},
+	})
+	return req
+}
+
+func overlaps(meta *backend.BlockMeta, start, end time.Time) bool {
+	return !meta.StartTime.After(end) && !meta.EndTime.Before(start)
+}
+
+// writeBlock adds the series of every interval of the window to acc and writes the sum with the
+// timestamp of the interval. The samples are written in a TSDB block in dir, its ID is returned if any
+// sample was written.
+func (b *backfiller) writeBlock(ctx context.Context, dir string, w *backfillWindow, acc *backfillAccumulator, logger log.Logger) (string, uint64, error) {
+	bw, err := tsdb.NewBlockWriter(logger, dir, b.cfg.Backfill.BlockDuration.Milliseconds())
+	if err != nil {
+		return "", 0, err
+	}
+	defer bw.Close()
+
+	var samples uint64
+	for i := range w.intervals {
+		t := w.collectionTime(i)
+
+		if interval := w.intervals[i]; interval != nil {
+			err = interval.reg.CollectAt(acc, t)
+			if err != nil {
+				return "", 0, err
+			}
+		}
+
+		appender := &countingAppender{Appender: bw.Appender(ctx)}
+		err = acc.writeTo(appender, t.UnixMilli())
+		if err != nil {
+			return "", 0, err
+		}
+		samples += appender.samples
+
+		if ctx.Err() != nil {
+			return "", 0, ctx.Err()
+		}
+	}
+
+	if samples == 0 {
+		return "", 0, nil
+	}
+
+	id, err := bw.Flush(ctx)
+	if err != nil {
+		return "", 0, err
+	}
+	return id.String(), samples, nil
+}
+
+// backfillAccumulator is an appender summing the samples appended per series. The registries of the
+// intervals are collected into it, their series only hold the spans of their interval. This assumes the
+// processors only use counters and histograms, the value of a gauge would be summed as well.
+type backfillAccumulator struct {
+	series    map[uint64]*backfillSeries
+	exemplars []backfillExemplar
+}
+
+type backfillSeries struct {
+	labels labels.Labels
+	value  float64
+}
+
+type backfillExemplar struct {
+	labels   labels.Labels
+	exemplar exemplar.Exemplar
+}
+
+var _ prometheus_storage.Appender = (*backfillAccumulator)(nil)
+
+func newBackfillAccumulator() *backfillAccumulator {
+	return &backfillAccumulator{
+		series: make(map[uint64]*backfillSeries),
+	}
+}
+
+func (a *backfillAccumulator) Append(_ prometheus_storage.SeriesRef, l labels.Labels, _ int64, v float64) (prometheus_storage.SeriesRef, error) {
+	hash := l.Hash()
+	s, ok := a.series[hash]
+	if !ok {
+		s = &backfillSeries{labels: l.Copy()}
+		a.series[hash] = s
+	}
+	s.value += v
+	return 0, nil
+}
+
+func (a *backfillAccumulator) AppendExemplar(_ prometheus_storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (prometheus_storage.SeriesRef, error) {
+	a.exemplars = append(a.exemplars, backfillExemplar{labels: l.Copy(), exemplar: e})
+	return 0, nil
+}
+
+func (a *backfillAccumulator) Commit() error {
+	return nil
+}
+
+func (a *backfillAccumulator) Rollback() error {
+	return nil
+}
+
+// writeTo writes the sum of every series and the exemplars appended since the last call to appender.
+func (a *backfillAccumulator) writeTo(appender prometheus_storage.Appender, timeMs int64) error {
+	for _, s := range a.series {
+		_, err := appender.Append(0, s.labels, timeMs, s.value)
+		if err != nil {
+			return tsdb_errors.NewMulti(err, appender.Rollback()).Err()
+		}
+	}
+	for _, e := range a.exemplars {
+		e.exemplar.Ts = timeMs
+		_, err := appender.AppendExemplar(0, e.labels, e.exemplar)
+		if err != nil {
+			return tsdb_errors.NewMulti(err, appender.Rollback()).Err()
+		}
+	}
+	a.exemplars = nil
+	return appender.Commit()
+}
+
+// countingAppender counts the samples appended.
+type countingAppender struct {
+	prometheus_storage.Appender
+	samples uint64
+}
+
+func (a *countingAppender) Append(ref prometheus_storage.SeriesRef, l labels.Labels, t int64, v float64) (prometheus_storage.SeriesRef, error) {
+	a.samples++
+	return a.Appender.Append(ref, l, t, v)
+}
@@ -0,0 +1,264 @@
+package generator
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/blocklist"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestBackfill(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	trace := test.MakeTraceWithSpanCount(1, 3, nil)
+	i := 0
+	for _, ils := range trace.Batches[0].InstrumentationLibrarySpans {
+		for _, s := range ils.Spans {
+			s.StartTimeUnixNano = uint64(start.Add(time.Duration(i) * 20 * time.Second).UnixNano())
+			s.EndTimeUnixNano = s.StartTimeUnixNano + uint64(time.Second)
+			i++
+		}
+	}
+	// a trace outside of the range is ignored
+	outside := test.MakeTraceWithSpanCount(1, 1, nil)
+	outside.Batches[0].InstrumentationLibrarySpans[0].Spans[0].StartTimeUnixNano = uint64(start.Add(-time.Hour).UnixNano())
+	outside.Batches[0].InstrumentationLibrarySpans[0].Spans[0].EndTimeUnixNano = uint64(start.Add(-time.Hour).UnixNano())
+
+	// the trace is stored in two blocks and should only be counted once
+	store := &mockReader{}
+	store.addBlock(t, start, start.Add(time.Minute), trace, outside)
+	store.addBlock(t, start, start.Add(time.Minute), trace)
+
+	// traces are read in ID order, a trace ending earlier is still counted in an earlier interval
+	late := test.MakeTraceWithSpanCount(1, 1, []byte{0xff})
+	late.Batches[0].InstrumentationLibrarySpans[0].Spans[0].StartTimeUnixNano = uint64(start.Add(2 * time.Hour).UnixNano())
+	late.Batches[0].InstrumentationLibrarySpans[0].Spans[0].EndTimeUnixNano = uint64(start.Add(2*time.Hour + time.Second).UnixNano())
+	early := test.MakeTraceWithSpanCount(1, 1, []byte{0x00})
+	early.Batches[0].InstrumentationLibrarySpans[0].Spans[0].StartTimeUnixNano = uint64(start.Add(time.Hour).UnixNano())
+	early.Batches[0].InstrumentationLibrarySpans[0].Spans[0].EndTimeUnixNano = uint64(start.Add(time.Hour + time.Second).UnixNano())
+	store.addBlock(t, start.Add(time.Hour), start.Add(3*time.Hour), late, early)
+
+	cfg := newBackfillConfig()
+	cfg.Backfill.Enabled = true
+	cfg.Backfill.Path = t.TempDir()
+
+	overrides := &mockOverrides{processors: map[string]struct{}{spanmetrics.Name: {}, servicegraphs.Name: {}}}
+
+	b := newBackfiller(cfg, overrides, store, log.NewNopLogger())
+	defer b.stop()
+
+	job, err := b.start("test", start, start.Add(3*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, BackfillStatusRunning, job.Status)
+
+	_, err = b.start("test", start, start.Add(3*time.Hour))
+	assert.ErrorIs(t, err, errBackfillRunning)
+
+	require.Eventually(t, func() bool {
+		job, _ = b.get("test")
+		return job.Status != BackfillStatusRunning
+	}, 10*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, BackfillStatusCompleted, job.Status, job.Error)
+	assert.Equal(t, uint64(5), job.Spans)
+	// every block duration is written to its own block
+	assert.Len(t, job.Blocks, 2)
+	// service graphs depend on the wall clock
+	assert.Equal(t, []string{servicegraphs.Name}, job.SkippedProcessors)
+
+	db, err := tsdb.OpenDBReadOnly(cfg.Backfill.Path+"/test", log.NewNopLogger())
+	require.NoError(t, err)
+	defer db.Close()
+
+	q, err := db.Querier(context.Background(), start.UnixMilli(), start.Add(3*time.Hour).UnixMilli())
+	require.NoError(t, err)
+	defer q.Close()
+
+	// the calls are collected every 15s, the last span ends at 41s
+	samples := map[int64]float64{}
+	set := q.Select(true, nil, labels.MustNewMatcher(labels.MatchEqual, labels.MetricName, "traces_spanmetrics_calls_total"))
+	for set.Next() {
+		it := set.At().Iterator()
+		for it.Next() {
+			ts, v := it.At()
+			samples[ts] += v
+		}
+	}
+	require.NoError(t, set.Err())
+
+	assert.Equal(t, 1.0, samples[start.Add(15*time.Second).UnixMilli()])
+	assert.Equal(t, 2.0, samples[start.Add(30*time.Second).UnixMilli()])
+	assert.Equal(t, 3.0, samples[start.Add(45*time.Second).UnixMilli()])
+	assert.Equal(t, 3.0, samples[start.Add(time.Hour).UnixMilli()])
+	assert.Equal(t, 4.0, samples[start.Add(time.Hour+15*time.Second).UnixMilli()])
+	// the counters keep increasing in the next block
+	assert.Equal(t, 4.0, samples[start.Add(2*time.Hour).UnixMilli()])
+	assert.Equal(t, 5.0, samples[start.Add(2*time.Hour+15*time.Second).UnixMilli()])
+	assert.Equal(t, 5.0, samples[start.Add(3*time.Hour).UnixMilli()])
+}
+
+func TestBackfill_invalid(t *testing.T) {
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cfg := newBackfillConfig()
+
+	b := newBackfiller(cfg, &mockOverrides{}, &mockReader{}, log.NewNopLogger())
+	defer b.stop()
+
+	_, err := b.start("test", start, start.Add(time.Hour))
+	assert.ErrorIs(t, err, errBackfillDisabled)
+
+	cfg.Backfill.Enabled = true
+	cfg.Backfill.Path = t.TempDir()
+
+	_, err = b.start("test", start, start)
+	assert.Error(t, err)
+
+	_, err = b.start("test", start, start.Add(cfg.Backfill.MaxDuration+time.Second))
+	assert.Error(t, err)
+
+	// no processors are enabled
+	_, err = b.start("test", start, start.Add(time.Hour))
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		job, _ := b.get("test")
+		return job.Status == BackfillStatusFailed
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestGenerator_BackfillHandler(t *testing.T) {
+	cfg := newBackfillConfig()
+
+	g := &Generator{
+		backfiller: newBackfiller(cfg, &mockOverrides{}, nil, log.NewNopLogger()),
+	}
+	defer g.backfiller.stop()
+
+	tests := []struct {
+		name           string
+		method         string
+		url            string
+		expectedStatus int
+	}{
+		{name: "no backfill", method: "GET", url: "/metrics-generator/backfill", expectedStatus: http.StatusNotFound},
+		{name: "missing end", method: "POST", url: "/metrics-generator/backfill?start=100", expectedStatus: http.StatusBadRequest},
+		{name: "invalid start", method: "POST", url: "/metrics-generator/backfill?start=foo&end=200", expectedStatus: http.StatusBadRequest},
+		{name: "disabled", method: "POST", url: "/metrics-generator/backfill?start=100&end=200", expectedStatus: http.StatusNotImplemented},
+		{name: "invalid method", method: "DELETE", url: "/metrics-generator/backfill", expectedStatus: http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(tc.method, tc.url, nil)
+			r = r.WithContext(user.InjectOrgID(r.Context(), "test"))
+			w := httptest.NewRecorder()
+
+			g.BackfillHandler(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+		})
+	}
+}
+
+func newBackfillConfig() *Config {
+	cfg := &Config{}
+	cfg.Processor.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Registry.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Backfill.RegisterFlagsAndApplyDefaults("", nil)
+	return cfg
+}
+
+type mockReader struct {
+	metas   []*backend.BlockMeta
+	objects map[uuid.UUID]map[string][]byte
+}
+
+var _ tempodb.Reader = (*mockReader)(nil)
+
+func (m *mockReader) addBlock(t *testing.T, start, end time.Time, traces ...*tempopb.Trace) {
+	decoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	meta := backend.NewBlockMeta("test", uuid.New(), "v2", backend.EncNone, model.CurrentEncoding)
+	meta.StartTime = start
+	meta.EndTime = end
+
+	if m.objects == nil {
+		m.objects = map[uuid.UUID]map[string][]byte{}
+	}
+	m.objects[meta.BlockID] = map[string][]byte{}
+	for _, trace := range traces {
+		segment, err := decoder.PrepareForWrite(trace, uint32(start.Unix()), uint32(end.Unix()))
+		require.NoError(t, err)
+		obj, err := decoder.ToObject([][]byte{segment})
+		require.NoError(t, err)
+		traceID := trace.Batches[0].InstrumentationLibrarySpans[0].Spans[0].TraceId
+		m.objects[meta.BlockID][string(traceID)] = obj
+	}
+	m.metas = append(m.metas, meta)
+}
+
+func (m *mockReader) Find(context.Context, string, common.ID, string, string, int64, int64) ([]*tempopb.Trace, []error, error) {
+	return nil, nil, nil
+}
+
+func (m *mockReader) FindMany(context.Context, string, []common.ID, string, string, int64, int64) ([][]*tempopb.Trace, [][]error, error) {
+	return nil, nil, nil
+}
+
+func (m *mockReader) Search(context.Context, *backend.BlockMeta, *tempopb.SearchRequest, common.SearchOptions) (*tempopb.SearchResponse, error) {
+	return nil, nil
+}
+
+func (m *mockReader) IterateObjects(_ context.Context, metas []*backend.BlockMeta, _ uint32, callback tempodb.IterateObjectCallback) error {
+	objects := map[string][][]byte{}
+	for _, meta := range metas {
+		for id, obj := range m.objects[meta.BlockID] {
+			objects[id] = append(objects[id], obj)
+		}
+	}
+
+	ids := make([]string, 0, len(objects))
+	for id := range objects {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		obj, _, err := model.StaticCombiner.Combine(model.CurrentEncoding, objects[id]...)
+		if err != nil {
+			return err
+		}
+		if !callback(common.ID(id), obj) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *mockReader) BlockMetas(string) []*backend.BlockMeta {
+	return m.metas
+}
+
+func (m *mockReader) EnablePolling(blocklist.JobSharder) {}
+
+func (m *mockReader) Shutdown() {}
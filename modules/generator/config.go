@@ -2,8 +2,16 @@ package generator
 
 import (
 	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/tempo/modules/generator/processor"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spanevents"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
@@ -23,6 +31,7 @@ type Config struct {
 	Processor ProcessorConfig `yaml:"processor"`
 	Registry  registry.Config `yaml:"registry"`
 	Storage   storage.Config  `yaml:"storage"`
+	Backfill  BackfillConfig  `yaml:"backfill"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
@@ -31,14 +40,123 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Processor.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.Registry.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.Storage.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.Backfill.RegisterFlagsAndApplyDefaults(prefix, f)
+}
+
+// BackfillConfig configures backfilling metrics from the traces stored in the backend.
+type BackfillConfig struct {
+	// Enabled allows starting backfills, it requires access to the backend.
+	Enabled bool `yaml:"enabled"`
+	// Path to write the TSDB blocks. Each tenant will be stored in its own subdirectory.
+	Path string `yaml:"path"`
+	// BlockDuration is the time range of a single TSDB block. Spans are read and processed per block.
+	BlockDuration time.Duration `yaml:"block_duration"`
+	// MaxDuration is the longest time range that can be backfilled at once.
+	MaxDuration time.Duration `yaml:"max_duration"`
+}
+
+func (cfg *BackfillConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	cfg.BlockDuration = 2 * time.Hour
+	cfg.MaxDuration = 7 * 24 * time.Hour
 }
 
 type ProcessorConfig struct {
 	ServiceGraphs servicegraphs.Config `yaml:"service_graphs"`
 	SpanMetrics   spanmetrics.Config   `yaml:"span_metrics"`
+	SpanEvents    spanevents.Config    `yaml:"span_events"`
+
+	// Plugins holds the config of the processors registered with processor.Register, keyed by
+	// processor name. The config of a plugin is set in the processor block under its name with
+	// dashes replaced by underscores, like the built-in processors.
+	Plugins map[string]interface{} `yaml:"-"`
 }
 
 func (cfg *ProcessorConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.ServiceGraphs.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.SpanMetrics.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.SpanEvents.RegisterFlagsAndApplyDefaults(prefix, f)
+
+	cfg.Plugins = make(map[string]interface{})
+	for _, name := range processor.RegisteredFactories() {
+		factory, _ := processor.GetFactory(name)
+		cfg.Plugins[name] = factory.NewConfig()
+	}
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of the yaml pkg. The config of the built-in
+// processors and of the plugins is decoded strictly into their typed configs.
+func (cfg *ProcessorConfig) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw map[string]interface{}
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	plugins := make(map[string]string)
+	for _, name := range processor.RegisteredFactories() {
+		plugins[pluginConfigKey(name)] = name
+	}
+
+	builtIn := make(map[string]interface{}, len(raw))
+	for key, value := range raw {
+		name, ok := plugins[key]
+		if !ok {
+			builtIn[key] = value
+			continue
+		}
+
+		pluginCfg, ok := cfg.Plugins[name]
+		if !ok {
+			factory, _ := processor.GetFactory(name)
+			pluginCfg = factory.NewConfig()
+		}
+		if err := remarshal(value, pluginCfg); err != nil {
+			return fmt.Errorf("invalid config for processor %s: %w", name, err)
+		}
+		if cfg.Plugins == nil {
+			cfg.Plugins = make(map[string]interface{})
+		}
+		cfg.Plugins[name] = pluginCfg
+	}
+
+	type plain ProcessorConfig
+	return remarshal(builtIn, (*plain)(cfg))
+}
+
+// MarshalYAML implements the Marshaler interface of the yaml pkg. The config of the plugins is
+// added under their name next to the config of the built-in processors.
+func (cfg ProcessorConfig) MarshalYAML() (interface{}, error) {
+	type plain ProcessorConfig
+	b, err := yaml.Marshal(plain(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	out := yaml.MapSlice{}
+	if err := yaml.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(cfg.Plugins))
+	for name := range cfg.Plugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out = append(out, yaml.MapItem{Key: pluginConfigKey(name), Value: cfg.Plugins[name]})
+	}
+	return out, nil
+}
+
+// pluginConfigKey returns the key of the config of a plugin in the processor block.
+func pluginConfigKey(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// remarshal decodes in into out by marshalling it to YAML and unmarshalling it strictly.
+func remarshal(in interface{}, out interface{}) error {
+	b, err := yaml.Marshal(in)
+	if err != nil {
+		return err
+	}
+	return yaml.UnmarshalStrict(b, out)
 }
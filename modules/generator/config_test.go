@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+)
+
+func TestProcessorConfig_plugins(t *testing.T) {
+	cfg := ProcessorConfig{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+	in := `
+span_metrics:
+  dimensions: [team]
+test_plugin:
+  value: configured
+`
+	require.NoError(t, yaml.UnmarshalStrict([]byte(in), &cfg))
+
+	assert.Equal(t, []string{"team"}, cfg.SpanMetrics.Dimensions)
+	// defaults of the built-in processors are kept
+	assert.NotEmpty(t, cfg.SpanMetrics.HistogramBuckets)
+	assert.Equal(t, &testPluginConfig{Value: "configured"}, cfg.Plugins[testPluginName])
+
+	out, err := yaml.Marshal(cfg)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "test_plugin:\n  value: configured\n")
+
+	roundTrip := ProcessorConfig{}
+	require.NoError(t, yaml.UnmarshalStrict(out, &roundTrip))
+	assert.Equal(t, cfg.SpanMetrics, roundTrip.SpanMetrics)
+	assert.Equal(t, cfg.Plugins, roundTrip.Plugins)
+}
+
+func TestProcessorConfig_invalid(t *testing.T) {
+	testCases := []struct {
+		name string
+		in   string
+	}{
+		{name: "unknown processor", in: "span_metricsss: {}"},
+		{name: "unknown field of a built-in processor", in: "span_metrics:\n  foo: bar"},
+		{name: "unknown field of a plugin", in: "test_plugin:\n  foo: bar"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := ProcessorConfig{}
+			cfg.RegisterFlagsAndApplyDefaults("", nil)
+			assert.Error(t, yaml.UnmarshalStrict([]byte(tc.in), &cfg))
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/grafana/tempo/modules/generator/storage"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb"
 )
 
 const (
@@ -50,15 +52,24 @@ type Generator struct {
 	// and will flush any remaining metrics.
 	readOnly atomic.Bool
 
+	backfiller *backfiller
+
 	reg    prometheus.Registerer
 	logger log.Logger
 }
 
-// New makes a new Generator.
-func New(cfg *Config, overrides metricsGeneratorOverrides, reg prometheus.Registerer, logger log.Logger) (*Generator, error) {
+// New makes a new Generator. The store is used to backfill metrics and can be nil if backfill is
+// disabled.
+func New(cfg *Config, overrides metricsGeneratorOverrides, store tempodb.Reader, reg prometheus.Registerer, logger log.Logger) (*Generator, error) {
 	if cfg.Storage.Path == "" {
 		return nil, errors.New("must configure metrics_generator.storage.path")
 	}
+	if cfg.Backfill.Enabled && cfg.Backfill.Path == "" {
+		return nil, errors.New("must configure metrics_generator.backfill.path")
+	}
+	if cfg.Storage.Local.Enabled && cfg.Storage.Local.Path == "" {
+		return nil, errors.New("must configure metrics_generator.storage.local.path")
+	}
 
 	g := &Generator{
 		cfg:       cfg,
@@ -66,6 +77,8 @@ func New(cfg *Config, overrides metricsGeneratorOverrides, reg prometheus.Regist
 
 		instances: map[string]*instance{},
 
+		backfiller: newBackfiller(cfg, overrides, store, logger),
+
 		reg:    reg,
 		logger: logger,
 	}
@@ -146,6 +159,8 @@ func (g *Generator) stopping(_ error) error {
 	// Mark as read-only
 	g.stopIncomingRequests()
 
+	g.backfiller.stop()
+
 	if g.subservices != nil {
 		err := services.StopManagerAndAwaitStopped(context.Background(), g.subservices)
 		if err != nil {
@@ -197,6 +212,77 @@ func (g *Generator) PushSpans(ctx context.Context, req *tempopb.PushSpansRequest
 	return &tempopb.PushResponse{}, nil
 }
 
+// GetServiceGraph returns the service graph edges collected by this metrics-generator. Edges are not
+// combined with other metrics-generators.
+func (g *Generator) GetServiceGraph(ctx context.Context, req *tempopb.ServiceGraphRequest) (*tempopb.ServiceGraphResponse, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "generator.GetServiceGraph")
+	defer span.Finish()
+
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("instanceID", instanceID)
+
+	inst, ok := g.getInstanceByID(instanceID)
+	if !ok {
+		return &tempopb.ServiceGraphResponse{}, nil
+	}
+
+	return &tempopb.ServiceGraphResponse{
+		Edges: inst.getServiceGraph(time.Unix(int64(req.Start), 0), time.Unix(int64(req.End), 0)),
+	}, nil
+}
+
+// GetMetricsSeries returns the series stored in the local TSDB of this metrics-generator. Series are
+// not combined with other metrics-generators.
+func (g *Generator) GetMetricsSeries(ctx context.Context, req *tempopb.MetricsSeriesRequest) (*tempopb.MetricsSeriesResponse, error) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, "generator.GetMetricsSeries")
+	defer span.Finish()
+
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("instanceID", instanceID)
+
+	inst, ok := g.getInstanceByID(instanceID)
+	if !ok {
+		return &tempopb.MetricsSeriesResponse{}, nil
+	}
+
+	series, err := inst.getMetricsSeries(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tempopb.MetricsSeriesResponse{
+		Series: series,
+	}, nil
+}
+
+// GetCardinality returns the active series of the metrics of the tenant in this metrics-generator. The
+// metrics are not combined with other metrics-generators.
+func (g *Generator) GetCardinality(ctx context.Context, req *tempopb.CardinalityRequest) (*tempopb.CardinalityResponse, error) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "generator.GetCardinality")
+	defer span.Finish()
+
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("instanceID", instanceID)
+
+	resp := &tempopb.CardinalityResponse{}
+	if inst, ok := g.getInstanceByID(instanceID); ok {
+		resp.Metrics = inst.getCardinality(int(req.Limit))
+	}
+	for _, m := range resp.Metrics {
+		resp.ActiveSeries += m.ActiveSeries
+	}
+	return resp, nil
+}
+
 func (g *Generator) getOrCreateInstance(instanceID string) (*instance, error) {
 	inst, ok := g.getInstanceByID(instanceID)
 	if ok {
@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/api"
+)
+
+const (
+	urlParamStart = "start"
+	urlParamEnd   = "end"
+)
+
+// CardinalityHandler returns the metrics of the tenant in this metrics-generator with their labels, sorted
+// by active series. The limit param controls the amount of values returned per label.
+func (g *Generator) CardinalityHandler(w http.ResponseWriter, r *http.Request) {
+	req, err := api.ParseCardinalityRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := g.GetCardinality(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	marshaller := &jsonpb.Marshaler{}
+	err = marshaller.Marshal(w, resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// BackfillHandler starts a backfill of the tenant on POST and returns the last backfill of the tenant
+// on GET. The start and end params are unix epoch seconds.
+func (g *Generator) BackfillHandler(w http.ResponseWriter, r *http.Request) {
+	instanceID, err := user.ExtractOrgID(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var job BackfillJob
+	switch r.Method {
+	case http.MethodGet:
+		var ok bool
+		job, ok = g.backfiller.get(instanceID)
+		if !ok {
+			http.Error(w, "no backfill found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+
+	case http.MethodPost:
+		start, err := parseUnixSeconds(r, urlParamStart)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		end, err := parseUnixSeconds(r, urlParamEnd)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		job, err = g.backfiller.start(instanceID, start, end)
+		switch {
+		case errors.Is(err, errBackfillDisabled):
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		case errors.Is(err, errBackfillRunning):
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+		w.WriteHeader(http.StatusAccepted)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(job)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseUnixSeconds(r *http.Request, param string) (time.Time, error) {
+	s := r.URL.Query().Get(param)
+	if s == "" {
+		return time.Time{}, errors.New("missing " + param)
+	}
+	seconds, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, errors.New("invalid " + param + ": must be unix epoch seconds")
+	}
+	return time.Unix(seconds, 0), nil
+}
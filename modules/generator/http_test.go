@@ -0,0 +1,90 @@
+package generator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestGenerator_CardinalityHandler(t *testing.T) {
+	inst, err := newInstance(&Config{}, "test", &mockOverrides{}, &noopStorage{}, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	defer inst.shutdown()
+
+	counter := inst.registry.NewCounter("my_counter", []string{"service"})
+	counter.Inc(registry.NewLabelValues([]string{"svc-1"}), 1)
+	counter.Inc(registry.NewLabelValues([]string{"svc-2"}), 1)
+
+	g := &Generator{
+		instances: map[string]*instance{"test": inst},
+	}
+
+	tests := []struct {
+		name           string
+		tenant         string
+		url            string
+		expectedStatus int
+		expected       *tempopb.CardinalityResponse
+	}{
+		{
+			name:           "tenant with metrics",
+			tenant:         "test",
+			url:            "/metrics-generator/cardinality?limit=1",
+			expectedStatus: http.StatusOK,
+			expected: &tempopb.CardinalityResponse{
+				ActiveSeries: 2,
+				Metrics: []*tempopb.MetricCardinality{
+					{
+						Name:         "my_counter",
+						ActiveSeries: 2,
+						Labels: []*tempopb.LabelCardinality{
+							{Name: "service", Values: 2, TopValues: []*tempopb.LabelValueCardinality{{Value: "svc-1", ActiveSeries: 1}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:           "unknown tenant",
+			tenant:         "unknown",
+			url:            "/metrics-generator/cardinality",
+			expectedStatus: http.StatusOK,
+			expected:       &tempopb.CardinalityResponse{},
+		},
+		{
+			name:           "invalid limit",
+			tenant:         "test",
+			url:            "/metrics-generator/cardinality?limit=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", tc.url, nil)
+			r = r.WithContext(user.InjectOrgID(r.Context(), tc.tenant))
+			w := httptest.NewRecorder()
+
+			g.CardinalityHandler(w, r)
+
+			require.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expected == nil {
+				return
+			}
+
+			actual := &tempopb.CardinalityResponse{}
+			require.NoError(t, jsonpb.Unmarshal(w.Body, actual))
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
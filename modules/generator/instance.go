@@ -3,6 +3,7 @@ package generator
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -11,9 +12,12 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+	prometheus_storage "github.com/prometheus/prometheus/storage"
 
 	"github.com/grafana/tempo/modules/generator/processor"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spanevents"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
@@ -21,7 +25,7 @@ import (
 )
 
 var (
-	allSupportedProcessors = []string{servicegraphs.Name, spanmetrics.Name}
+	builtInProcessors = []string{servicegraphs.Name, spanmetrics.Name, spanevents.Name}
 
 	metricActiveProcessors = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "tempo",
@@ -54,9 +58,10 @@ type instance struct {
 	registry *registry.ManagedRegistry
 	wal      storage.Storage
 
-	// processorsMtx protects the processors map, not the processors itself
-	processorsMtx sync.RWMutex
-	processors    map[string]processor.Processor
+	// processorsMtx protects the processors and processorScopes maps, not the processors itself
+	processorsMtx   sync.RWMutex
+	processors      map[string]processor.Processor
+	processorScopes map[string]processorScope
 
 	shutdownCh chan struct{}
 
@@ -64,6 +69,12 @@ type instance struct {
 	logger log.Logger
 }
 
+// processorScope is the config a processor was created with and the registry holding its metrics.
+type processorScope struct {
+	registry *registry.ScopedRegistry
+	cfg      ProcessorConfig
+}
+
 func newInstance(cfg *Config, instanceID string, overrides metricsGeneratorOverrides, wal storage.Storage, reg prometheus.Registerer, logger log.Logger) (*instance, error) {
 	logger = log.With(logger, "tenant", instanceID)
 
@@ -75,7 +86,8 @@ func newInstance(cfg *Config, instanceID string, overrides metricsGeneratorOverr
 		registry: registry.New(&cfg.Registry, overrides, instanceID, wal, logger),
 		wal:      wal,
 
-		processors: make(map[string]processor.Processor),
+		processors:      make(map[string]processor.Processor),
+		processorScopes: make(map[string]processorScope),
 
 		shutdownCh: make(chan struct{}, 1),
 
@@ -113,12 +125,16 @@ func (i *instance) watchOverrides() {
 	}
 }
 
+// updateProcessors adds and removes processors to match desiredProcessors. Processors whose
+// config was changed by the tenant overrides are rebuilt.
 func (i *instance) updateProcessors(desiredProcessors map[string]struct{}) error {
+	desiredCfg := i.processorConfig()
+
 	i.processorsMtx.RLock()
-	toAdd, toRemove := i.diffProcessors(desiredProcessors)
+	toAdd, toRemove, toReplace := i.diffProcessors(desiredProcessors, desiredCfg)
 	i.processorsMtx.RUnlock()
 
-	if len(toAdd) == 0 && len(toRemove) == 0 {
+	if len(toAdd) == 0 && len(toRemove) == 0 && len(toReplace) == 0 {
 		return nil
 	}
 
@@ -126,7 +142,7 @@ func (i *instance) updateProcessors(desiredProcessors map[string]struct{}) error
 	defer i.processorsMtx.Unlock()
 
 	for _, processorName := range toAdd {
-		err := i.addProcessor(processorName)
+		err := i.addProcessor(processorName, desiredCfg)
 		if err != nil {
 			return err
 		}
@@ -134,18 +150,47 @@ func (i *instance) updateProcessors(desiredProcessors map[string]struct{}) error
 	for _, processorName := range toRemove {
 		i.removeProcessor(processorName)
 	}
+	for _, processorName := range toReplace {
+		err := i.replaceProcessor(processorName, desiredCfg)
+		if err != nil {
+			return err
+		}
+	}
 
 	i.updateProcessorMetrics()
 
 	return nil
 }
 
-// diffProcessors compares the existings processors with desiredProcessors. Must be called under a
-// read lock.
-func (i *instance) diffProcessors(desiredProcessors map[string]struct{}) (toAdd []string, toRemove []string) {
+// processorConfig returns the processor config of this tenant: the global config with the tenant
+// overrides applied.
+func (i *instance) processorConfig() ProcessorConfig {
+	return tenantProcessorConfig(i.cfg.Processor, i.overrides, i.instanceID)
+}
+
+// tenantProcessorConfig applies the overrides of the tenant to cfg.
+func tenantProcessorConfig(cfg ProcessorConfig, overrides metricsGeneratorOverrides, tenant string) ProcessorConfig {
+	if dimensions := overrides.MetricsGeneratorProcessorSpanMetricsDimensions(tenant); dimensions != nil {
+		cfg.SpanMetrics.Dimensions = dimensions
+	}
+	if buckets := overrides.MetricsGeneratorProcessorSpanMetricsHistogramBuckets(tenant); buckets != nil {
+		cfg.SpanMetrics.HistogramBuckets = buckets
+	}
+	if filter := overrides.MetricsGeneratorProcessorSpanMetricsFilter(tenant); filter != nil {
+		cfg.SpanMetrics.Filter = *filter
+	}
+
+	return cfg
+}
+
+// diffProcessors compares the existings processors with desiredProcessors and desiredCfg. Must be
+// called under a read lock.
+func (i *instance) diffProcessors(desiredProcessors map[string]struct{}, desiredCfg ProcessorConfig) (toAdd, toRemove, toReplace []string) {
 	for processorName := range desiredProcessors {
 		if _, ok := i.processors[processorName]; !ok {
 			toAdd = append(toAdd, processorName)
+		} else if processorConfigChanged(processorName, i.processorScopes[processorName].cfg, desiredCfg) {
+			toReplace = append(toReplace, processorName)
 		}
 	}
 	for processorName := range i.processors {
@@ -153,36 +198,120 @@ func (i *instance) diffProcessors(desiredProcessors map[string]struct{}) (toAdd
 			toRemove = append(toRemove, processorName)
 		}
 	}
-	return toAdd, toRemove
+	return toAdd, toRemove, toReplace
+}
+
+// processorConfigChanged returns whether the config of the given processor differs.
+func processorConfigChanged(processorName string, old, new ProcessorConfig) bool {
+	switch processorName {
+	case spanmetrics.Name:
+		return !reflect.DeepEqual(old.SpanMetrics, new.SpanMetrics)
+	case servicegraphs.Name:
+		return !reflect.DeepEqual(old.ServiceGraphs, new.ServiceGraphs)
+	case spanevents.Name:
+		return !reflect.DeepEqual(old.SpanEvents, new.SpanEvents)
+	}
+	return !reflect.DeepEqual(old.Plugins[processorName], new.Plugins[processorName])
 }
 
 // addProcessor registers the processor and adds it to the processors map. Must be called under a
 // write lock.
-func (i *instance) addProcessor(processorName string) error {
+func (i *instance) addProcessor(processorName string, cfg ProcessorConfig) error {
 	level.Debug(i.logger).Log("msg", "adding processor", "processorName", processorName)
 
-	var newProcessor processor.Processor
+	// check the processor wasn't added in the meantime
+	if _, ok := i.processors[processorName]; ok {
+		return nil
+	}
+
+	newProcessor, scope, err := i.newProcessor(processorName, cfg)
+	if err != nil {
+		return err
+	}
+
+	i.processors[processorName] = newProcessor
+	i.processorScopes[processorName] = scope
+
+	return nil
+}
+
+// replaceProcessor rebuilds the processor with the given config. The existing processor is shut down
+// and its series are dropped first, so the series of both processors never count together against the
+// active series limit. If the new processor can't be created, the processor is recreated with its
+// previous config. Must be called under a write lock.
+func (i *instance) replaceProcessor(processorName string, cfg ProcessorConfig) error {
+	level.Debug(i.logger).Log("msg", "replacing processor", "processorName", processorName)
+
+	previousCfg := i.processorScopes[processorName].cfg
+	i.removeProcessor(processorName)
+
+	err := i.addProcessor(processorName, cfg)
+	if err != nil {
+		if restoreErr := i.addProcessor(processorName, previousCfg); restoreErr != nil {
+			level.Error(i.logger).Log("msg", "failed to restore processor with its previous config", "processorName", processorName, "err", restoreErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// newProcessor creates the processor with its own scoped registry.
+func (i *instance) newProcessor(processorName string, cfg ProcessorConfig) (processor.Processor, processorScope, error) {
+	scope := processorScope{
+		registry: i.registry.NewScopedRegistry(),
+		cfg:      cfg,
+	}
+
+	newProcessor, err := newProcessor(processorName, cfg, i.instanceID, scope.registry, i.logger)
+	if err != nil {
+		scope.registry.Close()
+		return nil, processorScope{}, err
+	}
+
+	return newProcessor, scope, nil
+}
+
+// newProcessor creates a built-in processor or a processor registered with processor.Register. Its
+// metrics are created in reg.
+func newProcessor(processorName string, cfg ProcessorConfig, tenant string, reg registry.Registry, logger log.Logger) (processor.Processor, error) {
 	switch processorName {
 	case spanmetrics.Name:
-		newProcessor = spanmetrics.New(i.cfg.Processor.SpanMetrics, i.registry)
+		return spanmetrics.New(cfg.SpanMetrics, reg)
 	case servicegraphs.Name:
-		newProcessor = servicegraphs.New(i.cfg.Processor.ServiceGraphs, i.instanceID, i.registry, i.logger)
-	default:
-		level.Error(i.logger).Log(
-			"msg", fmt.Sprintf("processor does not exist, supported processors: [%s]", strings.Join(allSupportedProcessors, ", ")),
+		return servicegraphs.New(cfg.ServiceGraphs, tenant, reg, logger), nil
+	case spanevents.Name:
+		return spanevents.New(cfg.SpanEvents, reg), nil
+	}
+
+	factory, ok := processor.GetFactory(processorName)
+	if !ok {
+		level.Error(logger).Log(
+			"msg", fmt.Sprintf("processor does not exist, supported processors: [%s]", strings.Join(supportedProcessors(), ", ")),
 			"processorName", processorName,
 		)
-		return fmt.Errorf("unknown processor %s", processorName)
+		return nil, fmt.Errorf("unknown processor %s", processorName)
 	}
 
-	// check the processor wasn't added in the meantime
-	if _, ok := i.processors[processorName]; ok {
-		return nil
+	pluginCfg, ok := cfg.Plugins[processorName]
+	if !ok {
+		pluginCfg = factory.NewConfig()
 	}
 
-	i.processors[processorName] = newProcessor
+	newProcessor, err := factory.New(pluginCfg, processor.Params{
+		Tenant:   tenant,
+		Registry: reg,
+		Logger:   log.With(logger, "processor", processorName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create processor %s: %w", processorName, err)
+	}
+	return newProcessor, nil
+}
 
-	return nil
+// supportedProcessors returns the names of the built-in processors and of the registered plugins.
+func supportedProcessors() []string {
+	return append(append([]string{}, builtInProcessors...), processor.RegisteredFactories()...)
 }
 
 // removeProcessor removes the processor from the processors map and shuts it down. Must be called
@@ -198,11 +327,16 @@ func (i *instance) removeProcessor(processorName string) {
 	delete(i.processors, processorName)
 
 	deletedProcessor.Shutdown(context.Background())
+
+	if scope, ok := i.processorScopes[processorName]; ok {
+		delete(i.processorScopes, processorName)
+		scope.registry.Close()
+	}
 }
 
 // updateProcessorMetrics updates the active processor metrics. Must be called under a read lock.
 func (i *instance) updateProcessorMetrics() {
-	for _, processorName := range allSupportedProcessors {
+	for _, processorName := range supportedProcessors() {
 		isPresent := 0.0
 		if _, ok := i.processors[processorName]; ok {
 			isPresent = 1.0
@@ -222,6 +356,130 @@ func (i *instance) pushSpans(ctx context.Context, req *tempopb.PushSpansRequest)
 	}
 }
 
+// serviceGraphProcessor is implemented by processors that can be queried for the service graph.
+type serviceGraphProcessor interface {
+	ServiceGraph(start, end time.Time) []*tempopb.ServiceGraphEdge
+}
+
+// getServiceGraph returns the edges collected by the service graphs processor between start and end.
+func (i *instance) getServiceGraph(start, end time.Time) []*tempopb.ServiceGraphEdge {
+	i.processorsMtx.RLock()
+	defer i.processorsMtx.RUnlock()
+
+	p, ok := i.processors[servicegraphs.Name].(serviceGraphProcessor)
+	if !ok {
+		return nil
+	}
+	return p.ServiceGraph(start, end)
+}
+
+// getCardinality returns the active series of the metrics in the registry and their labels. At most limit
+// values are returned per label.
+func (i *instance) getCardinality(limit int) []*tempopb.MetricCardinality {
+	metrics := i.registry.Cardinality(limit)
+
+	result := make([]*tempopb.MetricCardinality, 0, len(metrics))
+	for _, m := range metrics {
+		metric := &tempopb.MetricCardinality{
+			Name:         m.Name,
+			ActiveSeries: m.ActiveSeries,
+			Labels:       make([]*tempopb.LabelCardinality, 0, len(m.Labels)),
+		}
+		for _, l := range m.Labels {
+			label := &tempopb.LabelCardinality{
+				Name:      l.Name,
+				Values:    uint32(l.Values),
+				TopValues: make([]*tempopb.LabelValueCardinality, 0, len(l.TopValues)),
+			}
+			for _, v := range l.TopValues {
+				label.TopValues = append(label.TopValues, &tempopb.LabelValueCardinality{Value: v.Value, ActiveSeries: v.ActiveSeries})
+			}
+			metric.Labels = append(metric.Labels, label)
+		}
+		result = append(result, metric)
+	}
+	return result
+}
+
+// getMetricsSeries returns the series stored in the local TSDB matching the request. It fails once more
+// than req.MaxSamples samples match, if set.
+func (i *instance) getMetricsSeries(ctx context.Context, req *tempopb.MetricsSeriesRequest) ([]*tempopb.MetricsSeries, error) {
+	matchers, err := toLabelMatchers(req.Matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	q, err := i.wal.Querier(ctx, req.Start, req.End)
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+
+	hints := &prometheus_storage.SelectHints{Start: req.Start, End: req.End}
+	set := q.Select(false, hints, matchers...)
+
+	var series []*tempopb.MetricsSeries
+	samples := 0
+	for set.Next() {
+		s := set.At()
+
+		lbls := s.Labels()
+		result := &tempopb.MetricsSeries{
+			Labels: make([]*tempopb.MetricsLabel, 0, len(lbls)),
+		}
+		for _, l := range lbls {
+			result.Labels = append(result.Labels, &tempopb.MetricsLabel{Name: l.Name, Value: l.Value})
+		}
+
+		it := s.Iterator()
+		for it.Next() {
+			samples++
+			if req.MaxSamples > 0 && samples > int(req.MaxSamples) {
+				return nil, fmt.Errorf("query matched more than the maximum of %d samples", req.MaxSamples)
+			}
+
+			t, v := it.At()
+			result.Samples = append(result.Samples, &tempopb.MetricsSample{TimestampMs: t, Value: v})
+		}
+		if err := it.Err(); err != nil {
+			return nil, err
+		}
+
+		series = append(series, result)
+	}
+	if err := set.Err(); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+func toLabelMatchers(matchers []*tempopb.MetricsLabelMatcher) ([]*labels.Matcher, error) {
+	result := make([]*labels.Matcher, 0, len(matchers))
+	for _, m := range matchers {
+		var matchType labels.MatchType
+		switch m.Type {
+		case 0:
+			matchType = labels.MatchEqual
+		case 1:
+			matchType = labels.MatchNotEqual
+		case 2:
+			matchType = labels.MatchRegexp
+		case 3:
+			matchType = labels.MatchNotRegexp
+		default:
+			return nil, fmt.Errorf("invalid matcher type %d", m.Type)
+		}
+
+		matcher, err := labels.NewMatcher(matchType, m.Name, m.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, matcher)
+	}
+	return result, nil
+}
+
 func (i *instance) updatePushMetrics(req *tempopb.PushSpansRequest) {
 	size := 0
 	spanCount := 0
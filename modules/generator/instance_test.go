@@ -2,6 +2,7 @@ package generator
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
@@ -11,9 +12,15 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	prometheus_storage "github.com/prometheus/prometheus/storage"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/tempo/modules/generator/processor"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spanevents"
+	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
+	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
+	"github.com/grafana/tempo/pkg/spanfilter"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/util/test"
@@ -87,6 +94,22 @@ func Test_instance_updateProcessors(t *testing.T) {
 		assert.Equal(t, instance.processors[servicegraphs.Name].Name(), servicegraphs.Name)
 	})
 
+	t.Run("add span events processor", func(t *testing.T) {
+		processors := map[string]struct{}{
+			servicegraphs.Name: {},
+			spanevents.Name:    {},
+		}
+		err := instance.updateProcessors(processors)
+		assert.NoError(t, err)
+
+		assert.Len(t, instance.processors, 2)
+		assert.Equal(t, instance.processors[spanevents.Name].Name(), spanevents.Name)
+
+		err = instance.updateProcessors(map[string]struct{}{servicegraphs.Name: {}})
+		assert.NoError(t, err)
+		assert.Len(t, instance.processors, 1)
+	})
+
 	t.Run("add unknown processor", func(t *testing.T) {
 		processors := map[string]struct{}{
 			"span-metricsss": {}, // typo in the overrides
@@ -107,13 +130,219 @@ func Test_instance_updateProcessors(t *testing.T) {
 	})
 }
 
+func Test_instance_updateProcessorsConfig(t *testing.T) {
+	overrides := &mockOverrides{
+		processors: map[string]struct{}{
+			spanmetrics.Name:   {},
+			servicegraphs.Name: {},
+		},
+	}
+	instance, err := newInstance(&Config{}, "test", overrides, &noopStorage{}, prometheus.DefaultRegisterer, log.NewNopLogger())
+	assert.NoError(t, err)
+
+	// stop the update goroutine
+	close(instance.shutdownCh)
+
+	assert.Len(t, instance.processors, 2)
+	spanMetricsProcessor := instance.processors[spanmetrics.Name]
+	serviceGraphsProcessor := instance.processors[servicegraphs.Name]
+
+	t.Run("unchanged config", func(t *testing.T) {
+		err := instance.updateProcessors(overrides.processors)
+		assert.NoError(t, err)
+
+		assert.Same(t, spanMetricsProcessor, instance.processors[spanmetrics.Name])
+		assert.Same(t, serviceGraphsProcessor, instance.processors[servicegraphs.Name])
+	})
+
+	t.Run("tenant dimensions are applied", func(t *testing.T) {
+		overrides.spanMetricsDimensions = []string{"team"}
+
+		err := instance.updateProcessors(overrides.processors)
+		assert.NoError(t, err)
+
+		// only the span-metrics processor is rebuilt
+		assert.NotSame(t, spanMetricsProcessor, instance.processors[spanmetrics.Name])
+		assert.Same(t, serviceGraphsProcessor, instance.processors[servicegraphs.Name])
+		assert.Equal(t, []string{"team"}, instance.processorScopes[spanmetrics.Name].cfg.SpanMetrics.Dimensions)
+
+		spanMetricsProcessor = instance.processors[spanmetrics.Name]
+	})
+
+	t.Run("invalid filter keeps the previous config", func(t *testing.T) {
+		overrides.spanMetricsFilter = &spanfilter.Config{
+			Include: []spanfilter.Match{{Services: []string{"("}}},
+		}
+
+		err := instance.updateProcessors(overrides.processors)
+		assert.Error(t, err)
+
+		assert.Contains(t, instance.processors, spanmetrics.Name)
+		assert.Equal(t, spanfilter.Config{}, instance.processorScopes[spanmetrics.Name].cfg.SpanMetrics.Filter)
+		assert.Equal(t, []string{"team"}, instance.processorScopes[spanmetrics.Name].cfg.SpanMetrics.Dimensions)
+	})
+
+	t.Run("tenant filter is applied", func(t *testing.T) {
+		overrides.spanMetricsFilter = &spanfilter.Config{
+			Exclude: []spanfilter.Match{{Services: []string{"noisy-service"}}},
+		}
+
+		err := instance.updateProcessors(overrides.processors)
+		assert.NoError(t, err)
+
+		assert.NotSame(t, spanMetricsProcessor, instance.processors[spanmetrics.Name])
+		assert.Equal(t, *overrides.spanMetricsFilter, instance.processorScopes[spanmetrics.Name].cfg.SpanMetrics.Filter)
+	})
+}
+
+func Test_instance_pluginProcessor(t *testing.T) {
+	overrides := &mockOverrides{
+		processors: map[string]struct{}{
+			testPluginName: {},
+		},
+	}
+
+	cfg := &Config{}
+	cfg.Processor.RegisterFlagsAndApplyDefaults("", nil)
+	pluginCfg := cfg.Processor.Plugins[testPluginName].(*testPluginConfig)
+	assert.Equal(t, "default", pluginCfg.Value)
+
+	instance, err := newInstance(cfg, "test", overrides, &noopStorage{}, prometheus.DefaultRegisterer, log.NewNopLogger())
+	assert.NoError(t, err)
+
+	// stop the update goroutine
+	close(instance.shutdownCh)
+
+	assert.Len(t, instance.processors, 1)
+	p := instance.processors[testPluginName].(*testPlugin)
+	assert.Equal(t, "test", p.tenant)
+	assert.Equal(t, "default", p.cfg.Value)
+
+	t.Run("unchanged config", func(t *testing.T) {
+		err := instance.updateProcessors(overrides.processors)
+		assert.NoError(t, err)
+		assert.Same(t, p, instance.processors[testPluginName])
+	})
+
+	t.Run("changed config", func(t *testing.T) {
+		cfg.Processor.Plugins = map[string]interface{}{
+			testPluginName: &testPluginConfig{Value: "changed"},
+		}
+
+		err := instance.updateProcessors(overrides.processors)
+		assert.NoError(t, err)
+		assert.NotSame(t, p, instance.processors[testPluginName])
+		assert.Equal(t, "changed", instance.processors[testPluginName].(*testPlugin).cfg.Value)
+	})
+
+	t.Run("invalid config keeps the previous config", func(t *testing.T) {
+		cfg.Processor.Plugins = map[string]interface{}{
+			testPluginName: &testPluginConfig{Value: ""},
+		}
+
+		err := instance.updateProcessors(overrides.processors)
+		assert.Error(t, err)
+		assert.Equal(t, "changed", instance.processors[testPluginName].(*testPlugin).cfg.Value)
+	})
+}
+
+func Test_instance_replaceProcessorActiveSeries(t *testing.T) {
+	overrides := &mockOverrides{
+		processors: map[string]struct{}{
+			testPluginName: {},
+		},
+		maxActiveSeries: 1,
+	}
+
+	cfg := &Config{}
+	cfg.Processor.RegisterFlagsAndApplyDefaults("", nil)
+
+	instance, err := newInstance(cfg, "test", overrides, &noopStorage{}, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+
+	// stop the update goroutine
+	close(instance.shutdownCh)
+
+	// the series of the replaced processor are dropped before the new processor creates its series
+	cfg.Processor.Plugins = map[string]interface{}{
+		testPluginName: &testPluginConfig{Value: "changed"},
+	}
+	err = instance.updateProcessors(overrides.processors)
+	require.NoError(t, err)
+
+	metrics := instance.registry.Cardinality(1)
+	require.Len(t, metrics, 1)
+	assert.Equal(t, uint32(1), metrics[0].ActiveSeries)
+	assert.Equal(t, "changed", metrics[0].Labels[0].TopValues[0].Value)
+}
+
+func Test_builtInProcessorNamesAreReserved(t *testing.T) {
+	factory := processor.Factory{
+		NewConfig: func() interface{} { return &testPluginConfig{} },
+		New: func(interface{}, processor.Params) (processor.Processor, error) {
+			return &testPlugin{}, nil
+		},
+	}
+
+	for _, name := range builtInProcessors {
+		assert.Panics(t, func() { processor.Register(name, factory) }, name)
+		assert.Panics(t, func() { processor.Register(pluginConfigKey(name), factory) }, name)
+	}
+}
+
+const testPluginName = "test-plugin"
+
+func init() {
+	processor.Register(testPluginName, processor.Factory{
+		NewConfig: func() interface{} {
+			return &testPluginConfig{Value: "default"}
+		},
+		New: func(cfg interface{}, params processor.Params) (processor.Processor, error) {
+			pluginCfg := cfg.(*testPluginConfig)
+			if pluginCfg.Value == "" {
+				return nil, errors.New("value must not be empty")
+			}
+			// every test plugin holds a series with its config value
+			params.Registry.NewCounter("test_plugin_total", []string{"value"}).Inc(registry.NewLabelValues([]string{pluginCfg.Value}), 1)
+			return &testPlugin{cfg: *pluginCfg, tenant: params.Tenant}, nil
+		},
+	})
+}
+
+type testPluginConfig struct {
+	Value string `yaml:"value"`
+}
+
+type testPlugin struct {
+	cfg    testPluginConfig
+	tenant string
+}
+
+func (p *testPlugin) Name() string { return testPluginName }
+
+func (p *testPlugin) PushSpans(context.Context, *tempopb.PushSpansRequest) {}
+
+func (p *testPlugin) Shutdown(context.Context) {}
+
 type mockOverrides struct {
-	processors map[string]struct{}
+	maxActiveSeries            uint32
+	processors                 map[string]struct{}
+	spanMetricsDimensions      []string
+	spanMetricsHistogramBucket []float64
+	spanMetricsFilter          *spanfilter.Config
 }
 
 var _ metricsGeneratorOverrides = (*mockOverrides)(nil)
 
 func (m *mockOverrides) MetricsGeneratorMaxActiveSeries(userID string) uint32 {
+	return m.maxActiveSeries
+}
+
+func (m *mockOverrides) MetricsGeneratorMaxActiveSeriesPerMetric(userID string) uint32 {
+	return 0
+}
+
+func (m *mockOverrides) MetricsGeneratorMaxLabelValues(userID string) uint32 {
 	return 0
 }
 
@@ -125,6 +354,18 @@ func (m *mockOverrides) MetricsGeneratorProcessors(userID string) map[string]str
 	return m.processors
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsDimensions(userID string) []string {
+	return m.spanMetricsDimensions
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID string) []float64 {
+	return m.spanMetricsHistogramBucket
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsFilter(userID string) *spanfilter.Config {
+	return m.spanMetricsFilter
+}
+
 type noopStorage struct{}
 
 var _ storage.Storage = (*noopStorage)(nil)
@@ -133,6 +374,10 @@ func (m noopStorage) Appender(ctx context.Context) prometheus_storage.Appender {
 	return &noopAppender{}
 }
 
+func (m noopStorage) Querier(ctx context.Context, mint, maxt int64) (prometheus_storage.Querier, error) {
+	return prometheus_storage.NoopQuerier(), nil
+}
+
 func (m noopStorage) Close() error {
 	return nil
 }
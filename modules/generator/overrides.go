@@ -3,12 +3,16 @@ package generator
 import (
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/spanfilter"
 )
 
 type metricsGeneratorOverrides interface {
 	registry.Overrides
 
 	MetricsGeneratorProcessors(userID string) map[string]struct{}
+	MetricsGeneratorProcessorSpanMetricsDimensions(userID string) []string
+	MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID string) []float64
+	MetricsGeneratorProcessorSpanMetricsFilter(userID string) *spanfilter.Config
 }
 
 var _ metricsGeneratorOverrides = (*overrides.Overrides)(nil)
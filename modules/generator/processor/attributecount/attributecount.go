@@ -0,0 +1,157 @@
+// Package attributecount is a processor that counts spans by arbitrary attribute expressions. It is
+// implemented as a plugin registered with processor.Register rather than built into the
+// metrics-generator. cmd/tempo imports it, so it's available in every Tempo binary and is enabled per
+// tenant like the built-in processors.
+package attributecount
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/prometheus/util/strutil"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	tempo_util "github.com/grafana/tempo/pkg/util"
+)
+
+func init() {
+	gen.Register(Name, gen.Factory{
+		NewConfig: func() interface{} {
+			cfg := &Config{}
+			cfg.RegisterFlagsAndApplyDefaults("", nil)
+			return cfg
+		},
+		New: func(cfg interface{}, params gen.Params) (gen.Processor, error) {
+			return New(*cfg.(*Config), params.Registry)
+		},
+	})
+}
+
+const (
+	scopeSpan     = "span."
+	scopeResource = "resource."
+	scopeAny      = "."
+)
+
+// expression extracts a value from a span.
+type expression func(rs *v1_trace.ResourceSpans, span *v1_trace.Span) string
+
+type processor struct {
+	expressions []expression
+
+	spansTotal registry.Counter
+}
+
+func New(cfg Config, registry registry.Registry) (gen.Processor, error) {
+	if cfg.MetricName == "" {
+		return nil, fmt.Errorf("metric name must not be empty")
+	}
+
+	labels := make([]string, 0, len(cfg.Dimensions))
+	expressions := make([]expression, 0, len(cfg.Dimensions))
+	seen := make(map[string]string, len(cfg.Dimensions))
+
+	for _, d := range cfg.Dimensions {
+		expr, err := parseExpression(d)
+		if err != nil {
+			return nil, err
+		}
+
+		label := strutil.SanitizeLabelName(strings.TrimPrefix(d, scopeAny))
+		if other, ok := seen[label]; ok {
+			return nil, fmt.Errorf("dimensions %s and %s result in the same label %s", other, d, label)
+		}
+		seen[label] = d
+
+		labels = append(labels, label)
+		expressions = append(expressions, expr)
+	}
+
+	return &processor{
+		expressions: expressions,
+		spansTotal:  registry.NewCounter(cfg.MetricName, labels),
+	}, nil
+}
+
+// parseExpression returns the expression extracting the value described by s.
+func parseExpression(s string) (expression, error) {
+	switch {
+	case strings.HasPrefix(s, scopeSpan) && len(s) > len(scopeSpan):
+		key := strings.TrimPrefix(s, scopeSpan)
+		return func(_ *v1_trace.ResourceSpans, span *v1_trace.Span) string {
+			value, _ := processor_util.FindAttributeValue(key, span.Attributes)
+			return value
+		}, nil
+	case strings.HasPrefix(s, scopeResource) && len(s) > len(scopeResource):
+		key := strings.TrimPrefix(s, scopeResource)
+		return func(rs *v1_trace.ResourceSpans, _ *v1_trace.Span) string {
+			value, _ := processor_util.FindAttributeValue(key, resourceAttributes(rs))
+			return value
+		}, nil
+	case strings.HasPrefix(s, scopeAny) && len(s) > len(scopeAny):
+		key := strings.TrimPrefix(s, scopeAny)
+		return func(rs *v1_trace.ResourceSpans, span *v1_trace.Span) string {
+			value, _ := processor_util.FindAttributeValue(key, span.Attributes, resourceAttributes(rs))
+			return value
+		}, nil
+	case s == "name":
+		return func(_ *v1_trace.ResourceSpans, span *v1_trace.Span) string {
+			return span.GetName()
+		}, nil
+	case s == "kind":
+		return func(_ *v1_trace.ResourceSpans, span *v1_trace.Span) string {
+			return span.GetKind().String()
+		}, nil
+	case s == "status":
+		return func(_ *v1_trace.ResourceSpans, span *v1_trace.Span) string {
+			return span.GetStatus().GetCode().String()
+		}, nil
+	case s == "service":
+		return func(rs *v1_trace.ResourceSpans, _ *v1_trace.Span) string {
+			value, _ := processor_util.FindServiceName(resourceAttributes(rs))
+			return value
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid dimension %s: expected span.<key>, resource.<key>, .<key>, name, kind, status or service", s)
+}
+
+func resourceAttributes(rs *v1_trace.ResourceSpans) []*v1_common.KeyValue {
+	if rs.Resource == nil {
+		return nil
+	}
+	return rs.Resource.Attributes
+}
+
+func (p *processor) Name() string { return Name }
+
+func (p *processor) PushSpans(ctx context.Context, req *tempopb.PushSpansRequest) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "attributecount.PushSpans")
+	defer span.Finish()
+
+	for _, rs := range req.Batches {
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			for _, s := range ils.Spans {
+				p.aggregateMetricsForSpan(rs, s)
+			}
+		}
+	}
+}
+
+func (p *processor) Shutdown(_ context.Context) {
+}
+
+func (p *processor) aggregateMetricsForSpan(rs *v1_trace.ResourceSpans, span *v1_trace.Span) {
+	labelValues := make([]string, 0, len(p.expressions))
+	for _, expr := range p.expressions {
+		labelValues = append(labelValues, expr(rs, span))
+	}
+
+	p.spansTotal.IncWithExemplar(registry.NewLabelValues(labelValues), 1, tempo_util.TraceIDToHexString(span.TraceId))
+}
@@ -0,0 +1,94 @@
+package attributecount
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestAttributeCount(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Dimensions = []string{"service", "kind", "span.http.method", "resource.cluster", ".namespace"}
+
+	p, err := New(cfg, testRegistry)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(3, nil)
+	batch.Resource.Attributes = append(batch.Resource.Attributes, stringKV("cluster", "eu-west"), stringKV("namespace", "prod"))
+	for i, ils := range batch.InstrumentationLibrarySpans {
+		for _, s := range ils.Spans {
+			s.Attributes = append(s.Attributes, stringKV("http.method", "GET"))
+			if i == 0 {
+				// the span attribute takes precedence over the resource attribute
+				s.Attributes = append(s.Attributes, stringKV("namespace", "dev"))
+			}
+		}
+	}
+	devSpans := float64(len(batch.InstrumentationLibrarySpans[0].Spans))
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{batch}})
+
+	lbls := func(namespace string) labels.Labels {
+		return labels.FromMap(map[string]string{
+			"service":          "test-service",
+			"kind":             "SPAN_KIND_CLIENT",
+			"span_http_method": "GET",
+			"resource_cluster": "eu-west",
+			"namespace":        namespace,
+		})
+	}
+	assert.Equal(t, devSpans, testRegistry.Query("traces_spans_by_attribute_total", lbls("dev")))
+	assert.Equal(t, 3-devSpans, testRegistry.Query("traces_spans_by_attribute_total", lbls("prod")))
+}
+
+func TestAttributeCount_invalidConfig(t *testing.T) {
+	testCases := []struct {
+		name       string
+		dimensions []string
+		metricName string
+	}{
+		{name: "unknown intrinsic", dimensions: []string{"duration"}, metricName: "foo_total"},
+		{name: "empty key", dimensions: []string{"span."}, metricName: "foo_total"},
+		{name: "duplicate label", dimensions: []string{".http.method", "http.method"}, metricName: "foo_total"},
+		{name: "empty metric name", dimensions: []string{"name"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(Config{Dimensions: tc.dimensions, MetricName: tc.metricName}, registry.NewTestRegistry())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestAttributeCount_registered(t *testing.T) {
+	factory, ok := gen.GetFactory(Name)
+	require.True(t, ok)
+
+	cfg := factory.NewConfig()
+	assert.Equal(t, &Config{Dimensions: []string{"service"}, MetricName: "traces_spans_by_attribute_total"}, cfg)
+
+	p, err := factory.New(cfg, gen.Params{Tenant: "test", Registry: registry.NewTestRegistry()})
+	require.NoError(t, err)
+	assert.Equal(t, Name, p.Name())
+}
+
+func stringKV(k, v string) *v1_common.KeyValue {
+	return &v1_common.KeyValue{
+		Key:   k,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}},
+	}
+}
@@ -0,0 +1,26 @@
+package attributecount
+
+import (
+	"flag"
+)
+
+const (
+	Name = "attribute-count"
+)
+
+type Config struct {
+	// Dimensions are the attribute expressions spans are counted by, each expression is added as a
+	// label to the metric. Supported expressions:
+	//  - span.<key>: the attribute of the span
+	//  - resource.<key>: the attribute of the resource
+	//  - .<key>: the attribute of the span, or of the resource if the span doesn't have it
+	//  - name, kind, status and service: the name, kind, status code and service of the span
+	Dimensions []string `yaml:"dimensions"`
+	// MetricName is the name of the counter.
+	MetricName string `yaml:"metric_name"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	cfg.Dimensions = []string{"service"}
+	cfg.MetricName = "traces_spans_by_attribute_total"
+}
@@ -0,0 +1,90 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+)
+
+// Params are passed to a Factory when a processor is created for a tenant.
+type Params struct {
+	Tenant string
+	// Registry holds the metrics of the processor. Metrics created in this registry are removed
+	// when the processor is shut down.
+	Registry registry.Registry
+	Logger   log.Logger
+}
+
+// Factory creates processors that are not built into the metrics-generator. A processor registered
+// with a Factory is configured under its name in the processor block of the metrics-generator and is
+// enabled per tenant like the built-in processors.
+type Factory struct {
+	// NewConfig returns a pointer to a new config with the defaults applied. The YAML config of the
+	// processor is decoded into it.
+	NewConfig func() interface{}
+	// New creates a processor, cfg is the value returned by NewConfig after decoding.
+	New func(cfg interface{}, params Params) (Processor, error)
+}
+
+var (
+	factoriesMtx sync.RWMutex
+	factories    = map[string]Factory{}
+
+	// builtInNames are the names of the processors built into the metrics-generator. They are listed
+	// here as the packages of the built-in processors import this package.
+	builtInNames = map[string]struct{}{
+		"service-graphs": {},
+		"span-metrics":   {},
+		"span-events":    {},
+	}
+)
+
+// Register registers a processor factory under the given name. It is meant to be called from the
+// init function of the package implementing the processor, the processor is available in every
+// binary importing that package. Register panics if a factory with the same name is registered or if
+// the name, or its config key with dashes replaced by underscores, is the one of a built-in processor.
+func Register(name string, factory Factory) {
+	factoriesMtx.Lock()
+	defer factoriesMtx.Unlock()
+
+	if name == "" {
+		panic("processor name must not be empty")
+	}
+	if factory.NewConfig == nil || factory.New == nil {
+		panic(fmt.Sprintf("incomplete factory for processor %s", name))
+	}
+	if _, ok := builtInNames[strings.ReplaceAll(name, "_", "-")]; ok {
+		panic(fmt.Sprintf("processor name %s is reserved for a built-in processor", name))
+	}
+	if _, ok := factories[name]; ok {
+		panic(fmt.Sprintf("processor %s is already registered", name))
+	}
+	factories[name] = factory
+}
+
+// GetFactory returns the factory registered under the given name.
+func GetFactory(name string) (Factory, bool) {
+	factoriesMtx.RLock()
+	defer factoriesMtx.RUnlock()
+
+	factory, ok := factories[name]
+	return factory, ok
+}
+
+// RegisteredFactories returns the names of all registered factories, sorted.
+func RegisteredFactories() []string {
+	factoriesMtx.RLock()
+	defer factoriesMtx.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -28,6 +28,18 @@ type Config struct {
 	// (either value could get used)
 	Dimensions []string `yaml:"dimensions"`
 
+	// PeerAttributes are the client span attributes used to name a virtual server node
+	// if a client span expires without a server span. The first attribute found is used.
+	PeerAttributes []string `yaml:"peer_attributes"`
+
+	// EnableConnectionTypeLabel adds a connection_type label to the metrics to tell
+	// request/response, messaging and virtual node edges apart.
+	EnableConnectionTypeLabel bool `yaml:"enable_connection_type_label"`
+
+	// QueryRetention is how long collected edges are kept to answer service graph queries.
+	// Set to 0 to disable.
+	QueryRetention time.Duration `yaml:"query_retention"`
+
 	// SuccessCodes *successCodes `yaml:"success_codes"`
 }
 
@@ -37,4 +49,6 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Workers = 10
 	// TODO: Revisit this default value.
 	cfg.HistogramBuckets = prometheus.ExponentialBuckets(0.1, 2, 8)
+	cfg.PeerAttributes = []string{"peer.service", "db.name", "db.system"}
+	cfg.QueryRetention = time.Hour
 }
@@ -0,0 +1,140 @@
+package servicegraphs
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/tempo/modules/generator/processor/servicegraphs/store"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// graphBucketDuration is the resolution at which collected edges are aggregated for queries.
+const graphBucketDuration = time.Minute
+
+type edgeKey struct {
+	client, server, connectionType string
+}
+
+type graphBucket struct {
+	start time.Time
+	edges map[edgeKey]*tempopb.ServiceGraphEdge
+}
+
+// serviceGraph aggregates collected edges in buckets of graphBucketDuration so the service graph
+// of a time window can be queried after the edges were evicted from the store.
+type serviceGraph struct {
+	mtx       sync.Mutex
+	retention time.Duration
+	buckets   []*graphBucket // oldest first
+}
+
+func newServiceGraph(retention time.Duration) *serviceGraph {
+	return &serviceGraph{
+		retention: retention,
+	}
+}
+
+// record adds the edge to the bucket of its end time. Edges without an end time or ending after now
+// are added to the bucket of now, edges that ended before the retention are dropped. The server
+// latency is only recorded if the edge has a server span.
+func (g *serviceGraph) record(e *store.Edge, connectionType store.ConnectionType, withServer bool, now time.Time) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	g.prune(now)
+
+	ts := e.EndTime
+	if ts.IsZero() || ts.After(now) {
+		ts = now
+	}
+	start := ts.Truncate(graphBucketDuration)
+	if start.Add(graphBucketDuration).Before(now.Add(-g.retention)) {
+		return
+	}
+
+	b := g.bucket(start)
+
+	k := edgeKey{client: e.ClientService, server: e.ServerService, connectionType: string(connectionType)}
+	edge, ok := b.edges[k]
+	if !ok {
+		edge = &tempopb.ServiceGraphEdge{
+			Client:         k.client,
+			Server:         k.server,
+			ConnectionType: k.connectionType,
+		}
+		b.edges[k] = edge
+	}
+
+	edge.Requests++
+	if e.Failed {
+		edge.FailedRequests++
+	}
+	edge.ClientLatencySum += e.ClientLatencySec
+	if withServer {
+		edge.ServerRequests++
+		edge.ServerLatencySum += e.ServerLatencySec
+	}
+}
+
+// bucket returns the bucket starting at start, it's created if it doesn't exist yet. Must be called
+// under lock.
+func (g *serviceGraph) bucket(start time.Time) *graphBucket {
+	i := sort.Search(len(g.buckets), func(i int) bool {
+		return !g.buckets[i].start.Before(start)
+	})
+	if i < len(g.buckets) && g.buckets[i].start.Equal(start) {
+		return g.buckets[i]
+	}
+
+	b := &graphBucket{start: start, edges: map[edgeKey]*tempopb.ServiceGraphEdge{}}
+	g.buckets = append(g.buckets, nil)
+	copy(g.buckets[i+1:], g.buckets[i:])
+	g.buckets[i] = b
+	return b
+}
+
+// query returns the sum of all edges recorded in buckets overlapping with [start, end].
+func (g *serviceGraph) query(start, end time.Time) []*tempopb.ServiceGraphEdge {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+
+	edges := map[edgeKey]*tempopb.ServiceGraphEdge{}
+	for _, b := range g.buckets {
+		if b.start.Add(graphBucketDuration).Before(start) || b.start.After(end) {
+			continue
+		}
+		for k, e := range b.edges {
+			sum, ok := edges[k]
+			if !ok {
+				sum = &tempopb.ServiceGraphEdge{
+					Client:         e.Client,
+					Server:         e.Server,
+					ConnectionType: e.ConnectionType,
+				}
+				edges[k] = sum
+			}
+			sum.Requests += e.Requests
+			sum.FailedRequests += e.FailedRequests
+			sum.ClientLatencySum += e.ClientLatencySum
+			sum.ServerRequests += e.ServerRequests
+			sum.ServerLatencySum += e.ServerLatencySum
+		}
+	}
+
+	result := make([]*tempopb.ServiceGraphEdge, 0, len(edges))
+	for _, e := range edges {
+		result = append(result, e)
+	}
+	return result
+}
+
+// prune drops all buckets that ended before the retention. Must be called under lock.
+func (g *serviceGraph) prune(now time.Time) {
+	cutoff := now.Add(-g.retention)
+	i := 0
+	for i < len(g.buckets) && g.buckets[i].start.Add(graphBucketDuration).Before(cutoff) {
+		i++
+	}
+	g.buckets = g.buckets[i:]
+}
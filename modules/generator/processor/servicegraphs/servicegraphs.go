@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log"
@@ -56,6 +57,8 @@ type processor struct {
 	cfg Config
 
 	store store.Store
+	// graph is nil if service graph queries are disabled
+	graph *serviceGraph
 
 	// completed edges are pushed through this channel to be processed.
 	collectCh chan string
@@ -73,6 +76,9 @@ type processor struct {
 
 func New(cfg Config, tenant string, registry registry.Registry, logger log.Logger) gen.Processor {
 	labels := []string{"client", "server"}
+	if cfg.EnableConnectionTypeLabel {
+		labels = append(labels, "connection_type")
+	}
 	for _, d := range cfg.Dimensions {
 		labels = append(labels, strutil.SanitizeLabelName(d))
 	}
@@ -94,6 +100,9 @@ func New(cfg Config, tenant string, registry registry.Registry, logger log.Logge
 	}
 
 	p.store = store.NewStore(cfg.Wait, cfg.MaxItems, p.collectEdge)
+	if cfg.QueryRetention > 0 {
+		p.graph = newServiceGraph(cfg.QueryRetention)
+	}
 
 	expirationTicker := time.NewTicker(2 * time.Second)
 	for i := 0; i < cfg.Workers; i++ {
@@ -142,48 +151,60 @@ func (p *processor) consume(resourceSpans []*v1_trace.ResourceSpans) error {
 		}
 
 		for _, ils := range rs.InstrumentationLibrarySpans {
-			var (
-				edge *store.Edge
-				k    string
-				err  error
-			)
 			for _, span := range ils.Spans {
+				var (
+					keys []string
+					cb   store.Callback
+				)
 				switch span.Kind {
-				case v1_trace.Span_SPAN_KIND_CLIENT:
-					k = key(hex.EncodeToString(span.TraceId), hex.EncodeToString(span.SpanId))
-					edge, err = p.store.UpsertEdge(k, func(e *store.Edge) {
+				case v1_trace.Span_SPAN_KIND_CLIENT, v1_trace.Span_SPAN_KIND_PRODUCER:
+					keys = []string{key(hex.EncodeToString(span.TraceId), hex.EncodeToString(span.SpanId))}
+					cb = func(e *store.Edge) {
 						e.TraceID = tempo_util.TraceIDToHexString(span.TraceId)
 						e.ClientService = svcName
 						e.ClientLatencySec = spanDurationSec(span)
 						e.Failed = e.Failed || p.spanFailed(span)
+						updateEndTime(e, span)
+						if span.Kind == v1_trace.Span_SPAN_KIND_PRODUCER {
+							e.ConnectionType = store.MessagingSystem
+						} else {
+							e.PeerNode, e.PeerConnectionType = p.findPeerNode(rs.Resource.Attributes, span.Attributes)
+						}
 						p.upsertDimensions(e.Dimensions, rs.Resource.Attributes, span.Attributes)
-					})
-				case v1_trace.Span_SPAN_KIND_SERVER:
-					k = key(hex.EncodeToString(span.TraceId), hex.EncodeToString(span.ParentSpanId))
-					edge, err = p.store.UpsertEdge(k, func(e *store.Edge) {
+					}
+				case v1_trace.Span_SPAN_KIND_SERVER, v1_trace.Span_SPAN_KIND_CONSUMER:
+					keys = serverKeys(span)
+					cb = func(e *store.Edge) {
 						e.TraceID = tempo_util.TraceIDToHexString(span.TraceId)
 						e.ServerService = svcName
 						e.ServerLatencySec = spanDurationSec(span)
 						e.Failed = e.Failed || p.spanFailed(span)
+						updateEndTime(e, span)
+						if span.Kind == v1_trace.Span_SPAN_KIND_CONSUMER {
+							e.ConnectionType = store.MessagingSystem
+						}
 						p.upsertDimensions(e.Dimensions, rs.Resource.Attributes, span.Attributes)
-					})
+					}
 				default:
 					continue
 				}
 
-				if errors.Is(err, store.ErrTooManyItems) {
-					totalDroppedSpans++
-					p.metricDroppedSpans.Inc()
-					continue
-				}
-
-				// upsertEdge will only return this errTooManyItems
-				if err != nil {
-					return err
-				}
-
-				if edge.IsCompleted() {
-					p.collectCh <- k
+				for _, k := range keys {
+					edge, err := p.store.UpsertEdge(k, cb)
+					if errors.Is(err, store.ErrTooManyItems) {
+						totalDroppedSpans++
+						p.metricDroppedSpans.Inc()
+						continue
+					}
+
+					// upsertEdge will only return this errTooManyItems
+					if err != nil {
+						return err
+					}
+
+					if edge.IsCompleted() {
+						p.collectCh <- k
+					}
 				}
 			}
 		}
@@ -206,6 +227,28 @@ func (p *processor) upsertDimensions(m map[string]string, resourceAttr []*v1_com
 	}
 }
 
+// findPeerNode returns the value of the first peer attribute found and the connection type of the virtual node.
+func (p *processor) findPeerNode(resourceAttr []*v1_common.KeyValue, spanAttr []*v1_common.KeyValue) (string, store.ConnectionType) {
+	for _, attr := range p.cfg.PeerAttributes {
+		if v, ok := processor_util.FindAttributeValue(attr, resourceAttr, spanAttr); ok && v != "" {
+			if strings.HasPrefix(attr, "db.") {
+				return v, store.Database
+			}
+			return v, store.VirtualNode
+		}
+	}
+	return "", store.Unset
+}
+
+// ServiceGraph returns the edges collected between start and end. Latencies and request counts are
+// summed, rates and averages are left to the caller.
+func (p *processor) ServiceGraph(start, end time.Time) []*tempopb.ServiceGraphEdge {
+	if p.graph == nil {
+		return nil
+	}
+	return p.graph.query(start, end)
+}
+
 func (p *processor) Shutdown(_ context.Context) {
 	close(p.closeCh)
 }
@@ -214,25 +257,51 @@ func (p *processor) Shutdown(_ context.Context) {
 // Returns true if the edge is completed or expired and should be deleted.
 func (p *processor) collectEdge(e *store.Edge) {
 	if e.IsCompleted() {
-		labelValues := make([]string, 0, 2+len(p.cfg.Dimensions))
-		labelValues = append(labelValues, e.ClientService, e.ServerService)
-
-		for _, dimension := range p.cfg.Dimensions {
-			labelValues = append(labelValues, e.Dimensions[dimension])
+		p.recordEdge(e, e.ConnectionType)
+		p.serviceGraphRequestServerSecondsHistogram.ObserveWithExemplar(p.labelValues(e, e.ConnectionType), e.ServerLatencySec, e.TraceID)
+		if p.graph != nil {
+			p.graph.record(e, e.ConnectionType, true, time.Now())
+		}
+	} else if e.IsExpired() {
+		// A client span without server span is attributed to a virtual node
+		// if the server could be inferred from its attributes.
+		if e.ServerService == "" && e.ClientService != "" && e.PeerNode != "" {
+			e.ServerService = e.PeerNode
+			p.recordEdge(e, e.PeerConnectionType)
+			if p.graph != nil {
+				p.graph.record(e, e.PeerConnectionType, false, time.Now())
+			}
+			return
 		}
+		p.metricExpiredSpans.Inc()
+	}
+}
 
-		registryLabelValues := registry.NewLabelValues(labelValues)
+// recordEdge records the request counters and the client latency for the given edge.
+// The server latency is only known for completed edges and recorded separately.
+func (p *processor) recordEdge(e *store.Edge, connectionType store.ConnectionType) {
+	registryLabelValues := p.labelValues(e, connectionType)
 
-		p.serviceGraphRequestTotal.Inc(registryLabelValues, 1)
-		if e.Failed {
-			p.serviceGraphRequestFailedTotal.Inc(registryLabelValues, 1)
-		}
+	p.serviceGraphRequestTotal.Inc(registryLabelValues, 1)
+	if e.Failed {
+		p.serviceGraphRequestFailedTotal.Inc(registryLabelValues, 1)
+	}
 
-		p.serviceGraphRequestServerSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ServerLatencySec, e.TraceID)
-		p.serviceGraphRequestClientSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ClientLatencySec, e.TraceID)
-	} else if e.IsExpired() {
-		p.metricExpiredSpans.Inc()
+	p.serviceGraphRequestClientSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ClientLatencySec, e.TraceID)
+}
+
+func (p *processor) labelValues(e *store.Edge, connectionType store.ConnectionType) *registry.LabelValues {
+	labelValues := make([]string, 0, 3+len(p.cfg.Dimensions))
+	labelValues = append(labelValues, e.ClientService, e.ServerService)
+	if p.cfg.EnableConnectionTypeLabel {
+		labelValues = append(labelValues, string(connectionType))
 	}
+
+	for _, dimension := range p.cfg.Dimensions {
+		labelValues = append(labelValues, e.Dimensions[dimension])
+	}
+
+	return registry.NewLabelValues(labelValues)
 }
 
 func (p *processor) spanFailed(_ *v1_trace.Span) bool {
@@ -243,6 +312,27 @@ func spanDurationSec(span *v1_trace.Span) float64 {
 	return float64(span.EndTimeUnixNano-span.StartTimeUnixNano) / float64(time.Second.Nanoseconds())
 }
 
+// updateEndTime sets the end time of the edge to the end of the span if it ended later.
+func updateEndTime(e *store.Edge, span *v1_trace.Span) {
+	end := time.Unix(0, int64(span.EndTimeUnixNano))
+	if end.After(e.EndTime) {
+		e.EndTime = end
+	}
+}
+
+// serverKeys returns the keys of the edges a server or consumer span completes. Consumer spans are
+// paired with their producers through their span links, or through their parent if they have none.
+func serverKeys(span *v1_trace.Span) []string {
+	if span.Kind == v1_trace.Span_SPAN_KIND_CONSUMER && len(span.Links) > 0 {
+		keys := make([]string, 0, len(span.Links))
+		for _, link := range span.Links {
+			keys = append(keys, key(hex.EncodeToString(link.TraceId), hex.EncodeToString(link.SpanId)))
+		}
+		return keys
+	}
+	return []string{key(hex.EncodeToString(span.TraceId), hex.EncodeToString(span.ParentSpanId))}
+}
+
 func key(k1, k2 string) string {
 	return fmt.Sprintf("%s-%s", k1, k2)
 }
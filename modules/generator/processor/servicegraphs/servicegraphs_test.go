@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"sort"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/gogo/protobuf/jsonpb"
@@ -15,8 +17,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/tempo/modules/generator/processor/servicegraphs/store"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 )
 
 func TestServiceGraphs(t *testing.T) {
@@ -84,6 +90,204 @@ func TestServiceGraphs(t *testing.T) {
 	assert.Equal(t, 6.2, testRegistry.Query(`traces_service_graph_request_server_seconds_sum`, lbAppLabels))
 }
 
+func TestServiceGraphs_messagingAndVirtualNodes(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.HistogramBuckets = []float64{1.0}
+	cfg.EnableConnectionTypeLabel = true
+	// expire edges immediately
+	cfg.Wait = -time.Second
+
+	p := New(cfg, "test", testRegistry, log.NewNopLogger())
+	defer p.Shutdown(context.Background())
+
+	traceID := []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	span := func(id byte, parentID byte, kind v1_trace.Span_SpanKind, attrs ...*v1_common.KeyValue) *v1_trace.Span {
+		s := &v1_trace.Span{
+			TraceId:           traceID,
+			SpanId:            []byte{0, 0, 0, 0, 0, 0, 0, id},
+			Kind:              kind,
+			StartTimeUnixNano: uint64(time.Second),
+			EndTimeUnixNano:   uint64(2 * time.Second),
+			Attributes:        attrs,
+		}
+		if parentID != 0 {
+			s.ParentSpanId = []byte{0, 0, 0, 0, 0, 0, 0, parentID}
+		}
+		return s
+	}
+	batch := func(service string, spans ...*v1_trace.Span) *v1_trace.ResourceSpans {
+		return &v1_trace.ResourceSpans{
+			Resource: &v1_resource.Resource{
+				Attributes: []*v1_common.KeyValue{stringKV("service.name", service)},
+			},
+			InstrumentationLibrarySpans: []*v1_trace.InstrumentationLibrarySpans{{Spans: spans}},
+		}
+	}
+
+	// consumer linked to its producer
+	linkedConsumer := span(3, 0, v1_trace.Span_SPAN_KIND_CONSUMER)
+	linkedConsumer.Links = []*v1_trace.Span_Link{{TraceId: traceID, SpanId: []byte{0, 0, 0, 0, 0, 0, 0, 1}}}
+
+	batches := []*v1_trace.ResourceSpans{
+		batch("producer",
+			span(1, 0, v1_trace.Span_SPAN_KIND_PRODUCER),
+			span(2, 0, v1_trace.Span_SPAN_KIND_PRODUCER),
+		),
+		batch("consumer",
+			linkedConsumer,
+			// consumer child of its producer
+			span(4, 2, v1_trace.Span_SPAN_KIND_CONSUMER),
+		),
+		batch("app",
+			span(5, 0, v1_trace.Span_SPAN_KIND_CLIENT, stringKV("db.system", "postgresql")),
+			span(6, 0, v1_trace.Span_SPAN_KIND_CLIENT, stringKV("peer.service", "payments")),
+			// no peer attributes, expires without server
+			span(7, 0, v1_trace.Span_SPAN_KIND_CLIENT),
+		),
+	}
+
+	require.NoError(t, p.(*processor).consume(batches))
+	p.(*processor).store.Expire()
+
+	messagingLabels := labels.FromMap(map[string]string{
+		"client":          "producer",
+		"server":          "consumer",
+		"connection_type": "messaging_system",
+	})
+	databaseLabels := labels.FromMap(map[string]string{
+		"client":          "app",
+		"server":          "postgresql",
+		"connection_type": "database",
+	})
+	virtualNodeLabels := labels.FromMap(map[string]string{
+		"client":          "app",
+		"server":          "payments",
+		"connection_type": "virtual_node",
+	})
+
+	assert.Equal(t, 2.0, testRegistry.Query(`traces_service_graph_request_total`, messagingLabels))
+	assert.Equal(t, 2.0, testRegistry.Query(`traces_service_graph_request_server_seconds_count`, messagingLabels))
+	assert.Equal(t, 2.0, testRegistry.Query(`traces_service_graph_request_client_seconds_count`, messagingLabels))
+
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, databaseLabels))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_client_seconds_count`, databaseLabels))
+	assert.Equal(t, 0.0, testRegistry.Query(`traces_service_graph_request_server_seconds_count`, databaseLabels))
+
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, virtualNodeLabels))
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_client_seconds_count`, virtualNodeLabels))
+}
+
+func TestServiceGraphs_query(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+	p := New(cfg, "test", testRegistry, log.NewNopLogger())
+	defer p.Shutdown(context.Background())
+
+	traces, err := loadTestData("testdata/test-sample.json")
+	require.NoError(t, err)
+
+	// edges are bucketed by the end time of their spans, move the spans to now
+	now := time.Now()
+	shiftSpans(traces, now)
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: traces.Batches})
+
+	sgp := p.(*processor)
+	sgp.store.Expire()
+
+	edges := sgp.ServiceGraph(now.Add(-time.Minute), now)
+	sort.Slice(edges, func(i, j int) bool { return edges[i].Client < edges[j].Client })
+	assert.Equal(t, []*tempopb.ServiceGraphEdge{
+		{Client: "app", Server: "db", Requests: 3, ClientLatencySum: 4.4, ServerLatencySum: 5, ServerRequests: 3},
+		{Client: "lb", Server: "app", Requests: 3, ClientLatencySum: 7.8, ServerLatencySum: 6.2, ServerRequests: 3},
+	}, roundEdges(edges))
+
+	assert.Empty(t, sgp.ServiceGraph(now.Add(-time.Hour), now.Add(-30*time.Minute)))
+}
+
+func TestServiceGraph_retention(t *testing.T) {
+	g := newServiceGraph(time.Hour)
+	now := time.Unix(3600, 0)
+	edge := &store.Edge{ClientService: "client", ServerService: "server", ClientLatencySec: 1, ServerLatencySec: 0.5}
+
+	g.record(edge, store.Unset, true, now.Add(-90*time.Minute))
+	g.record(edge, store.Unset, true, now.Add(-30*time.Minute))
+	g.record(edge, store.Unset, false, now.Add(-30*time.Minute))
+	edge.Failed = true
+	g.record(edge, store.Unset, true, now)
+
+	// the first bucket is pruned
+	assert.Len(t, g.buckets, 2)
+
+	assert.Equal(t, []*tempopb.ServiceGraphEdge{
+		{Client: "client", Server: "server", Requests: 3, FailedRequests: 1, ClientLatencySum: 3, ServerLatencySum: 1, ServerRequests: 2},
+	}, g.query(now.Add(-time.Hour), now))
+	assert.Equal(t, []*tempopb.ServiceGraphEdge{
+		{Client: "client", Server: "server", Requests: 2, ClientLatencySum: 2, ServerLatencySum: 0.5, ServerRequests: 1},
+	}, g.query(now.Add(-time.Hour), now.Add(-time.Minute)))
+}
+
+func TestServiceGraph_spanEndTime(t *testing.T) {
+	g := newServiceGraph(time.Hour)
+	now := time.Unix(7200, 0)
+	edge := &store.Edge{ClientService: "client", ServerService: "server", ClientLatencySec: 1}
+
+	// edges are recorded in the bucket of their end time, in any order
+	edge.EndTime = now.Add(-10 * time.Minute)
+	g.record(edge, store.Unset, false, now)
+	edge.EndTime = now.Add(-30 * time.Minute)
+	g.record(edge, store.Unset, false, now)
+	// edges ending after now are recorded now
+	edge.EndTime = now.Add(time.Hour)
+	g.record(edge, store.Unset, false, now)
+	// edges ending before the retention are dropped
+	edge.EndTime = now.Add(-2 * time.Hour)
+	g.record(edge, store.Unset, false, now)
+
+	require.Len(t, g.buckets, 3)
+	assert.True(t, g.buckets[0].start.Before(g.buckets[1].start))
+	assert.True(t, g.buckets[1].start.Before(g.buckets[2].start))
+
+	assert.Equal(t, []*tempopb.ServiceGraphEdge{
+		{Client: "client", Server: "server", Requests: 1, ClientLatencySum: 1},
+	}, g.query(now.Add(-35*time.Minute), now.Add(-25*time.Minute)))
+	assert.Equal(t, []*tempopb.ServiceGraphEdge{
+		{Client: "client", Server: "server", Requests: 3, ClientLatencySum: 3},
+	}, g.query(now.Add(-time.Hour), now))
+}
+
+func TestServerKeys(t *testing.T) {
+	traceID := []byte{1}
+	linkedTraceID := []byte{2}
+
+	assert.Equal(t, []string{"01-03"}, serverKeys(&v1_trace.Span{
+		TraceId:      traceID,
+		ParentSpanId: []byte{3},
+		Kind:         v1_trace.Span_SPAN_KIND_SERVER,
+		Links:        []*v1_trace.Span_Link{{TraceId: linkedTraceID, SpanId: []byte{4}}},
+	}))
+	assert.Equal(t, []string{"01-03"}, serverKeys(&v1_trace.Span{
+		TraceId:      traceID,
+		ParentSpanId: []byte{3},
+		Kind:         v1_trace.Span_SPAN_KIND_CONSUMER,
+	}))
+	assert.Equal(t, []string{"02-04", "02-05"}, serverKeys(&v1_trace.Span{
+		TraceId:      traceID,
+		ParentSpanId: []byte{3},
+		Kind:         v1_trace.Span_SPAN_KIND_CONSUMER,
+		Links: []*v1_trace.Span_Link{
+			{TraceId: linkedTraceID, SpanId: []byte{4}},
+			{TraceId: linkedTraceID, SpanId: []byte{5}},
+		},
+	}))
+}
+
 func TestServiceGraphs_tooManySpansErr(t *testing.T) {
 	testRegistry := registry.TestRegistry{}
 
@@ -111,8 +315,48 @@ func loadTestData(path string) (*tempopb.Trace, error) {
 	return trace, err
 }
 
+// shiftSpans moves all spans of the trace so the last one ends at end.
+func shiftSpans(trace *tempopb.Trace, end time.Time) {
+	var last uint64
+	for _, rs := range trace.Batches {
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			for _, span := range ils.Spans {
+				if span.EndTimeUnixNano > last {
+					last = span.EndTimeUnixNano
+				}
+			}
+		}
+	}
+
+	offset := uint64(end.UnixNano()) - last
+	for _, rs := range trace.Batches {
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			for _, span := range ils.Spans {
+				span.StartTimeUnixNano += offset
+				span.EndTimeUnixNano += offset
+			}
+		}
+	}
+}
+
 func withLe(lbls labels.Labels, le float64) labels.Labels {
 	lb := labels.NewBuilder(lbls)
 	lb = lb.Set(labels.BucketLabel, strconv.FormatFloat(le, 'f', -1, 64))
 	return lb.Labels()
 }
+
+func stringKV(key, value string) *v1_common.KeyValue {
+	return &v1_common.KeyValue{
+		Key:   key,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+// roundEdges rounds the latency sums to avoid floating point errors
+func roundEdges(edges []*tempopb.ServiceGraphEdge) []*tempopb.ServiceGraphEdge {
+	for _, e := range edges {
+		e.ClientLatencySum = math.Round(e.ClientLatencySum*1000) / 1000
+		e.ServerLatencySum = math.Round(e.ServerLatencySum*1000) / 1000
+	}
+	return edges
+}
@@ -2,6 +2,20 @@ package store
 
 import "time"
 
+// ConnectionType is the kind of connection an Edge represents
+type ConnectionType string
+
+const (
+	// Unset is a request/response edge between a client and a server span
+	Unset ConnectionType = ""
+	// MessagingSystem is an edge between a producer and a consumer span
+	MessagingSystem ConnectionType = "messaging_system"
+	// Database is an edge between a client span and an uninstrumented database
+	Database ConnectionType = "database"
+	// VirtualNode is an edge between a client span and an uninstrumented service
+	VirtualNode ConnectionType = "virtual_node"
+)
+
 // Edge is an Edge between two nodes in the graph
 type Edge struct {
 	key string
@@ -14,9 +28,19 @@ type Edge struct {
 	// the Edge will be considered as failed.
 	Failed bool
 
+	ConnectionType ConnectionType
+
+	// PeerNode is the name of the server node inferred from the client span attributes.
+	// It is used if the Edge expires without a server span.
+	PeerNode           string
+	PeerConnectionType ConnectionType
+
 	// Additional dimension to add to the metrics
 	Dimensions map[string]string
 
+	// EndTime is the latest end time of the spans of the Edge.
+	EndTime time.Time
+
 	// expiration is the time at which the Edge expires, expressed as Unix time
 	expiration int64
 }
@@ -0,0 +1,21 @@
+package spanevents
+
+import (
+	"flag"
+)
+
+const (
+	Name = "span-events"
+)
+
+type Config struct {
+	// Additional dimensions (labels) to be added to the metric, along with the default ones
+	// (service, span_name, event_name and exception_type). Dimensions are searched for in the
+	// attributes of the event, the span and the resource, in this order.
+	Dimensions []string `yaml:"dimensions"`
+	// EventNames limits the events that are counted. By default all events are counted.
+	EventNames []string `yaml:"event_names"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+}
@@ -0,0 +1,98 @@
+package spanevents
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/prometheus/util/strutil"
+	semconv "go.opentelemetry.io/collector/model/semconv/v1.5.0"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	tempo_util "github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	metricEventsTotal = "traces_spanevents_total"
+)
+
+type processor struct {
+	cfg Config
+
+	eventNames map[string]struct{}
+
+	spanEventsTotal registry.Counter
+}
+
+func New(cfg Config, registry registry.Registry) gen.Processor {
+	labels := []string{"service", "span_name", "event_name", "exception_type"}
+	for _, d := range cfg.Dimensions {
+		labels = append(labels, strutil.SanitizeLabelName(d))
+	}
+
+	var eventNames map[string]struct{}
+	if len(cfg.EventNames) > 0 {
+		eventNames = make(map[string]struct{}, len(cfg.EventNames))
+		for _, name := range cfg.EventNames {
+			eventNames[name] = struct{}{}
+		}
+	}
+
+	return &processor{
+		cfg:             cfg,
+		eventNames:      eventNames,
+		spanEventsTotal: registry.NewCounter(metricEventsTotal, labels),
+	}
+}
+
+func (p *processor) Name() string { return Name }
+
+func (p *processor) PushSpans(ctx context.Context, req *tempopb.PushSpansRequest) {
+	span, _ := opentracing.StartSpanFromContext(ctx, "spanevents.PushSpans")
+	defer span.Finish()
+
+	p.aggregateMetrics(req.Batches)
+}
+
+func (p *processor) Shutdown(_ context.Context) {
+}
+
+func (p *processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
+	for _, rs := range resourceSpans {
+		// already extract service name, so we only have to do it once per batch of spans
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+
+		for _, ils := range rs.InstrumentationLibrarySpans {
+			for _, span := range ils.Spans {
+				for _, event := range span.Events {
+					p.aggregateMetricsForEvent(svcName, rs.Resource.Attributes, span, event)
+				}
+			}
+		}
+	}
+}
+
+func (p *processor) aggregateMetricsForEvent(svcName string, resourceAttributes []*v1_common.KeyValue, span *v1_trace.Span, event *v1_trace.Span_Event) {
+	if p.eventNames != nil {
+		if _, ok := p.eventNames[event.Name]; !ok {
+			return
+		}
+	}
+
+	// exception.type is only set on exception events, it's empty for other events
+	exceptionType, _ := processor_util.FindAttributeValue(semconv.AttributeExceptionType, event.Attributes)
+
+	labelValues := make([]string, 0, 4+len(p.cfg.Dimensions))
+	labelValues = append(labelValues, svcName, span.GetName(), event.Name, exceptionType)
+
+	for _, d := range p.cfg.Dimensions {
+		value, _ := processor_util.FindAttributeValue(d, event.Attributes, span.Attributes, resourceAttributes)
+		labelValues = append(labelValues, value)
+	}
+
+	p.spanEventsTotal.IncWithExemplar(registry.NewLabelValues(labelValues), 1, tempo_util.TraceIDToHexString(span.TraceId))
+}
@@ -0,0 +1,107 @@
+package spanevents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestSpanEvents(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+	p := New(cfg, testRegistry)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(3, nil)
+	for _, ils := range batch.InstrumentationLibrarySpans {
+		for _, s := range ils.Spans {
+			s.Events = []*v1_trace.Span_Event{
+				{Name: "exception", Attributes: []*v1_common.KeyValue{stringKV("exception.type", "java.lang.NullPointerException")}},
+				{Name: "exception", Attributes: []*v1_common.KeyValue{stringKV("exception.type", "java.io.IOException")}},
+				{Name: "cache.miss"},
+			}
+		}
+	}
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{batch}})
+
+	assert.Equal(t, 3.0, testRegistry.Query("traces_spanevents_total", eventLabels("exception", "java.lang.NullPointerException")))
+	assert.Equal(t, 3.0, testRegistry.Query("traces_spanevents_total", eventLabels("exception", "java.io.IOException")))
+	assert.Equal(t, 3.0, testRegistry.Query("traces_spanevents_total", eventLabels("cache.miss", "")))
+}
+
+func TestSpanEvents_eventNamesAndDimensions(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.EventNames = []string{"exception"}
+	cfg.Dimensions = []string{"exception.escaped", "http.method"}
+
+	p := New(cfg, testRegistry)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(1, nil)
+	for _, ils := range batch.InstrumentationLibrarySpans {
+		for _, s := range ils.Spans {
+			s.Attributes = append(s.Attributes, stringKV("http.method", "GET"))
+			s.Events = []*v1_trace.Span_Event{
+				{Name: "exception", Attributes: []*v1_common.KeyValue{
+					stringKV("exception.type", "TimeoutError"),
+					stringKV("exception.escaped", "true"),
+				}},
+				{Name: "cache.miss"},
+			}
+		}
+	}
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1_trace.ResourceSpans{batch}})
+
+	lbls := labels.FromMap(map[string]string{
+		"service":           "test-service",
+		"span_name":         "test",
+		"event_name":        "exception",
+		"exception_type":    "TimeoutError",
+		"exception_escaped": "true",
+		"http_method":       "GET",
+	})
+	assert.Equal(t, 1.0, testRegistry.Query("traces_spanevents_total", lbls))
+
+	// cache.miss is not in event_names
+	lbls = labels.FromMap(map[string]string{
+		"service":           "test-service",
+		"span_name":         "test",
+		"event_name":        "cache.miss",
+		"exception_type":    "",
+		"exception_escaped": "",
+		"http_method":       "GET",
+	})
+	assert.Equal(t, 0.0, testRegistry.Query("traces_spanevents_total", lbls))
+}
+
+func eventLabels(eventName, exceptionType string) labels.Labels {
+	return labels.FromMap(map[string]string{
+		"service":        "test-service",
+		"span_name":      "test",
+		"event_name":     eventName,
+		"exception_type": exceptionType,
+	})
+}
+
+func stringKV(k, v string) *v1_common.KeyValue {
+	return &v1_common.KeyValue{
+		Key:   k,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}},
+	}
+}
@@ -4,21 +4,46 @@ import (
 	"flag"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/spanfilter"
 )
 
 const (
 	Name = "span-metrics"
+
+	// HistogramTypeClassic uses a histogram with the fixed buckets HistogramBuckets.
+	HistogramTypeClassic = "classic"
+	// HistogramTypeExponential uses a histogram with fixed exponential buckets, their resolution is
+	// derived from ExponentialHistogramMaxBuckets. The buckets do not scale with the observed values.
+	HistogramTypeExponential = "exponential"
 )
 
 type Config struct {
 	// Buckets for latency histogram in seconds.
 	HistogramBuckets []float64 `yaml:"histogram_buckets"`
+	// HistogramType is either classic or exponential.
+	HistogramType string `yaml:"histogram_type"`
+	// Max amount of buckets per series of the exponential latency histogram.
+	ExponentialHistogramMaxBuckets int `yaml:"exponential_histogram_max_buckets"`
 	// Additional dimensions (labels) to be added to the metric,
 	// along with the default ones (service, span_name, span_kind and span_status).
 	Dimensions []string `yaml:"dimensions"`
+	// Filter selects the spans that are counted, by default all spans are counted.
+	Filter spanfilter.Config `yaml:"filter"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	// TODO: Revisit this default value.
 	cfg.HistogramBuckets = prometheus.ExponentialBuckets(0.002, 2, 12)
+	cfg.HistogramType = HistogramTypeClassic
+	cfg.ExponentialHistogramMaxBuckets = 40
+}
+
+// durationBuckets returns the buckets of the latency histogram.
+func (cfg *Config) durationBuckets() []float64 {
+	if cfg.HistogramType == HistogramTypeExponential {
+		return registry.ExponentialBuckets(cfg.ExponentialHistogramMaxBuckets)
+	}
+	return cfg.HistogramBuckets
 }
@@ -2,6 +2,7 @@ package spanmetrics
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/opentracing/opentracing-go"
@@ -10,6 +11,7 @@ import (
 	gen "github.com/grafana/tempo/modules/generator/processor"
 	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
 	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/spanfilter"
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
 	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
@@ -27,22 +29,33 @@ type processor struct {
 	spanMetricsCallsTotal      registry.Counter
 	spanMetricsDurationSeconds registry.Histogram
 
+	filter *spanfilter.Filter
+
 	// for testing
 	now func() time.Time
 }
 
-func New(cfg Config, registry registry.Registry) gen.Processor {
+func New(cfg Config, registry registry.Registry) (gen.Processor, error) {
+	filter, err := spanfilter.New(cfg.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
 	labels := []string{"service", "span_name", "span_kind", "span_status"}
 	for _, d := range cfg.Dimensions {
 		labels = append(labels, strutil.SanitizeLabelName(d))
 	}
 
-	return &processor{
-		cfg:                        cfg,
-		spanMetricsCallsTotal:      registry.NewCounter(metricCallsTotal, labels),
-		spanMetricsDurationSeconds: registry.NewHistogram(metricDurationSeconds, labels, cfg.HistogramBuckets),
-		now:                        time.Now,
+	p := &processor{
+		cfg:                   cfg,
+		spanMetricsCallsTotal: registry.NewCounter(metricCallsTotal, labels),
+		filter:                filter,
+		now:                   time.Now,
 	}
+
+	p.spanMetricsDurationSeconds = registry.NewHistogram(metricDurationSeconds, labels, cfg.durationBuckets())
+
+	return p, nil
 }
 
 func (p *processor) Name() string { return Name }
@@ -64,6 +77,9 @@ func (p *processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
 
 		for _, ils := range rs.InstrumentationLibrarySpans {
 			for _, span := range ils.Spans {
+				if !p.filter.Keep(svcName, rs.Resource.Attributes, span) {
+					continue
+				}
 				p.aggregateMetricsForSpan(svcName, rs.Resource, span)
 			}
 		}
@@ -9,8 +9,10 @@ import (
 
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/spanfilter"
 	"github.com/grafana/tempo/pkg/tempopb"
 	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
@@ -24,7 +26,8 @@ func TestSpanMetrics(t *testing.T) {
 	cfg.RegisterFlagsAndApplyDefaults("", nil)
 	cfg.HistogramBuckets = []float64{0.5, 1}
 
-	p := New(cfg, testRegistry)
+	p, err := New(cfg, testRegistry)
+	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
 	// TODO give these spans some duration so we can verify latencies are recorded correctly, in fact we should also test with various span names etc.
@@ -50,6 +53,35 @@ func TestSpanMetrics(t *testing.T) {
 	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_duration_seconds_sum", lbls))
 }
 
+func TestSpanMetrics_exponentialHistogram(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.HistogramType = HistogramTypeExponential
+
+	p, err := New(cfg, testRegistry)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(10, nil)
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	lbls := labels.FromMap(map[string]string{
+		"service":     "test-service",
+		"span_name":   "test",
+		"span_kind":   "SPAN_KIND_CLIENT",
+		"span_status": "STATUS_CODE_OK",
+	})
+
+	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_calls_total", lbls))
+
+	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_duration_seconds_bucket", withLe(lbls, math.Inf(1))))
+	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_duration_seconds_count", lbls))
+	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_duration_seconds_sum", lbls))
+}
+
 func TestSpanMetrics_dimensions(t *testing.T) {
 	testRegistry := registry.NewTestRegistry()
 
@@ -58,7 +90,8 @@ func TestSpanMetrics_dimensions(t *testing.T) {
 	cfg.HistogramBuckets = []float64{0.5, 1}
 	cfg.Dimensions = []string{"foo", "bar", "does-not-exist"}
 
-	p := New(cfg, testRegistry)
+	p, err := New(cfg, testRegistry)
+	require.NoError(t, err)
 	defer p.Shutdown(context.Background())
 
 	// TODO create some spans that are missing the custom dimensions/tags
@@ -101,6 +134,60 @@ func TestSpanMetrics_dimensions(t *testing.T) {
 	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_duration_seconds_sum", lbls))
 }
 
+func TestSpanMetrics_filter(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Filter = spanfilter.Config{
+		Exclude: []spanfilter.Match{{SpanKinds: []string{"SPAN_KIND_CLIENT"}}},
+	}
+
+	p, err := New(cfg, testRegistry)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(10, nil)
+	i := 0
+	for _, ils := range batch.InstrumentationLibrarySpans {
+		for _, s := range ils.Spans {
+			if i%2 == 0 {
+				s.Kind = trace_v1.Span_SPAN_KIND_SERVER
+			}
+			i++
+		}
+	}
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	serverLbls := labels.FromMap(map[string]string{
+		"service":     "test-service",
+		"span_name":   "test",
+		"span_kind":   "SPAN_KIND_SERVER",
+		"span_status": "STATUS_CODE_OK",
+	})
+	clientLbls := labels.FromMap(map[string]string{
+		"service":     "test-service",
+		"span_name":   "test",
+		"span_kind":   "SPAN_KIND_CLIENT",
+		"span_status": "STATUS_CODE_OK",
+	})
+
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanmetrics_calls_total", serverLbls))
+	assert.Equal(t, 0.0, testRegistry.Query("traces_spanmetrics_calls_total", clientLbls))
+}
+
+func TestSpanMetrics_invalidFilter(t *testing.T) {
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Filter = spanfilter.Config{
+		Include: []spanfilter.Match{{Services: []string{"("}}},
+	}
+
+	_, err := New(cfg, registry.NewTestRegistry())
+	assert.Error(t, err)
+}
+
 func withLe(lbls labels.Labels, le float64) labels.Labels {
 	lb := labels.NewBuilder(lbls)
 	lb = lb.Set(labels.BucketLabel, strconv.FormatFloat(le, 'f', -1, 64))
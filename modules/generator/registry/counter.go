@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
 	"go.uber.org/atomic"
@@ -18,8 +19,11 @@ type counter struct {
 	seriesMtx sync.RWMutex
 	series    map[uint64]*counterSeries
 
-	onAddSeries    func(count uint32) bool
-	onRemoveSeries func(count uint32)
+	// overflow are the label values of the series recording values of series exceeding the limits
+	overflow *LabelValues
+
+	onAddSeries    func(labelValues []string, count uint32) bool
+	onRemoveSeries func(labelValues []string, count uint32)
 }
 
 type counterSeries struct {
@@ -27,31 +31,42 @@ type counterSeries struct {
 	labelValues []string
 	value       *atomic.Float64
 	lastUpdated *atomic.Int64
+
+	// exemplarMtx guards the exemplar, a single traceID with the value it was recorded with. Both are
+	// updated together so the value always belongs to the traceID.
+	exemplarMtx   sync.Mutex
+	exemplar      string
+	exemplarValue float64
 }
 
 var _ Counter = (*counter)(nil)
 var _ metric = (*counter)(nil)
 
-func newCounter(name string, labels []string, onAddSeries func(uint32) bool, onRemoveSeries func(count uint32)) *counter {
+func newCounter(name string, labels []string, onAddSeries func(labelValues []string, count uint32) bool, onRemoveSeries func(labelValues []string, count uint32)) *counter {
 	if onAddSeries == nil {
-		onAddSeries = func(uint32) bool {
+		onAddSeries = func([]string, uint32) bool {
 			return true
 		}
 	}
 	if onRemoveSeries == nil {
-		onRemoveSeries = func(uint32) {}
+		onRemoveSeries = func([]string, uint32) {}
 	}
 
 	return &counter{
 		name:           name,
 		labels:         labels,
 		series:         make(map[uint64]*counterSeries),
+		overflow:       newOverflowLabelValues(len(labels)),
 		onAddSeries:    onAddSeries,
 		onRemoveSeries: onRemoveSeries,
 	}
 }
 
 func (c *counter) Inc(labelValues *LabelValues, value float64) {
+	c.IncWithExemplar(labelValues, value, "")
+}
+
+func (c *counter) IncWithExemplar(labelValues *LabelValues, value float64, traceID string) {
 	if value < 0 {
 		panic("counter can only increase")
 	}
@@ -66,40 +81,68 @@ func (c *counter) Inc(labelValues *LabelValues, value float64) {
 	c.seriesMtx.RUnlock()
 
 	if ok {
-		c.updateSeries(s, value)
+		c.updateSeries(s, value, traceID)
 		return
 	}
 
-	if !c.onAddSeries(1) {
+	if !c.onAddSeries(labelValues.getValues(), 1) {
+		// record the value in the overflow series so it isn't lost
+		if labelValues != c.overflow {
+			c.IncWithExemplar(c.overflow, value, traceID)
+		}
 		return
 	}
 
-	newSeries := c.newSeries(labelValues, value)
+	newSeries := c.newSeries(labelValues, value, traceID)
 
 	c.seriesMtx.Lock()
 	defer c.seriesMtx.Unlock()
 
 	s, ok = c.series[hash]
 	if ok {
-		c.updateSeries(s, value)
+		c.onRemoveSeries(labelValues.getValues(), 1)
+		c.updateSeries(s, value, traceID)
 		return
 	}
 	c.series[hash] = newSeries
 }
 
-func (c *counter) newSeries(labelValues *LabelValues, value float64) *counterSeries {
+func (c *counter) newSeries(labelValues *LabelValues, value float64, traceID string) *counterSeries {
 	return &counterSeries{
-		labelValues: labelValues.getValuesCopy(),
-		value:       atomic.NewFloat64(value),
-		lastUpdated: atomic.NewInt64(time.Now().UnixMilli()),
+		labelValues:   labelValues.getValuesCopy(),
+		value:         atomic.NewFloat64(value),
+		lastUpdated:   atomic.NewInt64(time.Now().UnixMilli()),
+		exemplar:      traceID,
+		exemplarValue: value,
 	}
 }
 
-func (c *counter) updateSeries(s *counterSeries, value float64) {
+func (c *counter) updateSeries(s *counterSeries, value float64, traceID string) {
 	s.value.Add(value)
+	if traceID != "" {
+		s.setExemplar(traceID, value)
+	}
 	s.lastUpdated.Store(time.Now().UnixMilli())
 }
 
+func (s *counterSeries) setExemplar(traceID string, value float64) {
+	s.exemplarMtx.Lock()
+	defer s.exemplarMtx.Unlock()
+
+	s.exemplar = traceID
+	s.exemplarValue = value
+}
+
+// takeExemplar returns the exemplar and clears it so it's only emitted once.
+func (s *counterSeries) takeExemplar() (traceID string, value float64) {
+	s.exemplarMtx.Lock()
+	defer s.exemplarMtx.Unlock()
+
+	traceID, value = s.exemplar, s.exemplarValue
+	s.exemplar = ""
+	return traceID, value
+}
+
 func (c *counter) collectMetrics(appender storage.Appender, timeMs int64, externalLabels map[string]string) (activeSeries int, err error) {
 	c.seriesMtx.RLock()
 	defer c.seriesMtx.RUnlock()
@@ -122,12 +165,25 @@ func (c *counter) collectMetrics(appender storage.Appender, timeMs int64, extern
 			lb.Set(name, s.labelValues[i])
 		}
 
-		_, err = appender.Append(0, lb.Labels(), timeMs, s.value.Load())
+		ref, err := appender.Append(0, lb.Labels(), timeMs, s.value.Load())
 		if err != nil {
-			return
+			return activeSeries, err
 		}
 
-		// TODO support exemplars
+		ex, exValue := s.takeExemplar()
+		if ex != "" {
+			_, err = appender.AppendExemplar(ref, lb.Labels(), exemplar.Exemplar{
+				Labels: []labels.Label{{
+					Name:  "traceID",
+					Value: ex,
+				}},
+				Value: exValue,
+				Ts:    timeMs,
+			})
+			if err != nil {
+				return activeSeries, err
+			}
+		}
 	}
 
 	return
@@ -140,7 +196,7 @@ func (c *counter) removeStaleSeries(staleTimeMs int64) {
 	for hash, s := range c.series {
 		if s.lastUpdated.Load() < staleTimeMs {
 			delete(c.series, hash)
-			c.onRemoveSeries(1)
+			c.onRemoveSeries(s.labelValues, 1)
 		}
 	}
 }
@@ -1,18 +1,22 @@
 package registry
 
 import (
+	"fmt"
 	"math/rand"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 )
 
 func Test_counter(t *testing.T) {
 	var seriesAdded int
-	onAdd := func(count uint32) bool {
+	onAdd := func(labelValues []string, count uint32) bool {
 		seriesAdded++
 		return true
 	}
@@ -58,7 +62,7 @@ func Test_counter_invalidLabelValues(t *testing.T) {
 
 func Test_counter_cantAdd(t *testing.T) {
 	canAdd := false
-	onAdd := func(count uint32) bool {
+	onAdd := func(labelValues []string, count uint32) bool {
 		assert.Equal(t, uint32(1), count)
 		return canAdd
 	}
@@ -92,9 +96,28 @@ func Test_counter_cantAdd(t *testing.T) {
 	collectMetricAndAssert(t, c, collectionTimeMs, nil, 2, expectedSamples, nil)
 }
 
+func Test_counter_overflow(t *testing.T) {
+	onAdd := func(labelValues []string, count uint32) bool {
+		return isOverflowLabelValues(labelValues) || labelValues[0] == "value-1"
+	}
+
+	c := newCounter("my_counter", []string{"label"}, onAdd, nil)
+
+	c.Inc(NewLabelValues([]string{"value-1"}), 1.0)
+	c.Inc(NewLabelValues([]string{"value-2"}), 2.0)
+	c.Inc(NewLabelValues([]string{"value-3"}), 3.0)
+
+	collectionTimeMs := time.Now().UnixMilli()
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "my_counter", "label": "value-1"}, collectionTimeMs, 1),
+		newSample(map[string]string{"__name__": "my_counter", "label": "__overflow__"}, collectionTimeMs, 5),
+	}
+	collectMetricAndAssert(t, c, collectionTimeMs, nil, 2, expectedSamples, nil)
+}
+
 func Test_counter_removeStaleSeries(t *testing.T) {
 	var removedSeries int
-	onRemove := func(count uint32) {
+	onRemove := func(labelValues []string, count uint32) {
 		assert.Equal(t, uint32(1), count)
 		removedSeries++
 	}
@@ -147,6 +170,72 @@ func Test_counter_externalLabels(t *testing.T) {
 	collectMetricAndAssert(t, c, collectionTimeMs, map[string]string{"external_label": "external_value"}, 2, expectedSamples, nil)
 }
 
+func Test_counter_exemplars(t *testing.T) {
+	c := newCounter("my_counter", []string{"label"}, nil, nil)
+
+	c.IncWithExemplar(NewLabelValues([]string{"value-1"}), 1.0, "trace-1")
+	c.IncWithExemplar(NewLabelValues([]string{"value-1"}), 2.0, "trace-2")
+	c.Inc(NewLabelValues([]string{"value-2"}), 1.0)
+
+	collectionTimeMs := time.Now().UnixMilli()
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "my_counter", "label": "value-1"}, collectionTimeMs, 3),
+		newSample(map[string]string{"__name__": "my_counter", "label": "value-2"}, collectionTimeMs, 1),
+	}
+	expectedExemplars := []exemplarSample{
+		newExemplar(map[string]string{"__name__": "my_counter", "label": "value-1"}, exemplar.Exemplar{
+			Labels: labels.FromMap(map[string]string{"traceID": "trace-2"}),
+			Value:  2.0,
+			Ts:     collectionTimeMs,
+		}),
+	}
+	collectMetricAndAssert(t, c, collectionTimeMs, nil, 2, expectedSamples, expectedExemplars)
+
+	// exemplars are only emitted once
+	collectionTimeMs = time.Now().UnixMilli()
+	for i := range expectedSamples {
+		expectedSamples[i].t = collectionTimeMs
+	}
+	collectMetricAndAssert(t, c, collectionTimeMs, nil, 2, expectedSamples, nil)
+}
+
+func Test_counter_exemplarsConcurrency(t *testing.T) {
+	c := newCounter("my_counter", []string{"label"}, nil, nil)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= 4; i++ {
+		wg.Add(1)
+		go func(value int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				c.IncWithExemplar(NewLabelValues([]string{"value-1"}), float64(value), fmt.Sprintf("trace-%d", value))
+			}
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// the value of every exemplar belongs to its traceID
+	for collecting := true; collecting; {
+		select {
+		case <-done:
+			collecting = false
+		default:
+		}
+
+		appender := &capturingAppender{}
+		_, err := c.collectMetrics(appender, 0, nil)
+		require.NoError(t, err)
+		for _, e := range appender.exemplars {
+			assert.Equal(t, fmt.Sprintf("trace-%g", e.e.Value), e.e.Labels.Get("traceID"))
+		}
+	}
+}
+
 func Test_counter_concurrencyDataRace(t *testing.T) {
 	c := newCounter("my_counter", []string{"label"}, nil, nil)
 
@@ -0,0 +1,40 @@
+package registry
+
+import (
+	"math"
+)
+
+const (
+	// exponentialBucketsMinExponent and exponentialBucketsMaxExponent are the powers of two the
+	// exponential buckets span, from ~1ms to ~4m when observing seconds.
+	exponentialBucketsMinExponent = -10
+	exponentialBucketsMaxExponent = 8
+
+	// exponentialBucketsMaxSchema is the highest resolution, at schema 2 every power of two is split
+	// in 4 buckets. Every bucket is a separate series, so the resolution is kept low.
+	exponentialBucketsMaxSchema = 2
+)
+
+// ExponentialBuckets returns the upper bounds of an exponential bucket layout spanning
+// 2^exponentialBucketsMinExponent to 2^exponentialBucketsMaxExponent, to be passed to NewHistogram.
+// The bounds are 2^(i*2^-schema), using the highest schema which results in at most maxBuckets
+// buckets. Schema 0, one bucket per power of two, is the lowest resolution and schema 2 the highest,
+// so the layout has between 19 and 73 buckets.
+//
+// The result is a classic histogram with fixed buckets: the layout does not scale with the observed
+// values and every series has all buckets.
+func ExponentialBuckets(maxBuckets int) []float64 {
+	powers := exponentialBucketsMaxExponent - exponentialBucketsMinExponent
+
+	schema := 0
+	for schema < exponentialBucketsMaxSchema && powers<<(schema+1)+1 <= maxBuckets {
+		schema++
+	}
+
+	bucketsPerPower := 1 << schema
+	buckets := make([]float64, 0, powers*bucketsPerPower+1)
+	for i := exponentialBucketsMinExponent * bucketsPerPower; i <= exponentialBucketsMaxExponent*bucketsPerPower; i++ {
+		buckets = append(buckets, math.Exp2(float64(i)/float64(bucketsPerPower)))
+	}
+	return buckets
+}
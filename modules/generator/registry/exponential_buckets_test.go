@@ -0,0 +1,58 @@
+package registry
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialBuckets(t *testing.T) {
+	tests := []struct {
+		maxBuckets      int
+		expectedBuckets int
+	}{
+		{maxBuckets: 0, expectedBuckets: 19},
+		{maxBuckets: 19, expectedBuckets: 19},
+		{maxBuckets: 40, expectedBuckets: 37},
+		{maxBuckets: 73, expectedBuckets: 73},
+		{maxBuckets: 100_000, expectedBuckets: 73},
+	}
+
+	for _, tc := range tests {
+		buckets := ExponentialBuckets(tc.maxBuckets)
+		assert.Len(t, buckets, tc.expectedBuckets, "max buckets %d", tc.maxBuckets)
+
+		// the layout always spans the same range with growing buckets
+		assert.Equal(t, math.Exp2(exponentialBucketsMinExponent), buckets[0])
+		assert.Equal(t, math.Exp2(exponentialBucketsMaxExponent), buckets[len(buckets)-1])
+		for i := 1; i < len(buckets); i++ {
+			assert.Greater(t, buckets[i], buckets[i-1])
+		}
+	}
+
+	// at schema 1 every power of two is split in 2 buckets
+	buckets := ExponentialBuckets(40)
+	assert.Equal(t, 1.0, buckets[20])
+	assert.InDelta(t, math.Sqrt2, buckets[21], 1e-12)
+	assert.Equal(t, 2.0, buckets[22])
+}
+
+func TestExponentialBuckets_histogram(t *testing.T) {
+	var seriesAdded uint32
+	onAdd := func(labelValues []string, count uint32) bool {
+		seriesAdded += count
+		return true
+	}
+
+	h := newHistogram("my_histogram", []string{"label"}, ExponentialBuckets(40), onAdd, nil)
+
+	h.ObserveWithExemplar(NewLabelValues([]string{"value-1"}), 1.0, "")
+	h.ObserveWithExemplar(NewLabelValues([]string{"value-1"}), 1000.0, "")
+
+	// sum + count + +Inf and all buckets, whatever values are observed
+	assert.Equal(t, uint32(3+37), seriesAdded)
+
+	h.ObserveWithExemplar(NewLabelValues([]string{"value-1"}), 0.0001, "")
+	assert.Equal(t, uint32(3+37), seriesAdded)
+}
@@ -0,0 +1,164 @@
+package registry
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"go.uber.org/atomic"
+)
+
+type gauge struct {
+	name   string
+	labels []string
+
+	// seriesMtx is used to sync modifications to the map, not to the data in series
+	seriesMtx sync.RWMutex
+	series    map[uint64]*gaugeSeries
+
+	// overflow are the label values of the series recording values of series exceeding the limits
+	overflow *LabelValues
+
+	onAddSeries    func(labelValues []string, count uint32) bool
+	onRemoveSeries func(labelValues []string, count uint32)
+}
+
+type gaugeSeries struct {
+	// labelValues should not be modified after creation
+	labelValues []string
+	value       *atomic.Float64
+	lastUpdated *atomic.Int64
+}
+
+var _ Gauge = (*gauge)(nil)
+var _ metric = (*gauge)(nil)
+
+func newGauge(name string, labels []string, onAddSeries func(labelValues []string, count uint32) bool, onRemoveSeries func(labelValues []string, count uint32)) *gauge {
+	if onAddSeries == nil {
+		onAddSeries = func([]string, uint32) bool {
+			return true
+		}
+	}
+	if onRemoveSeries == nil {
+		onRemoveSeries = func([]string, uint32) {}
+	}
+
+	return &gauge{
+		name:           name,
+		labels:         labels,
+		series:         make(map[uint64]*gaugeSeries),
+		overflow:       newOverflowLabelValues(len(labels)),
+		onAddSeries:    onAddSeries,
+		onRemoveSeries: onRemoveSeries,
+	}
+}
+
+func (g *gauge) Set(labelValues *LabelValues, value float64) {
+	g.updateOrCreateSeries(labelValues, func(s *gaugeSeries) {
+		s.value.Store(value)
+	})
+}
+
+func (g *gauge) Inc(labelValues *LabelValues, value float64) {
+	g.updateOrCreateSeries(labelValues, func(s *gaugeSeries) {
+		s.value.Add(value)
+	})
+}
+
+func (g *gauge) updateOrCreateSeries(labelValues *LabelValues, update func(s *gaugeSeries)) {
+	if len(g.labels) != len(labelValues.getValues()) {
+		panic(fmt.Sprintf("length of given label values does not match with labels, labels: %v, label values: %v", g.labels, labelValues))
+	}
+
+	hash := labelValues.getHash()
+
+	g.seriesMtx.RLock()
+	s, ok := g.series[hash]
+	g.seriesMtx.RUnlock()
+
+	if ok {
+		g.updateSeries(s, update)
+		return
+	}
+
+	if !g.onAddSeries(labelValues.getValues(), 1) {
+		// record the value in the overflow series so it isn't lost
+		if labelValues != g.overflow {
+			g.updateOrCreateSeries(g.overflow, update)
+		}
+		return
+	}
+
+	newSeries := g.newSeries(labelValues, update)
+
+	g.seriesMtx.Lock()
+	defer g.seriesMtx.Unlock()
+
+	s, ok = g.series[hash]
+	if ok {
+		g.onRemoveSeries(labelValues.getValues(), 1)
+		g.updateSeries(s, update)
+		return
+	}
+	g.series[hash] = newSeries
+}
+
+func (g *gauge) newSeries(labelValues *LabelValues, update func(s *gaugeSeries)) *gaugeSeries {
+	s := &gaugeSeries{
+		labelValues: labelValues.getValuesCopy(),
+		value:       atomic.NewFloat64(0),
+		lastUpdated: atomic.NewInt64(0),
+	}
+	g.updateSeries(s, update)
+	return s
+}
+
+func (g *gauge) updateSeries(s *gaugeSeries, update func(s *gaugeSeries)) {
+	update(s)
+	s.lastUpdated.Store(time.Now().UnixMilli())
+}
+
+func (g *gauge) collectMetrics(appender storage.Appender, timeMs int64, externalLabels map[string]string) (activeSeries int, err error) {
+	g.seriesMtx.RLock()
+	defer g.seriesMtx.RUnlock()
+
+	activeSeries = len(g.series)
+
+	lbls := make(labels.Labels, 1+len(externalLabels)+len(g.labels))
+	lb := labels.NewBuilder(lbls)
+
+	// set metric name
+	lb.Set(labels.MetricName, g.name)
+	// set external labels
+	for name, value := range externalLabels {
+		lb.Set(name, value)
+	}
+
+	for _, s := range g.series {
+		// set series-specific labels
+		for i, name := range g.labels {
+			lb.Set(name, s.labelValues[i])
+		}
+
+		_, err = appender.Append(0, lb.Labels(), timeMs, s.value.Load())
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (g *gauge) removeStaleSeries(staleTimeMs int64) {
+	g.seriesMtx.Lock()
+	defer g.seriesMtx.Unlock()
+
+	for hash, s := range g.series {
+		if s.lastUpdated.Load() < staleTimeMs {
+			delete(g.series, hash)
+			g.onRemoveSeries(s.labelValues, 1)
+		}
+	}
+}
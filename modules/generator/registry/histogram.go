@@ -26,8 +26,11 @@ type histogram struct {
 	seriesMtx sync.RWMutex
 	series    map[uint64]*histogramSeries
 
-	onAddSerie    func(count uint32) bool
-	onRemoveSerie func(count uint32)
+	// overflow are the label values of the series recording values of series exceeding the limits
+	overflow *LabelValues
+
+	onAddSerie    func(labelValues []string, count uint32) bool
+	onRemoveSerie func(labelValues []string, count uint32)
 }
 
 type histogramSeries struct {
@@ -46,14 +49,14 @@ type histogramSeries struct {
 var _ Histogram = (*histogram)(nil)
 var _ metric = (*histogram)(nil)
 
-func newHistogram(name string, labels []string, buckets []float64, onAddSeries func(uint32) bool, onRemoveSeries func(count uint32)) *histogram {
+func newHistogram(name string, labels []string, buckets []float64, onAddSeries func(labelValues []string, count uint32) bool, onRemoveSeries func(labelValues []string, count uint32)) *histogram {
 	if onAddSeries == nil {
-		onAddSeries = func(uint32) bool {
+		onAddSeries = func([]string, uint32) bool {
 			return true
 		}
 	}
 	if onRemoveSeries == nil {
-		onRemoveSeries = func(uint32) {}
+		onRemoveSeries = func([]string, uint32) {}
 	}
 
 	// add +Inf bucket
@@ -72,6 +75,7 @@ func newHistogram(name string, labels []string, buckets []float64, onAddSeries f
 		buckets:       buckets,
 		bucketLabels:  bucketLabels,
 		series:        make(map[uint64]*histogramSeries),
+		overflow:      newOverflowLabelValues(len(labels)),
 		onAddSerie:    onAddSeries,
 		onRemoveSerie: onRemoveSeries,
 	}
@@ -93,7 +97,11 @@ func (h *histogram) ObserveWithExemplar(labelValues *LabelValues, value float64,
 		return
 	}
 
-	if !h.onAddSerie(h.activeSeriesPerHistogramSerie()) {
+	if !h.onAddSerie(labelValues.getValues(), h.activeSeriesPerHistogramSerie()) {
+		// record the value in the overflow series so it isn't lost
+		if labelValues != h.overflow {
+			h.ObserveWithExemplar(h.overflow, value, traceID)
+		}
 		return
 	}
 
@@ -104,6 +112,7 @@ func (h *histogram) ObserveWithExemplar(labelValues *LabelValues, value float64,
 
 	s, ok = h.series[hash]
 	if ok {
+		h.onRemoveSerie(labelValues.getValues(), h.activeSeriesPerHistogramSerie())
 		h.updateSeries(s, value, traceID)
 		return
 	}
@@ -222,7 +231,7 @@ func (h *histogram) removeStaleSeries(staleTimeMs int64) {
 	for hash, s := range h.series {
 		if s.lastUpdated.Load() < staleTimeMs {
 			delete(h.series, hash)
-			h.onRemoveSerie(h.activeSeriesPerHistogramSerie())
+			h.onRemoveSerie(s.labelValues, h.activeSeriesPerHistogramSerie())
 		}
 	}
 }
@@ -14,7 +14,7 @@ import (
 
 func Test_histogram(t *testing.T) {
 	var seriesAdded int
-	onAdd := func(count uint32) bool {
+	onAdd := func(labelValues []string, count uint32) bool {
 		seriesAdded++
 		return true
 	}
@@ -104,7 +104,7 @@ func Test_histogram_invalidLabelValues(t *testing.T) {
 
 func Test_histogram_cantAdd(t *testing.T) {
 	canAdd := false
-	onAdd := func(count uint32) bool {
+	onAdd := func(labelValues []string, count uint32) bool {
 		assert.Equal(t, uint32(5), count)
 		return canAdd
 	}
@@ -156,7 +156,7 @@ func Test_histogram_cantAdd(t *testing.T) {
 
 func Test_histogram_removeStaleSeries(t *testing.T) {
 	var removedSeries int
-	onRemove := func(count uint32) {
+	onRemove := func(labelValues []string, count uint32) {
 		assert.Equal(t, uint32(5), count)
 		removedSeries++
 	}
@@ -4,12 +4,24 @@ package registry
 type Registry interface {
 	NewCounter(name string, labels []string) Counter
 	NewHistogram(name string, labels []string, buckets []float64) Histogram
+	NewGauge(name string, labels []string) Gauge
 }
 
 // Counter
 // https://prometheus.io/docs/concepts/metric_types/#counter
 type Counter interface {
 	Inc(values *LabelValues, value float64)
+	// IncWithExemplar increments the counter with value. traceID will be added as exemplar.
+	IncWithExemplar(values *LabelValues, value float64, traceID string)
+}
+
+// Gauge
+// https://prometheus.io/docs/concepts/metric_types/#gauge
+type Gauge interface {
+	// Set sets the gauge to value.
+	Set(values *LabelValues, value float64)
+	// Inc adds value to the gauge, value can be negative.
+	Inc(values *LabelValues, value float64)
 }
 
 // Histogram
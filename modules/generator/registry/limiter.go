@@ -0,0 +1,198 @@
+package registry
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/go-kit/log/level"
+)
+
+// overflowLabelValue is the value of all labels of the overflow series. Series that can't be created
+// because a series limit is reached are recorded in the overflow series of their metric instead.
+const overflowLabelValue = "__overflow__"
+
+// newOverflowLabelValues returns the label values of the overflow series of a metric with labelCount
+// labels.
+func newOverflowLabelValues(labelCount int) *LabelValues {
+	values := make([]string, labelCount)
+	for i := range values {
+		values[i] = overflowLabelValue
+	}
+	labelValues := NewLabelValues(values)
+	// calculate the hash upfront, LabelValues is not safe to be hashed concurrently
+	labelValues.getHash()
+	return labelValues
+}
+
+// isOverflowLabelValues returns true if these are the label values of an overflow series. Metrics
+// without labels don't have an overflow series.
+func isOverflowLabelValues(values []string) bool {
+	if len(values) == 0 {
+		return false
+	}
+	for _, v := range values {
+		if v != overflowLabelValue {
+			return false
+		}
+	}
+	return true
+}
+
+// seriesLimiter tracks the active series of a single metric and enforces the per-metric, the
+// per-label and the per-tenant series limits. It also keeps the cardinality of every label.
+type seriesLimiter struct {
+	registry *ManagedRegistry
+	name     string
+	labels   []string
+
+	mtx          sync.Mutex
+	activeSeries uint32
+	// labelValues holds for every label the active series per label value
+	labelValues []map[string]uint32
+}
+
+func newSeriesLimiter(registry *ManagedRegistry, name string, labels []string) *seriesLimiter {
+	labelValues := make([]map[string]uint32, len(labels))
+	for i := range labelValues {
+		labelValues[i] = make(map[string]uint32)
+	}
+
+	return &seriesLimiter{
+		registry:    registry,
+		name:        name,
+		labels:      labels,
+		labelValues: labelValues,
+	}
+}
+
+// onAddSeries is called before count series with the given label values are created. Returns false if
+// the series should not be created. The overflow series is always allowed.
+func (l *seriesLimiter) onAddSeries(labelValues []string, count uint32) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if isOverflowLabelValues(labelValues) {
+		l.registry.addMetricSeries(count)
+	} else {
+		if !l.withinLimits(labelValues, count) {
+			l.registry.metricTotalSeriesLimited.Inc()
+			return false
+		}
+		if !l.registry.onAddMetricSeries(count) {
+			return false
+		}
+	}
+
+	l.activeSeries += count
+	for i, value := range labelValues {
+		l.labelValues[i][value] += count
+	}
+	return true
+}
+
+// withinLimits checks the per-metric and per-label limits. Must be called under lock.
+func (l *seriesLimiter) withinLimits(labelValues []string, count uint32) bool {
+	tenant := l.registry.tenant
+
+	maxActiveSeries := l.registry.overrides.MetricsGeneratorMaxActiveSeriesPerMetric(tenant)
+	if maxActiveSeries != 0 && l.activeSeries+count > maxActiveSeries {
+		level.Warn(l.registry.logger).Log("msg", "reached max active series per metric", "metric", l.name, "active_series", l.activeSeries, "max_active_series_per_metric", maxActiveSeries)
+		return false
+	}
+
+	maxLabelValues := l.registry.overrides.MetricsGeneratorMaxLabelValues(tenant)
+	if maxLabelValues == 0 {
+		return true
+	}
+	for i, value := range labelValues {
+		if _, ok := l.labelValues[i][value]; ok {
+			continue
+		}
+		// the overflow series doesn't count towards the values of a label
+		values := len(l.labelValues[i])
+		if _, ok := l.labelValues[i][overflowLabelValue]; ok {
+			values--
+		}
+		if uint32(values) >= maxLabelValues {
+			level.Warn(l.registry.logger).Log("msg", "reached max label values", "metric", l.name, "label", l.labels[i], "max_label_values", maxLabelValues)
+			return false
+		}
+	}
+	return true
+}
+
+// onRemoveSeries is called after count series with the given label values are removed.
+func (l *seriesLimiter) onRemoveSeries(labelValues []string, count uint32) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.activeSeries -= count
+	for i, value := range labelValues {
+		l.labelValues[i][value] -= count
+		if l.labelValues[i][value] == 0 {
+			delete(l.labelValues[i], value)
+		}
+	}
+
+	l.registry.onRemoveMetricSeries(count)
+}
+
+// MetricCardinality is the amount of active series of a metric and its labels.
+type MetricCardinality struct {
+	Name         string             `json:"name"`
+	ActiveSeries uint32             `json:"activeSeries"`
+	Labels       []LabelCardinality `json:"labels"`
+}
+
+// LabelCardinality is the amount of distinct values of a label and the values with the most active
+// series.
+type LabelCardinality struct {
+	Name      string                  `json:"name"`
+	Values    int                     `json:"values"`
+	TopValues []LabelValueCardinality `json:"topValues"`
+}
+
+type LabelValueCardinality struct {
+	Value        string `json:"value"`
+	ActiveSeries uint32 `json:"activeSeries"`
+}
+
+// cardinality returns the cardinality of the metric. At most limit values are returned per label.
+func (l *seriesLimiter) cardinality(limit int) MetricCardinality {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	c := MetricCardinality{
+		Name:         l.name,
+		ActiveSeries: l.activeSeries,
+		Labels:       make([]LabelCardinality, 0, len(l.labels)),
+	}
+
+	for i, name := range l.labels {
+		values := make([]LabelValueCardinality, 0, len(l.labelValues[i]))
+		for value, activeSeries := range l.labelValues[i] {
+			values = append(values, LabelValueCardinality{Value: value, ActiveSeries: activeSeries})
+		}
+		sort.Slice(values, func(i, j int) bool {
+			if values[i].ActiveSeries != values[j].ActiveSeries {
+				return values[i].ActiveSeries > values[j].ActiveSeries
+			}
+			return values[i].Value < values[j].Value
+		})
+		if len(values) > limit {
+			values = values[:limit]
+		}
+
+		c.Labels = append(c.Labels, LabelCardinality{
+			Name:      name,
+			Values:    len(l.labelValues[i]),
+			TopValues: values,
+		})
+	}
+
+	sort.SliceStable(c.Labels, func(i, j int) bool {
+		return c.Labels[i].Values > c.Labels[j].Values
+	})
+
+	return c
+}
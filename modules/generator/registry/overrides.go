@@ -8,6 +8,8 @@ import (
 
 type Overrides interface {
 	MetricsGeneratorMaxActiveSeries(userID string) uint32
+	MetricsGeneratorMaxActiveSeriesPerMetric(userID string) uint32
+	MetricsGeneratorMaxLabelValues(userID string) uint32
 	MetricsGeneratorCollectionInterval(userID string) time.Duration
 }
 
@@ -2,7 +2,9 @@ package registry
 
 import (
 	"context"
+	"math"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,6 +13,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/prometheus/storage"
+	tsdb_errors "github.com/prometheus/prometheus/tsdb/errors"
 	"go.uber.org/atomic"
 )
 
@@ -57,7 +60,9 @@ type ManagedRegistry struct {
 
 	metricsMtx sync.RWMutex
 	// TODO we should not allow duplicate metrics, make this map[name]metric?
-	metrics      []metric
+	metrics []metric
+	// limiters holds the seriesLimiter of every metric
+	limiters     map[metric]*seriesLimiter
 	activeSeries atomic.Uint32
 
 	appendable storage.Appendable
@@ -84,6 +89,40 @@ var _ Registry = (*ManagedRegistry)(nil)
 func New(cfg *Config, overrides Overrides, tenant string, appendable storage.Appendable, logger log.Logger) *ManagedRegistry {
 	instanceCtx, cancel := context.WithCancel(context.Background())
 
+	r := newManagedRegistry(cfg, overrides, tenant, appendable, logger)
+	r.onShutdown = cancel
+
+	r.metricActiveSeries = metricActiveSeries.WithLabelValues(tenant)
+	r.metricTotalSeriesAdded = metricTotalSeriesAdded.WithLabelValues(tenant)
+	r.metricTotalSeriesRemoved = metricTotalSeriesRemoved.WithLabelValues(tenant)
+	r.metricTotalSeriesLimited = metricTotalSeriesLimited.WithLabelValues(tenant)
+	r.metricTotalCollections = metricTotalCollections.WithLabelValues(tenant)
+	r.metricFailedCollections = metricFailedCollections.WithLabelValues(tenant)
+
+	go job(instanceCtx, r.collectMetrics, r.collectionInterval)
+	go job(instanceCtx, r.removeStaleSeries, constantInterval(5*time.Minute))
+
+	return r
+}
+
+// NewBackfill creates a ManagedRegistry that doesn't collect itself and doesn't remove stale series.
+// Samples are written with CollectAt at the given timestamps, this is used to backfill metrics from
+// historical spans. Its series are not reported in the metrics of the tenant.
+func NewBackfill(cfg *Config, overrides Overrides, tenant string, logger log.Logger) *ManagedRegistry {
+	r := newManagedRegistry(cfg, overrides, tenant, nil, logger)
+	r.onShutdown = func() {}
+
+	r.metricActiveSeries = prometheus.NewGauge(prometheus.GaugeOpts{Name: "backfill_active_series"})
+	r.metricTotalSeriesAdded = prometheus.NewCounter(prometheus.CounterOpts{Name: "backfill_series_added_total"})
+	r.metricTotalSeriesRemoved = prometheus.NewCounter(prometheus.CounterOpts{Name: "backfill_series_removed_total"})
+	r.metricTotalSeriesLimited = prometheus.NewCounter(prometheus.CounterOpts{Name: "backfill_series_limited_total"})
+	r.metricTotalCollections = prometheus.NewCounter(prometheus.CounterOpts{Name: "backfill_collections_total"})
+	r.metricFailedCollections = prometheus.NewCounter(prometheus.CounterOpts{Name: "backfill_collections_failed_total"})
+
+	return r
+}
+
+func newManagedRegistry(cfg *Config, overrides Overrides, tenant string, appendable storage.Appendable, logger log.Logger) *ManagedRegistry {
 	externalLabels := make(map[string]string)
 	for k, v := range cfg.ExternalLabels {
 		externalLabels[k] = v
@@ -91,50 +130,68 @@ func New(cfg *Config, overrides Overrides, tenant string, appendable storage.App
 	hostname, _ := os.Hostname()
 	externalLabels["instance"] = hostname
 
-	r := &ManagedRegistry{
-		onShutdown: cancel,
-
+	return &ManagedRegistry{
 		cfg:            cfg,
 		overrides:      overrides,
 		tenant:         tenant,
 		externalLabels: externalLabels,
 
+		limiters: make(map[metric]*seriesLimiter),
+
 		appendable: appendable,
 
-		logger:                   logger,
-		metricActiveSeries:       metricActiveSeries.WithLabelValues(tenant),
-		metricTotalSeriesAdded:   metricTotalSeriesAdded.WithLabelValues(tenant),
-		metricTotalSeriesRemoved: metricTotalSeriesRemoved.WithLabelValues(tenant),
-		metricTotalSeriesLimited: metricTotalSeriesLimited.WithLabelValues(tenant),
-		metricTotalCollections:   metricTotalCollections.WithLabelValues(tenant),
-		metricFailedCollections:  metricFailedCollections.WithLabelValues(tenant),
+		logger: logger,
 	}
-
-	go job(instanceCtx, r.collectMetrics, r.collectionInterval)
-	go job(instanceCtx, r.removeStaleSeries, constantInterval(5*time.Minute))
-
-	return r
 }
 
 func (r *ManagedRegistry) NewCounter(name string, labels []string) Counter {
-	c := newCounter(name, labels, r.onAddMetricSeries, r.onRemoveMetricSeries)
-	r.registerMetric(c)
+	l := newSeriesLimiter(r, name, labels)
+	c := newCounter(name, labels, l.onAddSeries, l.onRemoveSeries)
+	r.registerMetric(c, l)
 	return c
 }
 
 func (r *ManagedRegistry) NewHistogram(name string, labels []string, buckets []float64) Histogram {
-	h := newHistogram(name, labels, buckets, r.onAddMetricSeries, r.onRemoveMetricSeries)
-	r.registerMetric(h)
+	l := newSeriesLimiter(r, name, labels)
+	h := newHistogram(name, labels, buckets, l.onAddSeries, l.onRemoveSeries)
+	r.registerMetric(h, l)
 	return h
 }
 
-func (r *ManagedRegistry) registerMetric(m metric) {
+func (r *ManagedRegistry) NewGauge(name string, labels []string) Gauge {
+	l := newSeriesLimiter(r, name, labels)
+	g := newGauge(name, labels, l.onAddSeries, l.onRemoveSeries)
+	r.registerMetric(g, l)
+	return g
+}
+
+func (r *ManagedRegistry) registerMetric(m metric, l *seriesLimiter) {
 	r.metricsMtx.Lock()
 	defer r.metricsMtx.Unlock()
 
 	r.metrics = append(r.metrics, m)
+	r.limiters[m] = l
+}
+
+// unregisterMetric removes the metric from the registry and drops all its series.
+func (r *ManagedRegistry) unregisterMetric(m metric) {
+	r.metricsMtx.Lock()
+	defer r.metricsMtx.Unlock()
+
+	for i, registered := range r.metrics {
+		if registered == m {
+			r.metrics = append(r.metrics[:i], r.metrics[i+1:]...)
+			break
+		}
+	}
+
+	// removing all series as stale series calls onRemoveMetricSeries for each of them
+	m.removeStaleSeries(math.MaxInt64)
+
+	delete(r.limiters, m)
 }
 
+// onAddMetricSeries checks the per-tenant limit before count series are added.
 func (r *ManagedRegistry) onAddMetricSeries(count uint32) bool {
 	maxActiveSeries := r.overrides.MetricsGeneratorMaxActiveSeries(r.tenant)
 	if maxActiveSeries != 0 && r.activeSeries.Load()+count > maxActiveSeries {
@@ -143,11 +200,16 @@ func (r *ManagedRegistry) onAddMetricSeries(count uint32) bool {
 		return false
 	}
 
+	r.addMetricSeries(count)
+	return true
+}
+
+// addMetricSeries adds count series without checking the limits.
+func (r *ManagedRegistry) addMetricSeries(count uint32) {
 	r.activeSeries.Add(count)
 
 	r.metricTotalSeriesAdded.Add(float64(count))
 	r.metricActiveSeries.Add(float64(count))
-	return true
 }
 
 func (r *ManagedRegistry) onRemoveMetricSeries(count uint32) {
@@ -157,28 +219,57 @@ func (r *ManagedRegistry) onRemoveMetricSeries(count uint32) {
 	r.metricActiveSeries.Sub(float64(count))
 }
 
+// Cardinality returns the active series of all metrics and their labels, sorted by active series. At
+// most limit values are returned per label.
+func (r *ManagedRegistry) Cardinality(limit int) []MetricCardinality {
+	r.metricsMtx.RLock()
+	defer r.metricsMtx.RUnlock()
+
+	result := make([]MetricCardinality, 0, len(r.metrics))
+	for _, m := range r.metrics {
+		result = append(result, r.limiters[m].cardinality(limit))
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ActiveSeries > result[j].ActiveSeries
+	})
+
+	return result
+}
+
 func (r *ManagedRegistry) collectMetrics(ctx context.Context) {
 	r.metricsMtx.RLock()
 	defer r.metricsMtx.RUnlock()
 
-	var err error
-	defer func() {
-		r.metricTotalCollections.Inc()
-		if err != nil {
-			level.Error(r.logger).Log("msg", "collecting metrics failed", "err", err)
-			r.metricFailedCollections.Inc()
-		}
-	}()
+	activeSeries, err := r.collect(r.appendable.Appender(ctx), time.Now().UnixMilli())
 
-	var activeSeries uint32
+	r.metricTotalCollections.Inc()
+	if err != nil {
+		level.Error(r.logger).Log("msg", "collecting metrics failed", "err", err)
+		r.metricFailedCollections.Inc()
+		return
+	}
+
+	level.Info(r.logger).Log("msg", "collecting metrics", "active_series", activeSeries)
+}
+
+// CollectAt writes the current value of all series into appender with timestamp t.
+func (r *ManagedRegistry) CollectAt(appender storage.Appender, t time.Time) error {
+	r.metricsMtx.RLock()
+	defer r.metricsMtx.RUnlock()
 
-	appender := r.appendable.Appender(ctx)
-	collectionTimeMs := time.Now().UnixMilli()
+	_, err := r.collect(appender, t.UnixMilli())
+	return err
+}
+
+// collect writes all series into appender and commits. Must be called under a read lock.
+func (r *ManagedRegistry) collect(appender storage.Appender, timeMs int64) (uint32, error) {
+	var activeSeries uint32
 
 	for _, m := range r.metrics {
-		active, err := m.collectMetrics(appender, collectionTimeMs, r.externalLabels)
+		active, err := m.collectMetrics(appender, timeMs, r.externalLabels)
 		if err != nil {
-			return
+			return 0, tsdb_errors.NewMulti(err, appender.Rollback()).Err()
 		}
 		activeSeries += uint32(active)
 	}
@@ -187,12 +278,7 @@ func (r *ManagedRegistry) collectMetrics(ctx context.Context) {
 	r.activeSeries.Store(activeSeries)
 	r.metricActiveSeries.Set(float64(activeSeries))
 
-	err = appender.Commit()
-	if err != nil {
-		return
-	}
-
-	level.Info(r.logger).Log("msg", "collecting metrics", "active_series", activeSeries)
+	return activeSeries, appender.Commit()
 }
 
 func (r *ManagedRegistry) collectionInterval() time.Duration {
@@ -8,7 +8,10 @@ import (
 	"time"
 
 	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestManagedRegistry_concurrency(t *testing.T) {
@@ -89,6 +92,50 @@ func TestManagedRegistry_histogram(t *testing.T) {
 	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
 }
 
+func TestManagedRegistry_gauge(t *testing.T) {
+	appender := &capturingAppender{}
+
+	registry := New(&Config{}, &mockOverrides{}, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	gauge := registry.NewGauge("my_gauge", []string{"label"})
+
+	gauge.Set(NewLabelValues([]string{"value-1"}), 2.0)
+	gauge.Inc(NewLabelValues([]string{"value-1"}), -0.5)
+
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "my_gauge", "label": "value-1", "instance": mustGetHostname()}, 0, 1.5),
+	}
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+}
+
+func TestManagedRegistry_exponentialBuckets(t *testing.T) {
+	appender := &capturingAppender{}
+
+	registry := New(&Config{}, &mockOverrides{}, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	histogram := registry.NewHistogram("histogram", []string{"label"}, ExponentialBuckets(40))
+
+	histogram.ObserveWithExemplar(NewLabelValues([]string{"value-1"}), 2.0, "")
+
+	// every bucket of the layout is written
+	active, err := registry.metrics[0].collectMetrics(appender, 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3+len(ExponentialBuckets(40)), active)
+
+	values := map[string]float64{}
+	for _, s := range appender.samples {
+		if le := s.l.Get(labels.BucketLabel); le != "" {
+			values[le] = s.v
+		}
+	}
+	assert.Len(t, values, len(ExponentialBuckets(40))+1)
+	assert.Equal(t, 0.0, values["1.4142135623730951"])
+	assert.Equal(t, 1.0, values["2"])
+	assert.Equal(t, 1.0, values["+Inf"])
+}
+
 func TestManagedRegistry_removeStaleSeries(t *testing.T) {
 	appender := &capturingAppender{}
 
@@ -159,17 +206,208 @@ func TestManagedRegistry_maxSeries(t *testing.T) {
 	counter2 := registry.NewCounter("metric_2", nil)
 
 	counter1.Inc(NewLabelValues([]string{"value-1"}), 1.0)
-	// these series should be discarded
+	// these series exceed the limit, value-2 is recorded in the overflow series and metric_2 is discarded
 	counter1.Inc(NewLabelValues([]string{"value-2"}), 1.0)
+	counter1.Inc(NewLabelValues([]string{"value-3"}), 2.0)
 	counter2.Inc(nil, 1.0)
 
-	assert.Equal(t, uint32(1), registry.activeSeries.Load())
+	assert.Equal(t, uint32(2), registry.activeSeries.Load())
 	expectedSamples := []sample{
 		newSample(map[string]string{"__name__": "metric_1", "label": "value-1", "instance": mustGetHostname()}, 0, 1),
+		newSample(map[string]string{"__name__": "metric_1", "label": "__overflow__", "instance": mustGetHostname()}, 0, 3),
 	}
 	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
 }
 
+func TestManagedRegistry_maxSeriesPerMetric(t *testing.T) {
+	appender := &capturingAppender{}
+
+	overrides := &mockOverrides{
+		maxActiveSeriesPerMetric: 1,
+	}
+	registry := New(&Config{}, overrides, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	counter1 := registry.NewCounter("metric_1", []string{"label"})
+	counter2 := registry.NewCounter("metric_2", []string{"label"})
+
+	counter1.Inc(NewLabelValues([]string{"value-1"}), 1.0)
+	counter1.Inc(NewLabelValues([]string{"value-2"}), 1.0)
+	counter2.Inc(NewLabelValues([]string{"value-1"}), 1.0)
+
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "metric_1", "label": "value-1", "instance": mustGetHostname()}, 0, 1),
+		newSample(map[string]string{"__name__": "metric_1", "label": "__overflow__", "instance": mustGetHostname()}, 0, 1),
+		newSample(map[string]string{"__name__": "metric_2", "label": "value-1", "instance": mustGetHostname()}, 0, 1),
+	}
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+}
+
+func TestManagedRegistry_maxLabelValues(t *testing.T) {
+	appender := &capturingAppender{}
+
+	overrides := &mockOverrides{
+		maxLabelValues: 2,
+	}
+	registry := New(&Config{}, overrides, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	histogram := registry.NewHistogram("histogram", []string{"service", "span_name"}, []float64{1.0})
+
+	histogram.ObserveWithExemplar(NewLabelValues([]string{"svc-1", "span-1"}), 1.0, "")
+	histogram.ObserveWithExemplar(NewLabelValues([]string{"svc-1", "span-2"}), 1.0, "")
+	histogram.ObserveWithExemplar(NewLabelValues([]string{"svc-2", "span-1"}), 1.0, "")
+	// span-3 exceeds the limit of span_name
+	histogram.ObserveWithExemplar(NewLabelValues([]string{"svc-2", "span-3"}), 2.0, "")
+
+	lbls := func(service, spanName string, extra map[string]string) map[string]string {
+		m := map[string]string{"service": service, "span_name": spanName, "instance": mustGetHostname()}
+		for k, v := range extra {
+			m[k] = v
+		}
+		return m
+	}
+
+	var expectedSamples []sample
+	for _, series := range [][]string{{"svc-1", "span-1"}, {"svc-1", "span-2"}, {"svc-2", "span-1"}} {
+		expectedSamples = append(expectedSamples,
+			newSample(lbls(series[0], series[1], map[string]string{"__name__": "histogram_count"}), 0, 1),
+			newSample(lbls(series[0], series[1], map[string]string{"__name__": "histogram_sum"}), 0, 1),
+			newSample(lbls(series[0], series[1], map[string]string{"__name__": "histogram_bucket", "le": "1"}), 0, 1),
+			newSample(lbls(series[0], series[1], map[string]string{"__name__": "histogram_bucket", "le": "+Inf"}), 0, 1),
+		)
+	}
+	expectedSamples = append(expectedSamples,
+		newSample(lbls("__overflow__", "__overflow__", map[string]string{"__name__": "histogram_count"}), 0, 1),
+		newSample(lbls("__overflow__", "__overflow__", map[string]string{"__name__": "histogram_sum"}), 0, 2),
+		newSample(lbls("__overflow__", "__overflow__", map[string]string{"__name__": "histogram_bucket", "le": "1"}), 0, 0),
+		newSample(lbls("__overflow__", "__overflow__", map[string]string{"__name__": "histogram_bucket", "le": "+Inf"}), 0, 1),
+	)
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+}
+
+func TestManagedRegistry_maxLabelValues_overflowNotCounted(t *testing.T) {
+	appender := &capturingAppender{}
+
+	overrides := &mockOverrides{
+		maxLabelValues: 2,
+	}
+	registry := New(&Config{}, overrides, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	counter := registry.NewCounter("counter", []string{"service", "status"})
+
+	counter.Inc(NewLabelValues([]string{"svc-1", "ok"}), 1.0)
+	counter.Inc(NewLabelValues([]string{"svc-1", "error"}), 1.0)
+	// unset exceeds the limit of status and creates the overflow series
+	counter.Inc(NewLabelValues([]string{"svc-2", "unset"}), 1.0)
+	// the overflow value doesn't count towards the limit of service
+	counter.Inc(NewLabelValues([]string{"svc-2", "ok"}), 1.0)
+
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "counter", "service": "svc-1", "status": "ok", "instance": mustGetHostname()}, 0, 1),
+		newSample(map[string]string{"__name__": "counter", "service": "svc-1", "status": "error", "instance": mustGetHostname()}, 0, 1),
+		newSample(map[string]string{"__name__": "counter", "service": "svc-2", "status": "ok", "instance": mustGetHostname()}, 0, 1),
+		newSample(map[string]string{"__name__": "counter", "service": "__overflow__", "status": "__overflow__", "instance": mustGetHostname()}, 0, 1),
+	}
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+}
+
+func TestManagedRegistry_cardinality(t *testing.T) {
+	registry := New(&Config{}, &mockOverrides{}, "test", &noopAppender{}, log.NewNopLogger())
+	defer registry.Close()
+
+	counter := registry.NewCounter("counter", []string{"service", "status"})
+	histogram := registry.NewHistogram("histogram", []string{"service"}, []float64{1.0})
+	gauge := registry.NewGauge("gauge", nil)
+
+	counter.Inc(NewLabelValues([]string{"svc-1", "ok"}), 1.0)
+	counter.Inc(NewLabelValues([]string{"svc-1", "error"}), 1.0)
+	counter.Inc(NewLabelValues([]string{"svc-2", "ok"}), 1.0)
+	counter.Inc(NewLabelValues([]string{"svc-3", "ok"}), 1.0)
+	histogram.ObserveWithExemplar(NewLabelValues([]string{"svc-1"}), 1.0, "")
+	gauge.Set(nil, 1.0)
+
+	expected := []MetricCardinality{
+		{
+			Name:         "counter",
+			ActiveSeries: 4,
+			Labels: []LabelCardinality{
+				{Name: "service", Values: 3, TopValues: []LabelValueCardinality{{Value: "svc-1", ActiveSeries: 2}, {Value: "svc-2", ActiveSeries: 1}}},
+				{Name: "status", Values: 2, TopValues: []LabelValueCardinality{{Value: "ok", ActiveSeries: 3}, {Value: "error", ActiveSeries: 1}}},
+			},
+		},
+		{
+			Name:         "histogram",
+			ActiveSeries: 4,
+			Labels: []LabelCardinality{
+				{Name: "service", Values: 1, TopValues: []LabelValueCardinality{{Value: "svc-1", ActiveSeries: 4}}},
+			},
+		},
+		{
+			Name:         "gauge",
+			ActiveSeries: 1,
+			Labels:       []LabelCardinality{},
+		},
+	}
+	assert.Equal(t, expected, registry.Cardinality(2))
+
+	// unregistering a metric removes its cardinality
+	registry.unregisterMetric(histogram.(metric))
+	assert.Len(t, registry.Cardinality(2), 2)
+}
+
+func TestManagedRegistry_scopedRegistry(t *testing.T) {
+	appender := &capturingAppender{}
+
+	registry := New(&Config{}, &mockOverrides{}, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	counter := registry.NewCounter("my_counter", nil)
+	counter.Inc(nil, 1.0)
+
+	scoped := registry.NewScopedRegistry()
+	scopedCounter := scoped.NewCounter("my_scoped_counter", []string{"label"})
+	scopedCounter.Inc(NewLabelValues([]string{"value-1"}), 1.0)
+	scopedCounter.Inc(NewLabelValues([]string{"value-2"}), 1.0)
+	scopedHistogram := scoped.NewHistogram("my_scoped_histogram", nil, []float64{1.0})
+	scopedHistogram.ObserveWithExemplar(nil, 1.0, "")
+
+	// counter + 2 scoped counter series + histogram with sum, count, 1 and +Inf buckets
+	assert.Equal(t, uint32(7), registry.activeSeries.Load())
+
+	scoped.Close()
+
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "my_counter", "instance": mustGetHostname()}, 0, 1),
+	}
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+}
+
+func TestManagedRegistry_backfill(t *testing.T) {
+	registry := NewBackfill(&Config{}, &mockOverrides{}, "backfill-test", log.NewNopLogger())
+	defer registry.Close()
+
+	counter := registry.NewCounter("my_counter", []string{"label"})
+	counter.Inc(NewLabelValues([]string{"value-1"}), 1.0)
+
+	lbls := map[string]string{"__name__": "my_counter", "label": "value-1", "instance": mustGetHostname()}
+
+	appender := &capturingAppender{}
+	require.NoError(t, registry.CollectAt(appender, time.UnixMilli(1000)))
+	assert.True(t, appender.isCommitted)
+	assert.Equal(t, []sample{newSample(lbls, 1000, 1.0)}, appender.samples)
+
+	counter.Inc(NewLabelValues([]string{"value-1"}), 2.0)
+
+	appender = &capturingAppender{}
+	require.NoError(t, registry.CollectAt(appender, time.UnixMilli(16000)))
+	assert.Equal(t, []sample{newSample(lbls, 16000, 3.0)}, appender.samples)
+
+	// series of a backfill are not reported in the metrics of the tenant
+	assert.Equal(t, 0.0, testutil.ToFloat64(metricActiveSeries.WithLabelValues("backfill-test")))
+}
+
 func collectRegistryMetricsAndAssert(t *testing.T, r *ManagedRegistry, appender *capturingAppender, expectedSamples []sample) {
 	assert.Equal(t, uint32(len(expectedSamples)), r.activeSeries.Load())
 
@@ -186,7 +424,9 @@ func collectRegistryMetricsAndAssert(t *testing.T, r *ManagedRegistry, appender
 }
 
 type mockOverrides struct {
-	maxActiveSeries uint32
+	maxActiveSeries          uint32
+	maxActiveSeriesPerMetric uint32
+	maxLabelValues           uint32
 }
 
 var _ Overrides = (*mockOverrides)(nil)
@@ -195,6 +435,14 @@ func (m *mockOverrides) MetricsGeneratorMaxActiveSeries(userID string) uint32 {
 	return m.maxActiveSeries
 }
 
+func (m *mockOverrides) MetricsGeneratorMaxActiveSeriesPerMetric(userID string) uint32 {
+	return m.maxActiveSeriesPerMetric
+}
+
+func (m *mockOverrides) MetricsGeneratorMaxLabelValues(userID string) uint32 {
+	return m.maxLabelValues
+}
+
 func (m *mockOverrides) MetricsGeneratorCollectionInterval(userID string) time.Duration {
 	return 15 * time.Second
 }
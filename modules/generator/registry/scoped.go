@@ -0,0 +1,58 @@
+package registry
+
+import "sync"
+
+// ScopedRegistry creates metrics in a ManagedRegistry and keeps track of them, so all metrics
+// created through it can be removed at once. This is used to remove the metrics of a processor
+// when the processor is removed or rebuilt.
+type ScopedRegistry struct {
+	parent *ManagedRegistry
+
+	mtx     sync.Mutex
+	metrics []metric
+}
+
+var _ Registry = (*ScopedRegistry)(nil)
+
+// NewScopedRegistry returns a ScopedRegistry creating metrics in r.
+func (r *ManagedRegistry) NewScopedRegistry() *ScopedRegistry {
+	return &ScopedRegistry{
+		parent: r,
+	}
+}
+
+func (s *ScopedRegistry) NewCounter(name string, labels []string) Counter {
+	c := s.parent.NewCounter(name, labels)
+	s.track(c.(metric))
+	return c
+}
+
+func (s *ScopedRegistry) NewHistogram(name string, labels []string, buckets []float64) Histogram {
+	h := s.parent.NewHistogram(name, labels, buckets)
+	s.track(h.(metric))
+	return h
+}
+
+func (s *ScopedRegistry) NewGauge(name string, labels []string) Gauge {
+	g := s.parent.NewGauge(name, labels)
+	s.track(g.(metric))
+	return g
+}
+
+func (s *ScopedRegistry) track(m metric) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.metrics = append(s.metrics, m)
+}
+
+// Close removes all metrics created by this ScopedRegistry from the parent registry.
+func (s *ScopedRegistry) Close() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, m := range s.metrics {
+		s.parent.unregisterMetric(m)
+	}
+	s.metrics = nil
+}
@@ -42,6 +42,14 @@ func (t *TestRegistry) NewHistogram(name string, labels []string, buckets []floa
 	}
 }
 
+func (t *TestRegistry) NewGauge(name string, labels []string) Gauge {
+	return &testGauge{
+		name:     name,
+		labels:   labels,
+		registry: t,
+	}
+}
+
 func (t *TestRegistry) addToMetric(name string, lbls labels.Labels, value float64) {
 	if t == nil || t.metrics == nil {
 		return
@@ -49,6 +57,13 @@ func (t *TestRegistry) addToMetric(name string, lbls labels.Labels, value float6
 	t.metrics[name+lbls.String()] += value
 }
 
+func (t *TestRegistry) setMetric(name string, lbls labels.Labels, value float64) {
+	if t == nil || t.metrics == nil {
+		return
+	}
+	t.metrics[name+lbls.String()] = value
+}
+
 // Query returns the value of the given metric. Note this is a rather naive query engine, it's only
 // possible to query metrics by using the exact same labels as they were stored with.
 func (t *TestRegistry) Query(name string, lbls labels.Labels) float64 {
@@ -75,6 +90,10 @@ type testCounter struct {
 var _ Counter = (*testCounter)(nil)
 
 func (t testCounter) Inc(values *LabelValues, value float64) {
+	t.IncWithExemplar(values, value, "")
+}
+
+func (t testCounter) IncWithExemplar(values *LabelValues, value float64, traceID string) {
 	if value < 0 {
 		panic("counter can only increase")
 	}
@@ -88,6 +107,31 @@ func (t testCounter) Inc(values *LabelValues, value float64) {
 	t.registry.addToMetric(t.name, lbls, value)
 }
 
+type testGauge struct {
+	name     string
+	labels   []string
+	registry *TestRegistry
+}
+
+var _ Gauge = (*testGauge)(nil)
+
+func (t testGauge) Set(values *LabelValues, value float64) {
+	t.registry.setMetric(t.name, t.labelsFor(values), value)
+}
+
+func (t testGauge) Inc(values *LabelValues, value float64) {
+	t.registry.addToMetric(t.name, t.labelsFor(values), value)
+}
+
+func (t testGauge) labelsFor(values *LabelValues) labels.Labels {
+	lbls := make(labels.Labels, len(t.labels))
+	for i, label := range t.labels {
+		lbls[i] = labels.Label{Name: label, Value: values.values[i]}
+	}
+	sort.Sort(lbls)
+	return lbls
+}
+
 type testHistogram struct {
 	nameSum    string
 	nameCount  string
@@ -45,3 +45,22 @@ func TestTestRegistry_histogram(t *testing.T) {
 	assert.Equal(t, 3.0, testRegistry.Query("histogram_count", lbls))
 	assert.Equal(t, 5.5, testRegistry.Query("histogram_sum", lbls))
 }
+
+func TestTestRegistry_gauge(t *testing.T) {
+	testRegistry := NewTestRegistry()
+
+	gauge := testRegistry.NewGauge("gauge", []string{"foo", "bar"})
+
+	labelValues := NewLabelValues([]string{"foo-value", "bar-value"})
+	gauge.Set(labelValues, 1.0)
+	gauge.Inc(labelValues, 2.0)
+
+	lbls := labels.FromMap(map[string]string{
+		"foo": "foo-value",
+		"bar": "bar-value",
+	})
+	assert.Equal(t, 3.0, testRegistry.Query("gauge", lbls))
+
+	gauge.Set(labelValues, 1.5)
+	assert.Equal(t, 1.5, testRegistry.Query("gauge", lbls))
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"time"
 
+	"github.com/grafana/dskit/backoff"
 	prometheus_config "github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/tsdb/agent"
 )
@@ -20,12 +21,61 @@ type Config struct {
 	// Prometheus remote write config
 	// https://prometheus.io/docs/prometheus/latest/configuration/configuration/#remote_write
 	RemoteWrite []prometheus_config.RemoteWriteConfig `yaml:"remote_write,omitempty"`
+
+	// Local stores the generated series in a TSDB in the metrics-generator so they can be queried
+	// without a Prometheus.
+	Local LocalConfig `yaml:"local"`
+
+	// OTLP exports the generated series to an OTLP-compatible metrics backend, next to remote write.
+	OTLP OTLPConfig `yaml:"otlp"`
+}
+
+type LocalConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Path to store the TSDB. Each tenant will be stored in its own subdirectory.
+	Path string `yaml:"path"`
+	// How long series are kept.
+	Retention time.Duration `yaml:"retention"`
+}
+
+type OTLPConfig struct {
+	// Endpoint of the OTLP receiver. The exporter is disabled if empty. For grpc this is host:port,
+	// for http the full URL, e.g. http://otel-collector:4318/v1/metrics.
+	Endpoint string `yaml:"endpoint"`
+	// Protocol is either grpc or http.
+	Protocol string `yaml:"protocol"`
+	// Insecure disables TLS for grpc.
+	Insecure bool `yaml:"insecure"`
+	// Headers are added to every export request. The X-Scope-OrgID header is set to the tenant.
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// Timeout of a single export request.
+	Timeout time.Duration `yaml:"timeout"`
+	// QueueSize is the number of collections waiting to be exported, collections are dropped when full.
+	QueueSize int `yaml:"queue_size"`
+	// RetryBackoff configures the retries of a failed export.
+	RetryBackoff backoff.Config `yaml:"retry_backoff"`
 }
 
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http"
+)
+
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.Wal = agentDefaultOptions()
 
 	cfg.RemoteWriteFlushDeadline = time.Minute
+
+	cfg.Local.Retention = 24 * time.Hour
+
+	cfg.OTLP.Protocol = OTLPProtocolGRPC
+	cfg.OTLP.Timeout = 10 * time.Second
+	cfg.OTLP.QueueSize = 10
+	cfg.OTLP.RetryBackoff = backoff.Config{
+		MinBackoff: 100 * time.Millisecond,
+		MaxBackoff: 5 * time.Second,
+		MaxRetries: 5,
+	}
 }
 
 // agentOptions is a copy of agent.Options but with yaml struct tags. Refer to agent.Options for
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/dskit/backoff"
 	prometheus_common_config "github.com/prometheus/common/config"
 	prometheus_config "github.com/prometheus/prometheus/config"
 	"github.com/stretchr/testify/assert"
@@ -21,6 +22,12 @@ remote_write:
   - url: http://prometheus/api/prom/push
     headers:
       foo: bar
+local:
+  enabled: true
+  path: /var/tempo/generator/tsdb
+otlp:
+  endpoint: otel-collector:4317
+  insecure: true
 `
 
 	var cfg Config
@@ -47,6 +54,23 @@ remote_write:
 		RemoteWrite: []prometheus_config.RemoteWriteConfig{
 			remoteWriteConfig,
 		},
+		Local: LocalConfig{
+			Enabled:   true,
+			Path:      "/var/tempo/generator/tsdb",
+			Retention: 24 * time.Hour,
+		},
+		OTLP: OTLPConfig{
+			Endpoint:  "otel-collector:4317",
+			Protocol:  OTLPProtocolGRPC,
+			Insecure:  true,
+			Timeout:   10 * time.Second,
+			QueueSize: 10,
+			RetryBackoff: backoff.Config{
+				MinBackoff: 100 * time.Millisecond,
+				MaxBackoff: 5 * time.Second,
+				MaxRetries: 5,
+			},
+		},
 	}
 	assert.Equal(t, expectedCfg, cfg)
 }
@@ -19,19 +19,7 @@ func generateTenantRemoteWriteConfigs(originalCfgs []prometheus_config.RemoteWri
 		cloneCfg := &prometheus_config.RemoteWriteConfig{}
 		*cloneCfg = originalCfg
 
-		// Copy headers so we can modify them
-		cloneCfg.Headers = copyMap(cloneCfg.Headers)
-
-		// Ensure that no variation of the X-Scope-OrgId header can be added, which might trick authentication
-		for k, v := range cloneCfg.Headers {
-			if strings.EqualFold(user.OrgIDHeaderName, strings.TrimSpace(k)) {
-				level.Warn(logger).Log("msg", "discarding X-Scope-OrgId header", "key", k, "value", v)
-				delete(cloneCfg.Headers, k)
-			}
-		}
-
-		// inject the X-Scope-OrgId header for multi-tenant metrics backends
-		cloneCfg.Headers[user.OrgIDHeaderName] = tenant
+		cloneCfg.Headers = generateTenantHeaders(cloneCfg.Headers, tenant, logger)
 
 		cloneCfgs = append(cloneCfgs, cloneCfg)
 	}
@@ -39,6 +27,26 @@ func generateTenantRemoteWriteConfigs(originalCfgs []prometheus_config.RemoteWri
 	return cloneCfgs
 }
 
+// generateTenantHeaders returns a copy of headers with the X-Scope-OrgID header set to the given
+// tenant. Any variation of this header already present is discarded.
+func generateTenantHeaders(headers map[string]string, tenant string, logger log.Logger) map[string]string {
+	// Copy headers so we can modify them
+	headers = copyMap(headers)
+
+	// Ensure that no variation of the X-Scope-OrgId header can be added, which might trick authentication
+	for k, v := range headers {
+		if strings.EqualFold(user.OrgIDHeaderName, strings.TrimSpace(k)) {
+			level.Warn(logger).Log("msg", "discarding X-Scope-OrgId header", "key", k, "value", v)
+			delete(headers, k)
+		}
+	}
+
+	// inject the X-Scope-OrgId header for multi-tenant metrics backends
+	headers[user.OrgIDHeaderName] = tenant
+
+	return headers
+}
+
 // copyMap creates a new map containing all values from the given map.
 func copyMap(m map[string]string) map[string]string {
 	newMap := make(map[string]string, len(m))
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+)
+
+var metricSecondaryFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "metrics_generator_storage_secondary_failures_total",
+	Help:      "The total number of failed appends and commits to the local TSDB or the OTLP exporter per tenant",
+}, []string{"tenant"})
+
+// fanoutAppender appends to the primary and the secondary appenders. Unlike the fanout of
+// Prometheus, the series references returned by the primary are not passed to the secondaries
+// since they are TSDBs or exporters with their own references.
+//
+// Only errors of the primary are returned. Errors of the secondaries are logged and counted, so a
+// failing local TSDB or OTLP exporter doesn't fail the remote write of the collection.
+type fanoutAppender struct {
+	primary     storage.Appender
+	secondaries []storage.Appender
+
+	tenant string
+	logger log.Logger
+}
+
+var _ storage.Appender = (*fanoutAppender)(nil)
+
+func (f *fanoutAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	ref, err := f.primary.Append(ref, l, t, v)
+	if err != nil {
+		return ref, err
+	}
+
+	for _, secondary := range f.secondaries {
+		if _, err := secondary.Append(0, l, t, v); err != nil {
+			f.secondaryFailed("append", err)
+		}
+	}
+	return ref, nil
+}
+
+func (f *fanoutAppender) AppendExemplar(ref storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
+	ref, err := f.primary.AppendExemplar(ref, l, e)
+	if err != nil {
+		return ref, err
+	}
+
+	for _, secondary := range f.secondaries {
+		if _, err := secondary.AppendExemplar(0, l, e); err != nil {
+			f.secondaryFailed("append exemplar", err)
+		}
+	}
+	return ref, nil
+}
+
+func (f *fanoutAppender) Commit() error {
+	err := f.primary.Commit()
+	if err != nil {
+		for _, secondary := range f.secondaries {
+			if rollbackErr := secondary.Rollback(); rollbackErr != nil {
+				f.secondaryFailed("rollback", rollbackErr)
+			}
+		}
+		return err
+	}
+
+	for _, secondary := range f.secondaries {
+		if err := secondary.Commit(); err != nil {
+			f.secondaryFailed("commit", err)
+		}
+	}
+	return nil
+}
+
+func (f *fanoutAppender) Rollback() error {
+	err := f.primary.Rollback()
+	for _, secondary := range f.secondaries {
+		if rollbackErr := secondary.Rollback(); rollbackErr != nil {
+			f.secondaryFailed("rollback", rollbackErr)
+		}
+	}
+	return err
+}
+
+func (f *fanoutAppender) secondaryFailed(op string, err error) {
+	metricSecondaryFailures.WithLabelValues(f.tenant).Inc()
+	level.Warn(f.logger).Log("msg", "secondary storage failed", "op", op, "err", err)
+}
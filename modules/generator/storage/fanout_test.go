@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFanoutAppender_secondaryErrors(t *testing.T) {
+	primary := &testAppender{}
+	failing := &testAppender{err: errors.New("secondary failed")}
+	healthy := &testAppender{}
+
+	app := &fanoutAppender{
+		primary:     primary,
+		secondaries: []storage.Appender{failing, healthy},
+		tenant:      "test-fanout-secondary-errors",
+		logger:      log.NewNopLogger(),
+	}
+
+	lbls := labels.FromStrings("__name__", "my_metric")
+
+	_, err := app.Append(0, lbls, 1, 1.0)
+	require.NoError(t, err)
+	_, err = app.AppendExemplar(0, lbls, exemplar.Exemplar{Value: 1.0})
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	assert.Equal(t, 2, primary.appended)
+	assert.True(t, primary.committed)
+	assert.Equal(t, 2, healthy.appended)
+	assert.True(t, healthy.committed)
+
+	// two appends and the commit of the failing secondary
+	assert.Equal(t, 3.0, testutil.ToFloat64(metricSecondaryFailures.WithLabelValues("test-fanout-secondary-errors")))
+}
+
+func TestFanoutAppender_primaryError(t *testing.T) {
+	primary := &testAppender{err: errors.New("primary failed")}
+	secondary := &testAppender{}
+
+	app := &fanoutAppender{
+		primary:     primary,
+		secondaries: []storage.Appender{secondary},
+		tenant:      "test-fanout-primary-error",
+		logger:      log.NewNopLogger(),
+	}
+
+	_, err := app.Append(0, labels.FromStrings("__name__", "my_metric"), 1, 1.0)
+	assert.Error(t, err)
+	assert.Equal(t, 0, secondary.appended)
+
+	assert.Error(t, app.Commit())
+	assert.False(t, secondary.committed)
+	assert.True(t, secondary.rolledBack)
+}
+
+type testAppender struct {
+	err error
+
+	appended   int
+	committed  bool
+	rolledBack bool
+}
+
+var _ storage.Appender = (*testAppender)(nil)
+
+func (a *testAppender) Append(ref storage.SeriesRef, _ labels.Labels, _ int64, _ float64) (storage.SeriesRef, error) {
+	if a.err != nil {
+		return 0, a.err
+	}
+	a.appended++
+	return ref, nil
+}
+
+func (a *testAppender) AppendExemplar(ref storage.SeriesRef, _ labels.Labels, _ exemplar.Exemplar) (storage.SeriesRef, error) {
+	if a.err != nil {
+		return 0, a.err
+	}
+	a.appended++
+	return ref, nil
+}
+
+func (a *testAppender) Commit() error {
+	if a.err != nil {
+		return a.err
+	}
+	a.committed = true
+	return nil
+}
+
+func (a *testAppender) Rollback() error {
+	a.rolledBack = true
+	return nil
+}
@@ -15,12 +15,15 @@ import (
 	"github.com/prometheus/prometheus/scrape"
 	"github.com/prometheus/prometheus/storage"
 	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/prometheus/prometheus/tsdb"
 	"github.com/prometheus/prometheus/tsdb/agent"
 	tsdb_errors "github.com/prometheus/prometheus/tsdb/errors"
 )
 
 type Storage interface {
 	storage.Appendable
+	// Queryable queries the local TSDB. If the local TSDB is disabled, no series are returned.
+	storage.Queryable
 
 	// Close closes the storage and all its underlying resources.
 	Close() error
@@ -30,7 +33,12 @@ type storageImpl struct {
 	walDir        string
 	wal           *agent.DB
 	remoteStorage *remote.Storage
+	// local is nil if the local TSDB is disabled
+	local *tsdb.DB
+	// otlp is nil if the OTLP exporter is disabled
+	otlp *otlpExporter
 
+	tenant string
 	logger log.Logger
 }
 
@@ -74,25 +82,97 @@ func New(cfg *Config, tenant string, reg prometheus.Registerer, logger log.Logge
 		return nil, err
 	}
 
+	var local *tsdb.DB
+	if cfg.Local.Enabled {
+		local, err = openLocal(&cfg.Local, tenant, logger)
+		if err != nil {
+			return nil, tsdb_errors.NewMulti(err, wal.Close(), remoteStorage.Close()).Err()
+		}
+	}
+
+	var otlpExp *otlpExporter
+	if cfg.OTLP.Endpoint != "" {
+		otlpExp, err = newOTLPExporter(&cfg.OTLP, tenant, logger)
+		if err != nil {
+			var localErr error
+			if local != nil {
+				localErr = local.Close()
+			}
+			return nil, tsdb_errors.NewMulti(err, wal.Close(), remoteStorage.Close(), localErr).Err()
+		}
+	}
+
 	return &storageImpl{
 		walDir:        walDir,
 		wal:           wal,
 		remoteStorage: remoteStorage,
+		local:         local,
+		otlp:          otlpExp,
 
+		tenant: tenant,
 		logger: logger,
 	}, nil
 }
 
+func openLocal(cfg *LocalConfig, tenant string, logger log.Logger) (*tsdb.DB, error) {
+	dir := filepath.Join(cfg.Path, tenant)
+
+	level.Info(logger).Log("msg", "opening local TSDB", "dir", dir)
+
+	opts := tsdb.DefaultOptions()
+	opts.RetentionDuration = cfg.Retention.Milliseconds()
+
+	// The metrics of the TSDB are not registered, they conflict with the metrics of the WAL.
+	db, err := tsdb.Open(dir, log.With(logger, "component", "tsdb"), nil, opts, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not open local TSDB: %w", err)
+	}
+	return db, nil
+}
+
 func (s *storageImpl) Appender(ctx context.Context) storage.Appender {
-	return s.wal.Appender(ctx)
+	var secondaries []storage.Appender
+	if s.local != nil {
+		secondaries = append(secondaries, s.local.Appender(ctx))
+	}
+	if s.otlp != nil {
+		secondaries = append(secondaries, s.otlp.Appender(ctx))
+	}
+
+	if len(secondaries) == 0 {
+		return s.wal.Appender(ctx)
+	}
+	return &fanoutAppender{
+		primary:     s.wal.Appender(ctx),
+		secondaries: secondaries,
+		tenant:      s.tenant,
+		logger:      s.logger,
+	}
+}
+
+func (s *storageImpl) Querier(ctx context.Context, mint, maxt int64) (storage.Querier, error) {
+	if s.local == nil {
+		return storage.NoopQuerier(), nil
+	}
+	return s.local.Querier(ctx, mint, maxt)
 }
 
 func (s *storageImpl) Close() error {
 	level.Info(s.logger).Log("msg", "closing WAL", "dir", s.walDir)
 
+	var localErr, otlpErr error
+	if s.local != nil {
+		localErr = s.local.Close()
+	}
+	if s.otlp != nil {
+		otlpErr = s.otlp.Close()
+	}
+
 	return tsdb_errors.NewMulti(
 		s.wal.Close(),
 		s.remoteStorage.Close(),
+		localErr,
+		otlpErr,
 		func() error {
 			// remove the WAL at shutdown since remote write starts at the end of the WAL anyways
 			// https://github.com/prometheus/prometheus/issues/8809
@@ -24,6 +24,8 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/weaveworks/common/user"
 	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/modules/generator/registry"
 )
 
 // Verify basic functionality like sending metrics and exemplars, buffering and retrying failed
@@ -99,6 +101,81 @@ func TestInstance(t *testing.T) {
 	assert.GreaterOrEqual(t, len(mockServer.timeSeries["test-tenant"]), 2)
 }
 
+func TestInstance_local(t *testing.T) {
+	var cfg Config
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Path = t.TempDir()
+	cfg.Local.Enabled = true
+	cfg.Local.Path = t.TempDir()
+
+	instance, err := New(&cfg, "test-tenant", prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+
+	now := time.Now().UnixMilli()
+
+	appender := instance.Appender(context.Background())
+	for i := 0; i < 3; i++ {
+		for _, value := range []string{"value-1", "value-2"} {
+			lbls := labels.FromStrings("__name__", "my_metric", "label", value)
+			ref, err := appender.Append(0, lbls, now+int64(i), float64(i))
+			require.NoError(t, err)
+
+			_, err = appender.AppendExemplar(ref, lbls, exemplar.Exemplar{
+				Labels: labels.FromStrings("traceID", "123"),
+				Value:  1.0,
+				Ts:     now + int64(i),
+				HasTs:  true,
+			})
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, appender.Commit())
+
+	querier, err := instance.Querier(context.Background(), now, now+10)
+	require.NoError(t, err)
+
+	set := querier.Select(true, nil, labels.MustNewMatcher(labels.MatchEqual, "label", "value-2"))
+	require.True(t, set.Next())
+
+	series := set.At()
+	assert.Equal(t, labels.FromStrings("__name__", "my_metric", "label", "value-2"), series.Labels())
+
+	var values []float64
+	it := series.Iterator()
+	for it.Next() {
+		_, v := it.At()
+		values = append(values, v)
+	}
+	assert.Equal(t, []float64{0, 1, 2}, values)
+
+	assert.False(t, set.Next())
+	require.NoError(t, set.Err())
+	require.NoError(t, querier.Close())
+
+	require.NoError(t, instance.Close())
+
+	// the local TSDB is kept on shutdown
+	entries, err := os.ReadDir(cfg.Local.Path)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestInstance_localDisabled(t *testing.T) {
+	var cfg Config
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Path = t.TempDir()
+
+	instance, err := New(&cfg, "test-tenant", prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	defer instance.Close()
+
+	querier, err := instance.Querier(context.Background(), 0, time.Now().UnixMilli())
+	require.NoError(t, err)
+
+	set := querier.Select(false, nil, labels.MustNewMatcher(labels.MatchEqual, "__name__", "my_metric"))
+	assert.False(t, set.Next())
+}
+
 // Verify multiple instances function next to each other, don't trample over each other and are isolated.
 func TestInstance_multiTenancy(t *testing.T) {
 	var err error
@@ -175,6 +252,51 @@ func TestInstance_multiTenancy(t *testing.T) {
 	}
 }
 
+// Verify series of a histogram with exponential buckets are shipped like any other series, with all
+// buckets of the layout.
+func TestInstance_exponentialBuckets(t *testing.T) {
+	logger := log.NewLogfmtLogger(log.NewSyncWriter(os.Stdout))
+
+	mockServer := newMockPrometheusRemoteWriterServer(logger)
+	defer mockServer.close()
+
+	var cfg Config
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Path = t.TempDir()
+	cfg.RemoteWrite = mockServer.remoteWriteConfig()
+
+	instance, err := New(&cfg, "test-tenant", prometheus.NewRegistry(), logger)
+	require.NoError(t, err)
+
+	reg := registry.New(&registry.Config{}, &mockOverrides{collectionInterval: 100 * time.Millisecond}, "test-tenant", instance, logger)
+	defer reg.Close()
+
+	histogram := reg.NewHistogram("latency_seconds", nil, registry.ExponentialBuckets(40))
+	for _, v := range []float64{0.001, 0.1, 60} {
+		histogram.ObserveWithExemplar(nil, v, "")
+	}
+
+	// 37 buckets + +Inf bucket
+	err = waitUntil(10*time.Second, func() bool {
+		mockServer.mtx.Lock()
+		defer mockServer.mtx.Unlock()
+
+		buckets := map[string]struct{}{}
+		for _, ts := range mockServer.timeSeries["test-tenant"] {
+			for _, l := range ts.Labels {
+				if l.Name == labels.BucketLabel {
+					buckets[l.Value] = struct{}{}
+				}
+			}
+		}
+		return len(buckets) == 38
+	})
+	require.NoError(t, err, "timed out while waiting for histogram buckets")
+
+	err = instance.Close()
+	assert.NoError(t, err)
+}
+
 func TestInstance_cantWriteToWAL(t *testing.T) {
 	var cfg Config
 	cfg.RegisterFlagsAndApplyDefaults("", nil)
@@ -256,6 +378,28 @@ func (m *mockPrometheusRemoteWriteServer) close() {
 	m.server.Close()
 }
 
+type mockOverrides struct {
+	collectionInterval time.Duration
+}
+
+var _ registry.Overrides = (*mockOverrides)(nil)
+
+func (m *mockOverrides) MetricsGeneratorMaxActiveSeries(userID string) uint32 {
+	return 0
+}
+
+func (m *mockOverrides) MetricsGeneratorMaxActiveSeriesPerMetric(userID string) uint32 {
+	return 0
+}
+
+func (m *mockOverrides) MetricsGeneratorMaxLabelValues(userID string) uint32 {
+	return 0
+}
+
+func (m *mockOverrides) MetricsGeneratorCollectionInterval(userID string) time.Duration {
+	return m.collectionInterval
+}
+
 // poll executes f every interval until ctx is done or cancelled.
 func poll(ctx context.Context, interval time.Duration, f func()) {
 	ticker := time.NewTicker(interval)
@@ -0,0 +1,557 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
+	"go.opentelemetry.io/collector/model/pdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+var (
+	metricOTLPDataPointsExported = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_otlp_data_points_exported_total",
+		Help:      "The total number of data points exported over OTLP per tenant",
+	}, []string{"tenant"})
+	metricOTLPExportFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_otlp_exports_failed_total",
+		Help:      "The total number of OTLP exports failed after all retries per tenant",
+	}, []string{"tenant"})
+	metricOTLPExportRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_otlp_export_retries_total",
+		Help:      "The total number of retried OTLP exports per tenant",
+	}, []string{"tenant"})
+	metricOTLPExportDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_otlp_exports_dropped_total",
+		Help:      "The total number of OTLP exports dropped because the queue was full per tenant",
+	}, []string{"tenant"})
+	metricOTLPQueueLength = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_otlp_queue_length",
+		Help:      "The number of collections waiting to be exported over OTLP per tenant",
+	}, []string{"tenant"})
+)
+
+const otlpInstrumentationLibrary = "tempo-metrics-generator"
+
+// otlpExporter sends the series appended during a collection of the registry to an OTLP metrics
+// receiver. Counters (series ending in _total) are sent as cumulative sums, series written as
+// _bucket, _sum and _count as histograms and all other series as gauges.
+//
+// Collections are queued on Commit and exported in the background with retries, a slow or failing
+// receiver doesn't block or fail the collection. Collections are dropped if the queue is full.
+type otlpExporter struct {
+	cfg *OTLPConfig
+
+	export func(ctx context.Context, md pdata.Metrics) error
+	close  func() error
+
+	queue chan pdata.Metrics
+	quit  chan struct{}
+	done  chan struct{}
+
+	// startTimesMtx protects startTimes and lastCommit
+	startTimesMtx sync.Mutex
+	// startTimes maps the hash of the labels of the cumulative series of the last collection to the
+	// time they were first appended.
+	startTimes map[uint64]pdata.Timestamp
+	// lastCommit is the time of the last commit, or of the creation of the exporter. Series appearing
+	// in a collection started after it.
+	lastCommit time.Time
+
+	logger                       log.Logger
+	metricOTLPDataPointsExported prometheus.Counter
+	metricOTLPExportFailed       prometheus.Counter
+	metricOTLPExportRetries      prometheus.Counter
+	metricOTLPExportDropped      prometheus.Counter
+	metricOTLPQueueLength        prometheus.Gauge
+}
+
+var _ storage.Appendable = (*otlpExporter)(nil)
+
+func newOTLPExporter(cfg *OTLPConfig, tenant string, logger log.Logger) (*otlpExporter, error) {
+	logger = log.With(logger, "component", "otlp")
+	headers := generateTenantHeaders(cfg.Headers, tenant, logger)
+
+	e := &otlpExporter{
+		cfg:        cfg,
+		queue:      make(chan pdata.Metrics, cfg.QueueSize),
+		quit:       make(chan struct{}),
+		done:       make(chan struct{}),
+		startTimes: make(map[uint64]pdata.Timestamp),
+		lastCommit: time.Now(),
+
+		logger:                       logger,
+		metricOTLPDataPointsExported: metricOTLPDataPointsExported.WithLabelValues(tenant),
+		metricOTLPExportFailed:       metricOTLPExportFailed.WithLabelValues(tenant),
+		metricOTLPExportRetries:      metricOTLPExportRetries.WithLabelValues(tenant),
+		metricOTLPExportDropped:      metricOTLPExportDropped.WithLabelValues(tenant),
+		metricOTLPQueueLength:        metricOTLPQueueLength.WithLabelValues(tenant),
+	}
+
+	switch cfg.Protocol {
+	case OTLPProtocolGRPC:
+		creds := credentials.NewTLS(&tls.Config{})
+		if cfg.Insecure {
+			creds = insecure.NewCredentials()
+		}
+		conn, err := grpc.Dial(cfg.Endpoint, grpc.WithTransportCredentials(creds))
+		if err != nil {
+			return nil, fmt.Errorf("could not dial OTLP endpoint: %w", err)
+		}
+		e.export = newOTLPGRPCExport(otlpgrpc.NewMetricsClient(conn), headers)
+		e.close = conn.Close
+	case OTLPProtocolHTTP:
+		e.export = newOTLPHTTPExport(http.DefaultClient, cfg.Endpoint, headers)
+		e.close = func() error { return nil }
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol %s, expected %s or %s", cfg.Protocol, OTLPProtocolGRPC, OTLPProtocolHTTP)
+	}
+
+	go e.exportLoop()
+
+	level.Info(logger).Log("msg", "exporting metrics over OTLP", "endpoint", cfg.Endpoint, "protocol", cfg.Protocol)
+
+	return e, nil
+}
+
+func newOTLPGRPCExport(client otlpgrpc.MetricsClient, headers map[string]string) func(ctx context.Context, md pdata.Metrics) error {
+	md := metadata.New(headers)
+	return func(ctx context.Context, metrics pdata.Metrics) error {
+		req := otlpgrpc.NewMetricsRequest()
+		req.SetMetrics(metrics)
+
+		_, err := client.Export(metadata.NewOutgoingContext(ctx, md), req)
+		return err
+	}
+}
+
+func newOTLPHTTPExport(client *http.Client, endpoint string, headers map[string]string) func(ctx context.Context, md pdata.Metrics) error {
+	marshaler := otlp.NewProtobufMetricsMarshaler()
+	return func(ctx context.Context, metrics pdata.Metrics) error {
+		body, err := marshaler.MarshalMetrics(metrics)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		req.Header.Set("Content-Type", "application/x-protobuf")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode/100 != 2 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return fmt.Errorf("OTLP endpoint returned %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+		}
+		return nil
+	}
+}
+
+func (e *otlpExporter) Appender(ctx context.Context) storage.Appender {
+	return &otlpAppender{
+		exporter:  e,
+		exemplars: make(map[uint64][]exemplar.Exemplar),
+	}
+}
+
+// Close exports the queued collections, waiting up to the export timeout, and closes the connection.
+func (e *otlpExporter) Close() error {
+	close(e.quit)
+
+	select {
+	case <-e.done:
+	case <-time.After(e.cfg.Timeout):
+		level.Warn(e.logger).Log("msg", "timed out exporting the queued metrics over OTLP")
+	}
+	return e.close()
+}
+
+// enqueue queues a collection to be exported, the collection is dropped if the queue is full.
+func (e *otlpExporter) enqueue(metrics pdata.Metrics) {
+	select {
+	case e.queue <- metrics:
+		e.metricOTLPQueueLength.Set(float64(len(e.queue)))
+	default:
+		e.metricOTLPExportDropped.Inc()
+		level.Warn(e.logger).Log("msg", "OTLP export queue is full, dropping collection", "data_points", metrics.DataPointCount())
+	}
+}
+
+func (e *otlpExporter) exportLoop() {
+	defer close(e.done)
+
+	for {
+		select {
+		case metrics := <-e.queue:
+			e.metricOTLPQueueLength.Set(float64(len(e.queue)))
+			e.exportWithRetries(metrics)
+		case <-e.quit:
+			// export what's left in the queue without retrying
+			for {
+				select {
+				case metrics := <-e.queue:
+					e.exportOnce(metrics)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *otlpExporter) exportWithRetries(metrics pdata.Metrics) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-e.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	retries := backoff.New(ctx, e.cfg.RetryBackoff)
+	for {
+		err := e.exportOnce(metrics)
+		if err == nil {
+			return
+		}
+
+		if !retries.Ongoing() {
+			e.metricOTLPExportFailed.Inc()
+			level.Error(e.logger).Log("msg", "exporting metrics over OTLP failed", "retries", retries.NumRetries(), "err", err)
+			return
+		}
+		retries.Wait()
+		e.metricOTLPExportRetries.Inc()
+	}
+}
+
+func (e *otlpExporter) exportOnce(metrics pdata.Metrics) error {
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Timeout)
+	defer cancel()
+
+	err := e.export(ctx, metrics)
+	if err != nil {
+		return err
+	}
+	e.metricOTLPDataPointsExported.Add(float64(metrics.DataPointCount()))
+	return nil
+}
+
+// startTimeFunc returns a function returning the start time of the cumulative series with the given
+// labels. A series first appended in this collection starts at the last commit, the start times of
+// series missing from the collection are forgotten so they start over if they are created again.
+// The returned function must be called for all cumulative series of the collection before commit is
+// called.
+func (e *otlpExporter) startTimeFunc() (startTime func(lbls labels.Labels) pdata.Timestamp, commit func()) {
+	e.startTimesMtx.Lock()
+	previous := e.startTimes
+	newSeriesStart := pdata.NewTimestampFromTime(e.lastCommit)
+	e.startTimesMtx.Unlock()
+
+	current := make(map[uint64]pdata.Timestamp, len(previous))
+	startTime = func(lbls labels.Labels) pdata.Timestamp {
+		hash := lbls.Hash()
+		start, ok := previous[hash]
+		if !ok {
+			start = newSeriesStart
+		}
+		current[hash] = start
+		return start
+	}
+	commit = func() {
+		e.startTimesMtx.Lock()
+		e.startTimes = current
+		e.lastCommit = time.Now()
+		e.startTimesMtx.Unlock()
+	}
+	return startTime, commit
+}
+
+// otlpAppender buffers the samples and exemplars of a single collection and queues them on Commit.
+type otlpAppender struct {
+	exporter *otlpExporter
+
+	samples []otlpSample
+	// exemplars are keyed by the hash of the labels of their series
+	exemplars map[uint64][]exemplar.Exemplar
+}
+
+type otlpSample struct {
+	labels labels.Labels
+	t      int64
+	v      float64
+}
+
+var _ storage.Appender = (*otlpAppender)(nil)
+
+func (a *otlpAppender) Append(_ storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	a.samples = append(a.samples, otlpSample{labels: l, t: t, v: v})
+	return 0, nil
+}
+
+func (a *otlpAppender) AppendExemplar(_ storage.SeriesRef, l labels.Labels, e exemplar.Exemplar) (storage.SeriesRef, error) {
+	hash := l.Hash()
+	a.exemplars[hash] = append(a.exemplars[hash], e)
+	return 0, nil
+}
+
+func (a *otlpAppender) Commit() error {
+	if len(a.samples) == 0 {
+		return nil
+	}
+
+	startTime, commitStartTimes := a.exporter.startTimeFunc()
+	metrics := toOTLPMetrics(a.samples, a.exemplars, startTime)
+	commitStartTimes()
+
+	a.exporter.enqueue(metrics)
+
+	a.samples = nil
+	a.exemplars = make(map[uint64][]exemplar.Exemplar)
+	return nil
+}
+
+func (a *otlpAppender) Rollback() error {
+	a.samples = nil
+	a.exemplars = make(map[uint64][]exemplar.Exemplar)
+	return nil
+}
+
+const (
+	suffixTotal  = "_total"
+	suffixBucket = "_bucket"
+	suffixSum    = "_sum"
+	suffixCount  = "_count"
+)
+
+// otlpHistogram collects the _bucket, _sum and _count series of a single histogram series.
+type otlpHistogram struct {
+	name      string
+	labels    labels.Labels
+	t         int64
+	buckets   []otlpBucket
+	sum       float64
+	count     float64
+	exemplars []exemplar.Exemplar
+}
+
+type otlpBucket struct {
+	le    float64
+	value float64
+}
+
+// toOTLPMetrics converts the samples of a collection into OTLP metrics. The labels of a series
+// become attributes of the data point, exemplars with a traceID label are sent with their trace ID.
+// startTime returns the start of the cumulative series with the given labels, the labels of a
+// histogram are those of its _count series without the suffix.
+func toOTLPMetrics(samples []otlpSample, exemplars map[uint64][]exemplar.Exemplar, startTime func(lbls labels.Labels) pdata.Timestamp) pdata.Metrics {
+	md := pdata.NewMetrics()
+	ilm := md.ResourceMetrics().AppendEmpty().InstrumentationLibraryMetrics().AppendEmpty()
+	ilm.InstrumentationLibrary().SetName(otlpInstrumentationLibrary)
+
+	metrics := make(map[string]pdata.Metric)
+	getMetric := func(name string, dataType pdata.MetricDataType) pdata.Metric {
+		m, ok := metrics[name]
+		if !ok {
+			m = ilm.Metrics().AppendEmpty()
+			m.SetName(name)
+			m.SetDataType(dataType)
+			switch dataType {
+			case pdata.MetricDataTypeSum:
+				m.Sum().SetIsMonotonic(true)
+				m.Sum().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+			case pdata.MetricDataTypeHistogram:
+				m.Histogram().SetAggregationTemporality(pdata.MetricAggregationTemporalityCumulative)
+			}
+			metrics[name] = m
+		}
+		return m
+	}
+
+	// histograms are written as _bucket, _sum and _count series, find their names first
+	histogramNames := make(map[string]struct{})
+	for _, s := range samples {
+		name := s.labels.Get(labels.MetricName)
+		if strings.HasSuffix(name, suffixBucket) && s.labels.Has(labels.BucketLabel) {
+			histogramNames[strings.TrimSuffix(name, suffixBucket)] = struct{}{}
+		}
+	}
+
+	histograms := make(map[string]*otlpHistogram)
+	var histogramKeys []string
+
+	for _, s := range samples {
+		name := s.labels.Get(labels.MetricName)
+
+		if histogramName, suffix, ok := splitHistogramName(name, histogramNames); ok {
+			lbls := labels.NewBuilder(s.labels).Del(labels.MetricName, labels.BucketLabel).Labels()
+			key := histogramName + lbls.String()
+
+			h, ok := histograms[key]
+			if !ok {
+				h = &otlpHistogram{name: histogramName, labels: lbls, t: s.t}
+				histograms[key] = h
+				histogramKeys = append(histogramKeys, key)
+			}
+
+			switch suffix {
+			case suffixBucket:
+				le, err := strconv.ParseFloat(s.labels.Get(labels.BucketLabel), 64)
+				if err != nil {
+					continue
+				}
+				h.buckets = append(h.buckets, otlpBucket{le: le, value: s.v})
+				h.exemplars = append(h.exemplars, exemplars[s.labels.Hash()]...)
+			case suffixSum:
+				h.sum = s.v
+			case suffixCount:
+				h.count = s.v
+			}
+			continue
+		}
+
+		var dp pdata.NumberDataPoint
+		if strings.HasSuffix(name, suffixTotal) {
+			dp = getMetric(name, pdata.MetricDataTypeSum).Sum().DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(startTime(s.labels))
+		} else {
+			dp = getMetric(name, pdata.MetricDataTypeGauge).Gauge().DataPoints().AppendEmpty()
+		}
+		dp.SetTimestamp(timestampFromMs(s.t))
+		dp.SetDoubleVal(s.v)
+		setOTLPAttributes(dp.Attributes(), s.labels)
+		appendOTLPExemplars(dp.Exemplars(), exemplars[s.labels.Hash()])
+	}
+
+	for _, key := range histogramKeys {
+		h := histograms[key]
+
+		dp := getMetric(h.name, pdata.MetricDataTypeHistogram).Histogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(startTime(labels.NewBuilder(h.labels).Set(labels.MetricName, h.name).Labels()))
+		dp.SetTimestamp(timestampFromMs(h.t))
+		dp.SetSum(h.sum)
+		dp.SetCount(uint64(h.count))
+		bounds, counts := toOTLPBuckets(h.buckets, h.count)
+		dp.SetExplicitBounds(bounds)
+		dp.SetBucketCounts(counts)
+		setOTLPAttributes(dp.Attributes(), h.labels)
+		appendOTLPExemplars(dp.Exemplars(), h.exemplars)
+	}
+
+	return md
+}
+
+// splitHistogramName returns the name of the histogram and the suffix if name is a series of one of
+// the given histograms.
+func splitHistogramName(name string, histogramNames map[string]struct{}) (string, string, bool) {
+	for _, suffix := range []string{suffixBucket, suffixSum, suffixCount} {
+		if !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		histogramName := strings.TrimSuffix(name, suffix)
+		if _, ok := histogramNames[histogramName]; ok {
+			return histogramName, suffix, true
+		}
+	}
+	return "", "", false
+}
+
+// toOTLPBuckets converts cumulative Prometheus buckets into explicit bounds and the count of every
+// bucket. OTLP buckets are not cumulative and the +Inf bucket is implicit.
+func toOTLPBuckets(buckets []otlpBucket, count float64) ([]float64, []uint64) {
+	sort.Slice(buckets, func(i, j int) bool {
+		return buckets[i].le < buckets[j].le
+	})
+
+	bounds := make([]float64, 0, len(buckets))
+	counts := make([]uint64, 0, len(buckets)+1)
+
+	var previous float64
+	for _, b := range buckets {
+		if !math.IsInf(b.le, +1) {
+			bounds = append(bounds, b.le)
+		}
+		counts = append(counts, uint64(math.Max(b.value-previous, 0)))
+		previous = b.value
+	}
+	// add the +Inf bucket if it wasn't written
+	if len(counts) == len(bounds) {
+		counts = append(counts, uint64(math.Max(count-previous, 0)))
+	}
+
+	return bounds, counts
+}
+
+func setOTLPAttributes(attributes pdata.AttributeMap, lbls labels.Labels) {
+	for _, l := range lbls {
+		if l.Name == labels.MetricName {
+			continue
+		}
+		attributes.InsertString(l.Name, l.Value)
+	}
+}
+
+func appendOTLPExemplars(dst pdata.ExemplarSlice, exemplars []exemplar.Exemplar) {
+	for _, e := range exemplars {
+		ex := dst.AppendEmpty()
+		ex.SetTimestamp(timestampFromMs(e.Ts))
+		ex.SetDoubleVal(e.Value)
+
+		for _, l := range e.Labels {
+			if l.Name == "traceID" {
+				if traceID, err := util.HexStringToTraceID(l.Value); err == nil {
+					var b [16]byte
+					copy(b[:], traceID)
+					ex.SetTraceID(pdata.NewTraceID(b))
+					continue
+				}
+			}
+			ex.FilteredAttributes().InsertString(l.Name, l.Value)
+		}
+	}
+}
+
+func timestampFromMs(ms int64) pdata.Timestamp {
+	return pdata.NewTimestampFromTime(time.UnixMilli(ms))
+}
@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/model/otlp"
+	"go.opentelemetry.io/collector/model/otlpgrpc"
+	"go.opentelemetry.io/collector/model/pdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestToOTLPMetrics(t *testing.T) {
+	startTime := time.Unix(100, 0)
+	timeMs := int64(200_000)
+
+	counterLabels := labels.FromStrings("__name__", "traces_spanmetrics_calls_total", "service", "svc")
+	bucketLabels := func(le string) labels.Labels {
+		return labels.FromStrings("__name__", "traces_spanmetrics_latency_bucket", "service", "svc", "le", le)
+	}
+
+	samples := []otlpSample{
+		{labels: counterLabels, t: timeMs, v: 4},
+		{labels: labels.FromStrings("__name__", "traces_service_graph_unpaired_spans", "client", "svc"), t: timeMs, v: 2},
+		{labels: labels.FromStrings("__name__", "traces_spanmetrics_latency_count", "service", "svc"), t: timeMs, v: 4},
+		{labels: labels.FromStrings("__name__", "traces_spanmetrics_latency_sum", "service", "svc"), t: timeMs, v: 5.5},
+		{labels: bucketLabels("2"), t: timeMs, v: 3},
+		{labels: bucketLabels("1"), t: timeMs, v: 1},
+		{labels: bucketLabels("+Inf"), t: timeMs, v: 4},
+	}
+	exemplars := map[uint64][]exemplar.Exemplar{
+		counterLabels.Hash():     {{Labels: labels.FromStrings("traceID", "1234"), Value: 1, Ts: timeMs}},
+		bucketLabels("2").Hash(): {{Labels: labels.FromStrings("traceID", "5678"), Value: 1.5, Ts: timeMs}},
+	}
+
+	md := toOTLPMetrics(samples, exemplars, func(labels.Labels) pdata.Timestamp {
+		return pdata.NewTimestampFromTime(startTime)
+	})
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	ilm := md.ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0)
+	assert.Equal(t, otlpInstrumentationLibrary, ilm.InstrumentationLibrary().Name())
+
+	metrics := ilm.Metrics()
+	require.Equal(t, 3, metrics.Len())
+
+	sum := metrics.At(0)
+	assert.Equal(t, "traces_spanmetrics_calls_total", sum.Name())
+	require.Equal(t, pdata.MetricDataTypeSum, sum.DataType())
+	assert.True(t, sum.Sum().IsMonotonic())
+	assert.Equal(t, pdata.MetricAggregationTemporalityCumulative, sum.Sum().AggregationTemporality())
+	dp := sum.Sum().DataPoints().At(0)
+	assert.Equal(t, 4.0, dp.DoubleVal())
+	assert.Equal(t, pdata.NewTimestampFromTime(startTime), dp.StartTimestamp())
+	assert.Equal(t, pdata.NewTimestampFromTime(time.UnixMilli(timeMs)), dp.Timestamp())
+	assert.Equal(t, map[string]interface{}{"service": "svc"}, dp.Attributes().AsRaw())
+	require.Equal(t, 1, dp.Exemplars().Len())
+	assert.Equal(t, pdata.NewTraceID([16]byte{14: 0x12, 15: 0x34}), dp.Exemplars().At(0).TraceID())
+
+	gauge := metrics.At(1)
+	assert.Equal(t, "traces_service_graph_unpaired_spans", gauge.Name())
+	require.Equal(t, pdata.MetricDataTypeGauge, gauge.DataType())
+	assert.Equal(t, 2.0, gauge.Gauge().DataPoints().At(0).DoubleVal())
+
+	histogram := metrics.At(2)
+	assert.Equal(t, "traces_spanmetrics_latency", histogram.Name())
+	require.Equal(t, pdata.MetricDataTypeHistogram, histogram.DataType())
+	require.Equal(t, 1, histogram.Histogram().DataPoints().Len())
+	hdp := histogram.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(4), hdp.Count())
+	assert.Equal(t, 5.5, hdp.Sum())
+	assert.Equal(t, []float64{1, 2}, hdp.ExplicitBounds())
+	assert.Equal(t, []uint64{1, 2, 1}, hdp.BucketCounts())
+	assert.Equal(t, map[string]interface{}{"service": "svc"}, hdp.Attributes().AsRaw())
+	require.Equal(t, 1, hdp.Exemplars().Len())
+	assert.Equal(t, 1.5, hdp.Exemplars().At(0).DoubleVal())
+}
+
+func TestToOTLPBuckets_withoutInf(t *testing.T) {
+	bounds, counts := toOTLPBuckets([]otlpBucket{{le: 1, value: 2}, {le: 5, value: 3}}, 7)
+	assert.Equal(t, []float64{1, 5}, bounds)
+	assert.Equal(t, []uint64{2, 1, 4}, counts)
+}
+
+func TestOTLPExporter_startTime(t *testing.T) {
+	var exports []pdata.Metrics
+	e := &otlpExporter{
+		startTimes: make(map[uint64]pdata.Timestamp),
+		lastCommit: time.Unix(100, 0),
+		queue:      make(chan pdata.Metrics, 10),
+
+		metricOTLPQueueLength: metricOTLPQueueLength.WithLabelValues("test-tenant"),
+	}
+
+	collect := func(names ...string) map[string]pdata.Timestamp {
+		appender := e.Appender(context.Background())
+		for _, name := range names {
+			_, err := appender.Append(0, labels.FromStrings("__name__", name), time.Now().UnixMilli(), 1)
+			require.NoError(t, err)
+		}
+		require.NoError(t, appender.Commit())
+		exports = append(exports, <-e.queue)
+
+		starts := map[string]pdata.Timestamp{}
+		metrics := exports[len(exports)-1].ResourceMetrics().At(0).InstrumentationLibraryMetrics().At(0).Metrics()
+		for i := 0; i < metrics.Len(); i++ {
+			starts[metrics.At(i).Name()] = metrics.At(i).Sum().DataPoints().At(0).StartTimestamp()
+		}
+		return starts
+	}
+
+	// series of the first collection start when the exporter was created
+	starts := collect("a_total")
+	assert.Equal(t, pdata.NewTimestampFromTime(time.Unix(100, 0)), starts["a_total"])
+
+	// new series start at the previous collection, existing series keep their start time
+	e.lastCommit = time.Unix(200, 0)
+	starts = collect("a_total", "b_total")
+	assert.Equal(t, pdata.NewTimestampFromTime(time.Unix(100, 0)), starts["a_total"])
+	assert.Equal(t, pdata.NewTimestampFromTime(time.Unix(200, 0)), starts["b_total"])
+
+	// a series missing from a collection starts over
+	collect("b_total")
+	e.lastCommit = time.Unix(300, 0)
+	starts = collect("a_total", "b_total")
+	assert.Equal(t, pdata.NewTimestampFromTime(time.Unix(300, 0)), starts["a_total"])
+	assert.Equal(t, pdata.NewTimestampFromTime(time.Unix(200, 0)), starts["b_total"])
+}
+
+func TestOTLPExporter_http(t *testing.T) {
+	var mtx sync.Mutex
+	var received pdata.Metrics
+	var orgID string
+	requests := 0
+	status := http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		defer mtx.Unlock()
+
+		requests++
+		orgID = r.Header.Get("X-Scope-OrgID")
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		received, err = otlp.NewProtobufMetricsUnmarshaler().UnmarshalMetrics(body)
+		require.NoError(t, err)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	cfg := &OTLPConfig{
+		Endpoint:  server.URL + "/v1/metrics",
+		Protocol:  OTLPProtocolHTTP,
+		Headers:   map[string]string{"x-scope-orgid": "other-tenant"},
+		Timeout:   time.Second,
+		QueueSize: 1,
+		RetryBackoff: backoff.Config{
+			MinBackoff: time.Millisecond,
+			MaxBackoff: time.Millisecond,
+			MaxRetries: 3,
+		},
+	}
+	exporter, err := newOTLPExporter(cfg, "test-tenant", log.NewNopLogger())
+	require.NoError(t, err)
+	defer exporter.Close()
+
+	appendSample(t, exporter)
+
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return requests == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	mtx.Lock()
+	assert.Equal(t, "test-tenant", orgID)
+	assert.Equal(t, 1, received.DataPointCount())
+	mtx.Unlock()
+
+	t.Run("failed exports are retried without failing the commit", func(t *testing.T) {
+		mtx.Lock()
+		status = http.StatusInternalServerError
+		mtx.Unlock()
+
+		failed := testutil.ToFloat64(exporter.metricOTLPExportFailed)
+		retried := testutil.ToFloat64(exporter.metricOTLPExportRetries)
+
+		appendSample(t, exporter)
+
+		// the first request and 3 retries
+		require.Eventually(t, func() bool {
+			mtx.Lock()
+			defer mtx.Unlock()
+			return requests == 5
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Eventually(t, func() bool {
+			return testutil.ToFloat64(exporter.metricOTLPExportFailed) == failed+1
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, retried+3, testutil.ToFloat64(exporter.metricOTLPExportRetries))
+	})
+}
+
+func TestOTLPExporter_grpc(t *testing.T) {
+	lis, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	receiver := &mockMetricsServer{}
+	server := grpc.NewServer()
+	otlpgrpc.RegisterMetricsServer(server, receiver)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	defer server.Stop()
+
+	cfg := &OTLPConfig{
+		Endpoint:  lis.Addr().String(),
+		Protocol:  OTLPProtocolGRPC,
+		Insecure:  true,
+		Timeout:   5 * time.Second,
+		QueueSize: 1,
+	}
+	exporter, err := newOTLPExporter(cfg, "test-tenant", log.NewNopLogger())
+	require.NoError(t, err)
+
+	appendSample(t, exporter)
+
+	// queued collections are exported on close
+	require.NoError(t, exporter.Close())
+
+	assert.Equal(t, []string{"test-tenant"}, receiver.orgID)
+	assert.Equal(t, 1, receiver.received.DataPointCount())
+}
+
+func TestOTLPExporter_invalidProtocol(t *testing.T) {
+	_, err := newOTLPExporter(&OTLPConfig{Endpoint: "localhost:4317", Protocol: "udp"}, "test-tenant", log.NewNopLogger())
+	assert.Error(t, err)
+}
+
+func appendSample(t *testing.T, exporter *otlpExporter) {
+	appender := exporter.Appender(context.Background())
+	_, err := appender.Append(0, labels.FromStrings("__name__", "foo_total", "bar", "baz"), 1000, 1)
+	require.NoError(t, err)
+	require.NoError(t, appender.Commit())
+}
+
+type mockMetricsServer struct {
+	received pdata.Metrics
+	orgID    []string
+}
+
+func (m *mockMetricsServer) Export(ctx context.Context, req otlpgrpc.MetricsRequest) (otlpgrpc.MetricsResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	m.orgID = md.Get("x-scope-orgid")
+	m.received = req.Metrics()
+	return otlpgrpc.NewMetricsResponse(), nil
+}
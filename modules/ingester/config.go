@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/dskit/flagext"
 	"github.com/grafana/dskit/ring"
 
+	"github.com/grafana/tempo/pkg/ingest"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb"
 )
@@ -25,6 +26,14 @@ type Config struct {
 	MaxBlockBytes        uint64        `yaml:"max_block_bytes"`
 	CompleteBlockTimeout time.Duration `yaml:"complete_block_timeout"`
 	OverrideRingKey      string        `yaml:"override_ring_key"`
+
+	LateSpans LateSpansConfig `yaml:"late_spans"`
+
+	// Kafka ingest path. Set from the top level ingest config.
+	Ingest ingest.Config `yaml:"-"`
+
+	// For testing.
+	ingestClient ingest.Client `yaml:"-"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
@@ -43,6 +52,7 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.DurationVar(&cfg.MaxBlockDuration, prefix+".max-block-duration", time.Hour, "Maximum duration which the head block can be appended to before cutting it.")
 	f.Uint64Var(&cfg.MaxBlockBytes, prefix+".max-block-bytes", 1024*1024*1024, "Maximum size of the head block before cutting it.")
 	f.DurationVar(&cfg.CompleteBlockTimeout, prefix+".complete-block-timeout", 3*tempodb.DefaultBlocklistPoll, "Duration to keep blocks in the ingester after they have been flushed.")
+	cfg.LateSpans.RegisterFlagsAndApplyDefaults(prefix+".late-spans", f)
 
 	hostname, err := os.Hostname()
 	if err != nil {
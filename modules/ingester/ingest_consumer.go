@@ -0,0 +1,344 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/ingest"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+var (
+	metricIngestRecordsConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_ingest_records_consumed_total",
+		Help:      "The total number of records consumed from the ingest topic.",
+	}, []string{"partition"})
+	metricIngestTracesFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_ingest_traces_failed_total",
+		Help:      "The total number of traces consumed from the ingest topic that could not be pushed.",
+	}, []string{"tenant"})
+	metricIngestTracesRetried = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_ingest_traces_retried_total",
+		Help:      "The total number of pushes of traces consumed from the ingest topic that were retried.",
+	}, []string{"tenant"})
+	metricIngestCommittedOffset = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_ingest_committed_offset",
+		Help:      "The last offset committed per partition of the ingest topic.",
+	}, []string{"partition"})
+)
+
+var partitionOrdinalRegex = regexp.MustCompile(`(\d+)$`)
+
+// ingestRetryBackoff is the backoff between retries of pushes refused because of a temporary condition. Retries are
+// unlimited, the partition is blocked until the push succeeds or the consumer is stopped.
+var ingestRetryBackoff = backoff.Config{
+	MinBackoff: 100 * time.Millisecond,
+	MaxBackoff: 10 * time.Second,
+}
+
+// ingestPartitions returns the partitions of the ingest topic this ingester consumes.
+func ingestPartitions(cfg Config) ([]int32, error) {
+	if len(cfg.Ingest.ConsumePartitions) > 0 {
+		return cfg.Ingest.ConsumePartitions, nil
+	}
+
+	match := partitionOrdinalRegex.FindString(cfg.LifecyclerConfig.ID)
+	if match == "" {
+		return nil, fmt.Errorf("unable to derive ingest partition from ingester id %s, set consume_partitions", cfg.LifecyclerConfig.ID)
+	}
+
+	partition, err := strconv.Atoi(match)
+	if err != nil {
+		return nil, err
+	}
+	if partition >= cfg.Ingest.Partitions {
+		return nil, fmt.Errorf("ingest partition %d derived from ingester id %s exceeds the number of partitions %d", partition, cfg.LifecyclerConfig.ID, cfg.Ingest.Partitions)
+	}
+
+	return []int32{int32(partition)}, nil
+}
+
+// offsetTracker tracks the records of a partition that have been consumed but whose traces have not all
+// been appended to the wal yet. The committable offset never advances past a record that still has traces
+// in memory.
+//
+// Delivery is at-least-once: records after the committed offset are consumed again after a restart even if their
+// traces already made it into the wal, or were flushed, before. The duplicate spans are deduplicated by the trace
+// combiner when the trace is read or its blocks are compacted.
+type offsetTracker struct {
+	mtx     sync.Mutex
+	pending map[int64]int // offset -> traces not yet in the wal
+	next    int64         // offset after the last consumed record
+}
+
+func newOffsetTracker(next int64) *offsetTracker {
+	return &offsetTracker{
+		pending: map[int64]int{},
+		next:    next,
+	}
+}
+
+// consumed registers a record with the given number of traces.
+func (t *offsetTracker) consumed(offset int64, traces int) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if traces > 0 {
+		t.pending[offset] = traces
+	}
+	if offset >= t.next {
+		t.next = offset + 1
+	}
+}
+
+// release marks a single trace of the record at offset as done.
+func (t *offsetTracker) release(offset int64) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	remaining, ok := t.pending[offset]
+	if !ok {
+		return
+	}
+	if remaining <= 1 {
+		delete(t.pending, offset)
+		return
+	}
+	t.pending[offset] = remaining - 1
+}
+
+// committable returns the offset that can be safely committed. All records before it are in the wal.
+func (t *offsetTracker) committable() int64 {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	min := t.next
+	for offset := range t.pending {
+		if offset < min {
+			min = offset
+		}
+	}
+	return min
+}
+
+// partitionConsumer pushes the records of a single partition of the ingest topic into the tenant instances.
+type partitionConsumer struct {
+	partition     int32
+	label         string
+	client        ingest.Client
+	consumer      ingest.PartitionConsumer
+	tracker       *offsetTracker
+	lastCommitted int64
+}
+
+func (i *Ingester) startIngestConsumers() error {
+	partitions, err := ingestPartitions(i.cfg)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	i.ingestCancel = cancel
+
+	for _, partition := range partitions {
+		offset, err := i.ingestClient.CommittedOffset(partition)
+		if err != nil {
+			return fmt.Errorf("failed to get committed offset for partition %d: %w", partition, err)
+		}
+
+		consumer, err := i.ingestClient.Consume(partition, offset)
+		if err != nil {
+			return err
+		}
+
+		if offset < 0 {
+			offset = 0
+		}
+		c := &partitionConsumer{
+			partition:     partition,
+			label:         strconv.Itoa(int(partition)),
+			client:        i.ingestClient,
+			consumer:      consumer,
+			tracker:       newOffsetTracker(offset),
+			lastCommitted: offset,
+		}
+		i.ingestConsumers = append(i.ingestConsumers, c)
+
+		level.Info(log.Logger).Log("msg", "consuming ingest partition", "partition", partition, "offset", offset)
+
+		i.ingestConsumersDone.Add(1)
+		go func() {
+			defer i.ingestConsumersDone.Done()
+			i.consumePartition(ctx, c)
+		}()
+	}
+
+	i.ingestConsumersDone.Add(1)
+	go func() {
+		defer i.ingestConsumersDone.Done()
+		i.commitLoop(ctx)
+	}()
+
+	return nil
+}
+
+func (i *Ingester) stopIngestConsumers() {
+	if i.ingestCancel == nil {
+		return
+	}
+
+	for _, c := range i.ingestConsumers {
+		_ = c.consumer.Close()
+	}
+	i.ingestCancel()
+	i.ingestConsumersDone.Wait()
+
+	// commit whatever made it into the wal before shutting down
+	i.commitIngestOffsets()
+
+	if err := i.ingestClient.Close(); err != nil {
+		level.Warn(log.Logger).Log("msg", "failed to close ingest client", "err", err)
+	}
+}
+
+func (i *Ingester) consumePartition(ctx context.Context, c *partitionConsumer) {
+	for {
+		select {
+		case r, ok := <-c.consumer.Records():
+			if !ok {
+				return
+			}
+			i.pushIngestRecord(ctx, c, r)
+
+		case err := <-c.consumer.Errors():
+			level.Error(log.Logger).Log("msg", "error consuming ingest partition", "partition", c.partition, "err", err)
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// pushIngestRecord pushes the traces of a record into the instance of its tenant. Traces that are refused because of
+// a temporary condition, e.g. the live traces limit, are retried until they are accepted or the consumer is stopped,
+// which blocks the partition in the meantime. The offset of a record that was not completely pushed is never released,
+// so the record is consumed again after a restart. Traces that are refused permanently, e.g. because they are too
+// large, are dropped like the distributor drops them on the push path.
+func (i *Ingester) pushIngestRecord(ctx context.Context, c *partitionConsumer, r *ingest.Record) {
+	metricIngestRecordsConsumed.WithLabelValues(c.label).Inc()
+
+	req := r.Request
+	defer req.Release()
+
+	if len(req.Traces) != len(req.Ids) {
+		level.Error(log.Logger).Log("msg", "skipping ingest record with mismatched traces/ids", "partition", c.partition, "offset", r.Offset)
+		c.tracker.consumed(r.Offset, 0)
+		return
+	}
+
+	c.tracker.consumed(r.Offset, len(req.Traces))
+	offset := r.Offset
+	release := func() {
+		c.tracker.release(offset)
+	}
+
+	b := backoff.New(ctx, ingestRetryBackoff)
+	var inst *instance
+	for {
+		var err error
+		inst, err = i.getOrCreateInstance(r.TenantID)
+		if err == nil {
+			break
+		}
+		level.Error(log.Logger).Log("msg", "failed to get instance for ingest record", "tenant", r.TenantID, "err", err)
+		b.Wait()
+		if !b.Ongoing() {
+			return
+		}
+	}
+
+	for j := range req.Traces {
+		var searchData tempopb.PreallocBytes
+		if len(req.SearchData) > j {
+			searchData = req.SearchData[j]
+		}
+
+		b.Reset()
+		for {
+			// the instance takes ownership of the data it is passed, the record holds on to its own reference in case
+			//  the push is retried.
+			err := inst.pushBytes(ctx, req.Ids[j].Slice, req.Traces[j].Ref(), searchData.Ref(), release)
+			if err == nil {
+				break
+			}
+
+			if !isRetryableIngestError(err) {
+				// data that is rejected by the instance is never going to make it into the wal. release it right
+				//  away so the offset can move on.
+				release()
+				metricIngestTracesFailed.WithLabelValues(r.TenantID).Inc()
+				level.Debug(log.Logger).Log("msg", "failed to push trace from ingest record", "tenant", r.TenantID, "err", err)
+				break
+			}
+
+			metricIngestTracesRetried.WithLabelValues(r.TenantID).Inc()
+			b.Wait()
+			if !b.Ongoing() {
+				// the consumer is stopping. the offset is held and the record is consumed again after a restart.
+				return
+			}
+		}
+	}
+}
+
+// isRetryableIngestError returns true if a push failed because of a temporary condition of the instance.
+func isRetryableIngestError(err error) bool {
+	var liveTracesErr *liveTracesExceededError
+	return errors.As(err, &liveTracesErr)
+}
+
+func (i *Ingester) commitLoop(ctx context.Context) {
+	ticker := time.NewTicker(i.cfg.Ingest.CommitInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			i.commitIngestOffsets()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (i *Ingester) commitIngestOffsets() {
+	for _, c := range i.ingestConsumers {
+		offset := c.tracker.committable()
+		if offset <= c.lastCommitted {
+			continue
+		}
+
+		if err := c.client.Commit(c.partition, offset); err != nil {
+			level.Error(log.Logger).Log("msg", "failed to commit ingest offset", "partition", c.partition, "offset", offset, "err", err)
+			continue
+		}
+
+		c.lastCommitted = offset
+		metricIngestCommittedOffset.WithLabelValues(c.label).Set(float64(offset))
+	}
+}
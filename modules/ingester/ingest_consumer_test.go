@@ -0,0 +1,206 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/backoff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaveworks/common/user"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/pkg/ingest"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestOffsetTracker(t *testing.T) {
+	tracker := newOffsetTracker(5)
+	assert.Equal(t, int64(5), tracker.committable())
+
+	tracker.consumed(5, 2)
+	tracker.consumed(6, 1)
+	tracker.consumed(7, 0)
+	assert.Equal(t, int64(5), tracker.committable())
+
+	// releasing a later record does not move the offset
+	tracker.release(6)
+	assert.Equal(t, int64(5), tracker.committable())
+
+	tracker.release(5)
+	assert.Equal(t, int64(5), tracker.committable())
+	tracker.release(5)
+	assert.Equal(t, int64(8), tracker.committable())
+
+	// unknown offsets are ignored
+	tracker.release(3)
+	assert.Equal(t, int64(8), tracker.committable())
+}
+
+func TestIngestPartitions(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          string
+		consume     []int32
+		expected    []int32
+		expectedErr bool
+	}{
+		{
+			name:     "explicit",
+			id:       "ingester-1",
+			consume:  []int32{2, 3},
+			expected: []int32{2, 3},
+		},
+		{
+			name:     "ordinal",
+			id:       "ingester-3",
+			expected: []int32{3},
+		},
+		{
+			name:        "ordinal out of range",
+			id:          "ingester-4",
+			expectedErr: true,
+		},
+		{
+			name:        "no ordinal",
+			id:          "ingester",
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{}
+			cfg.LifecyclerConfig.ID = tc.id
+			cfg.Ingest.Partitions = 4
+			cfg.Ingest.ConsumePartitions = tc.consume
+
+			actual, err := ingestPartitions(cfg)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestIngestConsumerCommitsAfterWAL(t *testing.T) {
+	broker := ingest.NewLocalBroker()
+
+	cfg := defaultIngesterTestConfig()
+	cfg.Ingest.Enabled = true
+	cfg.Ingest.Partitions = 1
+	cfg.Ingest.ConsumePartitions = []int32{0}
+	cfg.Ingest.CommitInterval = time.Hour
+	cfg.ingestClient = broker
+
+	ingester := defaultIngesterModuleWithConfig(t, t.TempDir(), cfg)
+	defer func() {
+		require.NoError(t, ingester.stopping(nil))
+	}()
+
+	traceIDs := [][]byte{test.ValidTraceID(nil), test.ValidTraceID(nil)}
+	req := &tempopb.PushBytesRequest{}
+	for _, id := range traceIDs {
+		r := makeRequest(id)
+		req.Ids = append(req.Ids, r.Ids...)
+		req.Traces = append(req.Traces, r.Traces...)
+	}
+
+	err := broker.Produce(context.Background(), 0, []*ingest.Record{{TenantID: "test", Request: req}})
+	require.NoError(t, err)
+
+	// the traces become queryable once the record is consumed
+	ctx := user.InjectOrgID(context.Background(), "test")
+	require.Eventually(t, func() bool {
+		for _, id := range traceIDs {
+			resp, err := ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: id})
+			if err != nil || resp.Trace == nil {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// live traces are not in the wal, nothing is committed
+	ingester.commitIngestOffsets()
+	offset, err := broker.CommittedOffset(0)
+	require.NoError(t, err)
+	assert.Equal(t, ingest.OffsetNone, offset)
+
+	inst, ok := ingester.getInstanceByID("test")
+	require.True(t, ok)
+	require.NoError(t, inst.CutCompleteTraces(0, true))
+
+	ingester.commitIngestOffsets()
+	offset, err = broker.CommittedOffset(0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), offset)
+}
+
+func TestIngestConsumerRetriesLiveTracesExceeded(t *testing.T) {
+	defer func(b backoff.Config) { ingestRetryBackoff = b }(ingestRetryBackoff)
+	ingestRetryBackoff = backoff.Config{MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+	broker := ingest.NewLocalBroker()
+
+	cfg := defaultIngesterTestConfig()
+	cfg.Ingest.Enabled = true
+	cfg.Ingest.Partitions = 1
+	cfg.Ingest.ConsumePartitions = []int32{0}
+	cfg.Ingest.CommitInterval = time.Hour
+	cfg.ingestClient = broker
+
+	ingester := defaultIngesterModuleWithConfig(t, t.TempDir(), cfg)
+	defer func() {
+		require.NoError(t, ingester.stopping(nil))
+	}()
+
+	// the instance is at its live traces limit
+	inst, err := ingester.getOrCreateInstance("test")
+	require.NoError(t, err)
+	inst.traceCount.Store(math.MaxInt32)
+
+	id := test.ValidTraceID(nil)
+	err = broker.Produce(context.Background(), 0, []*ingest.Record{{TenantID: "test", Request: makeRequest(id)}})
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	findTrace := func() bool {
+		resp, err := ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{TraceID: id})
+		return err == nil && resp.Trace != nil && len(resp.Trace.Batches) > 0
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, findTrace())
+	assert.Equal(t, int64(0), ingester.ingestConsumers[0].tracker.committable())
+
+	// the trace is pushed once the instance is below its limit again
+	inst.traceCount.Store(0)
+	require.Eventually(t, findTrace, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, inst.CutCompleteTraces(0, true))
+	assert.Equal(t, int64(1), ingester.ingestConsumers[0].tracker.committable())
+}
+
+func TestIsRetryableIngestError(t *testing.T) {
+	liveTracesErr := &liveTracesExceededError{instanceID: "test", err: errors.New("limit")}
+
+	assert.True(t, isRetryableIngestError(liveTracesErr))
+	assert.True(t, isRetryableIngestError(fmt.Errorf("push failed: %w", liveTracesErr)))
+	assert.False(t, isRetryableIngestError(newTraceTooLargeError(test.ValidTraceID(nil), 10, 20)))
+	assert.False(t, isRetryableIngestError(status.Error(codes.FailedPrecondition, liveTracesErr.Error())))
+
+	// the error is returned to the distributor with its message, which is used to classify discarded spans
+	s := status.Convert(liveTracesErr)
+	assert.Equal(t, codes.FailedPrecondition, s.Code())
+	assert.Equal(t, liveTracesErr.Error(), s.Message())
+}
@@ -24,11 +24,13 @@ import (
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/flushqueues"
 	_ "github.com/grafana/tempo/pkg/gogocodec" // force gogo codec registration
+	"github.com/grafana/tempo/pkg/ingest"
 	"github.com/grafana/tempo/pkg/model"
 	"github.com/grafana/tempo/pkg/model/decoder"
 	v1 "github.com/grafana/tempo/pkg/model/v1"
 	v2 "github.com/grafana/tempo/pkg/model/v2"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/pkg/validation"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -39,11 +41,20 @@ import (
 // attempted.
 var ErrReadOnly = errors.New("Ingester is shutting down")
 
-var metricFlushQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
-	Namespace: "tempo",
-	Name:      "ingester_flush_queue_length",
-	Help:      "The total number of series pending in the flush queue.",
-})
+var (
+	metricFlushQueueLength = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_flush_queue_length",
+		Help:      "The total number of series pending in the flush queue.",
+	})
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_shared_request_bytes",
+		Help:      "The total size of received push requests still referenced by live traces.",
+	}, func() float64 {
+		return float64(tempopb.SharedBufferBytes())
+	})
+)
 
 const (
 	ingesterRingKey = "ring"
@@ -69,11 +80,21 @@ type Ingester struct {
 
 	limiter *Limiter
 
+	// Kafka ingest path
+	ingestClient        ingest.Client
+	ingestConsumers     []*partitionConsumer
+	ingestConsumersDone sync.WaitGroup
+	ingestCancel        context.CancelFunc
+
 	subservicesWatcher *services.FailureWatcher
 }
 
 // New makes a new Ingester.
 func New(cfg Config, store storage.Store, limits *overrides.Overrides, reg prometheus.Registerer) (*Ingester, error) {
+	if err := cfg.LateSpans.Validate(); err != nil {
+		return nil, err
+	}
+
 	i := &Ingester{
 		cfg:          cfg,
 		instances:    map[string]*instance{},
@@ -84,6 +105,17 @@ func New(cfg Config, store storage.Store, limits *overrides.Overrides, reg prome
 
 	i.local = store.WAL().LocalBackend()
 
+	if cfg.Ingest.Enabled {
+		i.ingestClient = cfg.ingestClient
+		if i.ingestClient == nil {
+			var err error
+			i.ingestClient, err = ingest.NewKafkaClient(cfg.Ingest, log.Logger)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	i.flushQueuesDone.Add(cfg.ConcurrentFlushes)
 	for j := 0; j < cfg.ConcurrentFlushes; j++ {
 		go i.flushLoop(j)
@@ -126,6 +158,14 @@ func (i *Ingester) starting(ctx context.Context) error {
 		return fmt.Errorf("failed to start lifecycle: %w", err)
 	}
 
+	// Consume the ingest topic once the wal has been replayed. Consumption starts at the last committed
+	//  offset which is guaranteed to be in the wal.
+	if i.ingestClient != nil {
+		if err := i.startIngestConsumers(); err != nil {
+			return fmt.Errorf("failed to start ingest consumers: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -149,6 +189,7 @@ func (i *Ingester) loop(ctx context.Context) error {
 
 // stopping is run when ingester is asked to stop
 func (i *Ingester) stopping(_ error) error {
+	i.stopIngestConsumers()
 	i.markUnavailable()
 
 	if i.flushQueues != nil {
@@ -189,16 +230,19 @@ func (i *Ingester) PushBytes(ctx context.Context, req *tempopb.PushBytesRequest)
 	for i, t := range req.Traces {
 		trace, err := v1Decoder.PrepareForRead([][]byte{t.Slice})
 		if err != nil {
+			req.Release()
 			return nil, fmt.Errorf("error calling v1.PrepareForRead %w", err)
 		}
 
 		now := uint32(time.Now().Unix())
 		v2Slice, err := v2Decoder.PrepareForWrite(trace, now, now)
 		if err != nil {
+			req.Release()
 			return nil, fmt.Errorf("error calling v2.PrepareForWrite %w", err)
 		}
 
-		req.Traces[i].Slice = v2Slice
+		req.Traces[i].Release()
+		req.Traces[i] = tempopb.PreallocBytes{Slice: v2Slice}
 	}
 
 	return i.PushBytesV2(ctx, req)
@@ -208,20 +252,24 @@ func (i *Ingester) PushBytes(ctx context.Context, req *tempopb.PushBytesRequest)
 //  defined by ./pkg/model/v2
 func (i *Ingester) PushBytesV2(ctx context.Context, req *tempopb.PushBytesRequest) (*tempopb.PushResponse, error) {
 	if i.readonly {
+		req.Release()
 		return nil, ErrReadOnly
 	}
 
 	if len(req.Traces) != len(req.Ids) {
+		req.Release()
 		return nil, status.Errorf(codes.InvalidArgument, "mismatched traces/ids length: %d, %d", len(req.Traces), len(req.Ids))
 	}
 
 	instanceID, err := user.ExtractOrgID(ctx)
 	if err != nil {
+		req.Release()
 		return nil, err
 	}
 
 	instance, err := i.getOrCreateInstance(instanceID)
 	if err != nil {
+		req.Release()
 		return nil, err
 	}
 
@@ -264,6 +312,44 @@ func (i *Ingester) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequ
 	}, nil
 }
 
+// FindTracesByID looks up a batch of trace ids in the ingester. Only the traces found are returned.
+func (i *Ingester) FindTracesByID(ctx context.Context, req *tempopb.TracesByIDRequest) (*tempopb.TracesByIDResponse, error) {
+	// tracing instrumentation
+	span, ctx := opentracing.StartSpanFromContext(ctx, "ingester.FindTracesByID")
+	defer span.Finish()
+
+	resp := &tempopb.TracesByIDResponse{
+		Traces: map[string]*tempopb.TraceByIDResult{},
+	}
+
+	instanceID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inst, ok := i.getInstanceByID(instanceID)
+	if !ok || inst == nil {
+		return resp, nil
+	}
+
+	for _, id := range req.TraceIDs {
+		if !validation.ValidTraceID(id) {
+			return nil, fmt.Errorf("invalid trace id")
+		}
+
+		trace, err := inst.FindTraceByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if trace != nil {
+			resp.Traces[util.TraceIDToHexString(id)] = &tempopb.TraceByIDResult{Trace: trace}
+		}
+	}
+
+	span.LogFields(ot_log.Int("traces found", len(resp.Traces)))
+
+	return resp, nil
+}
+
 func (i *Ingester) CheckReady(ctx context.Context) error {
 	if err := i.lifecycler.CheckReady(ctx); err != nil {
 		return fmt.Errorf("ingester check ready failed %w", err)
@@ -287,6 +373,7 @@ func (i *Ingester) getOrCreateInstance(instanceID string) (*instance, error) {
 		if err != nil {
 			return nil, err
 		}
+		inst.cutTraces = newCutTraces(i.cfg.LateSpans)
 		i.instances[instanceID] = inst
 	}
 	return inst, nil
@@ -310,7 +310,10 @@ func TestFlush(t *testing.T) {
 }
 
 func defaultIngesterModule(t *testing.T, tmpDir string) *Ingester {
-	ingesterConfig := defaultIngesterTestConfig()
+	return defaultIngesterModuleWithConfig(t, tmpDir, defaultIngesterTestConfig())
+}
+
+func defaultIngesterModuleWithConfig(t *testing.T, tmpDir string, ingesterConfig Config) *Ingester {
 	limits, err := overrides.NewOverrides(defaultLimitsTestConfig())
 	require.NoError(t, err, "unexpected error creating overrides")
 
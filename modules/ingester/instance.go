@@ -19,6 +19,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/atomic"
 	"google.golang.org/grpc/codes"
+	grpc_status "google.golang.org/grpc/status"
 
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/model"
@@ -54,6 +55,22 @@ func (e traceTooLargeError) Error() string {
 		overrides.ErrorPrefixTraceTooLarge, e.maxBytes, e.reqSize, hex.EncodeToString(e.traceID))
 }
 
+// liveTracesExceededError is returned when a push is refused because the tenant has too many live traces. It is
+// temporary, the push succeeds once traces have been cut.
+type liveTracesExceededError struct {
+	instanceID string
+	err        error
+}
+
+func (e *liveTracesExceededError) Error() string {
+	return fmt.Sprintf("%s max live traces exceeded for tenant %s: %v", overrides.ErrorPrefixLiveTracesExceeded, e.instanceID, e.err)
+}
+
+// GRPCStatus returns the status the error is returned with by the gRPC server.
+func (e *liveTracesExceededError) GRPCStatus() *grpc_status.Status {
+	return grpc_status.New(codes.FailedPrecondition, e.Error())
+}
+
 // Errors returned on Query.
 var (
 	ErrTraceMissing = errors.New("Trace missing")
@@ -92,6 +109,7 @@ type instance struct {
 	traces      map[uint32]*liveTrace
 	largeTraces map[uint32]int // maxBytes that trace exceeded
 	traceCount  atomic.Int32
+	cutTraces   *cutTraces // recently cut traces, nil if late spans are not tracked
 
 	blocksMtx        sync.RWMutex
 	headBlock        *wal.AppendBlock
@@ -156,13 +174,20 @@ func newInstance(instanceID string, limiter *Limiter, writer tempodb.Writer, l *
 func (i *instance) PushBytesRequest(ctx context.Context, req *tempopb.PushBytesRequest) error {
 	for j := range req.Traces {
 		// Search data is optional.
-		var searchData []byte
-		if len(req.SearchData) > j && len(req.SearchData[j].Slice) > 0 {
-			searchData = req.SearchData[j].Slice
+		var searchData tempopb.PreallocBytes
+		if len(req.SearchData) > j {
+			searchData = req.SearchData[j]
 		}
 
-		err := i.PushBytes(ctx, req.Ids[j].Slice, req.Traces[j].Slice, searchData)
+		err := i.pushBytes(ctx, req.Ids[j].Slice, req.Traces[j], searchData, nil)
 		if err != nil {
+			// the remaining traces are not pushed
+			for k := j + 1; k < len(req.Traces); k++ {
+				req.Traces[k].Release()
+				if len(req.SearchData) > k {
+					req.SearchData[k].Release()
+				}
+			}
 			return err
 		}
 	}
@@ -171,8 +196,35 @@ func (i *instance) PushBytesRequest(ctx context.Context, req *tempopb.PushBytesR
 
 // PushBytes is used to push an unmarshalled tempopb.Trace to the instance
 func (i *instance) PushBytes(ctx context.Context, id []byte, traceBytes []byte, searchData []byte) error {
-	i.measureReceivedBytes(traceBytes, searchData)
+	return i.pushBytes(ctx, id, tempopb.PreallocBytes{Slice: traceBytes}, tempopb.PreallocBytes{Slice: searchData}, nil)
+}
+
+// pushBytes pushes a trace to the instance. onAppend, if not nil, is called once the pushed data has been
+// appended to the head block. It is not called if an error is returned.
+// The instance takes ownership of traceBytes and searchData and releases them once they are no longer needed.
+func (i *instance) pushBytes(ctx context.Context, id []byte, traceBytes, searchData tempopb.PreallocBytes, onAppend func()) error {
+	i.measureReceivedBytes(traceBytes.Slice, searchData.Slice)
+
+	err := i.validatePush(id)
+	if err == nil && i.cutTraces != nil {
+		var appended bool
+		appended, err = i.pushLate(id, traceBytes.Slice, searchData.Slice, onAppend)
+		if appended {
+			traceBytes.Release()
+			searchData.Release()
+			return nil
+		}
+	}
+	if err != nil {
+		traceBytes.Release()
+		searchData.Release()
+		return err
+	}
+
+	return i.push(ctx, id, traceBytes, searchData, onAppend)
+}
 
+func (i *instance) validatePush(id []byte) error {
 	if !validation.ValidTraceID(id) {
 		return status.Errorf(codes.InvalidArgument, "%s is not a valid traceid", hex.EncodeToString(id))
 	}
@@ -180,32 +232,114 @@ func (i *instance) PushBytes(ctx context.Context, id []byte, traceBytes []byte,
 	// check for max traces before grabbing the lock to better load shed
 	err := i.limiter.AssertMaxTracesPerUser(i.instanceID, int(i.traceCount.Load()))
 	if err != nil {
-		return status.Errorf(codes.FailedPrecondition, "%s max live traces exceeded for tenant %s: %v", overrides.ErrorPrefixLiveTracesExceeded, i.instanceID, err)
+		return &liveTracesExceededError{instanceID: i.instanceID, err: err}
 	}
 
-	return i.push(ctx, id, traceBytes, searchData)
+	return nil
 }
 
-func (i *instance) push(ctx context.Context, id, traceBytes, searchData []byte) error {
+// pushLate handles pushes for traces that were cut within the late spans window. Depending on the policy the data is
+// appended directly to the head block, in which case true is returned. Otherwise it is pushed as a new partial trace.
+func (i *instance) pushLate(id, traceBytes, searchData []byte, onAppend func()) (bool, error) {
+	now := time.Now()
+
+	i.tracesMtx.Lock()
+	tkn := i.tokenForTraceID(id)
+	_, live := i.traces[tkn]
+	_, large := i.largeTraces[tkn]
+	cut, late := i.cutTraces.get(tkn, now)
+	i.tracesMtx.Unlock()
+
+	if !late {
+		return false, nil
+	}
+	metricLateTraceDelay.WithLabelValues(i.instanceID).Observe(now.Sub(cut.cutAt).Seconds())
+
+	destination := lateDestinationNewTrace
+	if i.cutTraces.policy == LateSpansPolicyHeadBlock && !live && !large {
+		appended, err := i.appendLateTraceToHeadBlock(id, traceBytes, searchData, cut.blockID)
+		if err != nil {
+			return false, err
+		}
+		if appended {
+			destination = lateDestinationHeadBlock
+		}
+	}
+
+	metricLateTraceSegments.WithLabelValues(i.instanceID, destination).Inc()
+	metricLateTraceBytes.WithLabelValues(i.instanceID, destination).Add(float64(len(traceBytes)))
+
+	if destination != lateDestinationHeadBlock {
+		return false, nil
+	}
+	if onAppend != nil {
+		onAppend()
+	}
+	return true, nil
+}
+
+// appendLateTraceToHeadBlock appends a trace segment to the head block if it is still the block with the given ID.
+// It returns false if the head block has been cut since.
+func (i *instance) appendLateTraceToHeadBlock(id, traceBytes, searchData []byte, blockID uuid.UUID) (bool, error) {
+	segmentDecoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	start, end, err := segmentDecoder.FastRange(traceBytes)
+	if err != nil {
+		return false, fmt.Errorf("failed to get range of late trace: %w", err)
+	}
+	out, err := segmentDecoder.ToObject([][]byte{traceBytes})
+	if err != nil {
+		return false, err
+	}
+
+	var search [][]byte
+	if len(searchData) > 0 {
+		search = [][]byte{searchData}
+	}
+
+	i.blocksMtx.Lock()
+	defer i.blocksMtx.Unlock()
+
+	if i.headBlock.BlockID() != blockID {
+		return false, nil
+	}
+
+	return true, i.appendToHeadBlock(id, out, search, start, end)
+}
+
+// push pushes the trace bytes to the live trace. The live trace takes ownership of traceBytes and searchData if the
+// push is successful, otherwise they are released.
+func (i *instance) push(ctx context.Context, id []byte, traceBytes, searchData tempopb.PreallocBytes, onAppend func()) error {
 	i.tracesMtx.Lock()
 	defer i.tracesMtx.Unlock()
 
 	tkn := i.tokenForTraceID(id)
 
 	if maxBytes, ok := i.largeTraces[tkn]; ok {
-		return status.Errorf(codes.FailedPrecondition, (newTraceTooLargeError(id, maxBytes, len(traceBytes)).Error()))
+		err := status.Errorf(codes.FailedPrecondition, (newTraceTooLargeError(id, maxBytes, len(traceBytes.Slice)).Error()))
+		traceBytes.Release()
+		searchData.Release()
+		return err
 	}
 
 	trace := i.getOrCreateTrace(id)
-	err := trace.Push(ctx, i.instanceID, traceBytes, searchData)
+	err := trace.Push(ctx, i.instanceID, traceBytes.Slice, searchData.Slice)
 	if err != nil {
+		traceBytes.Release()
+		searchData.Release()
 		if e, ok := err.(*traceTooLargeError); ok {
 			i.largeTraces[tkn] = trace.maxBytes
 			return status.Errorf(codes.FailedPrecondition, e.Error())
 		}
+		return err
 	}
+	trace.buffers = append(trace.buffers, traceBytes, searchData)
 
-	return err
+	if onAppend != nil {
+		trace.onAppend = append(trace.onAppend, onAppend)
+	}
+
+	return nil
 }
 
 func (i *instance) measureReceivedBytes(traceBytes []byte, searchData []byte) {
@@ -221,28 +355,82 @@ func (i *instance) CutCompleteTraces(cutoff time.Duration, immediate bool) error
 	tracesToCut := i.tracesToCut(cutoff, immediate)
 	segmentDecoder := model.MustNewSegmentDecoder(model.CurrentEncoding)
 
-	for _, t := range tracesToCut {
+	// blocks the traces were cut into, used to detect late spans
+	var blockIDs []uuid.UUID
+	if i.cutTraces != nil {
+		blockIDs = make([]uuid.UUID, 0, len(tracesToCut))
+		defer func() {
+			i.rememberCutTraces(tracesToCut, blockIDs)
+		}()
+	}
+
+	for j, t := range tracesToCut {
 		// sort batches before cutting to reduce combinations during compaction
 		sortByteSlices(t.batches)
 
 		out, err := segmentDecoder.ToObject(t.batches)
 		if err != nil {
+			i.restoreTraces(tracesToCut[j:])
 			return err
 		}
 
-		err = i.writeTraceToHeadBlock(t.traceID, out, t.searchData, t.start, t.end)
+		blockID, err := i.writeTraceToHeadBlock(t.traceID, out, t.searchData, t.start, t.end)
 		if err != nil {
+			i.restoreTraces(tracesToCut[j:])
 			return err
 		}
+		if blockIDs != nil {
+			blockIDs = append(blockIDs, blockID)
+		}
 
-		// return trace byte slices to be reused by proto marshalling
+		for _, fn := range t.onAppend {
+			fn()
+		}
+
+		// release the buffers backing the trace. the appender copies the object, so they are not needed anymore.
 		//  WARNING: can't reuse traceid's b/c the appender takes ownership of byte slices that are passed to it
-		tempopb.ReuseByteSlices(t.batches)
+		t.release()
 	}
 
 	return nil
 }
 
+// restoreTraces puts traces that could not be written to the head block back into the live traces, so that they are
+// written by the next cut and their onAppend callbacks are only called once they have been written. A trace that was
+// pushed again in the meantime is merged into the new live trace.
+func (i *instance) restoreTraces(traces []*liveTrace) {
+	i.tracesMtx.Lock()
+	defer i.tracesMtx.Unlock()
+
+	for _, t := range traces {
+		// the trace is not retained with the requests it was pushed with, like any other live trace
+		t.detach()
+
+		tkn := i.tokenForTraceID(t.traceID)
+		if live, ok := i.traces[tkn]; ok {
+			live.merge(t)
+			continue
+		}
+		i.traces[tkn] = t
+	}
+	i.traceCount.Store(int32(len(i.traces)))
+}
+
+// rememberCutTraces adds the traces that were written to the head block to the index of cut traces. blockIDs holds
+// the block of each written trace, in order.
+func (i *instance) rememberCutTraces(traces []*liveTrace, blockIDs []uuid.UUID) {
+	now := time.Now()
+
+	i.tracesMtx.Lock()
+	defer i.tracesMtx.Unlock()
+
+	i.cutTraces.prune(now)
+	for j, blockID := range blockIDs {
+		i.cutTraces.add(i.tokenForTraceID(traces[j].traceID), blockID, now)
+	}
+	metricCutTraces.WithLabelValues(i.instanceID).Set(float64(i.cutTraces.len()))
+}
+
 // CutBlockIfReady cuts a completingBlock from the HeadBlock if ready.
 // Returns the ID of a block if one was cut or a nil ID if one was not cut, along with the error (if any).
 func (i *instance) CutBlockIfReady(maxBlockLifetime time.Duration, maxBlockBytes uint64, immediate bool) (uuid.UUID, error) {
@@ -483,7 +671,8 @@ func (i *instance) getOrCreateTrace(traceID []byte) *liveTrace {
 
 	maxBytes := i.limiter.limits.MaxBytesPerTrace(i.instanceID)
 	maxSearchBytes := i.limiter.limits.MaxSearchBytesPerTrace(i.instanceID)
-	trace = newTrace(traceID, maxBytes, maxSearchBytes)
+	maxSpans := i.limiter.limits.MaxSpansPerTrace(i.instanceID)
+	trace = newTrace(traceID, maxBytes, maxSearchBytes, maxSpans)
 	i.traces[fp] = trace
 	i.tracesCreatedTotal.Inc()
 	i.traceCount.Inc()
@@ -549,17 +738,27 @@ func (i *instance) tracesToCut(cutoff time.Duration, immediate bool) []*liveTrac
 		if cutoffTime.After(trace.lastAppend) || immediate {
 			tracesToCut = append(tracesToCut, trace)
 			delete(i.traces, key)
+			continue
 		}
+
+		// traces that stay live must not retain the requests they were pushed with
+		trace.detach()
 	}
 	i.traceCount.Store(int32(len(i.traces)))
 
 	return tracesToCut
 }
 
-func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, searchData [][]byte, start, end uint32) error {
+// writeTraceToHeadBlock appends a trace to the head block and returns the ID of the head block.
+func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, searchData [][]byte, start, end uint32) (uuid.UUID, error) {
 	i.blocksMtx.Lock()
 	defer i.blocksMtx.Unlock()
 
+	return i.headBlock.BlockID(), i.appendToHeadBlock(id, b, searchData, start, end)
+}
+
+// appendToHeadBlock must be called under the i.blocksMtx lock
+func (i *instance) appendToHeadBlock(id common.ID, b []byte, searchData [][]byte, start, end uint32) error {
 	err := i.headBlock.Append(id, b, start, end)
 	if err != nil {
 		return err
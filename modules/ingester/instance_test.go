@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/binary"
 	"math/rand"
+	"runtime"
 	"testing"
 	"time"
 
@@ -400,6 +401,39 @@ func TestInstanceCutCompleteTraces(t *testing.T) {
 	}
 }
 
+func TestInstanceCutCompleteTracesRestoresFailedTraces(t *testing.T) {
+	instance := defaultInstance(t, t.TempDir())
+
+	newLiveTrace := func(batch []byte, appended *bool) *liveTrace {
+		id := make([]byte, 16)
+		rand.Read(id)
+		tr := newTrace(id, 0, 0, 0)
+		tr.batches = [][]byte{batch}
+		tr.onAppend = []func(){func() { *appended = true }}
+		instance.traces[instance.tokenForTraceID(id)] = tr
+		return tr
+	}
+
+	// the segment of the bad trace is too short to be converted to an object
+	var badAppended, goodAppended bool
+	bad := newLiveTrace([]byte{1, 2}, &badAppended)
+	segment, err := model.MustNewSegmentDecoder(model.CurrentEncoding).PrepareForWrite(test.MakeTrace(1, nil), 0, 0)
+	require.NoError(t, err)
+	good := newLiveTrace(segment, &goodAppended)
+
+	err = instance.CutCompleteTraces(0, true)
+	require.Error(t, err)
+
+	// the failed trace is live again and not reported as appended
+	assert.False(t, badAppended)
+	assert.Same(t, bad, instance.traces[instance.tokenForTraceID(bad.traceID)])
+
+	// depending on the order of the cut the good trace has either been appended or is live again
+	_, live := instance.traces[instance.tokenForTraceID(good.traceID)]
+	assert.NotEqual(t, goodAppended, live)
+	assert.Equal(t, int32(len(instance.traces)), instance.traceCount.Load())
+}
+
 func TestInstanceCutBlockIfReady(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -653,6 +687,153 @@ func BenchmarkInstancePushExistingTrace(b *testing.B) {
 	}
 }
 
+func BenchmarkInstancePushBytesRequestAndCut(b *testing.B) {
+	request := &tempopb.PushBytesRequest{}
+	for i := 0; i < 100; i++ {
+		r := makeRequest(nil)
+		request.Ids = append(request.Ids, r.Ids...)
+		request.Traces = append(request.Traces, r.Traces...)
+	}
+	data, err := request.Marshal()
+	require.NoError(b, err)
+
+	for _, tc := range []struct {
+		name      string
+		unmarshal func(*tempopb.PushBytesRequest, []byte) error
+	}{
+		{name: "copy", unmarshal: (*tempopb.PushBytesRequest).Unmarshal},
+		{name: "shared", unmarshal: (*tempopb.PushBytesRequest).UnmarshalShared},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			instance := defaultInstance(b, b.TempDir())
+
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				req := &tempopb.PushBytesRequest{}
+				require.NoError(b, tc.unmarshal(req, data))
+				require.NoError(b, instance.PushBytesRequest(context.Background(), req))
+				require.NoError(b, instance.CutCompleteTraces(0, true))
+			}
+		})
+	}
+}
+
+// BenchmarkInstancePushLiveTraces pushes requests of 100 traces that stay live over many pushes, like traces of long
+// running requests do, and reports the throughput in spans, the GC cycles and the size of the retained requests.
+func BenchmarkInstancePushLiveTraces(b *testing.B) {
+	const (
+		tracesPerRequest = 100
+		liveTraces       = 10_000
+		detachEvery      = 100 // pushes between cuts that detach the live traces
+		cutEvery         = 1000
+		spansPerRequest  = tracesPerRequest * 10 // makeRequest creates traces of 10 spans
+	)
+
+	requests := make([][]byte, liveTraces/tracesPerRequest)
+	for i := range requests {
+		request := &tempopb.PushBytesRequest{}
+		for j := 0; j < tracesPerRequest; j++ {
+			id := make([]byte, 16)
+			binary.LittleEndian.PutUint32(id, uint32(i*tracesPerRequest+j+1))
+			r := makeRequest(id)
+			request.Ids = append(request.Ids, r.Ids...)
+			request.Traces = append(request.Traces, r.Traces...)
+		}
+		data, err := request.Marshal()
+		require.NoError(b, err)
+		requests[i] = data
+	}
+
+	for _, tc := range []struct {
+		name      string
+		unmarshal func(*tempopb.PushBytesRequest, []byte) error
+	}{
+		{name: "copy", unmarshal: (*tempopb.PushBytesRequest).Unmarshal},
+		{name: "shared", unmarshal: (*tempopb.PushBytesRequest).UnmarshalShared},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			instance := defaultInstance(b, b.TempDir())
+
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			maxRetained := int64(0)
+			sharedBefore := tempopb.SharedBufferBytes()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				// the slice is copied since the shared unmarshalling references it
+				data := append([]byte(nil), requests[i%len(requests)]...)
+
+				req := &tempopb.PushBytesRequest{}
+				require.NoError(b, tc.unmarshal(req, data))
+				require.NoError(b, instance.PushBytesRequest(context.Background(), req))
+
+				if retained := tempopb.SharedBufferBytes() - sharedBefore; retained > maxRetained {
+					maxRetained = retained
+				}
+
+				switch {
+				case (i+1)%cutEvery == 0:
+					require.NoError(b, instance.CutCompleteTraces(0, true))
+				case (i+1)%detachEvery == 0:
+					require.NoError(b, instance.CutCompleteTraces(-time.Hour, false))
+				}
+			}
+			elapsed := time.Since(start)
+			b.StopTimer()
+			runtime.ReadMemStats(&after)
+
+			b.ReportMetric(float64(b.N*spansPerRequest)/elapsed.Seconds(), "spans/s")
+			b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gc/op")
+			b.ReportMetric(float64(maxRetained)/1e6, "retained-MB")
+		})
+	}
+}
+
+func TestInstanceDetachesLiveTraces(t *testing.T) {
+	instance := defaultInstance(t, t.TempDir())
+
+	request := &tempopb.PushBytesRequest{}
+	traceIDs := [][]byte{test.ValidTraceID(nil), test.ValidTraceID(nil)}
+	for _, id := range traceIDs {
+		r := makeRequest(id)
+		request.Ids = append(request.Ids, r.Ids...)
+		request.Traces = append(request.Traces, r.Traces...)
+	}
+	data, err := request.Marshal()
+	require.NoError(t, err)
+
+	before := tempopb.SharedBufferBytes()
+
+	req := &tempopb.PushBytesRequest{}
+	require.NoError(t, req.UnmarshalShared(data))
+	require.NoError(t, instance.PushBytesRequest(context.Background(), req))
+	assert.Equal(t, before+int64(len(data)), tempopb.SharedBufferBytes())
+
+	// traces that stay live release the request
+	require.NoError(t, instance.CutCompleteTraces(-time.Hour, false))
+	assert.Equal(t, before, tempopb.SharedBufferBytes())
+	assert.Len(t, instance.traces, 2)
+
+	// the request can be reused without affecting the live traces
+	for i := range data {
+		data[i] = 0
+	}
+	for _, id := range traceIDs {
+		tr, err := instance.FindTraceByID(context.Background(), id)
+		require.NoError(t, err)
+		require.NotNil(t, tr)
+		assert.NotEmpty(t, tr.Batches)
+	}
+
+	require.NoError(t, instance.CutCompleteTraces(0, true))
+	assert.Equal(t, before, tempopb.SharedBufferBytes())
+}
+
 func BenchmarkInstanceFindTraceByID(b *testing.B) {
 	instance := defaultInstance(b, b.TempDir())
 	traceID := []byte{1, 2, 3, 4, 5, 6, 7, 8}
@@ -0,0 +1,154 @@
+package ingester
+
+import (
+	"container/list"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// LateSpansPolicyNone disables tracking of cut traces. Late spans start a new partial trace.
+	LateSpansPolicyNone = "none"
+	// LateSpansPolicyNewTrace tracks cut traces to report late spans, but late spans still start a new partial trace.
+	LateSpansPolicyNewTrace = "new_trace"
+	// LateSpansPolicyHeadBlock appends late spans directly to the head block if the trace was cut into the current
+	// head block. Otherwise they start a new partial trace.
+	LateSpansPolicyHeadBlock = "head_block"
+)
+
+const (
+	lateDestinationNewTrace  = "new_trace"
+	lateDestinationHeadBlock = "head_block"
+)
+
+var (
+	metricLateTraceSegments = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_late_trace_segments_total",
+		Help:      "The total number of trace segments received for traces that were already cut, per tenant and destination.",
+	}, []string{"tenant", "destination"})
+	metricLateTraceBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_late_trace_bytes_total",
+		Help:      "The total number of trace bytes received for traces that were already cut, per tenant and destination.",
+	}, []string{"tenant", "destination"})
+	metricCutTraces = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_late_spans_cut_traces",
+		Help:      "The current number of recently cut traces remembered to detect late spans, per tenant.",
+	}, []string{"tenant"})
+	metricLateTraceDelay = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "tempo",
+		Name:      "ingester_late_trace_delay_seconds",
+		Help:      "Time between cutting a trace and receiving late spans for it.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 10),
+	}, []string{"tenant"})
+)
+
+// LateSpansConfig configures how the ingester handles spans that arrive after their trace has been cut.
+type LateSpansConfig struct {
+	Policy    string        `yaml:"policy"`
+	Window    time.Duration `yaml:"window"`
+	MaxTraces int           `yaml:"max_traces"`
+}
+
+// RegisterFlagsAndApplyDefaults registers the flags.
+func (cfg *LateSpansConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.Policy, prefix+".policy", LateSpansPolicyNone, "Policy for spans of traces that were already cut. One of none, new_trace or head_block.")
+	f.DurationVar(&cfg.Window, prefix+".window", 2*time.Minute, "Duration cut traces are remembered to detect late spans.")
+	f.IntVar(&cfg.MaxTraces, prefix+".max-traces", 100000, "Maximum number of cut traces remembered per tenant.")
+}
+
+// Validate checks the config for errors.
+func (cfg *LateSpansConfig) Validate() error {
+	switch cfg.Policy {
+	case "", LateSpansPolicyNone, LateSpansPolicyNewTrace, LateSpansPolicyHeadBlock:
+	default:
+		return fmt.Errorf("unknown late spans policy %q", cfg.Policy)
+	}
+	if newCutTraces(*cfg) != nil && (cfg.Window <= 0 || cfg.MaxTraces <= 0) {
+		return fmt.Errorf("late spans window and max traces must be greater than zero")
+	}
+	return nil
+}
+
+type cutTrace struct {
+	token   uint32
+	blockID uuid.UUID
+	cutAt   time.Time
+}
+
+// cutTraces is a short-lived index of recently cut traces. Traces are evicted after the window or, if the index is
+// full, oldest first. It is not safe for concurrent use.
+type cutTraces struct {
+	policy    string
+	window    time.Duration
+	maxTraces int
+
+	entries map[uint32]*list.Element
+	order   *list.List // *cutTrace, oldest first
+}
+
+// newCutTraces returns the index for the given config or nil if late spans are not tracked.
+func newCutTraces(cfg LateSpansConfig) *cutTraces {
+	if cfg.Policy == "" || cfg.Policy == LateSpansPolicyNone {
+		return nil
+	}
+
+	return &cutTraces{
+		policy:    cfg.Policy,
+		window:    cfg.Window,
+		maxTraces: cfg.MaxTraces,
+		entries:   map[uint32]*list.Element{},
+		order:     list.New(),
+	}
+}
+
+// add records that the trace with the given token was cut into a block. A trace that is cut again moves to the back.
+func (c *cutTraces) add(token uint32, blockID uuid.UUID, now time.Time) {
+	if e, ok := c.entries[token]; ok {
+		c.order.Remove(e)
+	}
+	c.entries[token] = c.order.PushBack(&cutTrace{token: token, blockID: blockID, cutAt: now})
+
+	for c.order.Len() > c.maxTraces {
+		c.remove(c.order.Front())
+	}
+}
+
+// get returns the cut trace for the token if it was cut within the window.
+func (c *cutTraces) get(token uint32, now time.Time) (*cutTrace, bool) {
+	e, ok := c.entries[token]
+	if !ok {
+		return nil, false
+	}
+	t := e.Value.(*cutTrace)
+	if now.Sub(t.cutAt) > c.window {
+		return nil, false
+	}
+	return t, true
+}
+
+// prune removes all traces cut before the window.
+func (c *cutTraces) prune(now time.Time) {
+	for e := c.order.Front(); e != nil; e = c.order.Front() {
+		if now.Sub(e.Value.(*cutTrace).cutAt) <= c.window {
+			return
+		}
+		c.remove(e)
+	}
+}
+
+func (c *cutTraces) remove(e *list.Element) {
+	c.order.Remove(e)
+	delete(c.entries, e.Value.(*cutTrace).token)
+}
+
+func (c *cutTraces) len() int {
+	return c.order.Len()
+}
@@ -0,0 +1,128 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestCutTraces(t *testing.T) {
+	require.Nil(t, newCutTraces(LateSpansConfig{Policy: LateSpansPolicyNone}))
+	require.Nil(t, newCutTraces(LateSpansConfig{}))
+
+	c := newCutTraces(LateSpansConfig{Policy: LateSpansPolicyNewTrace, Window: time.Minute, MaxTraces: 2})
+	now := time.Now()
+	block1, block2 := uuid.New(), uuid.New()
+
+	c.add(1, block1, now)
+	c.add(2, block1, now.Add(time.Second))
+
+	cut, ok := c.get(1, now.Add(time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, block1, cut.blockID)
+
+	// outside of the window
+	_, ok = c.get(1, now.Add(time.Minute+time.Millisecond))
+	assert.False(t, ok)
+
+	// cutting a trace again moves it to the back
+	c.add(1, block2, now.Add(2*time.Second))
+	cut, ok = c.get(1, now.Add(2*time.Second))
+	require.True(t, ok)
+	assert.Equal(t, block2, cut.blockID)
+
+	// the oldest trace is evicted when full
+	c.add(3, block2, now.Add(3*time.Second))
+	assert.Equal(t, 2, c.len())
+	_, ok = c.get(2, now.Add(3*time.Second))
+	assert.False(t, ok)
+
+	c.prune(now.Add(time.Minute + 2*time.Second + time.Millisecond))
+	assert.Equal(t, 1, c.len())
+	_, ok = c.get(3, now.Add(time.Minute))
+	assert.True(t, ok)
+
+	c.prune(now.Add(time.Hour))
+	assert.Equal(t, 0, c.len())
+}
+
+func TestLateSpansConfigValidate(t *testing.T) {
+	assert.NoError(t, (&LateSpansConfig{}).Validate())
+	assert.NoError(t, (&LateSpansConfig{Policy: LateSpansPolicyHeadBlock, Window: time.Minute, MaxTraces: 1}).Validate())
+	assert.Error(t, (&LateSpansConfig{Policy: "unknown"}).Validate())
+	assert.Error(t, (&LateSpansConfig{Policy: LateSpansPolicyNewTrace}).Validate())
+}
+
+func TestInstanceLateSpans(t *testing.T) {
+	tests := []struct {
+		policy string
+		// whether late spans are appended to the head block instead of starting a new live trace
+		expectedAppended bool
+	}{
+		{policy: LateSpansPolicyNone},
+		{policy: LateSpansPolicyNewTrace},
+		{policy: LateSpansPolicyHeadBlock, expectedAppended: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.policy, func(t *testing.T) {
+			i := defaultInstance(t, t.TempDir())
+			i.cutTraces = newCutTraces(LateSpansConfig{Policy: tc.policy, Window: time.Minute, MaxTraces: 10})
+
+			id := test.ValidTraceID(nil)
+			spans := 0
+			push := func(expectedAppended bool) {
+				testTrace := test.MakeTrace(2, id)
+				trace.SortTrace(testTrace)
+				spans += countTraceSpans(testTrace)
+				traceBytes, err := model.MustNewSegmentDecoder(model.CurrentEncoding).PrepareForWrite(testTrace, 0, 0)
+				require.NoError(t, err)
+
+				appended := false
+				require.NoError(t, i.pushBytes(context.Background(), id, tempopb.PreallocBytes{Slice: traceBytes}, tempopb.PreallocBytes{}, func() { appended = true }))
+				assert.Equal(t, expectedAppended, appended)
+				if expectedAppended {
+					assert.Len(t, i.traces, 0)
+				} else {
+					assert.Len(t, i.traces, 1)
+				}
+			}
+
+			push(false)
+			require.NoError(t, i.CutCompleteTraces(0, true))
+
+			// late spans for the cut trace
+			push(tc.expectedAppended)
+
+			found, err := i.FindTraceByID(context.Background(), id)
+			require.NoError(t, err)
+			assert.Equal(t, spans, countTraceSpans(found))
+
+			// once the head block was cut late spans always start a new trace
+			require.NoError(t, i.CutCompleteTraces(0, true))
+			_, err = i.CutBlockIfReady(0, 0, true)
+			require.NoError(t, err)
+
+			push(false)
+		})
+	}
+}
+
+func countTraceSpans(tr *tempopb.Trace) int {
+	count := 0
+	for _, b := range tr.Batches {
+		for _, ils := range b.InstrumentationLibrarySpans {
+			count += len(ils.Spans)
+		}
+	}
+	return count
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/log"
 )
 
@@ -21,6 +23,9 @@ var (
 	}, []string{"tenant"})
 )
 
+// reasonSpansPerTraceExceeded indicates that a live trace had more spans than allowed
+const reasonSpansPerTraceExceeded = "spans_per_trace_exceeded"
+
 type liveTrace struct {
 	batches    [][]byte
 	lastAppend time.Time
@@ -33,19 +38,34 @@ type liveTrace struct {
 	maxBytes     int
 	currentBytes int
 
+	// span limits
+	maxSpans     int
+	currentSpans int
+
 	// List of flatbuffers
 	searchData         [][]byte
 	maxSearchBytes     int
 	currentSearchBytes int
+
+	// called once the trace has been appended to the head block
+	onAppend []func()
+
+	// buffers backing batches and searchData. They are released once the trace has been appended to the head block
+	// or once the trace outlives the cut that follows its push, see detach.
+	buffers []tempopb.PreallocBytes
+	// number of batches and search data that have been copied out of the buffers
+	detachedBatches    int
+	detachedSearchData int
 }
 
-func newTrace(traceID []byte, maxBytes int, maxSearchBytes int) *liveTrace {
+func newTrace(traceID []byte, maxBytes int, maxSearchBytes int, maxSpans int) *liveTrace {
 	return &liveTrace{
 		batches:        make([][]byte, 0, 10), // 10 for luck
 		lastAppend:     time.Now(),
 		traceID:        traceID,
 		maxBytes:       maxBytes,
 		maxSearchBytes: maxSearchBytes,
+		maxSpans:       maxSpans,
 		decoder:        model.MustNewSegmentDecoder(model.CurrentEncoding),
 	}
 }
@@ -65,6 +85,21 @@ func (t *liveTrace) Push(_ context.Context, instanceID string, trace []byte, sea
 	if err != nil {
 		return fmt.Errorf("failed to get range while adding segment: %w", err)
 	}
+
+	if t.maxSpans != 0 {
+		var dropped int
+		trace, dropped, err = t.limitSpans(trace, start, end)
+		if err != nil {
+			return err
+		}
+		if dropped > 0 {
+			overrides.RecordDiscardedSpans(dropped, reasonSpansPerTraceExceeded, instanceID)
+		}
+		if trace == nil {
+			return nil
+		}
+	}
+
 	t.batches = append(t.batches, trace)
 	if t.start == 0 || start < t.start {
 		t.start = start
@@ -87,3 +122,130 @@ func (t *liveTrace) Push(_ context.Context, instanceID string, trace []byte, sea
 
 	return nil
 }
+
+// limitSpans drops the spans of the segment that exceed the span limit of the trace. It returns the segment to append,
+// which is nil if all of its spans were dropped, and the number of dropped spans. Spans are counted without decoding
+// the segment, segments within the limit are returned as is. Only segments that have to be truncated are decoded and
+// encoded again.
+func (t *liveTrace) limitSpans(segment []byte, start, end uint32) ([]byte, int, error) {
+	count, err := t.decoder.FastSpanCount(segment)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count spans while limiting spans: %w", err)
+	}
+	if t.currentSpans+count <= t.maxSpans {
+		t.currentSpans += count
+		return segment, 0, nil
+	}
+	if t.currentSpans >= t.maxSpans {
+		return nil, count, nil
+	}
+
+	tr, err := t.decoder.PrepareForRead([][]byte{segment})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to decode segment while limiting spans: %w", err)
+	}
+
+	remaining := t.maxSpans - t.currentSpans
+	if remaining < 0 {
+		remaining = 0
+	}
+	kept, dropped := truncateSpans(tr, remaining)
+	t.currentSpans += kept
+
+	switch {
+	case dropped == 0:
+		return segment, 0, nil
+	case kept == 0:
+		return nil, dropped, nil
+	}
+
+	segment, err = t.decoder.PrepareForWrite(tr, start, end)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to encode truncated segment: %w", err)
+	}
+	return segment, dropped, nil
+}
+
+// truncateSpans drops all spans of the trace beyond maxSpans in place. It returns the number of kept and dropped spans.
+func truncateSpans(tr *tempopb.Trace, maxSpans int) (int, int) {
+	kept, dropped := 0, 0
+	batches := tr.Batches[:0]
+	for _, b := range tr.Batches {
+		ilss := b.InstrumentationLibrarySpans[:0]
+		for _, ils := range b.InstrumentationLibrarySpans {
+			remaining := maxSpans - kept
+			if len(ils.Spans) > remaining {
+				dropped += len(ils.Spans) - remaining
+				ils.Spans = ils.Spans[:remaining]
+			}
+			if len(ils.Spans) == 0 {
+				continue
+			}
+			kept += len(ils.Spans)
+			ilss = append(ilss, ils)
+		}
+		if len(ilss) == 0 {
+			continue
+		}
+		b.InstrumentationLibrarySpans = ilss
+		batches = append(batches, b)
+	}
+	tr.Batches = batches
+
+	return kept, dropped
+}
+
+// detach copies the batches and search data that still reference received buffers and releases the buffers. A
+// buffer is usually a whole push request, so a long running trace would otherwise retain the requests of all its
+// pushes, including the data of all other traces in them, until it is cut.
+func (t *liveTrace) detach() {
+	if len(t.buffers) == 0 {
+		return
+	}
+
+	for i := t.detachedBatches; i < len(t.batches); i++ {
+		t.batches[i] = append(make([]byte, 0, len(t.batches[i])), t.batches[i]...)
+	}
+	t.detachedBatches = len(t.batches)
+
+	for i := t.detachedSearchData; i < len(t.searchData); i++ {
+		t.searchData[i] = append(make([]byte, 0, len(t.searchData[i])), t.searchData[i]...)
+	}
+	t.detachedSearchData = len(t.searchData)
+
+	for i := range t.buffers {
+		t.buffers[i].Release()
+	}
+	t.buffers = nil
+}
+
+// merge adds the data of an older, detached live trace of the same trace ID to the trace.
+func (t *liveTrace) merge(older *liveTrace) {
+	// the data of older does not reference any buffers, so it is put before the data that might still do
+	t.batches = append(older.batches, t.batches...)
+	t.detachedBatches += len(older.batches)
+	t.searchData = append(older.searchData, t.searchData...)
+	t.detachedSearchData += len(older.searchData)
+	t.onAppend = append(older.onAppend, t.onAppend...)
+
+	t.currentBytes += older.currentBytes
+	t.currentSpans += older.currentSpans
+	t.currentSearchBytes += older.currentSearchBytes
+
+	if t.start == 0 || (older.start != 0 && older.start < t.start) {
+		t.start = older.start
+	}
+	if older.end > t.end {
+		t.end = older.end
+	}
+}
+
+// release releases the buffers backing the trace. The batches and search data must not be used afterwards.
+func (t *liveTrace) release() {
+	for i := range t.buffers {
+		t.buffers[i].Release()
+	}
+	t.buffers = nil
+	t.batches = nil
+	t.searchData = nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/grafana/tempo/pkg/model"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	prom_dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -14,7 +15,7 @@ import (
 func TestTraceMaxSearchBytes(t *testing.T) {
 	tenantID := "fake"
 	maxSearchBytes := 100
-	tr := newTrace(nil, 0, maxSearchBytes)
+	tr := newTrace(nil, 0, maxSearchBytes, 0)
 	fakeTrace := make([]byte, 64)
 
 	getMetric := func() float64 {
@@ -42,7 +43,7 @@ func TestTraceMaxSearchBytes(t *testing.T) {
 func TestTraceStartEndTime(t *testing.T) {
 	s := model.MustNewSegmentDecoder(model.CurrentEncoding)
 
-	tr := newTrace(nil, 0, 0)
+	tr := newTrace(nil, 0, 0, 0)
 
 	// initial push
 	buff, err := s.PrepareForWrite(&tempopb.Trace{}, 10, 20)
@@ -71,3 +72,166 @@ func TestTraceStartEndTime(t *testing.T) {
 	assert.Equal(t, uint32(5), tr.start)
 	assert.Equal(t, uint32(25), tr.end)
 }
+
+func TestTraceMaxSpans(t *testing.T) {
+	s := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	spanID := byte(0)
+	makeSegment := func(spansPerILS ...[]int) []byte {
+		trace := &tempopb.Trace{}
+		for _, batch := range spansPerILS {
+			b := &v1.ResourceSpans{}
+			for _, n := range batch {
+				ils := &v1.InstrumentationLibrarySpans{}
+				for i := 0; i < n; i++ {
+					spanID++
+					ils.Spans = append(ils.Spans, &v1.Span{SpanId: []byte{spanID}})
+				}
+				b.InstrumentationLibrarySpans = append(b.InstrumentationLibrarySpans, ils)
+			}
+			trace.Batches = append(trace.Batches, b)
+		}
+		buff, err := s.PrepareForWrite(trace, 10, 20)
+		require.NoError(t, err)
+		return buff
+	}
+
+	tr := newTrace(nil, 0, 0, 5)
+
+	// within the limit, the segment is kept as is
+	segment := makeSegment([]int{2, 1})
+	require.NoError(t, tr.Push(context.Background(), "test", segment, nil))
+	assert.Equal(t, [][]byte{segment}, tr.batches)
+	assert.Equal(t, 3, tr.currentSpans)
+
+	// the limit applies across pushes
+	require.NoError(t, tr.Push(context.Background(), "test", makeSegment([]int{1}, []int{2, 1}), nil))
+	assert.Len(t, tr.batches, 2)
+	assert.Equal(t, 5, tr.currentSpans)
+
+	// all spans are dropped once the limit is reached
+	require.NoError(t, tr.Push(context.Background(), "test", makeSegment([]int{3}), nil))
+	assert.Len(t, tr.batches, 2)
+	assert.Equal(t, 5, tr.currentSpans)
+
+	actual, err := s.PrepareForRead(tr.batches)
+	require.NoError(t, err)
+	var spanIDs []byte
+	for _, b := range actual.Batches {
+		for _, ils := range b.InstrumentationLibrarySpans {
+			for _, span := range ils.Spans {
+				spanIDs = append(spanIDs, span.SpanId...)
+			}
+		}
+	}
+	assert.ElementsMatch(t, []byte{1, 2, 3, 4, 5}, spanIDs)
+	assert.Equal(t, uint32(10), tr.start)
+	assert.Equal(t, uint32(20), tr.end)
+}
+
+func TestTraceMerge(t *testing.T) {
+	var calls []string
+	older := newTrace(nil, 0, 0, 0)
+	older.batches = [][]byte{{1}}
+	older.searchData = [][]byte{{2}}
+	older.onAppend = []func(){func() { calls = append(calls, "older") }}
+	older.currentBytes, older.currentSpans, older.currentSearchBytes = 1, 2, 3
+	older.start, older.end = 10, 20
+
+	tr := newTrace(nil, 0, 0, 0)
+	tr.batches = [][]byte{{3}}
+	tr.onAppend = []func(){func() { calls = append(calls, "newer") }}
+	tr.currentBytes, tr.currentSpans, tr.currentSearchBytes = 4, 5, 6
+	tr.start, tr.end = 15, 25
+
+	tr.merge(older)
+
+	assert.Equal(t, [][]byte{{1}, {3}}, tr.batches)
+	assert.Equal(t, [][]byte{{2}}, tr.searchData)
+	assert.Equal(t, 1, tr.detachedBatches)
+	assert.Equal(t, 1, tr.detachedSearchData)
+	assert.Equal(t, 5, tr.currentBytes)
+	assert.Equal(t, 7, tr.currentSpans)
+	assert.Equal(t, 9, tr.currentSearchBytes)
+	assert.Equal(t, uint32(10), tr.start)
+	assert.Equal(t, uint32(25), tr.end)
+
+	for _, fn := range tr.onAppend {
+		fn()
+	}
+	assert.Equal(t, []string{"older", "newer"}, calls)
+}
+
+func TestTruncateSpans(t *testing.T) {
+	makeTrace := func(spansPerILS ...[]int) *tempopb.Trace {
+		trace := &tempopb.Trace{}
+		for _, batch := range spansPerILS {
+			b := &v1.ResourceSpans{}
+			for _, n := range batch {
+				b.InstrumentationLibrarySpans = append(b.InstrumentationLibrarySpans, &v1.InstrumentationLibrarySpans{
+					Spans: make([]*v1.Span, n),
+				})
+			}
+			trace.Batches = append(trace.Batches, b)
+		}
+		return trace
+	}
+
+	tests := []struct {
+		name            string
+		maxSpans        int
+		trace           *tempopb.Trace
+		expectedKept    int
+		expectedDropped int
+		expectedSpans   [][]int
+	}{
+		{
+			name:          "under limit",
+			maxSpans:      5,
+			trace:         makeTrace([]int{2, 3}),
+			expectedKept:  5,
+			expectedSpans: [][]int{{2, 3}},
+		},
+		{
+			name:            "split ils",
+			maxSpans:        3,
+			trace:           makeTrace([]int{2, 3}),
+			expectedKept:    3,
+			expectedDropped: 2,
+			expectedSpans:   [][]int{{2, 1}},
+		},
+		{
+			name:            "drop batches",
+			maxSpans:        2,
+			trace:           makeTrace([]int{2, 1}, []int{4}),
+			expectedKept:    2,
+			expectedDropped: 5,
+			expectedSpans:   [][]int{{2}},
+		},
+		{
+			name:            "drop all",
+			maxSpans:        0,
+			trace:           makeTrace([]int{2}),
+			expectedDropped: 2,
+			expectedSpans:   [][]int{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kept, dropped := truncateSpans(tc.trace, tc.maxSpans)
+			require.Equal(t, tc.expectedKept, kept)
+			require.Equal(t, tc.expectedDropped, dropped)
+
+			actual := [][]int{}
+			for _, b := range tc.trace.Batches {
+				var spans []int
+				for _, ils := range b.InstrumentationLibrarySpans {
+					spans = append(spans, len(ils.Spans))
+				}
+				actual = append(actual, spans)
+			}
+			assert.Equal(t, tc.expectedSpans, actual)
+		})
+	}
+}
@@ -13,6 +13,29 @@ var metricDiscardedSpans = promauto.NewCounterVec(prometheus.CounterOpts{
 	Help:      "The total number of samples that were discarded.",
 }, []string{discardReasonLabel, "tenant"})
 
+var metricDiscardedSpansByAttribute = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "discarded_spans_by_attribute_total",
+	Help:      "The total number of samples that were discarded per value of the tenant's rate limit attribute.",
+}, []string{discardReasonLabel, "tenant", "attribute", "value"})
+
+var metricTruncatedSpans = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "truncated_spans_total",
+	Help:      "The total number of spans that were truncated to fit the tenant's span limits.",
+}, []string{discardReasonLabel, "tenant"})
+
 func RecordDiscardedSpans(spansDiscarded int, reason string, tenant string) {
 	metricDiscardedSpans.WithLabelValues(reason, tenant).Add(float64(spansDiscarded))
 }
+
+// RecordDiscardedSpansByAttribute records discarded spans for a single value of a resource attribute, e.g. a
+// service name. Callers must bound the values, e.g. to the values with a configured limit.
+func RecordDiscardedSpansByAttribute(spansDiscarded int, reason string, tenant string, attribute string, value string) {
+	metricDiscardedSpansByAttribute.WithLabelValues(reason, tenant, attribute, value).Add(float64(spansDiscarded))
+}
+
+// RecordTruncatedSpans records spans that were accepted after part of their data was dropped.
+func RecordTruncatedSpans(spansTruncated int, reason string, tenant string) {
+	metricTruncatedSpans.WithLabelValues(reason, tenant).Add(float64(spansTruncated))
+}
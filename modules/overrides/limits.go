@@ -6,6 +6,8 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
+
+	"github.com/grafana/tempo/pkg/spanfilter"
 )
 
 const (
@@ -30,6 +32,11 @@ const (
 	MetricIngestionRateLimitBytes   = "ingestion_rate_limit_bytes"
 	MetricIngestionBurstSizeBytes   = "ingestion_burst_size_bytes"
 	MetricBlockRetention            = "block_retention"
+	MetricMaxSpansPerTrace          = "max_spans_per_trace"
+	MetricMaxAttributesPerSpan      = "max_attributes_per_span"
+	MetricMaxAttributeValueLength   = "max_attribute_value_length"
+	MetricMaxEventsPerSpan          = "max_events_per_span"
+	MetricMaxLinksPerSpan           = "max_links_per_span"
 )
 
 var (
@@ -50,16 +57,35 @@ type Limits struct {
 	IngestionBurstSizeBytes int       `yaml:"ingestion_burst_size_bytes" json:"ingestion_burst_size_bytes"`
 	SearchTagsAllowList     ListToMap `yaml:"search_tags_allow_list" json:"search_tags_allow_list"`
 
+	// Nested ingestion rate limits keyed by the value of a resource attribute. They are applied on top
+	// of the tenant limits above.
+	IngestionRateLimitAttribute  string                        `yaml:"ingestion_rate_limit_attribute" json:"ingestion_rate_limit_attribute"`
+	IngestionAttributeRateLimits map[string]AttributeRateLimit `yaml:"ingestion_attribute_rate_limits" json:"ingestion_attribute_rate_limits"`
+
+	// Span limits enforced in the distributor. Spans are truncated instead of the push being rejected.
+	MaxAttributesPerSpan    int `yaml:"max_attributes_per_span" json:"max_attributes_per_span"`
+	MaxAttributeValueLength int `yaml:"max_attribute_value_length" json:"max_attribute_value_length"`
+	MaxEventsPerSpan        int `yaml:"max_events_per_span" json:"max_events_per_span"`
+	MaxLinksPerSpan         int `yaml:"max_links_per_span" json:"max_links_per_span"`
+
 	// Ingester enforced limits.
 	MaxLocalTracesPerUser  int `yaml:"max_traces_per_user" json:"max_traces_per_user"`
 	MaxGlobalTracesPerUser int `yaml:"max_global_traces_per_user" json:"max_global_traces_per_user"`
 	MaxSearchBytesPerTrace int `yaml:"max_search_bytes_per_trace" json:"max_search_bytes_per_trace"`
+	MaxSpansPerTrace       int `yaml:"max_spans_per_trace" json:"max_spans_per_trace"`
 
 	// Metrics-generator config
-	MetricsGeneratorRingSize           int           `yaml:"metrics_generator_ring_size" json:"metrics_generator_ring_size"`
-	MetricsGeneratorProcessors         ListToMap     `yaml:"metrics_generator_processors" json:"metrics_generator_processors"`
-	MetricsGeneratorMaxActiveSeries    uint32        `yaml:"metrics_generator_max_active_series" json:"metrics_generator_max_active_series"`
-	MetricsGeneratorCollectionInterval time.Duration `yaml:"metrics_generator_collection_interval" json:"metrics_generator_collection_interval"`
+	MetricsGeneratorRingSize                 int           `yaml:"metrics_generator_ring_size" json:"metrics_generator_ring_size"`
+	MetricsGeneratorProcessors               ListToMap     `yaml:"metrics_generator_processors" json:"metrics_generator_processors"`
+	MetricsGeneratorMaxActiveSeries          uint32        `yaml:"metrics_generator_max_active_series" json:"metrics_generator_max_active_series"`
+	MetricsGeneratorMaxActiveSeriesPerMetric uint32        `yaml:"metrics_generator_max_active_series_per_metric" json:"metrics_generator_max_active_series_per_metric"`
+	MetricsGeneratorMaxLabelValues           uint32        `yaml:"metrics_generator_max_label_values" json:"metrics_generator_max_label_values"`
+	MetricsGeneratorCollectionInterval       time.Duration `yaml:"metrics_generator_collection_interval" json:"metrics_generator_collection_interval"`
+
+	// Span-metrics processor config, overrides the global processor config if set.
+	MetricsGeneratorProcessorSpanMetricsDimensions       []string           `yaml:"metrics_generator_processor_span_metrics_dimensions" json:"metrics_generator_processor_span_metrics_dimensions"`
+	MetricsGeneratorProcessorSpanMetricsHistogramBuckets []float64          `yaml:"metrics_generator_processor_span_metrics_histogram_buckets" json:"metrics_generator_processor_span_metrics_histogram_buckets"`
+	MetricsGeneratorProcessorSpanMetricsFilter           *spanfilter.Config `yaml:"metrics_generator_processor_span_metrics_filter" json:"metrics_generator_processor_span_metrics_filter"`
 
 	// Compactor enforced limits.
 	BlockRetention model.Duration `yaml:"block_retention" json:"block_retention"`
@@ -67,8 +93,14 @@ type Limits struct {
 	// Querier enforced limits.
 	MaxBytesPerTagValuesQuery int `yaml:"max_bytes_per_tag_values_query" json:"max_bytes_per_tag_values_query"`
 
-	// MaxBytesPerTrace is enforced in the Ingester, Compactor, Querier (Search) and Serverless (Search). It
-	//  it not enforce currently when doing a trace by id lookup.
+	// Query-frontend enforced limits.
+	MaxQueriersPerTenant    int `yaml:"max_queriers_per_tenant" json:"max_queriers_per_tenant"`
+	MaxConcurrentQueryBytes int `yaml:"max_concurrent_query_bytes" json:"max_concurrent_query_bytes"`
+	MaxTraceIDsPerBatch     int `yaml:"max_trace_ids_per_batch" json:"max_trace_ids_per_batch"`
+	MaxBytesPerBatchQuery   int `yaml:"max_bytes_per_batch_query" json:"max_bytes_per_batch_query"`
+
+	// MaxBytesPerTrace is enforced in the Ingester, Compactor, Querier (Search), Serverless (Search) and
+	//  Query-frontend (trace summary). It it not enforce currently when doing a trace by id lookup.
 	MaxBytesPerTrace int `yaml:"max_bytes_per_trace" json:"max_bytes_per_trace"`
 
 	// Configuration for overrides, convenient if it goes here.
@@ -76,22 +108,49 @@ type Limits struct {
 	PerTenantOverridePeriod model.Duration `yaml:"per_tenant_override_period" json:"per_tenant_override_period"`
 }
 
+// AttributeRateLimit is the ingestion rate limit for spans sharing a resource attribute value.
+type AttributeRateLimit struct {
+	RateLimitBytes int `yaml:"rate_limit_bytes" json:"rate_limit_bytes"`
+	BurstSizeBytes int `yaml:"burst_size_bytes" json:"burst_size_bytes"`
+}
+
+// Burst returns the burst size of the limit. It defaults to the rate limit if no burst size is configured, a burst
+// of 0 would refuse all spans of the value.
+func (l AttributeRateLimit) Burst() int {
+	if l.BurstSizeBytes > 0 {
+		return l.BurstSizeBytes
+	}
+	return l.RateLimitBytes
+}
+
 // RegisterFlags adds the flags required to config this to the given FlagSet
 func (l *Limits) RegisterFlags(f *flag.FlagSet) {
 	// Distributor Limits
 	f.StringVar(&l.IngestionRateStrategy, "distributor.rate-limit-strategy", "local", "Whether the various ingestion rate limits should be applied individually to each distributor instance (local), or evenly shared across the cluster (global).")
 	f.IntVar(&l.IngestionRateLimitBytes, "distributor.ingestion-rate-limit-bytes", 15e6, "Per-user ingestion rate limit in bytes per second.")
 	f.IntVar(&l.IngestionBurstSizeBytes, "distributor.ingestion-burst-size-bytes", 20e6, "Per-user ingestion burst size in bytes. Should be set to the expected size (in bytes) of a single push request.")
+	f.StringVar(&l.IngestionRateLimitAttribute, "distributor.ingestion-rate-limit-attribute", "service.name", "Resource attribute the per-attribute ingestion rate limits are keyed by.")
+	f.IntVar(&l.MaxAttributesPerSpan, "distributor.max-attributes-per-span", 0, "Maximum number of attributes per span. Additional attributes are dropped. 0 to disable.")
+	f.IntVar(&l.MaxAttributeValueLength, "distributor.max-attribute-value-length", 0, "Maximum length of string attribute values in bytes. Longer values are truncated. 0 to disable.")
+	f.IntVar(&l.MaxEventsPerSpan, "distributor.max-events-per-span", 0, "Maximum number of events per span. Additional events are dropped. 0 to disable.")
+	f.IntVar(&l.MaxLinksPerSpan, "distributor.max-links-per-span", 0, "Maximum number of links per span. Additional links are dropped. 0 to disable.")
 
 	// Ingester limits
 	f.IntVar(&l.MaxLocalTracesPerUser, "ingester.max-traces-per-user", 10e3, "Maximum number of active traces per user, per ingester. 0 to disable.")
 	f.IntVar(&l.MaxGlobalTracesPerUser, "ingester.max-global-traces-per-user", 0, "Maximum number of active traces per user, across the cluster. 0 to disable.")
 	f.IntVar(&l.MaxBytesPerTrace, "ingester.max-bytes-per-trace", 50e5, "Maximum size of a trace in bytes.  0 to disable.")
 	f.IntVar(&l.MaxSearchBytesPerTrace, "ingester.max-search-bytes-per-trace", 5e3, "Maximum size of search data per trace in bytes.  0 to disable.")
+	f.IntVar(&l.MaxSpansPerTrace, "ingester.max-spans-per-trace", 0, "Maximum number of spans of a live trace in the ingester. Additional spans are dropped. 0 to disable.")
 
 	// Querier limits
 	f.IntVar(&l.MaxBytesPerTagValuesQuery, "querier.max-bytes-per-tag-values-query", 50e5, "Maximum size of response for a tag-values query. Used mainly to limit large the number of values associated with a particular tag")
 
+	// Query-frontend limits
+	f.IntVar(&l.MaxQueriersPerTenant, "frontend.max-queriers-per-tenant", 0, "Maximum number of queriers that can handle requests of a single tenant. 0 to use all queriers.")
+	f.IntVar(&l.MaxConcurrentQueryBytes, "frontend.max-concurrent-query-bytes", 0, "Maximum estimated bytes to scan of the jobs of a single tenant executed by queriers at the same time. 0 to disable.")
+	f.IntVar(&l.MaxTraceIDsPerBatch, "frontend.max-trace-ids-per-batch", 1000, "Maximum number of trace IDs of a batch trace by ID request. 0 to disable.")
+	f.IntVar(&l.MaxBytesPerBatchQuery, "frontend.max-bytes-per-batch-query", 50e6, "Maximum size in bytes of the traces returned by a batch trace by ID request. 0 to disable.")
+
 	f.StringVar(&l.PerTenantOverrideConfig, "limits.per-user-override-config", "", "File name of per-user overrides.")
 	_ = l.PerTenantOverridePeriod.Set("10s")
 	f.Var(&l.PerTenantOverridePeriod, "limits.per-user-override-period", "Period with this to reload the overrides.")
@@ -110,4 +169,9 @@ func (l *Limits) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(l.IngestionRateLimitBytes), MetricIngestionRateLimitBytes)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(l.IngestionBurstSizeBytes), MetricIngestionBurstSizeBytes)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(l.BlockRetention), MetricBlockRetention)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(l.MaxSpansPerTrace), MetricMaxSpansPerTrace)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(l.MaxAttributesPerSpan), MetricMaxAttributesPerSpan)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(l.MaxAttributeValueLength), MetricMaxAttributeValueLength)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(l.MaxEventsPerSpan), MetricMaxEventsPerSpan)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(l.MaxLinksPerSpan), MetricMaxLinksPerSpan)
 }
@@ -41,6 +41,11 @@ ingestion_burst_size_bytes: 100_000
 search_tags_allow_list:
 - a
 - b
+ingestion_rate_limit_attribute: service.name
+ingestion_attribute_rate_limits:
+  checkout:
+    rate_limit_bytes: 1000
+    burst_size_bytes: 2000
 
 max_traces_per_user: 1000
 max_global_traces_per_user: 1000
@@ -59,6 +64,13 @@ per_tenant_override_period: 1m
 	"search_tags_allow_list" : [
 	  "a", "b"
 	],
+	"ingestion_rate_limit_attribute": "service.name",
+	"ingestion_attribute_rate_limits": {
+	  "checkout": {
+	    "rate_limit_bytes": 1000,
+	    "burst_size_bytes": 2000
+	  }
+	},
 
 	"max_traces_per_user": 1000,
 	"max_global_traces_per_user": 1000,
@@ -12,11 +12,15 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"gopkg.in/yaml.v2"
 
+	"github.com/grafana/tempo/pkg/spanfilter"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 )
 
-const wildcardTenant = "*"
+const (
+	wildcardTenant         = "*"
+	wildcardAttributeValue = "*"
+)
 
 var (
 	metricOverridesLimitsDesc = prometheus.NewDesc(
@@ -248,6 +252,30 @@ func (o *Overrides) MaxBytesPerTagValuesQuery(userID string) int {
 	return o.getOverridesForUser(userID).MaxBytesPerTagValuesQuery
 }
 
+// MaxTraceIDsPerBatch returns the maximum number of trace IDs of a batch trace by ID request of a user,
+// 0 for no limit.
+func (o *Overrides) MaxTraceIDsPerBatch(userID string) int {
+	return o.getOverridesForUser(userID).MaxTraceIDsPerBatch
+}
+
+// MaxBytesPerBatchQuery returns the maximum size in bytes of the traces returned by a batch trace by ID
+// request of a user, 0 for no limit.
+func (o *Overrides) MaxBytesPerBatchQuery(userID string) int {
+	return o.getOverridesForUser(userID).MaxBytesPerBatchQuery
+}
+
+// MaxQueriersPerUser returns the maximum number of queriers the requests of a user are shuffle sharded to,
+// 0 to use all queriers.
+func (o *Overrides) MaxQueriersPerUser(userID string) int {
+	return o.getOverridesForUser(userID).MaxQueriersPerTenant
+}
+
+// MaxConcurrentQueryBytes returns the maximum estimated bytes to scan of the requests of a user queriers
+// execute at the same time, 0 to disable.
+func (o *Overrides) MaxConcurrentQueryBytes(userID string) int {
+	return o.getOverridesForUser(userID).MaxConcurrentQueryBytes
+}
+
 // IngestionRateLimitBytes is the number of spans per second allowed for this tenant.
 func (o *Overrides) IngestionRateLimitBytes(userID string) float64 {
 	return float64(o.getOverridesForUser(userID).IngestionRateLimitBytes)
@@ -258,6 +286,52 @@ func (o *Overrides) IngestionBurstSizeBytes(userID string) int {
 	return o.getOverridesForUser(userID).IngestionBurstSizeBytes
 }
 
+// IngestionRateLimitAttribute is the resource attribute the nested ingestion rate limits are keyed by.
+func (o *Overrides) IngestionRateLimitAttribute(userID string) string {
+	return o.getOverridesForUser(userID).IngestionRateLimitAttribute
+}
+
+// IngestionAttributeRateLimits are the nested ingestion rate limits for this tenant keyed by attribute value.
+// The key "*" applies to every value that is not listed explicitly. Each value is limited separately.
+func (o *Overrides) IngestionAttributeRateLimits(userID string) map[string]AttributeRateLimit {
+	return o.getOverridesForUser(userID).IngestionAttributeRateLimits
+}
+
+// IngestionAttributeRateLimit returns the nested ingestion rate limit for the given attribute value.
+func (o *Overrides) IngestionAttributeRateLimit(userID string, value string) (AttributeRateLimit, bool) {
+	limits := o.getOverridesForUser(userID).IngestionAttributeRateLimits
+	if l, ok := limits[value]; ok {
+		return l, true
+	}
+	l, ok := limits[wildcardAttributeValue]
+	return l, ok
+}
+
+// MaxSpansPerTrace is the maximum number of spans of a live trace in the ingester for this tenant.
+func (o *Overrides) MaxSpansPerTrace(userID string) int {
+	return o.getOverridesForUser(userID).MaxSpansPerTrace
+}
+
+// MaxAttributesPerSpan is the maximum number of attributes per span for this tenant.
+func (o *Overrides) MaxAttributesPerSpan(userID string) int {
+	return o.getOverridesForUser(userID).MaxAttributesPerSpan
+}
+
+// MaxAttributeValueLength is the maximum length of a string attribute value in bytes for this tenant.
+func (o *Overrides) MaxAttributeValueLength(userID string) int {
+	return o.getOverridesForUser(userID).MaxAttributeValueLength
+}
+
+// MaxEventsPerSpan is the maximum number of events per span for this tenant.
+func (o *Overrides) MaxEventsPerSpan(userID string) int {
+	return o.getOverridesForUser(userID).MaxEventsPerSpan
+}
+
+// MaxLinksPerSpan is the maximum number of links per span for this tenant.
+func (o *Overrides) MaxLinksPerSpan(userID string) int {
+	return o.getOverridesForUser(userID).MaxLinksPerSpan
+}
+
 // SearchTagsAllowList is the list of tags to be extracted for search, for this tenant.
 func (o *Overrides) SearchTagsAllowList(userID string) map[string]struct{} {
 	return o.getOverridesForUser(userID).SearchTagsAllowList.GetMap()
@@ -280,12 +354,42 @@ func (o *Overrides) MetricsGeneratorMaxActiveSeries(userID string) uint32 {
 	return o.getOverridesForUser(userID).MetricsGeneratorMaxActiveSeries
 }
 
+// MetricsGeneratorMaxActiveSeriesPerMetric is the maximum amount of active series of a single
+// metric in the metrics-generator registry for this tenant.
+func (o *Overrides) MetricsGeneratorMaxActiveSeriesPerMetric(userID string) uint32 {
+	return o.getOverridesForUser(userID).MetricsGeneratorMaxActiveSeriesPerMetric
+}
+
+// MetricsGeneratorMaxLabelValues is the maximum amount of distinct values of a label of a single
+// metric in the metrics-generator registry for this tenant.
+func (o *Overrides) MetricsGeneratorMaxLabelValues(userID string) uint32 {
+	return o.getOverridesForUser(userID).MetricsGeneratorMaxLabelValues
+}
+
 // MetricsGeneratorCollectionInterval is the collection interval of the metrics-generator registry
 // for this tenant.
 func (o *Overrides) MetricsGeneratorCollectionInterval(userID string) time.Duration {
 	return o.getOverridesForUser(userID).MetricsGeneratorCollectionInterval
 }
 
+// MetricsGeneratorProcessorSpanMetricsDimensions are the additional dimensions of the span-metrics
+// processor for this tenant. Returns nil if not overridden.
+func (o *Overrides) MetricsGeneratorProcessorSpanMetricsDimensions(userID string) []string {
+	return o.getOverridesForUser(userID).MetricsGeneratorProcessorSpanMetricsDimensions
+}
+
+// MetricsGeneratorProcessorSpanMetricsHistogramBuckets are the latency histogram buckets of the
+// span-metrics processor for this tenant. Returns nil if not overridden.
+func (o *Overrides) MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID string) []float64 {
+	return o.getOverridesForUser(userID).MetricsGeneratorProcessorSpanMetricsHistogramBuckets
+}
+
+// MetricsGeneratorProcessorSpanMetricsFilter selects the spans processed by the span-metrics
+// processor for this tenant. Returns nil if not overridden.
+func (o *Overrides) MetricsGeneratorProcessorSpanMetricsFilter(userID string) *spanfilter.Config {
+	return o.getOverridesForUser(userID).MetricsGeneratorProcessorSpanMetricsFilter
+}
+
 // BlockRetention is the duration of the block retention for this tenant.
 func (o *Overrides) BlockRetention(userID string) time.Duration {
 	return time.Duration(o.getOverridesForUser(userID).BlockRetention)
@@ -325,6 +429,11 @@ func (o *Overrides) Collect(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.IngestionRateLimitBytes), MetricIngestionRateLimitBytes, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.IngestionBurstSizeBytes), MetricIngestionBurstSizeBytes, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.BlockRetention), MetricBlockRetention, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.MaxSpansPerTrace), MetricMaxSpansPerTrace, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.MaxAttributesPerSpan), MetricMaxAttributesPerSpan, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.MaxAttributeValueLength), MetricMaxAttributeValueLength, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.MaxEventsPerSpan), MetricMaxEventsPerSpan, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.MaxLinksPerSpan), MetricMaxLinksPerSpan, tenant)
 
 	}
 }
@@ -11,7 +11,8 @@ import (
 
 // Config for a querier.
 type Config struct {
-	Search SearchConfig `yaml:"search"`
+	Search  SearchConfig  `yaml:"search"`
+	Metrics MetricsConfig `yaml:"metrics"`
 
 	TraceLookupQueryTimeout time.Duration `yaml:"query_timeout"`
 	ExtraQueryDelay         time.Duration `yaml:"extra_query_delay,omitempty"`
@@ -27,6 +28,15 @@ type SearchConfig struct {
 	HedgeRequestsUpTo int           `yaml:"external_hedge_requests_up_to"`
 }
 
+// MetricsConfig configures the PromQL engine querying the metrics stored by the metrics-generators.
+type MetricsConfig struct {
+	QueryTimeout  time.Duration `yaml:"query_timeout"`
+	MaxSamples    int           `yaml:"max_samples"`
+	LookbackDelta time.Duration `yaml:"lookback_delta"`
+	// MaxFetchedSamples limits the raw samples fetched from the metrics-generators for a single selector
+	MaxFetchedSamples int `yaml:"max_fetched_samples"`
+}
+
 // RegisterFlagsAndApplyDefaults register flags.
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.TraceLookupQueryTimeout = 10 * time.Second
@@ -36,6 +46,10 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Search.HedgeRequestsAt = 4 * time.Second
 	cfg.Search.HedgeRequestsUpTo = 3
 	cfg.Search.QueryTimeout = 30 * time.Second
+	cfg.Metrics.QueryTimeout = 30 * time.Second
+	cfg.Metrics.MaxSamples = 50_000_000
+	cfg.Metrics.LookbackDelta = 5 * time.Minute
+	cfg.Metrics.MaxFetchedSamples = 10_000_000
 	cfg.Worker = worker.Config{
 		MatchMaxConcurrency:   true,
 		MaxConcurrentRequests: cfg.MaxConcurrentQueries,
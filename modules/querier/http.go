@@ -2,6 +2,7 @@ package querier
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -16,6 +17,10 @@ import (
 	"github.com/opentracing/opentracing-go"
 	ot_log "github.com/opentracing/opentracing-go/log"
 	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/parser"
+	prometheus_storage "github.com/prometheus/prometheus/storage"
+	"github.com/weaveworks/common/user"
 )
 
 const (
@@ -49,17 +54,26 @@ func (q *Querier) TraceByIDHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	timeStart, timeEnd, err := api.ParseTraceByIDTimeRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	span.LogFields(
 		ot_log.String("msg", "validated request"),
 		ot_log.String("blockStart", blockStart),
 		ot_log.String("blockEnd", blockEnd),
-		ot_log.String("queryMode", queryMode))
+		ot_log.String("queryMode", queryMode),
+		ot_log.Uint32("timeStart", timeStart),
+		ot_log.Uint32("timeEnd", timeEnd))
 
 	resp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
 		TraceID:    byteID,
 		BlockStart: blockStart,
 		BlockEnd:   blockEnd,
 		QueryMode:  queryMode,
+		Start:      timeStart,
+		End:        timeEnd,
 	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -98,6 +112,81 @@ func (q *Querier) TraceByIDHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
 }
 
+// TracesByIDHandler is a http.HandlerFunc to retrieve a batch of traces
+func (q *Querier) TracesByIDHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying backends
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.TraceLookupQueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.TracesByIDHandler")
+	defer span.Finish()
+
+	orgID, _ := user.ExtractOrgID(ctx)
+	byteIDs, err := api.ParseTraceIDs(r, q.limits.MaxTraceIDsPerBatch(orgID))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// validate request
+	blockStart, blockEnd, queryMode, err := validateAndSanitizeRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	timeStart, timeEnd, err := api.ParseTraceByIDTimeRange(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	span.LogFields(
+		ot_log.String("msg", "validated request"),
+		ot_log.Int("traceIDs", len(byteIDs)),
+		ot_log.String("blockStart", blockStart),
+		ot_log.String("blockEnd", blockEnd),
+		ot_log.String("queryMode", queryMode),
+		ot_log.Uint32("timeStart", timeStart),
+		ot_log.Uint32("timeEnd", timeEnd))
+
+	resp, err := q.FindTracesByID(ctx, &tempopb.TracesByIDRequest{
+		TraceIDs:   byteIDs,
+		BlockStart: blockStart,
+		BlockEnd:   blockEnd,
+		QueryMode:  queryMode,
+		Start:      timeStart,
+		End:        timeEnd,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
+		span.SetTag("contentType", api.HeaderAcceptProtobuf)
+		b, err := proto.Marshal(resp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptProtobuf)
+		_, err = w.Write(b)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		return
+	}
+
+	span.SetTag("contentType", api.HeaderAcceptJSON)
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	marshaller := &jsonpb.Marshaler{}
+	err = marshaller.Marshal(w, resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 // return values are (blockStart, blockEnd, queryMode, error)
 func validateAndSanitizeRequest(r *http.Request) (string, string, string, error) {
 	q := r.URL.Query().Get(QueryModeKey)
@@ -255,3 +344,150 @@ func (q *Querier) SearchTagValuesHandler(w http.ResponseWriter, r *http.Request)
 	}
 	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
 }
+
+func (q *Querier) ServiceGraphHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying the metrics-generators
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.Search.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.ServiceGraphHandler")
+	defer span.Finish()
+
+	req, err := api.ParseServiceGraphRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	span.SetTag("ServiceGraphRequest", req.String())
+
+	resp, err := q.GetServiceGraph(ctx, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	marshaller := &jsonpb.Marshaler{}
+	err = marshaller.Marshal(w, resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+}
+
+// CardinalityHandler returns the active series of the metrics of the tenant summed over all
+// metrics-generators.
+func (q *Querier) CardinalityHandler(w http.ResponseWriter, r *http.Request) {
+	// Enforce the query timeout while querying the metrics-generators
+	ctx, cancel := context.WithDeadline(r.Context(), time.Now().Add(q.cfg.Search.QueryTimeout))
+	defer cancel()
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.CardinalityHandler")
+	defer span.Finish()
+
+	req, err := api.ParseCardinalityRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := q.GetCardinality(ctx, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	marshaller := &jsonpb.Marshaler{}
+	err = marshaller.Marshal(w, resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+}
+
+// QueryRangeHandler evaluates a PromQL range query against the metrics-generators. The response
+// uses the format of the Prometheus HTTP API so the endpoint can be used as a Prometheus data source.
+func (q *Querier) QueryRangeHandler(w http.ResponseWriter, r *http.Request) {
+	span, ctx := opentracing.StartSpanFromContext(r.Context(), "Querier.QueryRangeHandler")
+	defer span.Finish()
+
+	req, err := api.ParseQueryRangeRequest(r)
+	if err != nil {
+		writePrometheusError(w, http.StatusBadRequest, "bad_data", err)
+		return
+	}
+
+	span.SetTag("query", req.Query)
+
+	res, err := q.QueryRange(ctx, req.Query, req.Start, req.End, req.Step)
+	if err != nil {
+		writePrometheusError(w, http.StatusBadRequest, "bad_data", err)
+		return
+	}
+	if res.Err != nil {
+		switch res.Err.(type) {
+		case promql.ErrQueryCanceled:
+			writePrometheusError(w, http.StatusServiceUnavailable, "canceled", res.Err)
+		case promql.ErrQueryTimeout:
+			writePrometheusError(w, http.StatusServiceUnavailable, "timeout", res.Err)
+		default:
+			writePrometheusError(w, http.StatusUnprocessableEntity, "execution", res.Err)
+		}
+		return
+	}
+
+	writePrometheusResponse(w, http.StatusOK, &prometheusResponse{
+		Status: "success",
+		Data: &prometheusQueryData{
+			ResultType: res.Value.Type(),
+			Result:     res.Value,
+		},
+		Warnings: warningsToStrings(res.Warnings),
+	})
+}
+
+type prometheusResponse struct {
+	Status    string               `json:"status"`
+	Data      *prometheusQueryData `json:"data,omitempty"`
+	ErrorType string               `json:"errorType,omitempty"`
+	Error     string               `json:"error,omitempty"`
+	Warnings  []string             `json:"warnings,omitempty"`
+}
+
+type prometheusQueryData struct {
+	ResultType parser.ValueType `json:"resultType"`
+	Result     parser.Value     `json:"result"`
+}
+
+func writePrometheusError(w http.ResponseWriter, status int, errorType string, err error) {
+	writePrometheusResponse(w, status, &prometheusResponse{
+		Status:    "error",
+		ErrorType: errorType,
+		Error:     err.Error(),
+	})
+}
+
+func writePrometheusResponse(w http.ResponseWriter, status int, resp *prometheusResponse) {
+	b, err := json.Marshal(resp)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	w.WriteHeader(status)
+	_, _ = w.Write(b)
+}
+
+func warningsToStrings(warnings prometheus_storage.Warnings) []string {
+	if len(warnings) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		result = append(result, w.Error())
+	}
+	return result
+}
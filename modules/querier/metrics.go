@@ -0,0 +1,335 @@
+package querier
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/ring"
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	prometheus_storage "github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
+	"github.com/weaveworks/common/user"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+func newMetricsEngine(cfg MetricsConfig) *promql.Engine {
+	return promql.NewEngine(promql.EngineOpts{
+		Logger:        log.Logger,
+		MaxSamples:    cfg.MaxSamples,
+		Timeout:       cfg.QueryTimeout,
+		LookbackDelta: cfg.LookbackDelta,
+		NoStepSubqueryIntervalFn: func(int64) int64 {
+			return time.Minute.Milliseconds()
+		},
+		EnableAtModifier:     true,
+		EnableNegativeOffset: true,
+	})
+}
+
+// QueryRange evaluates a PromQL range query against the metrics stored by the metrics-generators.
+func (q *Querier) QueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) (*promql.Result, error) {
+	if q.metricsEngine == nil {
+		return nil, errors.New("the metrics-generator is not enabled")
+	}
+
+	rangeQuery, err := q.metricsEngine.NewRangeQuery(&generatorQueryable{q: q}, query, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	defer rangeQuery.Close()
+
+	return rangeQuery.Exec(ctx), nil
+}
+
+// GeneratorLabel is added to the series fetched from the metrics-generators, its value is the address of
+// the metrics-generator holding the series. Spans are sharded across the metrics-generators by trace ID, so
+// each of them holds a partial count of the same series. Their series are kept apart and are combined
+// with an aggregation like sum. Matchers on this label select the metrics-generators to query, they are
+// not sent to the metrics-generators.
+const GeneratorLabel = "generator"
+
+// GetMetricsSeries returns the series matching the request from the local TSDB of all
+// metrics-generators. Failing metrics-generators are returned as warnings, the request only fails if all
+// of them fail or more than max_fetched_samples samples match. Matchers on GeneratorLabel are applied to
+// the address of the metrics-generators.
+func (q *Querier) GetMetricsSeries(ctx context.Context, req *tempopb.MetricsSeriesRequest) (*tempopb.MetricsSeriesResponse, prometheus_storage.Warnings, error) {
+	if q.generatorRing == nil {
+		return nil, nil, errors.New("the metrics-generator is not enabled")
+	}
+
+	_, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error extracting org id in Querier.GetMetricsSeries")
+	}
+
+	replicationSet, err := q.generatorRing.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error finding metrics-generators in Querier.GetMetricsSeries")
+	}
+
+	generatorMatchers, err := splitGeneratorMatchers(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(generatorMatchers) > 0 {
+		instances := make([]ring.InstanceDesc, 0, len(replicationSet.Instances))
+		for _, instance := range replicationSet.Instances {
+			if matchesGenerator(generatorMatchers, instance.Addr) {
+				instances = append(instances, instance)
+			}
+		}
+		if len(instances) == 0 {
+			return &tempopb.MetricsSeriesResponse{}, nil, nil
+		}
+		replicationSet.Instances = instances
+	}
+
+	maxSamples := q.cfg.Metrics.MaxFetchedSamples
+	req.MaxSamples = uint32(maxSamples)
+
+	type generatorResult struct {
+		addr string
+		resp *tempopb.MetricsSeriesResponse
+		err  error
+	}
+
+	// errors are returned as part of the result so a single metrics-generator doesn't fail the request
+	results, err := replicationSet.Do(ctx, 0, func(ctx context.Context, generator *ring.InstanceDesc) (interface{}, error) {
+		result := &generatorResult{addr: generator.Addr}
+
+		client, err := q.generatorPool.GetClientFor(generator.Addr)
+		if err != nil {
+			result.err = err
+			return result, nil
+		}
+		result.resp, result.err = client.(tempopb.MetricsGeneratorClient).GetMetricsSeries(ctx, req)
+		return result, nil
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error querying metrics-generators in Querier.GetMetricsSeries")
+	}
+
+	resp := &tempopb.MetricsSeriesResponse{}
+	var warnings prometheus_storage.Warnings
+	samples := 0
+	for _, r := range results {
+		result := r.(*generatorResult)
+		if result.err != nil {
+			level.Warn(log.Logger).Log("msg", "error querying metrics-generator", "generator", result.addr, "err", result.err)
+			warnings = append(warnings, fmt.Errorf("error querying metrics-generator %s: %w", result.addr, result.err))
+			continue
+		}
+
+		for _, s := range result.resp.Series {
+			samples += len(s.Samples)
+			s.Labels = append(s.Labels, &tempopb.MetricsLabel{Name: GeneratorLabel, Value: result.addr})
+		}
+		if maxSamples > 0 && samples > maxSamples {
+			return nil, nil, fmt.Errorf("query matched more than the maximum of %d samples", maxSamples)
+		}
+		resp.Series = append(resp.Series, result.resp.Series...)
+	}
+	if len(results) > 0 && len(warnings) == len(results) {
+		return nil, nil, errors.Wrap(warnings[0], "all metrics-generators failed in Querier.GetMetricsSeries")
+	}
+
+	return resp, warnings, nil
+}
+
+// splitGeneratorMatchers removes the matchers on GeneratorLabel from the request and returns them.
+func splitGeneratorMatchers(req *tempopb.MetricsSeriesRequest) ([]*labels.Matcher, error) {
+	var generatorMatchers []*labels.Matcher
+	matchers := req.Matchers[:0]
+	for _, m := range req.Matchers {
+		if m.Name != GeneratorLabel {
+			matchers = append(matchers, m)
+			continue
+		}
+		matcher, err := fromMetricsLabelMatcher(m)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher on label %s: %w", GeneratorLabel, err)
+		}
+		generatorMatchers = append(generatorMatchers, matcher)
+	}
+	req.Matchers = matchers
+	return generatorMatchers, nil
+}
+
+func matchesGenerator(matchers []*labels.Matcher, addr string) bool {
+	for _, m := range matchers {
+		if !m.Matches(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// generatorQueryable is a storage.Queryable fetching series from the metrics-generators.
+type generatorQueryable struct {
+	q *Querier
+}
+
+var _ prometheus_storage.Queryable = (*generatorQueryable)(nil)
+
+func (g *generatorQueryable) Querier(ctx context.Context, mint, maxt int64) (prometheus_storage.Querier, error) {
+	return &generatorQuerier{
+		ctx:  ctx,
+		q:    g.q,
+		mint: mint,
+		maxt: maxt,
+	}, nil
+}
+
+type generatorQuerier struct {
+	ctx        context.Context
+	q          *Querier
+	mint, maxt int64
+}
+
+var _ prometheus_storage.Querier = (*generatorQuerier)(nil)
+
+func (g *generatorQuerier) Select(_ bool, hints *prometheus_storage.SelectHints, matchers ...*labels.Matcher) prometheus_storage.SeriesSet {
+	req := &tempopb.MetricsSeriesRequest{
+		Start: g.mint,
+		End:   g.maxt,
+	}
+	if hints != nil {
+		req.Start = hints.Start
+		req.End = hints.End
+	}
+	for _, m := range matchers {
+		matcher, err := toMetricsLabelMatcher(m)
+		if err != nil {
+			return prometheus_storage.ErrSeriesSet(err)
+		}
+		req.Matchers = append(req.Matchers, matcher)
+	}
+
+	resp, warnings, err := g.q.GetMetricsSeries(g.ctx, req)
+	if err != nil {
+		return prometheus_storage.ErrSeriesSet(err)
+	}
+
+	set := newSeriesSet(resp.Series)
+	set.warnings = warnings
+	return set
+}
+
+func (g *generatorQuerier) LabelValues(string, ...*labels.Matcher) ([]string, prometheus_storage.Warnings, error) {
+	return nil, nil, errors.New("label values are not supported")
+}
+
+func (g *generatorQuerier) LabelNames(...*labels.Matcher) ([]string, prometheus_storage.Warnings, error) {
+	return nil, nil, errors.New("label names are not supported")
+}
+
+func (g *generatorQuerier) Close() error {
+	return nil
+}
+
+func toMetricsLabelMatcher(m *labels.Matcher) (*tempopb.MetricsLabelMatcher, error) {
+	var matchType uint32
+	switch m.Type {
+	case labels.MatchEqual:
+		matchType = 0
+	case labels.MatchNotEqual:
+		matchType = 1
+	case labels.MatchRegexp:
+		matchType = 2
+	case labels.MatchNotRegexp:
+		matchType = 3
+	default:
+		return nil, fmt.Errorf("unknown match type %d", m.Type)
+	}
+
+	return &tempopb.MetricsLabelMatcher{
+		Type:  matchType,
+		Name:  m.Name,
+		Value: m.Value,
+	}, nil
+}
+
+func fromMetricsLabelMatcher(m *tempopb.MetricsLabelMatcher) (*labels.Matcher, error) {
+	var matchType labels.MatchType
+	switch m.Type {
+	case 0:
+		matchType = labels.MatchEqual
+	case 1:
+		matchType = labels.MatchNotEqual
+	case 2:
+		matchType = labels.MatchRegexp
+	case 3:
+		matchType = labels.MatchNotRegexp
+	default:
+		return nil, fmt.Errorf("unknown match type %d", m.Type)
+	}
+
+	return labels.NewMatcher(matchType, m.Name, m.Value)
+}
+
+// newSeriesSet returns the series sorted by their labels.
+func newSeriesSet(series []*tempopb.MetricsSeries) *seriesSet {
+	result := make([]prometheus_storage.Series, 0, len(series))
+	for _, s := range series {
+		lbls := make(labels.Labels, 0, len(s.Labels))
+		for _, l := range s.Labels {
+			lbls = append(lbls, labels.Label{Name: l.Name, Value: l.Value})
+		}
+		sort.Sort(lbls)
+
+		samples := make([]tsdbutil.Sample, 0, len(s.Samples))
+		for _, smpl := range s.Samples {
+			samples = append(samples, sample{t: smpl.TimestampMs, v: smpl.Value})
+		}
+		sort.Slice(samples, func(i, j int) bool {
+			return samples[i].T() < samples[j].T()
+		})
+
+		result = append(result, prometheus_storage.NewListSeries(lbls, samples))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return labels.Compare(result[i].Labels(), result[j].Labels()) < 0
+	})
+
+	return &seriesSet{series: result, i: -1}
+}
+
+type sample struct {
+	t int64
+	v float64
+}
+
+func (s sample) T() int64   { return s.t }
+func (s sample) V() float64 { return s.v }
+
+type seriesSet struct {
+	series   []prometheus_storage.Series
+	i        int
+	warnings prometheus_storage.Warnings
+}
+
+var _ prometheus_storage.SeriesSet = (*seriesSet)(nil)
+
+func (s *seriesSet) Next() bool {
+	s.i++
+	return s.i < len(s.series)
+}
+
+func (s *seriesSet) At() prometheus_storage.Series {
+	return s.series[s.i]
+}
+
+func (s *seriesSet) Err() error {
+	return nil
+}
+
+func (s *seriesSet) Warnings() prometheus_storage.Warnings {
+	return s.warnings
+}
@@ -0,0 +1,198 @@
+package querier
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	prometheus_storage "github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestNewSeriesSet(t *testing.T) {
+	series := []*tempopb.MetricsSeries{
+		{
+			Labels:  []*tempopb.MetricsLabel{{Name: "__name__", Value: "my_metric"}, {Name: "label", Value: "b"}, {Name: GeneratorLabel, Value: "generator-1"}},
+			Samples: []*tempopb.MetricsSample{{TimestampMs: 2, Value: 2}, {TimestampMs: 1, Value: 1}},
+		},
+		{
+			// same series as the first one, returned by another metrics-generator
+			Labels:  []*tempopb.MetricsLabel{{Name: "label", Value: "b"}, {Name: "__name__", Value: "my_metric"}, {Name: GeneratorLabel, Value: "generator-0"}},
+			Samples: []*tempopb.MetricsSample{{TimestampMs: 2, Value: 2}, {TimestampMs: 3, Value: 3}},
+		},
+		{
+			Labels:  []*tempopb.MetricsLabel{{Name: "__name__", Value: "my_metric"}, {Name: "label", Value: "a"}, {Name: GeneratorLabel, Value: "generator-0"}},
+			Samples: []*tempopb.MetricsSample{{TimestampMs: 1, Value: 5}},
+		},
+	}
+
+	set := newSeriesSet(series)
+
+	require.True(t, set.Next())
+	assert.Equal(t, labels.FromStrings("__name__", "my_metric", GeneratorLabel, "generator-0", "label", "a"), set.At().Labels())
+	assert.Equal(t, []sample{{1, 5}}, expandSamples(t, set.At()))
+
+	require.True(t, set.Next())
+	assert.Equal(t, labels.FromStrings("__name__", "my_metric", GeneratorLabel, "generator-0", "label", "b"), set.At().Labels())
+	assert.Equal(t, []sample{{2, 2}, {3, 3}}, expandSamples(t, set.At()))
+
+	require.True(t, set.Next())
+	assert.Equal(t, labels.FromStrings("__name__", "my_metric", GeneratorLabel, "generator-1", "label", "b"), set.At().Labels())
+	assert.Equal(t, []sample{{1, 1}, {2, 2}}, expandSamples(t, set.At()))
+
+	assert.False(t, set.Next())
+	assert.NoError(t, set.Err())
+}
+
+func TestToMetricsLabelMatcher(t *testing.T) {
+	tests := []struct {
+		matcher  *labels.Matcher
+		expected *tempopb.MetricsLabelMatcher
+	}{
+		{labels.MustNewMatcher(labels.MatchEqual, "foo", "bar"), &tempopb.MetricsLabelMatcher{Type: 0, Name: "foo", Value: "bar"}},
+		{labels.MustNewMatcher(labels.MatchNotEqual, "foo", "bar"), &tempopb.MetricsLabelMatcher{Type: 1, Name: "foo", Value: "bar"}},
+		{labels.MustNewMatcher(labels.MatchRegexp, "foo", "b.*"), &tempopb.MetricsLabelMatcher{Type: 2, Name: "foo", Value: "b.*"}},
+		{labels.MustNewMatcher(labels.MatchNotRegexp, "foo", "b.*"), &tempopb.MetricsLabelMatcher{Type: 3, Name: "foo", Value: "b.*"}},
+	}
+
+	for _, tc := range tests {
+		matcher, err := toMetricsLabelMatcher(tc.matcher)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, matcher)
+	}
+
+	_, err := toMetricsLabelMatcher(&labels.Matcher{Type: labels.MatchType(42), Name: "foo"})
+	assert.EqualError(t, err, "unknown match type 42")
+}
+
+func TestSplitGeneratorMatchers(t *testing.T) {
+	req := &tempopb.MetricsSeriesRequest{
+		Matchers: []*tempopb.MetricsLabelMatcher{
+			{Type: 0, Name: "__name__", Value: "my_metric"},
+			{Type: 2, Name: GeneratorLabel, Value: "generator-.*"},
+			{Type: 1, Name: GeneratorLabel, Value: "generator-1"},
+		},
+	}
+
+	generatorMatchers, err := splitGeneratorMatchers(req)
+	require.NoError(t, err)
+	assert.Equal(t, []*tempopb.MetricsLabelMatcher{{Type: 0, Name: "__name__", Value: "my_metric"}}, req.Matchers)
+	require.Len(t, generatorMatchers, 2)
+
+	tests := []struct {
+		addr     string
+		expected bool
+	}{
+		{"generator-0", true},
+		{"generator-1", false},
+		{"other-0", false},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.expected, matchesGenerator(generatorMatchers, tc.addr), tc.addr)
+	}
+
+	_, err = splitGeneratorMatchers(&tempopb.MetricsSeriesRequest{
+		Matchers: []*tempopb.MetricsLabelMatcher{{Type: 42, Name: GeneratorLabel, Value: "generator-0"}},
+	})
+	assert.EqualError(t, err, "invalid matcher on label generator: unknown match type 42")
+}
+
+func TestMetricsEngine(t *testing.T) {
+	engine := newMetricsEngine(MetricsConfig{
+		QueryTimeout:  time.Minute,
+		MaxSamples:    1000,
+		LookbackDelta: 5 * time.Minute,
+	})
+
+	start := time.Unix(0, 0)
+	series := []*tempopb.MetricsSeries{
+		{
+			Labels: []*tempopb.MetricsLabel{{Name: "__name__", Value: "traces_spanmetrics_calls_total"}, {Name: "service", Value: "app"}, {Name: GeneratorLabel, Value: "generator-0"}},
+		},
+		{
+			Labels: []*tempopb.MetricsLabel{{Name: "__name__", Value: "traces_spanmetrics_calls_total"}, {Name: "service", Value: "app"}, {Name: GeneratorLabel, Value: "generator-1"}},
+		},
+	}
+	// each metrics-generator counts half of the spans: the counters increase by 15 every 15 seconds together,
+	// they are scraped at different times
+	for i := int64(0); i <= 40; i++ {
+		series[0].Samples = append(series[0].Samples, &tempopb.MetricsSample{
+			TimestampMs: start.Add(time.Duration(i) * 15 * time.Second).UnixMilli(),
+			Value:       float64(i * 10),
+		})
+		series[1].Samples = append(series[1].Samples, &tempopb.MetricsSample{
+			TimestampMs: start.Add(time.Duration(i)*15*time.Second + 7*time.Second).UnixMilli(),
+			Value:       float64(i * 5),
+		})
+	}
+
+	query, err := engine.NewRangeQuery(staticQueryable(series), `sum by (service) (rate(traces_spanmetrics_calls_total[1m]))`, start.Add(5*time.Minute), start.Add(10*time.Minute), time.Minute)
+	require.NoError(t, err)
+	defer query.Close()
+
+	res := query.Exec(context.Background())
+	require.NoError(t, res.Err)
+
+	matrix, err := res.Matrix()
+	require.NoError(t, err)
+	require.Len(t, matrix, 1)
+	assert.Equal(t, labels.FromStrings("service", "app"), matrix[0].Metric)
+	require.Len(t, matrix[0].Points, 6)
+	for _, p := range matrix[0].Points {
+		assert.InDelta(t, 1.0, p.V, 0.001)
+	}
+}
+
+func expandSamples(t *testing.T, s prometheus_storage.Series) []sample {
+	var result []sample
+	it := s.Iterator()
+	for it.Next() {
+		ts, v := it.At()
+		result = append(result, sample{ts, v})
+	}
+	require.NoError(t, it.Err())
+	return result
+}
+
+type staticQueryable []*tempopb.MetricsSeries
+
+var _ prometheus_storage.Queryable = (staticQueryable)(nil)
+
+func (s staticQueryable) Querier(context.Context, int64, int64) (prometheus_storage.Querier, error) {
+	return s, nil
+}
+
+func (s staticQueryable) Select(_ bool, _ *prometheus_storage.SelectHints, matchers ...*labels.Matcher) prometheus_storage.SeriesSet {
+	var result []*tempopb.MetricsSeries
+	for _, series := range s {
+		lbls := labels.Labels{}
+		for _, l := range series.Labels {
+			lbls = append(lbls, labels.Label{Name: l.Name, Value: l.Value})
+		}
+
+		matches := true
+		for _, m := range matchers {
+			matches = matches && m.Matches(lbls.Get(m.Name))
+		}
+		if matches {
+			result = append(result, series)
+		}
+	}
+	return newSeriesSet(result)
+}
+
+func (s staticQueryable) LabelValues(string, ...*labels.Matcher) ([]string, prometheus_storage.Warnings, error) {
+	return nil, nil, nil
+}
+
+func (s staticQueryable) LabelNames(...*labels.Matcher) ([]string, prometheus_storage.Warnings, error) {
+	return nil, nil, nil
+}
+
+func (s staticQueryable) Close() error {
+	return nil
+}
@@ -22,17 +22,20 @@ import (
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/promql"
 	httpgrpc_server "github.com/weaveworks/common/httpgrpc/server"
 	"github.com/weaveworks/common/user"
 	"go.uber.org/multierr"
 	"golang.org/x/sync/semaphore"
 
+	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/querier/worker"
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/querystats"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
@@ -48,6 +51,11 @@ var (
 		Name:      "querier_ingester_clients",
 		Help:      "The current number of ingester clients.",
 	})
+	metricGeneratorClients = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "querier_metrics_generator_clients",
+		Help:      "The current number of metrics-generator clients.",
+	})
 	metricEndpointDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Namespace: "tempo",
 		Name:      "querier_external_endpoint_duration_seconds",
@@ -66,6 +74,12 @@ type Querier struct {
 	store  storage.Store
 	limits *overrides.Overrides
 
+	// generatorRing and generatorPool are nil if the metrics-generator is not enabled
+	generatorRing ring.ReadRing
+	generatorPool *ring_client.Pool
+	// metricsEngine evaluates PromQL queries against the metrics-generators
+	metricsEngine *promql.Engine
+
 	searchClient     *http.Client
 	searchPreferSelf *semaphore.Weighted
 
@@ -78,8 +92,8 @@ type responseFromIngesters struct {
 	response interface{}
 }
 
-// New makes a new Querier.
-func New(cfg Config, clientCfg ingester_client.Config, ring ring.ReadRing, store storage.Store, limits *overrides.Overrides) (*Querier, error) {
+// New makes a new Querier. generatorRing is nil if the metrics-generator is not enabled.
+func New(cfg Config, clientCfg ingester_client.Config, ring ring.ReadRing, generatorClientCfg generator_client.Config, generatorRing ring.ReadRing, store storage.Store, limits *overrides.Overrides) (*Querier, error) {
 	factory := func(addr string) (ring_client.PoolClient, error) {
 		return ingester_client.New(addr, clientCfg)
 	}
@@ -99,6 +113,19 @@ func New(cfg Config, clientCfg ingester_client.Config, ring ring.ReadRing, store
 		searchClient:     http.DefaultClient,
 	}
 
+	if generatorRing != nil {
+		q.generatorRing = generatorRing
+		q.generatorPool = ring_client.NewPool("querier_metrics_generator_pool",
+			generatorClientCfg.PoolConfig,
+			ring_client.NewRingServiceDiscovery(generatorRing),
+			func(addr string) (ring_client.PoolClient, error) {
+				return generator_client.New(addr, generatorClientCfg)
+			},
+			metricGeneratorClients,
+			log.Logger)
+		q.metricsEngine = newMetricsEngine(cfg.Metrics)
+	}
+
 	//
 	if cfg.Search.HedgeRequestsAt != 0 {
 		var err error
@@ -124,6 +151,9 @@ func (q *Querier) CreateAndRegisterWorker(handler http.Handler) error {
 		return fmt.Errorf("failed to create frontend worker: %w", err)
 	}
 
+	if q.generatorPool != nil {
+		return q.RegisterSubservices(worker, q.pool, q.generatorPool)
+	}
 	return q.RegisterSubservices(worker, q.pool)
 }
 
@@ -215,9 +245,12 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 	}
 
 	var failedBlocks int
+	var stats *querystats.Collector
 	if req.QueryMode == QueryModeBlocks || req.QueryMode == QueryModeAll {
 		span.LogFields(ot_log.String("msg", "searching store"))
-		partialTraces, blockErrs, err := q.store.Find(opentracing.ContextWithSpan(ctx, span), userID, req.TraceID, req.BlockStart, req.BlockEnd)
+		var storeCtx context.Context
+		stats, storeCtx = querystats.NewContext(ctx)
+		partialTraces, blockErrs, err := q.store.Find(opentracing.ContextWithSpan(storeCtx, span), userID, req.TraceID, req.BlockStart, req.BlockEnd, int64(req.Start), int64(req.End))
 		if err != nil {
 			return nil, errors.Wrap(err, "error querying store in Querier.FindTraceByID")
 		}
@@ -242,10 +275,113 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 		Trace: completeTrace,
 		Metrics: &tempopb.TraceByIDMetrics{
 			FailedBlocks: uint32(failedBlocks),
+			Stats:        stats.Stats(),
 		},
 	}, nil
 }
 
+// FindTracesByID looks up a batch of trace ids. Ingesters are queried once and each block is searched once for
+// all the ids it may contain. Only the ids found or whose lookup failed in some blocks are returned.
+func (q *Querier) FindTracesByID(ctx context.Context, req *tempopb.TracesByIDRequest) (*tempopb.TracesByIDResponse, error) {
+	for _, id := range req.TraceIDs {
+		if !validation.ValidTraceID(id) {
+			return nil, fmt.Errorf("invalid trace id")
+		}
+	}
+
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.FindTracesByID")
+	}
+
+	span, ctx := opentracing.StartSpanFromContext(ctx, "Querier.FindTracesByID")
+	defer span.Finish()
+
+	ids := make([]common.ID, len(req.TraceIDs))
+	hexIDs := make([]string, len(req.TraceIDs))
+	combiners := make(map[string]*trace.Combiner, len(req.TraceIDs))
+	for i, id := range req.TraceIDs {
+		ids[i] = id
+		hexIDs[i] = util.TraceIDToHexString(id)
+		combiners[hexIDs[i]] = trace.NewCombiner()
+	}
+
+	if req.QueryMode == QueryModeIngesters || req.QueryMode == QueryModeAll {
+		replicationSet, err := q.ring.GetReplicationSetForOperation(ring.Read)
+		if err != nil {
+			return nil, errors.Wrap(err, "error finding ingesters in Querier.FindTracesByID")
+		}
+
+		span.LogFields(ot_log.String("msg", "searching ingesters"))
+		// get responses from all ingesters in parallel
+		responses, err := q.forGivenIngesters(ctx, replicationSet, func(client tempopb.QuerierClient) (interface{}, error) {
+			return client.FindTracesByID(opentracing.ContextWithSpan(ctx, span), req)
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "error querying ingesters in Querier.FindTracesByID")
+		}
+
+		for _, r := range responses {
+			for hexID, result := range r.response.(*tempopb.TracesByIDResponse).Traces {
+				if c, ok := combiners[hexID]; ok && result.Trace != nil {
+					c.Consume(result.Trace)
+				}
+			}
+		}
+		span.LogFields(ot_log.String("msg", "done searching ingesters"))
+	}
+
+	failedBlocks := make([]int, len(ids))
+	blockErrors := make([]error, len(ids))
+	var stats *querystats.Collector
+	if req.QueryMode == QueryModeBlocks || req.QueryMode == QueryModeAll {
+		span.LogFields(ot_log.String("msg", "searching store"))
+		var storeCtx context.Context
+		stats, storeCtx = querystats.NewContext(ctx)
+		partialTraces, blockErrs, err := q.store.FindMany(opentracing.ContextWithSpan(storeCtx, span), userID, ids, req.BlockStart, req.BlockEnd, int64(req.Start), int64(req.End))
+		if err != nil {
+			return nil, errors.Wrap(err, "error querying store in Querier.FindTracesByID")
+		}
+
+		for i := range ids {
+			if len(blockErrs[i]) > 0 {
+				failedBlocks[i] = len(blockErrs[i])
+				blockErrors[i] = multierr.Combine(blockErrs[i]...)
+				_ = level.Warn(log.Logger).Log("msg", fmt.Sprintf("failed to query %d blocks", failedBlocks[i]), "traceID", hexIDs[i], "blockErrs", blockErrors[i])
+			}
+
+			for _, partialTrace := range partialTraces[i] {
+				combiners[hexIDs[i]].Consume(partialTrace)
+			}
+		}
+		span.LogFields(ot_log.String("msg", "done searching store"))
+	}
+
+	resp := &tempopb.TracesByIDResponse{
+		Traces: make(map[string]*tempopb.TraceByIDResult, len(ids)),
+		Metrics: &tempopb.TraceByIDMetrics{
+			Stats: stats.Stats(),
+		},
+	}
+	for i, hexID := range hexIDs {
+		completeTrace, _ := combiners[hexID].Result()
+		if completeTrace == nil && failedBlocks[i] == 0 {
+			continue
+		}
+
+		result := &tempopb.TraceByIDResult{
+			Trace:        completeTrace,
+			FailedBlocks: uint32(failedBlocks[i]),
+		}
+		if blockErrors[i] != nil {
+			result.Error = blockErrors[i].Error()
+		}
+		resp.Traces[hexID] = result
+	}
+
+	return resp, nil
+}
+
 // forGivenIngesters runs f, in parallel, for given ingesters
 func (q *Querier) forGivenIngesters(ctx context.Context, replicationSet ring.ReplicationSet, f func(client tempopb.QuerierClient) (interface{}, error)) ([]responseFromIngesters, error) {
 	results, err := replicationSet.Do(ctx, q.cfg.ExtraQueryDelay, func(ctx context.Context, ingester *ring.InstanceDesc) (interface{}, error) {
@@ -391,6 +527,294 @@ func (q *Querier) SearchTagValues(ctx context.Context, req *tempopb.SearchTagVal
 	return resp, nil
 }
 
+// GetServiceGraph returns the service graph between the start and end of the request. The edges
+// collected by all metrics-generators are combined. Failing metrics-generators are returned as warnings,
+// the request only fails if all of them fail.
+func (q *Querier) GetServiceGraph(ctx context.Context, req *tempopb.ServiceGraphRequest) (*tempopb.ServiceGraphResponse, error) {
+	if q.generatorRing == nil {
+		return nil, errors.New("the metrics-generator is not enabled")
+	}
+
+	_, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.GetServiceGraph")
+	}
+
+	replicationSet, err := q.generatorRing.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding metrics-generators in Querier.GetServiceGraph")
+	}
+
+	type generatorResult struct {
+		addr string
+		resp *tempopb.ServiceGraphResponse
+		err  error
+	}
+
+	// errors are returned as part of the result so a single metrics-generator doesn't fail the request
+	results, err := replicationSet.Do(ctx, 0, func(ctx context.Context, generator *ring.InstanceDesc) (interface{}, error) {
+		result := &generatorResult{addr: generator.Addr}
+
+		client, err := q.generatorPool.GetClientFor(generator.Addr)
+		if err != nil {
+			result.err = err
+			return result, nil
+		}
+		result.resp, result.err = client.(tempopb.MetricsGeneratorClient).GetServiceGraph(ctx, req)
+		return result, nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying metrics-generators in Querier.GetServiceGraph")
+	}
+
+	responses := make([]*tempopb.ServiceGraphResponse, 0, len(results))
+	var warnings []error
+	for _, r := range results {
+		result := r.(*generatorResult)
+		if result.err != nil {
+			level.Warn(log.Logger).Log("msg", "error querying metrics-generator", "generator", result.addr, "err", result.err)
+			warnings = append(warnings, fmt.Errorf("error querying metrics-generator %s: %w", result.addr, result.err))
+			continue
+		}
+		responses = append(responses, result.resp)
+	}
+	if len(results) > 0 && len(warnings) == len(results) {
+		return nil, errors.Wrap(warnings[0], "all metrics-generators failed in Querier.GetServiceGraph")
+	}
+
+	window := time.Duration(req.End-req.Start) * time.Second
+	resp := combineServiceGraph(responses, window)
+	for _, w := range warnings {
+		resp.Warnings = append(resp.Warnings, w.Error())
+	}
+	return resp, nil
+}
+
+// combineServiceGraph sums the edges of all responses and computes the nodes, rates and average
+// latencies over the window.
+func combineServiceGraph(responses []*tempopb.ServiceGraphResponse, window time.Duration) *tempopb.ServiceGraphResponse {
+	type edgeKey struct {
+		client, server, connectionType string
+	}
+
+	edges := map[edgeKey]*tempopb.ServiceGraphEdge{}
+	for _, resp := range responses {
+		for _, e := range resp.Edges {
+			k := edgeKey{e.Client, e.Server, e.ConnectionType}
+			sum, ok := edges[k]
+			if !ok {
+				sum = &tempopb.ServiceGraphEdge{
+					Client:         e.Client,
+					Server:         e.Server,
+					ConnectionType: e.ConnectionType,
+				}
+				edges[k] = sum
+			}
+			sum.Requests += e.Requests
+			sum.FailedRequests += e.FailedRequests
+			sum.ClientLatencySum += e.ClientLatencySum
+			sum.ServerRequests += e.ServerRequests
+			sum.ServerLatencySum += e.ServerLatencySum
+		}
+	}
+
+	rate := func(count uint64) float64 {
+		if window <= 0 {
+			return 0
+		}
+		return float64(count) / window.Seconds()
+	}
+	ratio := func(a float64, b uint64) float64 {
+		if b == 0 {
+			return 0
+		}
+		return a / float64(b)
+	}
+
+	nodes := map[string]*tempopb.ServiceGraphNode{}
+	node := func(name string) *tempopb.ServiceGraphNode {
+		n, ok := nodes[name]
+		if !ok {
+			n = &tempopb.ServiceGraphNode{Name: name}
+			nodes[name] = n
+		}
+		return n
+	}
+
+	resp := &tempopb.ServiceGraphResponse{
+		Edges: make([]*tempopb.ServiceGraphEdge, 0, len(edges)),
+	}
+	for _, e := range edges {
+		e.RequestRate = rate(e.Requests)
+		e.ErrorRate = ratio(float64(e.FailedRequests), e.Requests)
+		e.ClientLatencyAvg = ratio(e.ClientLatencySum, e.Requests)
+		e.ServerLatencyAvg = ratio(e.ServerLatencySum, e.ServerRequests)
+		resp.Edges = append(resp.Edges, e)
+
+		node(e.Client)
+		server := node(e.Server)
+		server.Requests += e.Requests
+		server.FailedRequests += e.FailedRequests
+	}
+
+	resp.Nodes = make([]*tempopb.ServiceGraphNode, 0, len(nodes))
+	for _, n := range nodes {
+		n.RequestRate = rate(n.Requests)
+		n.ErrorRate = ratio(float64(n.FailedRequests), n.Requests)
+		resp.Nodes = append(resp.Nodes, n)
+	}
+
+	sort.Slice(resp.Nodes, func(i, j int) bool {
+		return resp.Nodes[i].Name < resp.Nodes[j].Name
+	})
+	sort.Slice(resp.Edges, func(i, j int) bool {
+		a, b := resp.Edges[i], resp.Edges[j]
+		if a.Client != b.Client {
+			return a.Client < b.Client
+		}
+		if a.Server != b.Server {
+			return a.Server < b.Server
+		}
+		return a.ConnectionType < b.ConnectionType
+	})
+
+	return resp
+}
+
+// GetCardinality returns the active series of the metrics of the tenant summed over all metrics-generators.
+// Failing metrics-generators are returned as warnings, the request only fails if all of them fail.
+func (q *Querier) GetCardinality(ctx context.Context, req *tempopb.CardinalityRequest) (*tempopb.CardinalityResponse, error) {
+	if q.generatorRing == nil {
+		return nil, errors.New("the metrics-generator is not enabled")
+	}
+
+	_, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error extracting org id in Querier.GetCardinality")
+	}
+
+	replicationSet, err := q.generatorRing.GetReplicationSetForOperation(ring.Read)
+	if err != nil {
+		return nil, errors.Wrap(err, "error finding metrics-generators in Querier.GetCardinality")
+	}
+
+	type generatorResult struct {
+		addr string
+		resp *tempopb.CardinalityResponse
+		err  error
+	}
+
+	// errors are returned as part of the result so a single metrics-generator doesn't fail the request
+	results, err := replicationSet.Do(ctx, 0, func(ctx context.Context, generator *ring.InstanceDesc) (interface{}, error) {
+		result := &generatorResult{addr: generator.Addr}
+
+		client, err := q.generatorPool.GetClientFor(generator.Addr)
+		if err != nil {
+			result.err = err
+			return result, nil
+		}
+		result.resp, result.err = client.(tempopb.MetricsGeneratorClient).GetCardinality(ctx, req)
+		return result, nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying metrics-generators in Querier.GetCardinality")
+	}
+
+	responses := make([]*tempopb.CardinalityResponse, 0, len(results))
+	var warnings []error
+	for _, r := range results {
+		result := r.(*generatorResult)
+		if result.err != nil {
+			level.Warn(log.Logger).Log("msg", "error querying metrics-generator", "generator", result.addr, "err", result.err)
+			warnings = append(warnings, fmt.Errorf("error querying metrics-generator %s: %w", result.addr, result.err))
+			continue
+		}
+		responses = append(responses, result.resp)
+	}
+	if len(results) > 0 && len(warnings) == len(results) {
+		return nil, errors.Wrap(warnings[0], "all metrics-generators failed in Querier.GetCardinality")
+	}
+
+	resp := combineCardinality(responses, int(req.Limit))
+	for _, w := range warnings {
+		resp.Warnings = append(resp.Warnings, w.Error())
+	}
+	return resp, nil
+}
+
+// combineCardinality sums the active series of the metrics and label values of all responses. Spans are
+// sharded across the metrics-generators by trace ID, so the same label values are usually seen by all of
+// them: the amount of distinct values of a label is the maximum of the responses and the top values are
+// summed from the values returned by each metrics-generator. At most limit values are returned per label.
+func combineCardinality(responses []*tempopb.CardinalityResponse, limit int) *tempopb.CardinalityResponse {
+	type labelKey struct {
+		metric, label string
+	}
+
+	metrics := map[string]*tempopb.MetricCardinality{}
+	labels := map[labelKey]*tempopb.LabelCardinality{}
+	values := map[labelKey]map[string]uint32{}
+
+	resp := &tempopb.CardinalityResponse{}
+	for _, r := range responses {
+		resp.ActiveSeries += r.ActiveSeries
+
+		for _, m := range r.Metrics {
+			metric, ok := metrics[m.Name]
+			if !ok {
+				metric = &tempopb.MetricCardinality{Name: m.Name}
+				metrics[m.Name] = metric
+				resp.Metrics = append(resp.Metrics, metric)
+			}
+			metric.ActiveSeries += m.ActiveSeries
+
+			for _, l := range m.Labels {
+				k := labelKey{m.Name, l.Name}
+				label, ok := labels[k]
+				if !ok {
+					label = &tempopb.LabelCardinality{Name: l.Name}
+					labels[k] = label
+					values[k] = map[string]uint32{}
+					metric.Labels = append(metric.Labels, label)
+				}
+				if l.Values > label.Values {
+					label.Values = l.Values
+				}
+				for _, v := range l.TopValues {
+					values[k][v.Value] += v.ActiveSeries
+				}
+			}
+		}
+	}
+
+	for k, label := range labels {
+		for value, activeSeries := range values[k] {
+			label.TopValues = append(label.TopValues, &tempopb.LabelValueCardinality{Value: value, ActiveSeries: activeSeries})
+		}
+		sort.Slice(label.TopValues, func(i, j int) bool {
+			a, b := label.TopValues[i], label.TopValues[j]
+			if a.ActiveSeries != b.ActiveSeries {
+				return a.ActiveSeries > b.ActiveSeries
+			}
+			return a.Value < b.Value
+		})
+		if len(label.TopValues) > limit {
+			label.TopValues = label.TopValues[:limit]
+		}
+	}
+
+	for _, m := range resp.Metrics {
+		sort.SliceStable(m.Labels, func(i, j int) bool {
+			return m.Labels[i].Values > m.Labels[j].Values
+		})
+	}
+	sort.SliceStable(resp.Metrics, func(i, j int) bool {
+		return resp.Metrics[i].ActiveSeries > resp.Metrics[j].ActiveSeries
+	})
+
+	return resp
+}
+
 // SearchBlock searches the specified subset of the block for the passed tags.
 func (q *Querier) SearchBlock(ctx context.Context, req *tempopb.SearchBlockRequest) (*tempopb.SearchResponse, error) {
 	// if we have no external configuration always search in the querier
@@ -446,7 +870,17 @@ func (q *Querier) internalSearchBlock(ctx context.Context, req *tempopb.SearchBl
 	opts.TotalPages = int(req.PagesToSearch)
 	opts.MaxBytes = q.limits.MaxBytesPerTrace(tenantID)
 
-	return q.store.Search(ctx, meta, req.SearchReq, opts)
+	stats, ctx := querystats.NewContext(ctx)
+	resp, err := q.store.Search(ctx, meta, req.SearchReq, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Metrics == nil {
+		resp.Metrics = &tempopb.SearchMetrics{}
+	}
+	resp.Metrics.Stats = stats.Stats()
+	return resp, nil
 }
 
 func (q *Querier) postProcessSearchResults(req *tempopb.SearchRequest, rr []responseFromIngesters) *tempopb.SearchResponse {
@@ -7,9 +7,11 @@ import (
 	"testing"
 	"time"
 
+	generator_client "github.com/grafana/tempo/modules/generator/client"
 	"github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/uber-go/atomic"
 	"github.com/weaveworks/common/user"
@@ -66,7 +68,7 @@ func TestQuerierUsesSearchExternalEndpoint(t *testing.T) {
 		o, err := overrides.NewOverrides(overrides.Limits{})
 		require.NoError(t, err)
 
-		q, err := New(tc.cfg, client.Config{}, nil, nil, o)
+		q, err := New(tc.cfg, client.Config{}, nil, generator_client.Config{}, nil, nil, o)
 		require.NoError(t, err)
 
 		for i := 0; i < tc.queriesToExecute; i++ {
@@ -78,3 +80,78 @@ func TestQuerierUsesSearchExternalEndpoint(t *testing.T) {
 		require.Equal(t, tc.externalExpected, numExternalRequests.Load())
 	}
 }
+
+func TestCombineServiceGraph(t *testing.T) {
+	responses := []*tempopb.ServiceGraphResponse{
+		{
+			Edges: []*tempopb.ServiceGraphEdge{
+				{Client: "app", Server: "db", Requests: 10, FailedRequests: 1, ClientLatencySum: 2, ServerLatencySum: 1, ServerRequests: 10},
+				{Client: "lb", Server: "app", Requests: 20, ClientLatencySum: 4, ServerLatencySum: 2, ServerRequests: 20},
+			},
+		},
+		{
+			Edges: []*tempopb.ServiceGraphEdge{
+				{Client: "app", Server: "db", Requests: 10, FailedRequests: 4, ClientLatencySum: 2, ServerLatencySum: 1, ServerRequests: 10},
+				{Client: "app", Server: "postgresql", ConnectionType: "database", Requests: 5, ClientLatencySum: 1},
+			},
+		},
+		{},
+	}
+
+	expected := &tempopb.ServiceGraphResponse{
+		Nodes: []*tempopb.ServiceGraphNode{
+			{Name: "app", Requests: 20, RequestRate: 0.2},
+			{Name: "db", Requests: 20, FailedRequests: 5, RequestRate: 0.2, ErrorRate: 0.25},
+			{Name: "lb"},
+			{Name: "postgresql", Requests: 5, RequestRate: 0.05},
+		},
+		Edges: []*tempopb.ServiceGraphEdge{
+			{Client: "app", Server: "db", Requests: 20, FailedRequests: 5, ClientLatencySum: 4, ServerLatencySum: 2, ServerRequests: 20, RequestRate: 0.2, ErrorRate: 0.25, ClientLatencyAvg: 0.2, ServerLatencyAvg: 0.1},
+			{Client: "app", Server: "postgresql", ConnectionType: "database", Requests: 5, ClientLatencySum: 1, RequestRate: 0.05, ClientLatencyAvg: 0.2},
+			{Client: "lb", Server: "app", Requests: 20, ClientLatencySum: 4, ServerLatencySum: 2, ServerRequests: 20, RequestRate: 0.2, ClientLatencyAvg: 0.2, ServerLatencyAvg: 0.1},
+		},
+	}
+
+	assert.Equal(t, expected, combineServiceGraph(responses, 100*time.Second))
+}
+
+func TestCombineCardinality(t *testing.T) {
+	responses := []*tempopb.CardinalityResponse{
+		{
+			ActiveSeries: 5,
+			Metrics: []*tempopb.MetricCardinality{
+				{Name: "calls_total", ActiveSeries: 3, Labels: []*tempopb.LabelCardinality{
+					{Name: "service", Values: 2, TopValues: []*tempopb.LabelValueCardinality{{Value: "app", ActiveSeries: 2}, {Value: "db", ActiveSeries: 1}}},
+				}},
+				{Name: "size_total", ActiveSeries: 2, Labels: []*tempopb.LabelCardinality{
+					{Name: "service", Values: 2, TopValues: []*tempopb.LabelValueCardinality{{Value: "app", ActiveSeries: 1}, {Value: "db", ActiveSeries: 1}}},
+				}},
+			},
+		},
+		{
+			ActiveSeries: 4,
+			Metrics: []*tempopb.MetricCardinality{
+				{Name: "calls_total", ActiveSeries: 4, Labels: []*tempopb.LabelCardinality{
+					{Name: "service", Values: 3, TopValues: []*tempopb.LabelValueCardinality{{Value: "db", ActiveSeries: 2}, {Value: "lb", ActiveSeries: 1}}},
+					{Name: "status", Values: 1, TopValues: []*tempopb.LabelValueCardinality{{Value: "ok", ActiveSeries: 4}}},
+				}},
+			},
+		},
+		{},
+	}
+
+	expected := &tempopb.CardinalityResponse{
+		ActiveSeries: 9,
+		Metrics: []*tempopb.MetricCardinality{
+			{Name: "calls_total", ActiveSeries: 7, Labels: []*tempopb.LabelCardinality{
+				{Name: "service", Values: 3, TopValues: []*tempopb.LabelValueCardinality{{Value: "db", ActiveSeries: 3}, {Value: "app", ActiveSeries: 2}}},
+				{Name: "status", Values: 1, TopValues: []*tempopb.LabelValueCardinality{{Value: "ok", ActiveSeries: 4}}},
+			}},
+			{Name: "size_total", ActiveSeries: 2, Labels: []*tempopb.LabelCardinality{
+				{Name: "service", Values: 2, TopValues: []*tempopb.LabelValueCardinality{{Value: "app", ActiveSeries: 1}, {Value: "db", ActiveSeries: 1}}},
+			}},
+		},
+	}
+
+	assert.Equal(t, expected, combineCardinality(responses, 2))
+}
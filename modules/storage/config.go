@@ -10,6 +10,7 @@ import (
 	"github.com/grafana/tempo/tempodb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/azure"
+	"github.com/grafana/tempo/tempodb/backend/diskcache"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
@@ -80,6 +81,13 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.Trace.BackgroundCache.WriteBackBuffer = 10000
 	cfg.Trace.BackgroundCache.WriteBackGoroutines = 10
 
+	cfg.Trace.LRU = &cache.LRUConfig{}
+	cfg.Trace.LRU.MaxSizeBytes = 128 * 1024 * 1024
+
+	cfg.Trace.DiskCache = &diskcache.Config{}
+	f.StringVar(&cfg.Trace.DiskCache.Path, util.PrefixConfig(prefix, "trace.disk-cache.path"), "", "path to cache ranges of blocks read by queries at, disabled if empty.")
+	cfg.Trace.DiskCache.MaxSizeBytes = 10 * 1024 * 1024 * 1024
+
 	cfg.Trace.Pool = &pool.Config{}
 	f.IntVar(&cfg.Trace.Pool.MaxWorkers, util.PrefixConfig(prefix, "trace.pool.max-workers"), 50, "Workers in the worker pool.")
 	f.IntVar(&cfg.Trace.Pool.QueueDepth, util.PrefixConfig(prefix, "trace.pool.queue-depth"), 10000, "Work item queue depth.")
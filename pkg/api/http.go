@@ -1,8 +1,10 @@
 package api
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -27,6 +29,18 @@ const (
 	urlParamStart       = "start"
 	urlParamEnd         = "end"
 
+	// trace comparison
+	urlParamTraceA = "a"
+	urlParamTraceB = "b"
+
+	// trace summary
+	urlParamPrune      = "prune"
+	urlParamPruneSpans = "prune_spans"
+
+	// metrics query range
+	urlParamQuery = "query"
+	urlParamStep  = "step"
+
 	// backend search (querier/serverless)
 	urlParamStartPage     = "startPage"
 	urlParamPagesToSearch = "pagesToSearch"
@@ -48,14 +62,49 @@ const (
 	PathPrefixQuerier = "/querier"
 
 	PathTraces          = "/api/traces/{traceID}"
+	PathTracesBatch     = "/api/traces"
+	PathTracesCompare   = "/api/traces/compare"
+	PathTraceSummary    = "/api/traces/{traceID}/summary"
 	PathSearch          = "/api/search"
 	PathSearchTags      = "/api/search/tags"
 	PathSearchTagValues = "/api/search/tag/{tagName}/values"
 	PathEcho            = "/api/echo"
+	PathDependencies    = "/api/dependencies"
+	PathQueryRange      = "/api/v1/query_range"
+	PathCardinality     = "/api/metrics/cardinality"
+	PathQueries         = "/api/queries"
+	PathQuery           = "/api/queries/{queryID}"
 
 	defaultLimit = 20
+
+	defaultServiceGraphWindow = time.Hour
+
+	defaultQueryRangeWindow = time.Hour
+
+	defaultCardinalityLimit = 10
+	// maxQueryRangePoints is the maximum of points per series, this matches the limit of Prometheus.
+	maxQueryRangePoints = 11000
+
+	// maxTraceSummaryPrunedSubtrees is the maximum number of subtrees of the pruned trace of a trace summary.
+	maxTraceSummaryPrunedSubtrees = 100
+	// defaultTraceSummaryPrunedSpans and maxTraceSummaryPrunedSpans bound the number of spans of the pruned
+	// trace of a trace summary.
+	defaultTraceSummaryPrunedSpans = 1000
+	maxTraceSummaryPrunedSpans     = 10000
 )
 
+// TracesByIDBody is the JSON body of a batch trace by ID request.
+type TracesByIDBody struct {
+	TraceIDs []string `json:"traceIDs"`
+}
+
+// QueryRangeRequest is a PromQL range query against the metrics stored by the metrics-generators.
+type QueryRangeRequest struct {
+	Query      string
+	Start, End time.Time
+	Step       time.Duration
+}
+
 func ParseTraceID(r *http.Request) ([]byte, error) {
 	vars := mux.Vars(r)
 	traceID, ok := vars[URLParamTraceID]
@@ -71,6 +120,118 @@ func ParseTraceID(r *http.Request) ([]byte, error) {
 	return byteID, nil
 }
 
+// ParseTraceIDs decodes the trace IDs of the JSON body of a batch trace by ID request. Duplicate IDs
+// are dropped. Batches of more than maxTraceIDs are rejected, 0 means unlimited.
+func ParseTraceIDs(r *http.Request, maxTraceIDs int) ([][]byte, error) {
+	body := &TracesByIDBody{}
+	if err := json.NewDecoder(r.Body).Decode(body); err != nil {
+		return nil, fmt.Errorf("invalid body: %w", err)
+	}
+
+	if len(body.TraceIDs) == 0 {
+		return nil, errors.New("please provide at least one traceID")
+	}
+	if maxTraceIDs > 0 && len(body.TraceIDs) > maxTraceIDs {
+		return nil, fmt.Errorf("too many traceIDs: %d, the maximum is %d", len(body.TraceIDs), maxTraceIDs)
+	}
+
+	seen := make(map[string]struct{}, len(body.TraceIDs))
+	ids := make([][]byte, 0, len(body.TraceIDs))
+	for _, traceID := range body.TraceIDs {
+		byteID, err := util.HexStringToTraceID(traceID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid traceID %s: %w", traceID, err)
+		}
+
+		key := util.TraceIDToHexString(byteID)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		ids = append(ids, byteID)
+	}
+
+	return ids, nil
+}
+
+// ParseTraceComparisonRequest decodes the IDs of the two traces of a trace comparison request.
+func ParseTraceComparisonRequest(r *http.Request) ([]byte, []byte, error) {
+	ids := make([][]byte, 2)
+	for i, param := range []string{urlParamTraceA, urlParamTraceB} {
+		traceID, ok := extractQueryParam(r, param)
+		if !ok {
+			return nil, nil, fmt.Errorf("please provide the traceIDs to compare as %s and %s", urlParamTraceA, urlParamTraceB)
+		}
+
+		byteID, err := util.HexStringToTraceID(traceID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %w", param, err)
+		}
+		ids[i] = byteID
+	}
+
+	return ids[0], ids[1], nil
+}
+
+// ParseTraceSummaryPrune decodes the optional prune and prune_spans params of a trace summary request: the
+// number of subtrees of the pruned trace returned with the summary and its maximum number of spans. 0
+// subtrees means no trace is returned.
+func ParseTraceSummaryPrune(r *http.Request) (int, int, error) {
+	s, ok := extractQueryParam(r, urlParamPrune)
+	if !ok {
+		return 0, 0, nil
+	}
+
+	prune, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid prune: %w", err)
+	}
+	if prune < 0 || prune > maxTraceSummaryPrunedSubtrees {
+		return 0, 0, fmt.Errorf("invalid prune: must be between 0 and %d", maxTraceSummaryPrunedSubtrees)
+	}
+
+	spans := defaultTraceSummaryPrunedSpans
+	if s, ok := extractQueryParam(r, urlParamPruneSpans); ok {
+		spans, err = strconv.Atoi(s)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid prune_spans: %w", err)
+		}
+		if spans <= 0 || spans > maxTraceSummaryPrunedSpans {
+			return 0, 0, fmt.Errorf("invalid prune_spans: must be between 1 and %d", maxTraceSummaryPrunedSpans)
+		}
+	}
+
+	return prune, spans, nil
+}
+
+// ParseTraceByIDTimeRange decodes the optional start and end params of a trace by ID request in unix
+// epoch seconds. They are hints of the time range the trace was ingested in, 0 means unbounded.
+func ParseTraceByIDTimeRange(r *http.Request) (uint32, uint32, error) {
+	var start, end uint32
+
+	if s, ok := extractQueryParam(r, urlParamStart); ok {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid start: %w", err)
+		}
+		start = uint32(v)
+	}
+
+	if s, ok := extractQueryParam(r, urlParamEnd); ok {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid end: %w", err)
+		}
+		end = uint32(v)
+	}
+
+	if start != 0 && end != 0 && end < start {
+		return 0, 0, fmt.Errorf("http parameter start must be before end. received start=%d end=%d", start, end)
+	}
+
+	return start, end, nil
+}
+
 // ParseSearchRequest takes an http.Request and decodes query params to create a tempopb.SearchRequest
 func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	req := &tempopb.SearchRequest{
@@ -177,6 +338,135 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	return req, nil
 }
 
+// ParseServiceGraphRequest takes an http.Request and decodes the start and end params in unix seconds. End
+// defaults to now and start to one hour before end.
+func ParseServiceGraphRequest(r *http.Request) (*tempopb.ServiceGraphRequest, error) {
+	req := &tempopb.ServiceGraphRequest{}
+
+	if s, ok := extractQueryParam(r, urlParamEnd); ok {
+		end, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end: %w", err)
+		}
+		req.End = uint32(end)
+	} else {
+		req.End = uint32(time.Now().Unix())
+	}
+
+	if s, ok := extractQueryParam(r, urlParamStart); ok {
+		start, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start: %w", err)
+		}
+		req.Start = uint32(start)
+	} else {
+		req.Start = req.End - uint32(defaultServiceGraphWindow.Seconds())
+	}
+
+	if req.End <= req.Start {
+		return nil, fmt.Errorf("http parameter start must be before end. received start=%d end=%d", req.Start, req.End)
+	}
+
+	return req, nil
+}
+
+// ParseCardinalityRequest takes an http.Request and decodes the limit param, the amount of values returned
+// per label. It defaults to 10.
+func ParseCardinalityRequest(r *http.Request) (*tempopb.CardinalityRequest, error) {
+	req := &tempopb.CardinalityRequest{
+		Limit: defaultCardinalityLimit,
+	}
+
+	if s, ok := extractQueryParam(r, urlParamLimit); ok {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			return nil, errors.New("invalid limit: must be a positive number")
+		}
+		req.Limit = uint32(limit)
+	}
+
+	return req, nil
+}
+
+// ParseQueryRangeRequest takes an http.Request and decodes the query, start, end and step params. Like
+// Prometheus, start and end are unix timestamps in seconds or RFC3339 and step is a duration or a
+// number of seconds. The params can be passed in the url or as a form. End defaults to now, start to
+// one hour before end and step to a value resulting in 250 points per series.
+func ParseQueryRangeRequest(r *http.Request) (*QueryRangeRequest, error) {
+	req := &QueryRangeRequest{
+		Query: r.FormValue(urlParamQuery),
+	}
+	if req.Query == "" {
+		return nil, errors.New("http parameter query is required")
+	}
+
+	req.End = time.Now()
+	if s := r.FormValue(urlParamEnd); s != "" {
+		end, err := parseTime(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end: %w", err)
+		}
+		req.End = end
+	}
+
+	req.Start = req.End.Add(-defaultQueryRangeWindow)
+	if s := r.FormValue(urlParamStart); s != "" {
+		start, err := parseTime(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start: %w", err)
+		}
+		req.Start = start
+	}
+
+	if req.End.Before(req.Start) {
+		return nil, fmt.Errorf("http parameter start must be before end. received start=%s end=%s", req.Start.Format(time.RFC3339), req.End.Format(time.RFC3339))
+	}
+
+	req.Step = req.End.Sub(req.Start) / 250
+	if req.Step < time.Second {
+		req.Step = time.Second
+	}
+	if s := r.FormValue(urlParamStep); s != "" {
+		step, err := parseDuration(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid step: %w", err)
+		}
+		if step <= 0 {
+			return nil, errors.New("invalid step: must be a positive duration")
+		}
+		req.Step = step
+	}
+
+	if req.End.Sub(req.Start)/req.Step > maxQueryRangePoints {
+		return nil, fmt.Errorf("exceeded maximum resolution of %d points per series, decrease the range or increase the step", maxQueryRangePoints)
+	}
+
+	return req, nil
+}
+
+// parseTime parses a unix timestamp in seconds, with optional decimals, or a RFC3339 time.
+func parseTime(s string) (time.Time, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		sec, frac := math.Modf(f)
+		return time.Unix(int64(sec), int64(math.Round(frac*1000))*int64(time.Millisecond)), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q to a valid timestamp", s)
+}
+
+// parseDuration parses a number of seconds, with optional decimals, or a duration.
+func parseDuration(s string) (time.Duration, error) {
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(f * float64(time.Second)), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	return 0, fmt.Errorf("cannot parse %q to a valid duration", s)
+}
+
 // ParseBlockSearchRequest parses all http parameters necessary to perform a block search.
 func ParseSearchBlockRequest(r *http.Request) (*tempopb.SearchBlockRequest, error) {
 	searchReq, err := ParseSearchRequest(r)
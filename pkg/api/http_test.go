@@ -5,10 +5,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/grafana/tempo/cmd/tempo-query/tempo"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -309,6 +312,337 @@ func TestParseSearchBlockRequest(t *testing.T) {
 	}
 }
 
+func TestParseServiceGraphRequest(t *testing.T) {
+	tests := []struct {
+		url           string
+		expected      *tempopb.ServiceGraphRequest
+		expectedError string
+	}{
+		{
+			url:      "/?start=10&end=20",
+			expected: &tempopb.ServiceGraphRequest{Start: 10, End: 20},
+		},
+		{
+			url:      "/?end=7200",
+			expected: &tempopb.ServiceGraphRequest{Start: 3600, End: 7200},
+		},
+		{
+			url:           "/?start=20&end=10",
+			expectedError: "http parameter start must be before end. received start=20 end=10",
+		},
+		{
+			url:           "/?start=foo",
+			expectedError: "invalid start: strconv.ParseInt: parsing \"foo\": invalid syntax",
+		},
+	}
+
+	for _, tc := range tests {
+		r := httptest.NewRequest("GET", tc.url, nil)
+		actualReq, actualErr := ParseServiceGraphRequest(r)
+
+		if len(tc.expectedError) != 0 {
+			assert.EqualError(t, actualErr, tc.expectedError)
+			assert.Nil(t, actualReq)
+			continue
+		}
+		assert.NoError(t, actualErr)
+		assert.Equal(t, tc.expected, actualReq)
+	}
+
+	// end defaults to now
+	actualReq, err := ParseServiceGraphRequest(httptest.NewRequest("GET", "/", nil))
+	require.NoError(t, err)
+	assert.InDelta(t, time.Now().Unix(), actualReq.End, 5)
+	assert.Equal(t, actualReq.End-3600, actualReq.Start)
+}
+
+func TestParseCardinalityRequest(t *testing.T) {
+	tests := []struct {
+		url           string
+		expected      *tempopb.CardinalityRequest
+		expectedError string
+	}{
+		{
+			url:      "/",
+			expected: &tempopb.CardinalityRequest{Limit: 10},
+		},
+		{
+			url:      "/?limit=3",
+			expected: &tempopb.CardinalityRequest{Limit: 3},
+		},
+		{
+			url:           "/?limit=0",
+			expectedError: "invalid limit: must be a positive number",
+		},
+		{
+			url:           "/?limit=foo",
+			expectedError: "invalid limit: must be a positive number",
+		},
+	}
+
+	for _, tc := range tests {
+		r := httptest.NewRequest("GET", tc.url, nil)
+		actualReq, actualErr := ParseCardinalityRequest(r)
+
+		if len(tc.expectedError) != 0 {
+			assert.EqualError(t, actualErr, tc.expectedError)
+			assert.Nil(t, actualReq)
+			continue
+		}
+		assert.NoError(t, actualErr)
+		assert.Equal(t, tc.expected, actualReq)
+	}
+}
+
+func TestParseTraceIDs(t *testing.T) {
+	tests := []struct {
+		body          string
+		expected      []string
+		expectedError string
+	}{
+		{
+			body:     `{"traceIDs": ["1", "0a"]}`,
+			expected: []string{"1", "a"},
+		},
+		{
+			body:     `{"traceIDs": ["1", "01", "2"]}`,
+			expected: []string{"1", "2"},
+		},
+		{
+			body:          `{"traceIDs": []}`,
+			expectedError: "please provide at least one traceID",
+		},
+		{
+			body:          `{"traceIDs": ["zz"]}`,
+			expectedError: "invalid traceID zz: trace IDs can only contain hex characters: invalid character 'z' at position 1",
+		},
+		{
+			body:          `[`,
+			expectedError: "invalid body: unexpected EOF",
+		},
+		{
+			body:          `{"traceIDs": [` + strings.Repeat(`"1",`, 10) + `"1"]}`,
+			expectedError: "too many traceIDs: 11, the maximum is 10",
+		},
+	}
+
+	for _, tc := range tests {
+		ids, err := ParseTraceIDs(httptest.NewRequest("POST", "/", strings.NewReader(tc.body)), 10)
+
+		if len(tc.expectedError) != 0 {
+			assert.EqualError(t, err, tc.expectedError)
+			continue
+		}
+		assert.NoError(t, err)
+		actual := make([]string, 0, len(ids))
+		for _, id := range ids {
+			actual = append(actual, util.TraceIDToHexString(id))
+		}
+		assert.Equal(t, tc.expected, actual)
+	}
+}
+
+func TestParseTraceComparisonRequest(t *testing.T) {
+	tests := []struct {
+		url           string
+		expectedA     string
+		expectedB     string
+		expectedError string
+	}{
+		{
+			url:       "/?a=1&b=0a",
+			expectedA: "1",
+			expectedB: "a",
+		},
+		{
+			url:           "/?a=1",
+			expectedError: "please provide the traceIDs to compare as a and b",
+		},
+		{
+			url:           "/?a=zz&b=1",
+			expectedError: "invalid a: trace IDs can only contain hex characters: invalid character 'z' at position 1",
+		},
+	}
+
+	for _, tc := range tests {
+		a, b, err := ParseTraceComparisonRequest(httptest.NewRequest("GET", tc.url, nil))
+
+		if len(tc.expectedError) != 0 {
+			assert.EqualError(t, err, tc.expectedError)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expectedA, util.TraceIDToHexString(a))
+		assert.Equal(t, tc.expectedB, util.TraceIDToHexString(b))
+	}
+}
+
+func TestParseTraceSummaryPrune(t *testing.T) {
+	tests := []struct {
+		url           string
+		expected      int
+		expectedSpans int
+		expectedError string
+	}{
+		{
+			url: "/",
+		},
+		{
+			url:           "/?prune=10",
+			expected:      10,
+			expectedSpans: 1000,
+		},
+		{
+			url:           "/?prune=10&prune_spans=50",
+			expected:      10,
+			expectedSpans: 50,
+		},
+		{
+			url:           "/?prune=-1",
+			expectedError: "invalid prune: must be between 0 and 100",
+		},
+		{
+			url:           "/?prune=101",
+			expectedError: "invalid prune: must be between 0 and 100",
+		},
+		{
+			url:           "/?prune=foo",
+			expectedError: "invalid prune: strconv.Atoi: parsing \"foo\": invalid syntax",
+		},
+		{
+			url:           "/?prune=10&prune_spans=0",
+			expectedError: "invalid prune_spans: must be between 1 and 10000",
+		},
+		{
+			url:           "/?prune=10&prune_spans=10001",
+			expectedError: "invalid prune_spans: must be between 1 and 10000",
+		},
+	}
+
+	for _, tc := range tests {
+		prune, spans, err := ParseTraceSummaryPrune(httptest.NewRequest("GET", tc.url, nil))
+
+		if len(tc.expectedError) != 0 {
+			assert.EqualError(t, err, tc.expectedError)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, prune)
+		assert.Equal(t, tc.expectedSpans, spans)
+	}
+}
+
+func TestParseTraceByIDTimeRange(t *testing.T) {
+	tests := []struct {
+		url           string
+		expectedStart uint32
+		expectedEnd   uint32
+		expectedError string
+	}{
+		{
+			url: "/",
+		},
+		{
+			url:           "/?start=10&end=20",
+			expectedStart: 10,
+			expectedEnd:   20,
+		},
+		{
+			url:           "/?start=10",
+			expectedStart: 10,
+		},
+		{
+			url:         "/?end=20",
+			expectedEnd: 20,
+		},
+		{
+			url:           "/?start=20&end=10",
+			expectedError: "http parameter start must be before end. received start=20 end=10",
+		},
+		{
+			url:           "/?end=foo",
+			expectedError: "invalid end: strconv.ParseInt: parsing \"foo\": invalid syntax",
+		},
+	}
+
+	for _, tc := range tests {
+		start, end, err := ParseTraceByIDTimeRange(httptest.NewRequest("GET", tc.url, nil))
+
+		if len(tc.expectedError) != 0 {
+			assert.EqualError(t, err, tc.expectedError)
+			continue
+		}
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expectedStart, start)
+		assert.Equal(t, tc.expectedEnd, end)
+	}
+}
+
+func TestParseQueryRangeRequest(t *testing.T) {
+	tests := []struct {
+		url           string
+		expected      *QueryRangeRequest
+		expectedError string
+	}{
+		{
+			url:      "/?query=up&start=10&end=20&step=5",
+			expected: &QueryRangeRequest{Query: "up", Start: time.Unix(10, 0), End: time.Unix(20, 0), Step: 5 * time.Second},
+		},
+		{
+			url:      "/?query=up&start=10.5&end=20&step=1m",
+			expected: &QueryRangeRequest{Query: "up", Start: time.Unix(10, 500*int64(time.Millisecond)), End: time.Unix(20, 0), Step: time.Minute},
+		},
+		{
+			url:      "/?query=up&start=1970-01-01T00:00:10Z&end=1970-01-01T01:00:10Z",
+			expected: &QueryRangeRequest{Query: "up", Start: time.Unix(10, 0).UTC(), End: time.Unix(3610, 0).UTC(), Step: 14400 * time.Millisecond},
+		},
+		{
+			url:      "/?query=up&end=7200&step=1",
+			expected: &QueryRangeRequest{Query: "up", Start: time.Unix(3600, 0), End: time.Unix(7200, 0), Step: time.Second},
+		},
+		{
+			url:           "/?start=10&end=20",
+			expectedError: "http parameter query is required",
+		},
+		{
+			url:           "/?query=up&start=20&end=10",
+			expectedError: "http parameter start must be before end. received start=" + time.Unix(20, 0).Format(time.RFC3339) + " end=" + time.Unix(10, 0).Format(time.RFC3339),
+		},
+		{
+			url:           "/?query=up&start=foo",
+			expectedError: "invalid start: cannot parse \"foo\" to a valid timestamp",
+		},
+		{
+			url:           "/?query=up&step=-1",
+			expectedError: "invalid step: must be a positive duration",
+		},
+		{
+			url:           "/?query=up&start=0&end=86400&step=1",
+			expectedError: "exceeded maximum resolution of 11000 points per series, decrease the range or increase the step",
+		},
+	}
+
+	for _, tc := range tests {
+		r := httptest.NewRequest("GET", tc.url, nil)
+		actualReq, actualErr := ParseQueryRangeRequest(r)
+
+		if len(tc.expectedError) != 0 {
+			assert.EqualError(t, actualErr, tc.expectedError)
+			assert.Nil(t, actualReq)
+			continue
+		}
+		assert.NoError(t, actualErr)
+		assert.Equal(t, tc.expected, actualReq)
+	}
+
+	// params can be passed as a form
+	r := httptest.NewRequest("POST", "/", strings.NewReader("query=up&start=10&end=20&step=5"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	actualReq, err := ParseQueryRangeRequest(r)
+	require.NoError(t, err)
+	assert.Equal(t, &QueryRangeRequest{Query: "up", Start: time.Unix(10, 0), End: time.Unix(20, 0), Step: 5 * time.Second}, actualReq)
+}
+
 func TestBuildSearchBlockRequest(t *testing.T) {
 	tests := []struct {
 		req     *tempopb.SearchBlockRequest
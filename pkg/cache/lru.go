@@ -0,0 +1,177 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// LRUConfig configures the in-process LRU cache.
+type LRUConfig struct {
+	// MaxSizeBytes is the maximum size of the keys and values stored in the cache.
+	MaxSizeBytes int `yaml:"max_size_bytes"`
+	// TTL is how long values stay in the cache, 0 keeps them until they are evicted.
+	TTL time.Duration `yaml:"ttl"`
+}
+
+type lruEntry struct {
+	key     string
+	buf     []byte
+	expires time.Time
+}
+
+// LRU is a Cache storing values in memory. The least recently used values are evicted when the
+// size of the keys and values exceeds the configured maximum. Stored buffers are not copied and
+// must not be modified after they are stored or fetched.
+type LRU struct {
+	cfg LRUConfig
+	now func() time.Time
+
+	mtx   sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+
+	hits      prometheus.Counter
+	misses    prometheus.Counter
+	evictions prometheus.Counter
+	sizeBytes prometheus.Gauge
+	entries   prometheus.Gauge
+}
+
+// NewLRU makes a new LRU cache.
+func NewLRU(cfg LRUConfig, name string, reg prometheus.Registerer) *LRU {
+	return &LRU{
+		cfg:   cfg,
+		now:   time.Now,
+		ll:    list.New(),
+		items: map[string]*list.Element{},
+
+		hits: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   "tempo",
+			Name:        "cache_lru_hits_total",
+			Help:        "Total count of keys found in the LRU cache.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+		misses: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   "tempo",
+			Name:        "cache_lru_misses_total",
+			Help:        "Total count of keys missing or expired in the LRU cache.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+		evictions: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Namespace:   "tempo",
+			Name:        "cache_lru_evictions_total",
+			Help:        "Total count of values evicted from the LRU cache to stay below the maximum size.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+		sizeBytes: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   "tempo",
+			Name:        "cache_lru_size_bytes",
+			Help:        "Current size of the keys and values in the LRU cache.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+		entries: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace:   "tempo",
+			Name:        "cache_lru_entries",
+			Help:        "Current number of values in the LRU cache.",
+			ConstLabels: prometheus.Labels{"name": name},
+		}),
+	}
+}
+
+// Store implements Cache
+func (c *LRU) Store(_ context.Context, keys []string, bufs [][]byte) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var expires time.Time
+	if c.cfg.TTL > 0 {
+		expires = c.now().Add(c.cfg.TTL)
+	}
+
+	for i, key := range keys {
+		if elem, ok := c.items[key]; ok {
+			c.remove(elem)
+		}
+
+		size := entrySize(key, bufs[i])
+		if size > c.cfg.MaxSizeBytes {
+			continue
+		}
+
+		for c.size+size > c.cfg.MaxSizeBytes {
+			c.remove(c.ll.Back())
+			c.evictions.Inc()
+		}
+
+		c.items[key] = c.ll.PushFront(&lruEntry{
+			key:     key,
+			buf:     bufs[i],
+			expires: expires,
+		})
+		c.size += size
+	}
+
+	c.updateGauges()
+}
+
+// Fetch implements Cache
+func (c *LRU) Fetch(_ context.Context, keys []string) (found []string, bufs [][]byte, missing []string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	now := c.now()
+	for _, key := range keys {
+		elem, ok := c.items[key]
+		if ok {
+			entry := elem.Value.(*lruEntry)
+			if entry.expires.IsZero() || now.Before(entry.expires) {
+				c.ll.MoveToFront(elem)
+				found = append(found, key)
+				bufs = append(bufs, entry.buf)
+				continue
+			}
+			c.remove(elem)
+		}
+		missing = append(missing, key)
+	}
+
+	c.hits.Add(float64(len(found)))
+	c.misses.Add(float64(len(missing)))
+	c.updateGauges()
+
+	return
+}
+
+// Stop implements Cache
+func (c *LRU) Stop() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.ll.Init()
+	c.items = map[string]*list.Element{}
+	c.size = 0
+	c.updateGauges()
+}
+
+// remove removes the element from the cache. Must be called under lock.
+func (c *LRU) remove(elem *list.Element) {
+	entry := c.ll.Remove(elem).(*lruEntry)
+	delete(c.items, entry.key)
+	c.size -= entrySize(entry.key, entry.buf)
+}
+
+// updateGauges must be called under lock.
+func (c *LRU) updateGauges() {
+	c.sizeBytes.Set(float64(c.size))
+	c.entries.Set(float64(len(c.items)))
+}
+
+func entrySize(key string, buf []byte) int {
+	return len(key) + len(buf)
+}
@@ -0,0 +1,72 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+
+	// every value is 1 byte key + 4 bytes value
+	c := NewLRU(LRUConfig{MaxSizeBytes: 15}, "test", prometheus.NewRegistry())
+
+	c.Store(ctx, []string{"a", "b", "c"}, [][]byte{[]byte("aaaa"), []byte("bbbb"), []byte("cccc")})
+	assert.Equal(t, 15, c.size)
+
+	// a is now the most recently used value
+	found, bufs, missing := c.Fetch(ctx, []string{"a", "x"})
+	assert.Equal(t, []string{"a"}, found)
+	assert.Equal(t, [][]byte{[]byte("aaaa")}, bufs)
+	assert.Equal(t, []string{"x"}, missing)
+
+	// storing d evicts b
+	c.Store(ctx, []string{"d"}, [][]byte{[]byte("dddd")})
+	found, _, missing = c.Fetch(ctx, []string{"a", "b", "c", "d"})
+	assert.Equal(t, []string{"a", "c", "d"}, found)
+	assert.Equal(t, []string{"b"}, missing)
+
+	// overwriting a value updates the size
+	c.Store(ctx, []string{"a"}, [][]byte{[]byte("a")})
+	assert.Equal(t, 12, c.size)
+
+	// values larger than the cache are not stored
+	c.Store(ctx, []string{"e"}, [][]byte{make([]byte, 20)})
+	_, _, missing = c.Fetch(ctx, []string{"e"})
+	assert.Equal(t, []string{"e"}, missing)
+	assert.Equal(t, 12, c.size)
+
+	assert.Equal(t, 4.0, testutil.ToFloat64(c.hits))
+	assert.Equal(t, 3.0, testutil.ToFloat64(c.misses))
+	assert.Equal(t, 1.0, testutil.ToFloat64(c.evictions))
+	assert.Equal(t, 12.0, testutil.ToFloat64(c.sizeBytes))
+	assert.Equal(t, 3.0, testutil.ToFloat64(c.entries))
+
+	c.Stop()
+	assert.Equal(t, 0, c.size)
+	assert.Equal(t, 0.0, testutil.ToFloat64(c.entries))
+}
+
+func TestLRU_TTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+
+	c := NewLRU(LRUConfig{MaxSizeBytes: 100, TTL: time.Minute}, "test", prometheus.NewRegistry())
+	c.now = func() time.Time { return now }
+
+	c.Store(ctx, []string{"a"}, [][]byte{[]byte("a")})
+
+	now = now.Add(59 * time.Second)
+	found, _, _ := c.Fetch(ctx, []string{"a"})
+	assert.Equal(t, []string{"a"}, found)
+
+	now = now.Add(time.Second)
+	_, _, missing := c.Fetch(ctx, []string{"a"})
+	assert.Equal(t, []string{"a"}, missing)
+	assert.Equal(t, 0, c.size)
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"context"
+)
+
+type tiered []Cache
+
+// NewTiered makes a new Cache combining several caches, ordered from the fastest to the slowest.
+// Values are stored in every cache. Keys are fetched from the caches in order until they are found,
+// values found in a slower cache are stored in the faster caches before it.
+func NewTiered(caches []Cache) Cache {
+	if len(caches) == 1 {
+		return caches[0]
+	}
+	return tiered(caches)
+}
+
+// Store implements Cache
+func (t tiered) Store(ctx context.Context, keys []string, bufs [][]byte) {
+	for _, c := range t {
+		c.Store(ctx, keys, bufs)
+	}
+}
+
+// Fetch implements Cache
+func (t tiered) Fetch(ctx context.Context, keys []string) (found []string, bufs [][]byte, missing []string) {
+	missing = keys
+
+	for i, c := range t {
+		var tierFound []string
+		var tierBufs [][]byte
+		tierFound, tierBufs, missing = c.Fetch(ctx, missing)
+
+		if len(tierFound) > 0 {
+			for _, faster := range t[:i] {
+				faster.Store(ctx, tierFound, tierBufs)
+			}
+			found = append(found, tierFound...)
+			bufs = append(bufs, tierBufs...)
+		}
+
+		if len(missing) == 0 {
+			break
+		}
+	}
+
+	return
+}
+
+// Stop implements Cache
+func (t tiered) Stop() {
+	for _, c := range t {
+		c.Stop()
+	}
+}
@@ -0,0 +1,38 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/tempo/pkg/cache"
+)
+
+func TestTiered(t *testing.T) {
+	ctx := context.Background()
+
+	fast := cache.NewMockCache()
+	slow := cache.NewMockCache()
+	c := cache.NewTiered([]cache.Cache{fast, slow})
+
+	fast.Store(ctx, []string{"a"}, [][]byte{[]byte("a")})
+	slow.Store(ctx, []string{"b"}, [][]byte{[]byte("b")})
+	c.Store(ctx, []string{"c"}, [][]byte{[]byte("c")})
+
+	found, bufs, missing := c.Fetch(ctx, []string{"a", "b", "c", "d"})
+	assert.Equal(t, []string{"a", "c", "b"}, found)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("c"), []byte("b")}, bufs)
+	assert.Equal(t, []string{"d"}, missing)
+
+	// values found in the slow cache are stored in the fast cache
+	found, _, _ = fast.Fetch(ctx, []string{"a", "b", "c"})
+	assert.Equal(t, []string{"a", "b", "c"}, found)
+
+	c.Stop()
+}
+
+func TestTiered_single(t *testing.T) {
+	c := cache.NewMockCache()
+	assert.Equal(t, c, cache.NewTiered([]cache.Cache{c}))
+}
@@ -31,6 +31,12 @@ func init() {
 	encoding.RegisterCodec(newCodec())
 }
 
+// sharedUnmarshaler is implemented by messages that can be unmarshalled without copying their byte fields. gRPC
+// allocates a new buffer for every received message, so it is safe for the message to reference it.
+type sharedUnmarshaler interface {
+	UnmarshalShared(data []byte) error
+}
+
 // gogoCodec forces the use of gogo proto marshalling/unmarshalling for Tempo/Cortex/Jaeger/etcd structs
 type gogoCodec struct {
 }
@@ -59,6 +65,10 @@ func (c *gogoCodec) Marshal(v interface{}) ([]byte, error) {
 
 // Unmarshal implements encoding.Codec
 func (c *gogoCodec) Unmarshal(data []byte, v interface{}) error {
+	if u, ok := v.(sharedUnmarshaler); ok {
+		return u.UnmarshalShared(data)
+	}
+
 	t := reflect.TypeOf(v)
 	elem := t.Elem()
 	// use gogo proto only for Tempo/Cortex/Jaeger/etcd types
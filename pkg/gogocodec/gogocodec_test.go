@@ -3,6 +3,7 @@
 package gogocodec
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/golang/protobuf/proto" //lint:ignore SA1019 deprecated package
@@ -29,6 +30,28 @@ func TestCodecMarshallAndUnmarshall_tempo_type(t *testing.T) {
 	assert.Equal(t, req1, req2)
 }
 
+func TestCodecUnmarshallPushBytesRequestShared(t *testing.T) {
+	c := newCodec()
+	req1 := &tempopb.PushBytesRequest{
+		Ids:    []tempopb.PreallocBytes{{Slice: []byte{0x01}}},
+		Traces: []tempopb.PreallocBytes{{Slice: []byte{0x02, 0x03}}},
+	}
+	data, err := c.Marshal(req1)
+	require.NoError(t, err)
+
+	req2 := &tempopb.PushBytesRequest{}
+	err = c.Unmarshal(data, req2)
+	require.NoError(t, err)
+	assert.Equal(t, req1.Traces[0].Slice, req2.Traces[0].Slice)
+	assert.Equal(t, req1.Ids[0].Slice, req2.Ids[0].Slice)
+
+	// the trace references the received buffer
+	idx := bytes.Index(data, req1.Traces[0].Slice)
+	require.GreaterOrEqual(t, idx, 0)
+	assert.Same(t, &data[idx], &req2.Traces[0].Slice[0])
+	req2.Release()
+}
+
 func TestCodecMarshallAndUnmarshall_foreign_type(t *testing.T) {
 	// marshal a foreign object (anything other than Tempo/Cortex/Jaeger) using the custom codec
 	c := newCodec()
@@ -0,0 +1,69 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+// OffsetNone is returned by Client.CommittedOffset when no offset has been committed for a
+// partition. Consumption then begins at the oldest retained record.
+const OffsetNone int64 = -1
+
+var errClosed = errors.New("ingest client closed")
+
+// Record is a single message of the ingest topic. It carries the rebatched traces of a single
+// tenant that hash to the same partition.
+type Record struct {
+	TenantID string
+	Offset   int64
+	Request  *tempopb.PushBytesRequest
+}
+
+// Client reads and writes records of the ingest topic.
+type Client interface {
+	// Produce writes records to a partition. It returns once the records have been acknowledged.
+	Produce(ctx context.Context, partition int32, records []*Record) error
+	// Consume returns a consumer for a partition starting at the given offset.
+	Consume(partition int32, offset int64) (PartitionConsumer, error)
+	// CommittedOffset returns the next offset to consume for a partition or OffsetNone.
+	CommittedOffset(partition int32) (int64, error)
+	// Commit marks all records before offset as processed.
+	Commit(partition int32, offset int64) error
+	Close() error
+}
+
+// PartitionConsumer streams the records of a single partition.
+type PartitionConsumer interface {
+	Records() <-chan *Record
+	Errors() <-chan error
+	Close() error
+}
+
+// PartitionForToken returns the partition a trace with the given ring token is written to.
+func PartitionForToken(token uint32, partitions int32) int32 {
+	return int32(token % uint32(partitions))
+}
+
+func encodeRecord(r *Record) ([]byte, error) {
+	if r.TenantID == "" {
+		return nil, errors.New("record has no tenant")
+	}
+	return r.Request.Marshal()
+}
+
+// decodeRecord decodes a record value. The request references value instead of copying it, so value must not be
+// modified afterwards.
+func decodeRecord(tenantID string, offset int64, value []byte) (*Record, error) {
+	req := &tempopb.PushBytesRequest{}
+	if err := req.UnmarshalShared(value); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal ingest record at offset %d: %w", offset, err)
+	}
+	return &Record{
+		TenantID: tenantID,
+		Offset:   offset,
+		Request:  req,
+	}, nil
+}
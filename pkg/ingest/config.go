@@ -0,0 +1,53 @@
+package ingest
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+// Config for the Kafka-native ingest path. When enabled, distributors write rebatched traces to
+// a partitioned Kafka topic instead of pushing them to the ingesters, and ingesters consume
+// their partitions directly.
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+
+	Brokers       flagext.StringSliceCSV `yaml:"brokers"`
+	Topic         string                 `yaml:"topic"`
+	Partitions    int                    `yaml:"partitions"`
+	ConsumerGroup string                 `yaml:"consumer_group"`
+
+	// Partitions consumed by this ingester. If empty the ingester consumes the partition matching
+	// the ordinal at the end of its lifecycler ID (e.g. ingester-3 consumes partition 3).
+	ConsumePartitions []int32 `yaml:"consume_partitions"`
+
+	WriteTimeout   time.Duration `yaml:"write_timeout"`
+	CommitInterval time.Duration `yaml:"commit_interval"`
+}
+
+// RegisterFlagsAndApplyDefaults registers the flags.
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+".enabled", false, "Write traces to Kafka in the distributors and consume them in the ingesters.")
+	f.Var(&cfg.Brokers, prefix+".brokers", "Comma separated list of Kafka brokers.")
+	f.StringVar(&cfg.Topic, prefix+".topic", "tempo-ingest", "Kafka topic traces are written to.")
+	f.IntVar(&cfg.Partitions, prefix+".partitions", 1, "Number of partitions of the Kafka topic. Must match the topic configuration.")
+	f.StringVar(&cfg.ConsumerGroup, prefix+".consumer-group", "tempo-ingester", "Kafka consumer group used to commit ingester offsets.")
+	f.DurationVar(&cfg.WriteTimeout, prefix+".write-timeout", 10*time.Second, "Timeout for writes to the Kafka topic.")
+	f.DurationVar(&cfg.CommitInterval, prefix+".commit-interval", time.Second, "How often ingesters commit the offsets of data that made it into the WAL.")
+}
+
+// Validate checks the config for errors.
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.Topic == "" {
+		return errors.New("ingest topic must be set")
+	}
+	if cfg.Partitions <= 0 {
+		return errors.New("ingest partitions must be greater than zero")
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Shopify/sarama"
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+)
+
+const tenantHeader = "X-Scope-OrgID"
+
+type kafkaClient struct {
+	topic string
+
+	client   sarama.Client
+	producer sarama.SyncProducer
+	consumer sarama.Consumer
+	offsets  sarama.OffsetManager
+
+	pomsMtx sync.Mutex
+	poms    map[int32]sarama.PartitionOffsetManager
+
+	logger log.Logger
+}
+
+var _ Client = (*kafkaClient)(nil)
+
+// NewKafkaClient creates a Client backed by a Kafka cluster.
+func NewKafkaClient(cfg Config, logger log.Logger) (Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	saramaCfg := sarama.NewConfig()
+	saramaCfg.Version = sarama.V2_0_0_0
+	saramaCfg.Producer.Partitioner = sarama.NewManualPartitioner
+	saramaCfg.Producer.RequiredAcks = sarama.WaitForAll
+	saramaCfg.Producer.Return.Successes = true
+	saramaCfg.Producer.Timeout = cfg.WriteTimeout
+	saramaCfg.Consumer.Return.Errors = true
+	saramaCfg.Consumer.Offsets.Initial = sarama.OffsetOldest
+	// offsets are only committed explicitly after the data has been written to the wal
+	saramaCfg.Consumer.Offsets.AutoCommit.Enable = false
+
+	client, err := sarama.NewClient(cfg.Brokers, saramaCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka client: %w", err)
+	}
+
+	producer, err := sarama.NewSyncProducerFromClient(client)
+	if err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create kafka producer: %w", err)
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(client)
+	if err != nil {
+		_ = producer.Close()
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create kafka consumer: %w", err)
+	}
+
+	offsets, err := sarama.NewOffsetManagerFromClient(cfg.ConsumerGroup, client)
+	if err != nil {
+		_ = consumer.Close()
+		_ = producer.Close()
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create kafka offset manager: %w", err)
+	}
+
+	return &kafkaClient{
+		topic:    cfg.Topic,
+		client:   client,
+		producer: producer,
+		consumer: consumer,
+		offsets:  offsets,
+		poms:     map[int32]sarama.PartitionOffsetManager{},
+		logger:   logger,
+	}, nil
+}
+
+// Produce implements Client
+func (k *kafkaClient) Produce(_ context.Context, partition int32, records []*Record) error {
+	msgs := make([]*sarama.ProducerMessage, 0, len(records))
+	for _, r := range records {
+		value, err := encodeRecord(r)
+		if err != nil {
+			return err
+		}
+
+		msgs = append(msgs, &sarama.ProducerMessage{
+			Topic:     k.topic,
+			Partition: partition,
+			Value:     sarama.ByteEncoder(value),
+			Headers: []sarama.RecordHeader{
+				{Key: []byte(tenantHeader), Value: []byte(r.TenantID)},
+			},
+		})
+	}
+
+	return k.producer.SendMessages(msgs)
+}
+
+// Consume implements Client
+func (k *kafkaClient) Consume(partition int32, offset int64) (PartitionConsumer, error) {
+	if offset < 0 {
+		offset = sarama.OffsetOldest
+	}
+
+	pc, err := k.consumer.ConsumePartition(k.topic, partition, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume partition %d: %w", partition, err)
+	}
+
+	c := &kafkaPartitionConsumer{
+		pc:      pc,
+		records: make(chan *Record),
+		errors:  make(chan error, 1),
+		logger:  log.With(k.logger, "partition", partition),
+	}
+	c.wg.Add(1)
+	go c.loop()
+
+	return c, nil
+}
+
+// CommittedOffset implements Client
+func (k *kafkaClient) CommittedOffset(partition int32) (int64, error) {
+	pom, err := k.partitionOffsetManager(partition)
+	if err != nil {
+		return 0, err
+	}
+
+	offset, _ := pom.NextOffset()
+	if offset < 0 {
+		return OffsetNone, nil
+	}
+	return offset, nil
+}
+
+// Commit implements Client
+func (k *kafkaClient) Commit(partition int32, offset int64) error {
+	pom, err := k.partitionOffsetManager(partition)
+	if err != nil {
+		return err
+	}
+
+	pom.MarkOffset(offset, "")
+	k.offsets.Commit()
+	return nil
+}
+
+// Close implements Client
+func (k *kafkaClient) Close() error {
+	k.pomsMtx.Lock()
+	for _, pom := range k.poms {
+		_ = pom.Close()
+	}
+	k.pomsMtx.Unlock()
+
+	_ = k.offsets.Close()
+	_ = k.consumer.Close()
+	_ = k.producer.Close()
+	return k.client.Close()
+}
+
+func (k *kafkaClient) partitionOffsetManager(partition int32) (sarama.PartitionOffsetManager, error) {
+	k.pomsMtx.Lock()
+	defer k.pomsMtx.Unlock()
+
+	if pom, ok := k.poms[partition]; ok {
+		return pom, nil
+	}
+
+	pom, err := k.offsets.ManagePartition(k.topic, partition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to manage offsets for partition %d: %w", partition, err)
+	}
+	k.poms[partition] = pom
+	return pom, nil
+}
+
+type kafkaPartitionConsumer struct {
+	pc      sarama.PartitionConsumer
+	records chan *Record
+	errors  chan error
+	wg      sync.WaitGroup
+	logger  log.Logger
+}
+
+func (c *kafkaPartitionConsumer) loop() {
+	defer c.wg.Done()
+	defer close(c.records)
+
+	msgs := c.pc.Messages()
+	errs := c.pc.Errors()
+	for msgs != nil || errs != nil {
+		select {
+		case msg, ok := <-msgs:
+			if !ok {
+				msgs = nil
+				continue
+			}
+
+			var tenantID string
+			for _, h := range msg.Headers {
+				if string(h.Key) == tenantHeader {
+					tenantID = string(h.Value)
+				}
+			}
+
+			r, err := decodeRecord(tenantID, msg.Offset, msg.Value)
+			if err != nil {
+				c.sendError(err)
+				continue
+			}
+			c.records <- r
+
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			c.sendError(err)
+		}
+	}
+}
+
+func (c *kafkaPartitionConsumer) sendError(err error) {
+	select {
+	case c.errors <- err:
+	default:
+		level.Error(c.logger).Log("msg", "dropping ingest consumer error", "err", err)
+	}
+}
+
+func (c *kafkaPartitionConsumer) Records() <-chan *Record {
+	return c.records
+}
+
+func (c *kafkaPartitionConsumer) Errors() <-chan error {
+	return c.errors
+}
+
+func (c *kafkaPartitionConsumer) Close() error {
+	// AsyncClose drains and closes the message channels which in turn ends the loop. The loop might be
+	//  blocked sending a record so drain those as well.
+	c.pc.AsyncClose()
+	go func() {
+		for range c.records {
+		}
+	}()
+	c.wg.Wait()
+	return nil
+}
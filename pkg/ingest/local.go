@@ -0,0 +1,199 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type localMessage struct {
+	tenantID string
+	value    []byte
+}
+
+type localPartition struct {
+	messages  []localMessage
+	committed int64
+}
+
+// LocalBroker is an in-process stand-in for Kafka. Records are kept in memory and encoded the
+// same way they are on the wire. It is intended for tests and local development only.
+type LocalBroker struct {
+	mtx        sync.Mutex
+	partitions map[int32]*localPartition
+	notify     chan struct{}
+	closed     bool
+}
+
+var _ Client = (*LocalBroker)(nil)
+
+// NewLocalBroker creates an empty LocalBroker.
+func NewLocalBroker() *LocalBroker {
+	return &LocalBroker{
+		partitions: map[int32]*localPartition{},
+		notify:     make(chan struct{}),
+	}
+}
+
+// Produce implements Client
+func (b *LocalBroker) Produce(_ context.Context, partition int32, records []*Record) error {
+	msgs := make([]localMessage, 0, len(records))
+	for _, r := range records {
+		value, err := encodeRecord(r)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, localMessage{tenantID: r.TenantID, value: value})
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if b.closed {
+		return errClosed
+	}
+
+	p := b.partition(partition)
+	p.messages = append(p.messages, msgs...)
+
+	// wake up all waiting consumers
+	close(b.notify)
+	b.notify = make(chan struct{})
+
+	return nil
+}
+
+// Consume implements Client
+func (b *LocalBroker) Consume(partition int32, offset int64) (PartitionConsumer, error) {
+	if offset < 0 {
+		offset = 0
+	}
+
+	c := &localConsumer{
+		broker:    b,
+		partition: partition,
+		records:   make(chan *Record),
+		errors:    make(chan error, 1),
+		done:      make(chan struct{}),
+	}
+	c.wg.Add(1)
+	go c.loop(offset)
+
+	return c, nil
+}
+
+// CommittedOffset implements Client
+func (b *LocalBroker) CommittedOffset(partition int32) (int64, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.partition(partition).committed, nil
+}
+
+// Commit implements Client
+func (b *LocalBroker) Commit(partition int32, offset int64) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.partition(partition).committed = offset
+	return nil
+}
+
+// Close implements Client
+func (b *LocalBroker) Close() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.closed = true
+	return nil
+}
+
+// Len returns the number of records written to a partition.
+func (b *LocalBroker) Len(partition int32) int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return len(b.partition(partition).messages)
+}
+
+// partition must be called under lock
+func (b *LocalBroker) partition(partition int32) *localPartition {
+	p, ok := b.partitions[partition]
+	if !ok {
+		p = &localPartition{committed: OffsetNone}
+		b.partitions[partition] = p
+	}
+	return p
+}
+
+// next returns the message at offset or a channel that is closed when new messages arrive.
+func (b *LocalBroker) next(partition int32, offset int64) (*localMessage, <-chan struct{}) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	p := b.partition(partition)
+	if offset < int64(len(p.messages)) {
+		return &p.messages[offset], nil
+	}
+	return nil, b.notify
+}
+
+type localConsumer struct {
+	broker    *LocalBroker
+	partition int32
+	records   chan *Record
+	errors    chan error
+	done      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+func (c *localConsumer) loop(offset int64) {
+	defer c.wg.Done()
+	defer close(c.records)
+
+	for {
+		msg, wait := c.broker.next(c.partition, offset)
+		if msg == nil {
+			select {
+			case <-wait:
+				continue
+			case <-c.done:
+				return
+			}
+		}
+
+		r, err := decodeRecord(msg.tenantID, offset, msg.value)
+		if err != nil {
+			select {
+			case c.errors <- fmt.Errorf("partition %d: %w", c.partition, err):
+			default:
+			}
+			offset++
+			continue
+		}
+
+		select {
+		case c.records <- r:
+			offset++
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func (c *localConsumer) Records() <-chan *Record {
+	return c.records
+}
+
+func (c *localConsumer) Errors() <-chan error {
+	return c.errors
+}
+
+func (c *localConsumer) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+	c.wg.Wait()
+	return nil
+}
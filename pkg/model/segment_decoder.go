@@ -29,6 +29,8 @@ type SegmentDecoder interface {
 	// FastRange returns the start and end unix epoch timestamp of the provided segment. If its not possible to efficiently get these
 	// values from the underlying encoding then it should return decoder.ErrUnsupported
 	FastRange(segment []byte) (uint32, uint32, error)
+	// FastSpanCount returns the number of spans of the provided segment without unmarshalling the trace.
+	FastSpanCount(segment []byte) (int, error)
 }
 
 // NewSegmentDecoder returns a Decoder given the passed string.
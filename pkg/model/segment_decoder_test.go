@@ -76,6 +76,31 @@ func TestSegmentDecoderToObjectDecoderRange(t *testing.T) {
 	}
 }
 
+func TestSegmentDecoderFastSpanCount(t *testing.T) {
+	for _, e := range AllEncodings {
+		t.Run(e, func(t *testing.T) {
+			segmentDecoder, err := NewSegmentDecoder(e)
+			require.NoError(t, err)
+
+			// random trace
+			trace := test.MakeTrace(100, nil)
+			expected := 0
+			for _, b := range trace.Batches {
+				for _, ils := range b.InstrumentationLibrarySpans {
+					expected += len(ils.Spans)
+				}
+			}
+
+			segment, err := segmentDecoder.PrepareForWrite(trace, 10, 20)
+			require.NoError(t, err)
+
+			actual, err := segmentDecoder.FastSpanCount(segment)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		})
+	}
+}
+
 func TestSegmentDecoderFastRange(t *testing.T) {
 	for _, e := range AllEncodings {
 		t.Run(e, func(t *testing.T) {
@@ -0,0 +1,277 @@
+package trace
+
+import (
+	"encoding/hex"
+	"math"
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	// ComparisonMatched marks a span found in both traces.
+	ComparisonMatched = "matched"
+	// ComparisonMissing marks a span of trace a not found in trace b.
+	ComparisonMissing = "missing"
+	// ComparisonExtra marks a span of trace b not found in trace a.
+	ComparisonExtra = "extra"
+)
+
+// Comparison is the difference between two traces. Durations are in nanoseconds and deltas are b - a.
+type Comparison struct {
+	DurationNanosA     uint64            `json:"durationNanosA"`
+	DurationNanosB     uint64            `json:"durationNanosB"`
+	DurationDeltaNanos int64             `json:"durationDeltaNanos"`
+	Matched            int               `json:"matched"`
+	Missing            int               `json:"missing"`
+	Extra              int               `json:"extra"`
+	Roots              []*ComparisonNode `json:"roots"`
+}
+
+// ComparisonNode is a span of the aligned span trees of two traces.
+type ComparisonNode struct {
+	Service            string            `json:"service"`
+	Operation          string            `json:"operation"`
+	Status             string            `json:"status"`
+	SpanIDA            string            `json:"spanIDA,omitempty"`
+	SpanIDB            string            `json:"spanIDB,omitempty"`
+	DurationNanosA     uint64            `json:"durationNanosA,omitempty"`
+	DurationNanosB     uint64            `json:"durationNanosB,omitempty"`
+	DurationDeltaNanos int64             `json:"durationDeltaNanos"`
+	Attributes         []AttributeDiff   `json:"attributes,omitempty"`
+	Children           []*ComparisonNode `json:"children,omitempty"`
+}
+
+// AttributeDiff is a span attribute whose value differs between two matched spans. A or B is empty if the
+// attribute is only set on one of them.
+type AttributeDiff struct {
+	Key string `json:"key"`
+	A   string `json:"a,omitempty"`
+	B   string `json:"b,omitempty"`
+}
+
+type spanNode struct {
+	span     *v1.Span
+	service  string
+	children []*spanNode
+}
+
+func (n *spanNode) key() string {
+	return n.service + "/" + n.span.Name
+}
+
+// Compare aligns the spans of two traces and returns their differences. Spans are aligned by their
+// service and operation name within the same parent, siblings with the same name are paired in start
+// time order.
+func Compare(a, b *tempopb.Trace) *Comparison {
+	c := &Comparison{
+		DurationNanosA: traceDuration(a),
+		DurationNanosB: traceDuration(b),
+	}
+	c.DurationDeltaNanos = int64(c.DurationNanosB) - int64(c.DurationNanosA)
+	c.Roots = c.compareNodes(spanTree(a), spanTree(b))
+
+	return c
+}
+
+func (c *Comparison) compareNodes(a, b []*spanNode) []*ComparisonNode {
+	// queue the nodes of b by key, they are consumed in start time order
+	byKey := map[string][]*spanNode{}
+	for _, n := range b {
+		byKey[n.key()] = append(byKey[n.key()], n)
+	}
+
+	matched := map[*spanNode]bool{}
+	nodes := make([]*ComparisonNode, 0, len(a))
+	for _, na := range a {
+		candidates := byKey[na.key()]
+		if len(candidates) == 0 {
+			nodes = append(nodes, c.unmatchedNode(na, ComparisonMissing))
+			continue
+		}
+
+		nb := candidates[0]
+		byKey[na.key()] = candidates[1:]
+		matched[nb] = true
+		nodes = append(nodes, c.matchedNode(na, nb))
+	}
+
+	for _, nb := range b {
+		if !matched[nb] {
+			nodes = append(nodes, c.unmatchedNode(nb, ComparisonExtra))
+		}
+	}
+
+	return nodes
+}
+
+func (c *Comparison) matchedNode(a, b *spanNode) *ComparisonNode {
+	c.Matched++
+
+	durationA := spanDuration(a.span)
+	durationB := spanDuration(b.span)
+	return &ComparisonNode{
+		Service:            a.service,
+		Operation:          a.span.Name,
+		Status:             ComparisonMatched,
+		SpanIDA:            hex.EncodeToString(a.span.SpanId),
+		SpanIDB:            hex.EncodeToString(b.span.SpanId),
+		DurationNanosA:     durationA,
+		DurationNanosB:     durationB,
+		DurationDeltaNanos: int64(durationB) - int64(durationA),
+		Attributes:         compareAttributes(a.span.Attributes, b.span.Attributes),
+		Children:           c.compareNodes(a.children, b.children),
+	}
+}
+
+// unmatchedNode returns the node of a span found in a single trace. Its children are unmatched as well.
+func (c *Comparison) unmatchedNode(n *spanNode, status string) *ComparisonNode {
+	node := &ComparisonNode{
+		Service:   n.service,
+		Operation: n.span.Name,
+		Status:    status,
+	}
+
+	duration := spanDuration(n.span)
+	if status == ComparisonMissing {
+		c.Missing++
+		node.SpanIDA = hex.EncodeToString(n.span.SpanId)
+		node.DurationNanosA = duration
+		node.DurationDeltaNanos = -int64(duration)
+	} else {
+		c.Extra++
+		node.SpanIDB = hex.EncodeToString(n.span.SpanId)
+		node.DurationNanosB = duration
+		node.DurationDeltaNanos = int64(duration)
+	}
+
+	for _, child := range n.children {
+		node.Children = append(node.Children, c.unmatchedNode(child, status))
+	}
+
+	return node
+}
+
+// compareAttributes returns the attributes whose values differ, sorted by key.
+func compareAttributes(a, b []*v1common.KeyValue) []AttributeDiff {
+	valuesA := attributeValues(a)
+	valuesB := attributeValues(b)
+
+	var diffs []AttributeDiff
+	for k, va := range valuesA {
+		if vb, ok := valuesB[k]; !ok || va != vb {
+			diffs = append(diffs, AttributeDiff{Key: k, A: va, B: vb})
+		}
+	}
+	for k, vb := range valuesB {
+		if _, ok := valuesA[k]; !ok {
+			diffs = append(diffs, AttributeDiff{Key: k, B: vb})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Key < diffs[j].Key
+	})
+	return diffs
+}
+
+func attributeValues(attrs []*v1common.KeyValue) map[string]string {
+	values := make(map[string]string, len(attrs))
+	for _, kv := range attrs {
+		if kv.Value == nil {
+			values[kv.Key] = ""
+			continue
+		}
+		values[kv.Key] = util.StringifyAnyValue(kv.Value)
+	}
+	return values
+}
+
+// spanTree builds the span tree of a trace. Spans whose parent is not in the trace are roots. Siblings
+// are sorted by start time like SortTrace does.
+func spanTree(t *tempopb.Trace) []*spanNode {
+	if t == nil {
+		return nil
+	}
+
+	var nodes []*spanNode
+	bySpanID := map[string]*spanNode{}
+	for _, b := range t.Batches {
+		service := ""
+		if b.Resource != nil {
+			for _, a := range b.Resource.Attributes {
+				if a.Key == ServiceNameTag {
+					service = a.Value.GetStringValue()
+					break
+				}
+			}
+		}
+
+		for _, ils := range b.InstrumentationLibrarySpans {
+			for _, s := range ils.Spans {
+				n := &spanNode{span: s, service: service}
+				nodes = append(nodes, n)
+				bySpanID[string(s.SpanId)] = n
+			}
+		}
+	}
+
+	var roots []*spanNode
+	for _, n := range nodes {
+		parent, ok := bySpanID[string(n.span.ParentSpanId)]
+		if len(n.span.ParentSpanId) == 0 || !ok || parent == n {
+			roots = append(roots, n)
+			continue
+		}
+		parent.children = append(parent.children, n)
+	}
+
+	for _, n := range nodes {
+		sortSpanNodes(n.children)
+	}
+	sortSpanNodes(roots)
+
+	return roots
+}
+
+func sortSpanNodes(nodes []*spanNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return compareSpans(nodes[i].span, nodes[j].span)
+	})
+}
+
+func spanDuration(s *v1.Span) uint64 {
+	if s.EndTimeUnixNano < s.StartTimeUnixNano {
+		return 0
+	}
+	return s.EndTimeUnixNano - s.StartTimeUnixNano
+}
+
+func traceDuration(t *tempopb.Trace) uint64 {
+	if t == nil {
+		return 0
+	}
+
+	start := uint64(math.MaxUint64)
+	end := uint64(0)
+	for _, b := range t.Batches {
+		for _, ils := range b.InstrumentationLibrarySpans {
+			for _, s := range ils.Spans {
+				if s.StartTimeUnixNano < start {
+					start = s.StartTimeUnixNano
+				}
+				if s.EndTimeUnixNano > end {
+					end = s.EndTimeUnixNano
+				}
+			}
+		}
+	}
+
+	if end < start {
+		return 0
+	}
+	return end - start
+}
@@ -0,0 +1,128 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	a := compareTestTrace(
+		compareTestBatch("frontend",
+			&v1.Span{SpanId: []byte{1}, Name: "GET /", StartTimeUnixNano: 0, EndTimeUnixNano: 100},
+		),
+		compareTestBatch("backend",
+			&v1.Span{SpanId: []byte{2}, ParentSpanId: []byte{1}, Name: "query", StartTimeUnixNano: 10, EndTimeUnixNano: 30,
+				Attributes: []*v1common.KeyValue{stringAttr("db", "users"), stringAttr("rows", "1")}},
+			&v1.Span{SpanId: []byte{3}, ParentSpanId: []byte{1}, Name: "query", StartTimeUnixNano: 40, EndTimeUnixNano: 50},
+			&v1.Span{SpanId: []byte{4}, ParentSpanId: []byte{1}, Name: "cache", StartTimeUnixNano: 50, EndTimeUnixNano: 60},
+		),
+	)
+	b := compareTestTrace(
+		compareTestBatch("backend",
+			&v1.Span{SpanId: []byte{12}, ParentSpanId: []byte{11}, Name: "query", StartTimeUnixNano: 10, EndTimeUnixNano: 80,
+				Attributes: []*v1common.KeyValue{stringAttr("db", "users"), stringAttr("rows", "1000"), stringAttr("retry", "true")}},
+			&v1.Span{SpanId: []byte{13}, ParentSpanId: []byte{11}, Name: "query", StartTimeUnixNano: 90, EndTimeUnixNano: 100},
+			&v1.Span{SpanId: []byte{14}, ParentSpanId: []byte{13}, Name: "lock", StartTimeUnixNano: 90, EndTimeUnixNano: 95},
+		),
+		compareTestBatch("frontend",
+			&v1.Span{SpanId: []byte{11}, Name: "GET /", StartTimeUnixNano: 0, EndTimeUnixNano: 150},
+		),
+	)
+
+	c := Compare(a, b)
+
+	assert.Equal(t, uint64(100), c.DurationNanosA)
+	assert.Equal(t, uint64(150), c.DurationNanosB)
+	assert.Equal(t, int64(50), c.DurationDeltaNanos)
+	assert.Equal(t, 3, c.Matched)
+	assert.Equal(t, 1, c.Missing)
+	assert.Equal(t, 1, c.Extra)
+
+	require.Len(t, c.Roots, 1)
+	root := c.Roots[0]
+	assert.Equal(t, ComparisonMatched, root.Status)
+	assert.Equal(t, "frontend", root.Service)
+	assert.Equal(t, "GET /", root.Operation)
+	assert.Equal(t, "01", root.SpanIDA)
+	assert.Equal(t, "0b", root.SpanIDB)
+	assert.Equal(t, int64(50), root.DurationDeltaNanos)
+	assert.Empty(t, root.Attributes)
+
+	require.Len(t, root.Children, 3)
+
+	// siblings with the same name are paired in start time order
+	first := root.Children[0]
+	assert.Equal(t, ComparisonMatched, first.Status)
+	assert.Equal(t, "02", first.SpanIDA)
+	assert.Equal(t, "0c", first.SpanIDB)
+	assert.Equal(t, int64(50), first.DurationDeltaNanos)
+	assert.Equal(t, []AttributeDiff{
+		{Key: "retry", B: "true"},
+		{Key: "rows", A: "1", B: "1000"},
+	}, first.Attributes)
+
+	second := root.Children[1]
+	assert.Equal(t, ComparisonMatched, second.Status)
+	assert.Equal(t, "03", second.SpanIDA)
+	assert.Equal(t, "0d", second.SpanIDB)
+	assert.Equal(t, int64(0), second.DurationDeltaNanos)
+	require.Len(t, second.Children, 1)
+	assert.Equal(t, &ComparisonNode{
+		Service:            "backend",
+		Operation:          "lock",
+		Status:             ComparisonExtra,
+		SpanIDB:            "0e",
+		DurationNanosB:     5,
+		DurationDeltaNanos: 5,
+	}, second.Children[0])
+
+	assert.Equal(t, &ComparisonNode{
+		Service:            "backend",
+		Operation:          "cache",
+		Status:             ComparisonMissing,
+		SpanIDA:            "04",
+		DurationNanosA:     10,
+		DurationDeltaNanos: -10,
+	}, root.Children[2])
+}
+
+func TestCompareEmpty(t *testing.T) {
+	c := Compare(nil, &tempopb.Trace{})
+	assert.Equal(t, &Comparison{Roots: []*ComparisonNode{}}, c)
+
+	b := compareTestTrace(compareTestBatch("frontend",
+		&v1.Span{SpanId: []byte{1}, Name: "GET /", StartTimeUnixNano: 0, EndTimeUnixNano: 100},
+		&v1.Span{SpanId: []byte{2}, ParentSpanId: []byte{1}, Name: "query", StartTimeUnixNano: 10, EndTimeUnixNano: 20},
+	))
+	c = Compare(nil, b)
+	assert.Equal(t, 2, c.Extra)
+	require.Len(t, c.Roots, 1)
+	assert.Equal(t, ComparisonExtra, c.Roots[0].Status)
+	require.Len(t, c.Roots[0].Children, 1)
+	assert.Equal(t, ComparisonExtra, c.Roots[0].Children[0].Status)
+}
+
+func compareTestTrace(batches ...*v1.ResourceSpans) *tempopb.Trace {
+	return &tempopb.Trace{Batches: batches}
+}
+
+func compareTestBatch(service string, spans ...*v1.Span) *v1.ResourceSpans {
+	return &v1.ResourceSpans{
+		Resource: &v1resource.Resource{
+			Attributes: []*v1common.KeyValue{stringAttr(ServiceNameTag, service)},
+		},
+		InstrumentationLibrarySpans: []*v1.InstrumentationLibrarySpans{
+			{Spans: spans},
+		},
+	}
+}
+
+func stringAttr(k, v string) *v1common.KeyValue {
+	return &v1common.KeyValue{Key: k, Value: &v1common.AnyValue{Value: &v1common.AnyValue_StringValue{StringValue: v}}}
+}
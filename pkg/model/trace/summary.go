@@ -0,0 +1,385 @@
+package trace
+
+import (
+	"encoding/hex"
+	"sort"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+// maxSummaryErrorSpans is the maximum of error spans listed in a Summary, all of them are counted.
+const maxSummaryErrorSpans = 100
+
+// Summary describes the structure of a trace without returning its spans. Durations are in nanoseconds.
+type Summary struct {
+	SpanCount     int                `json:"spanCount"`
+	DurationNanos uint64             `json:"durationNanos"`
+	Depth         int                `json:"depth"`
+	ErrorCount    int                `json:"errorCount"`
+	ErrorSpans    []SummarySpan      `json:"errorSpans"`
+	CriticalPath  []SummarySpan      `json:"criticalPath"`
+	Services      []ServiceSummary   `json:"services"`
+	Operations    []OperationSummary `json:"operations"`
+}
+
+// SummarySpan is a span referenced by a Summary. On the critical path DurationNanos is the time the span
+// spent on the path, otherwise it's the duration of the span.
+type SummarySpan struct {
+	SpanID        string `json:"spanID"`
+	Service       string `json:"service"`
+	Operation     string `json:"operation"`
+	DurationNanos uint64 `json:"durationNanos"`
+}
+
+// ServiceSummary aggregates the spans of a service. Self time is the time spans spent outside of their
+// children.
+type ServiceSummary struct {
+	Service        string `json:"service"`
+	SpanCount      int    `json:"spanCount"`
+	ErrorCount     int    `json:"errorCount"`
+	SelfTimeNanos  uint64 `json:"selfTimeNanos"`
+	TotalTimeNanos uint64 `json:"totalTimeNanos"`
+}
+
+// OperationSummary aggregates the spans of an operation of a service.
+type OperationSummary struct {
+	Service        string `json:"service"`
+	Operation      string `json:"operation"`
+	SpanCount      int    `json:"spanCount"`
+	ErrorCount     int    `json:"errorCount"`
+	SelfTimeNanos  uint64 `json:"selfTimeNanos"`
+	TotalTimeNanos uint64 `json:"totalTimeNanos"`
+}
+
+// Summarize computes the summary of a trace: its critical path, the span counts and self time per service
+// and operation, its error spans and the depth of its span tree.
+func Summarize(t *tempopb.Trace) *Summary {
+	s := &Summary{
+		DurationNanos: traceDuration(t),
+		ErrorSpans:    []SummarySpan{},
+		CriticalPath:  []SummarySpan{},
+		Services:      []ServiceSummary{},
+		Operations:    []OperationSummary{},
+	}
+
+	roots := spanTree(t)
+	services := map[string]*ServiceSummary{}
+	operations := map[string]*OperationSummary{}
+
+	// walk the tree iteratively, very large traces can be very deep
+	type entry struct {
+		node  *spanNode
+		depth int
+	}
+	stack := make([]entry, 0, len(roots))
+	for i := len(roots) - 1; i >= 0; i-- {
+		stack = append(stack, entry{node: roots[i], depth: 1})
+	}
+	for len(stack) > 0 {
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := e.node
+
+		s.SpanCount++
+		if e.depth > s.Depth {
+			s.Depth = e.depth
+		}
+
+		isError := n.span.Status != nil && n.span.Status.Code == v1.Status_STATUS_CODE_ERROR
+		if isError {
+			s.ErrorCount++
+			if len(s.ErrorSpans) < maxSummaryErrorSpans {
+				s.ErrorSpans = append(s.ErrorSpans, summarySpan(n, spanDuration(n.span)))
+			}
+		}
+
+		duration := spanDuration(n.span)
+		self := selfTime(n)
+
+		svc, ok := services[n.service]
+		if !ok {
+			svc = &ServiceSummary{Service: n.service}
+			services[n.service] = svc
+		}
+		op, ok := operations[n.key()]
+		if !ok {
+			op = &OperationSummary{Service: n.service, Operation: n.span.Name}
+			operations[n.key()] = op
+		}
+		svc.SpanCount++
+		svc.SelfTimeNanos += self
+		svc.TotalTimeNanos += duration
+		op.SpanCount++
+		op.SelfTimeNanos += self
+		op.TotalTimeNanos += duration
+		if isError {
+			svc.ErrorCount++
+			op.ErrorCount++
+		}
+
+		for i := len(n.children) - 1; i >= 0; i-- {
+			stack = append(stack, entry{node: n.children[i], depth: e.depth + 1})
+		}
+	}
+
+	for _, svc := range services {
+		s.Services = append(s.Services, *svc)
+	}
+	sort.Slice(s.Services, func(i, j int) bool {
+		if s.Services[i].SelfTimeNanos == s.Services[j].SelfTimeNanos {
+			return s.Services[i].Service < s.Services[j].Service
+		}
+		return s.Services[i].SelfTimeNanos > s.Services[j].SelfTimeNanos
+	})
+
+	for _, op := range operations {
+		s.Operations = append(s.Operations, *op)
+	}
+	sort.Slice(s.Operations, func(i, j int) bool {
+		if s.Operations[i].SelfTimeNanos == s.Operations[j].SelfTimeNanos {
+			if s.Operations[i].Service == s.Operations[j].Service {
+				return s.Operations[i].Operation < s.Operations[j].Operation
+			}
+			return s.Operations[i].Service < s.Operations[j].Service
+		}
+		return s.Operations[i].SelfTimeNanos > s.Operations[j].SelfTimeNanos
+	})
+
+	// the critical path starts at the root ending last
+	var root *spanNode
+	for _, r := range roots {
+		if root == nil || r.span.EndTimeUnixNano > root.span.EndTimeUnixNano {
+			root = r
+		}
+	}
+	if root != nil {
+		s.CriticalPath = criticalPath(root)
+	}
+
+	return s
+}
+
+// criticalPath returns the spans on the critical path below root in chronological order. Walking back from
+// the end of a span, the time is attributed to the child ending last before the cursor, or to the span
+// itself if no child is running.
+func criticalPath(root *spanNode) []SummarySpan {
+	var path []SummarySpan
+	appendSegment := func(n *spanNode, d uint64) {
+		if d == 0 {
+			return
+		}
+		// merge consecutive segments of the same span
+		if len(path) > 0 && path[len(path)-1].SpanID == hex.EncodeToString(n.span.SpanId) {
+			path[len(path)-1].DurationNanos += d
+			return
+		}
+		path = append(path, summarySpan(n, d))
+	}
+
+	// walk the tree iteratively, a frame is a span being walked back from cursor to its start
+	type frame struct {
+		node          *spanNode
+		start, cursor uint64
+		children      []*spanNode
+		next          int
+	}
+	newFrame := func(n *spanNode, end uint64) frame {
+		cursor := n.span.EndTimeUnixNano
+		if end < cursor {
+			cursor = end
+		}
+
+		children := make([]*spanNode, len(n.children))
+		copy(children, n.children)
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].span.EndTimeUnixNano > children[j].span.EndTimeUnixNano
+		})
+
+		return frame{node: n, start: n.span.StartTimeUnixNano, cursor: cursor, children: children}
+	}
+
+	stack := []frame{newFrame(root, root.span.EndTimeUnixNano)}
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+
+		if f.next == len(f.children) {
+			if f.cursor > f.start {
+				appendSegment(f.node, f.cursor-f.start)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		c := f.children[f.next]
+		f.next++
+		if c.span.StartTimeUnixNano >= f.cursor || f.cursor <= f.start {
+			continue
+		}
+
+		childEnd := c.span.EndTimeUnixNano
+		if childEnd > f.cursor {
+			childEnd = f.cursor
+		}
+		if f.cursor > childEnd {
+			appendSegment(f.node, f.cursor-childEnd)
+		}
+
+		// the span continues where the child started once the child is walked
+		f.cursor = c.span.StartTimeUnixNano
+		if f.cursor < f.start {
+			f.cursor = f.start
+		}
+		stack = append(stack, newFrame(c, childEnd))
+	}
+
+	// the path was built walking back in time
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// selfTime returns the time a span spent outside of its children.
+func selfTime(n *spanNode) uint64 {
+	start, end := n.span.StartTimeUnixNano, n.span.EndTimeUnixNano
+	if end <= start {
+		return 0
+	}
+
+	// children are sorted by start time, merge their intervals clipped to the span
+	var covered uint64
+	var curStart, curEnd uint64
+	for _, c := range n.children {
+		cStart, cEnd := c.span.StartTimeUnixNano, c.span.EndTimeUnixNano
+		if cStart < start {
+			cStart = start
+		}
+		if cEnd > end {
+			cEnd = end
+		}
+		if cEnd <= cStart {
+			continue
+		}
+
+		if cStart > curEnd {
+			covered += curEnd - curStart
+			curStart, curEnd = cStart, cEnd
+			continue
+		}
+		if cEnd > curEnd {
+			curEnd = cEnd
+		}
+	}
+	covered += curEnd - curStart
+
+	return end - start - covered
+}
+
+// Prune returns a copy of the trace holding the whole subtrees of up to n of its slowest spans, with at most
+// maxSpans spans. The ancestors of the subtrees are kept to connect them to the roots of the trace. Spans
+// are considered from the slowest, a span whose subtree doesn't fit in the spans left is skipped so its
+// slowest descendants can be kept instead.
+func Prune(t *tempopb.Trace, n int, maxSpans int) *tempopb.Trace {
+	if t == nil {
+		return nil
+	}
+
+	// walk the tree iteratively to find the parent and the subtree size of every span
+	var nodes []*spanNode
+	parents := map[*spanNode]*spanNode{}
+	stack := spanTree(t)
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		nodes = append(nodes, node)
+		for _, c := range node.children {
+			parents[c] = node
+			stack = append(stack, c)
+		}
+	}
+	sizes := make(map[*spanNode]int, len(nodes))
+	for i := len(nodes) - 1; i >= 0; i-- {
+		sizes[nodes[i]]++
+		if p, ok := parents[nodes[i]]; ok {
+			sizes[p] += sizes[nodes[i]]
+		}
+	}
+
+	candidates := make([]*spanNode, len(nodes))
+	copy(candidates, nodes)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return spanDuration(candidates[i].span) > spanDuration(candidates[j].span)
+	})
+
+	keep := map[*v1.Span]bool{}
+	kept, subtrees := 0, 0
+	for _, c := range candidates {
+		if subtrees == n {
+			break
+		}
+		// the span is either part of a kept subtree or an ancestor of one
+		if keep[c.span] {
+			continue
+		}
+
+		var ancestors []*spanNode
+		for p, ok := parents[c]; ok && !keep[p.span]; p, ok = parents[p] {
+			ancestors = append(ancestors, p)
+		}
+		if kept+len(ancestors)+sizes[c] > maxSpans {
+			continue
+		}
+
+		for _, a := range ancestors {
+			keep[a.span] = true
+		}
+		subtree := []*spanNode{c}
+		for len(subtree) > 0 {
+			node := subtree[len(subtree)-1]
+			subtree = subtree[:len(subtree)-1]
+
+			keep[node.span] = true
+			subtree = append(subtree, node.children...)
+		}
+		kept += len(ancestors) + sizes[c]
+		subtrees++
+	}
+
+	pruned := &tempopb.Trace{}
+	for _, b := range t.Batches {
+		var ilss []*v1.InstrumentationLibrarySpans
+		for _, ils := range b.InstrumentationLibrarySpans {
+			var keptSpans []*v1.Span
+			for _, s := range ils.Spans {
+				if keep[s] {
+					keptSpans = append(keptSpans, s)
+				}
+			}
+			if len(keptSpans) > 0 {
+				ilss = append(ilss, &v1.InstrumentationLibrarySpans{
+					InstrumentationLibrary: ils.InstrumentationLibrary,
+					Spans:                  keptSpans,
+				})
+			}
+		}
+		if len(ilss) > 0 {
+			pruned.Batches = append(pruned.Batches, &v1.ResourceSpans{
+				Resource:                    b.Resource,
+				InstrumentationLibrarySpans: ilss,
+			})
+		}
+	}
+
+	return pruned
+}
+
+func summarySpan(n *spanNode, d uint64) SummarySpan {
+	return SummarySpan{
+		SpanID:        hex.EncodeToString(n.span.SpanId),
+		Service:       n.service,
+		Operation:     n.span.Name,
+		DurationNanos: d,
+	}
+}
@@ -0,0 +1,119 @@
+package trace
+
+import (
+	"testing"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func summaryTestTrace() *tempopb.Trace {
+	return compareTestTrace(
+		compareTestBatch("frontend",
+			&v1.Span{SpanId: []byte{1}, Name: "GET /", StartTimeUnixNano: 0, EndTimeUnixNano: 100},
+		),
+		compareTestBatch("backend",
+			&v1.Span{SpanId: []byte{2}, ParentSpanId: []byte{1}, Name: "query", StartTimeUnixNano: 10, EndTimeUnixNano: 40,
+				Status: &v1.Status{Code: v1.Status_STATUS_CODE_ERROR}},
+			&v1.Span{SpanId: []byte{3}, ParentSpanId: []byte{1}, Name: "cache", StartTimeUnixNano: 30, EndTimeUnixNano: 90},
+		),
+		compareTestBatch("db",
+			&v1.Span{SpanId: []byte{4}, ParentSpanId: []byte{2}, Name: "select", StartTimeUnixNano: 15, EndTimeUnixNano: 35},
+		),
+	)
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize(summaryTestTrace())
+
+	assert.Equal(t, 4, s.SpanCount)
+	assert.Equal(t, uint64(100), s.DurationNanos)
+	assert.Equal(t, 3, s.Depth)
+	assert.Equal(t, 1, s.ErrorCount)
+	assert.Equal(t, []SummarySpan{
+		{SpanID: "02", Service: "backend", Operation: "query", DurationNanos: 30},
+	}, s.ErrorSpans)
+
+	// the path spends time in the root until the query starts, in the query until the select starts, in the
+	// select until the cache starts and in the root once the cache ends
+	assert.Equal(t, []SummarySpan{
+		{SpanID: "01", Service: "frontend", Operation: "GET /", DurationNanos: 10},
+		{SpanID: "02", Service: "backend", Operation: "query", DurationNanos: 5},
+		{SpanID: "04", Service: "db", Operation: "select", DurationNanos: 15},
+		{SpanID: "03", Service: "backend", Operation: "cache", DurationNanos: 60},
+		{SpanID: "01", Service: "frontend", Operation: "GET /", DurationNanos: 10},
+	}, s.CriticalPath)
+
+	assert.Equal(t, []ServiceSummary{
+		{Service: "backend", SpanCount: 2, ErrorCount: 1, SelfTimeNanos: 70, TotalTimeNanos: 90},
+		{Service: "db", SpanCount: 1, SelfTimeNanos: 20, TotalTimeNanos: 20},
+		{Service: "frontend", SpanCount: 1, SelfTimeNanos: 20, TotalTimeNanos: 100},
+	}, s.Services)
+
+	assert.Equal(t, []OperationSummary{
+		{Service: "backend", Operation: "cache", SpanCount: 1, SelfTimeNanos: 60, TotalTimeNanos: 60},
+		{Service: "db", Operation: "select", SpanCount: 1, SelfTimeNanos: 20, TotalTimeNanos: 20},
+		{Service: "frontend", Operation: "GET /", SpanCount: 1, SelfTimeNanos: 20, TotalTimeNanos: 100},
+		{Service: "backend", Operation: "query", SpanCount: 1, ErrorCount: 1, SelfTimeNanos: 10, TotalTimeNanos: 30},
+	}, s.Operations)
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	s := Summarize(&tempopb.Trace{})
+
+	assert.Equal(t, &Summary{
+		ErrorSpans:   []SummarySpan{},
+		CriticalPath: []SummarySpan{},
+		Services:     []ServiceSummary{},
+		Operations:   []OperationSummary{},
+	}, s)
+}
+
+func TestPrune(t *testing.T) {
+	tr := summaryTestTrace()
+
+	// the select outlives its parent and is the slowest span after the root
+	tr.Batches[2].InstrumentationLibrarySpans[0].Spans[0].EndTimeUnixNano = 95
+
+	// the root and the select don't fit with their ancestors and subtrees, the cache is kept with the root
+	pruned := Prune(tr, 1, 2)
+	require.Len(t, pruned.Batches, 2)
+	assert.Equal(t, []byte{1}, pruned.Batches[0].InstrumentationLibrarySpans[0].Spans[0].SpanId)
+	require.Len(t, pruned.Batches[1].InstrumentationLibrarySpans[0].Spans, 1)
+	assert.Equal(t, []byte{3}, pruned.Batches[1].InstrumentationLibrarySpans[0].Spans[0].SpanId)
+
+	// the select is kept with its ancestors, the cache doesn't fit anymore
+	pruned = Prune(tr, 2, 3)
+	require.Len(t, pruned.Batches, 3)
+	assert.Equal(t, []byte{1}, pruned.Batches[0].InstrumentationLibrarySpans[0].Spans[0].SpanId)
+	require.Len(t, pruned.Batches[1].InstrumentationLibrarySpans[0].Spans, 1)
+	assert.Equal(t, []byte{2}, pruned.Batches[1].InstrumentationLibrarySpans[0].Spans[0].SpanId)
+	assert.Equal(t, []byte{4}, pruned.Batches[2].InstrumentationLibrarySpans[0].Spans[0].SpanId)
+
+	// the original trace is left untouched
+	assert.Len(t, tr.Batches[1].InstrumentationLibrarySpans[0].Spans, 2)
+
+	// the subtree of the root is the whole trace
+	pruned = Prune(tr, 1, 10)
+	assert.Equal(t, tr, pruned)
+}
+
+func TestCriticalPathDeepTrace(t *testing.T) {
+	// a chain of spans deeper than a recursive walk could handle comfortably
+	depth := 100_000
+	spans := make([]*v1.Span, 0, depth)
+	for i := 0; i < depth; i++ {
+		s := &v1.Span{SpanId: []byte{byte(i >> 16), byte(i >> 8), byte(i)}, StartTimeUnixNano: uint64(i), EndTimeUnixNano: uint64(2*depth - i)}
+		if i > 0 {
+			s.ParentSpanId = spans[i-1].SpanId
+		}
+		spans = append(spans, s)
+	}
+
+	s := Summarize(compareTestTrace(compareTestBatch("svc", spans...)))
+	assert.Equal(t, depth, s.Depth)
+	// every span is on the path before and after its child, the innermost span once
+	assert.Len(t, s.CriticalPath, 2*depth-1)
+}
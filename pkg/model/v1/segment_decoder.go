@@ -53,3 +53,7 @@ func (d *SegmentDecoder) ToObject(segments [][]byte) ([]byte, error) {
 func (d *SegmentDecoder) FastRange([]byte) (uint32, uint32, error) {
 	return 0, 0, decoder.ErrUnsupported
 }
+
+func (d *SegmentDecoder) FastSpanCount(segment []byte) (int, error) {
+	return tempopb.SpanCount(segment)
+}
@@ -83,6 +83,14 @@ func (d *SegmentDecoder) FastRange(buff []byte) (uint32, uint32, error) {
 	return start, end, err
 }
 
+func (d *SegmentDecoder) FastSpanCount(buff []byte) (int, error) {
+	buff, _, _, err := stripStartEnd(buff)
+	if err != nil {
+		return 0, err
+	}
+	return tempopb.SpanCount(buff)
+}
+
 func marshalWithStartEnd(pb proto.Message, start uint32, end uint32) ([]byte, error) {
 	const uint32Size = 4
 
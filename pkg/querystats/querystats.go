@@ -0,0 +1,114 @@
+// Package querystats collects statistics about the work done to execute a query. A Collector is
+// carried in the context of the query and updated by the layers reading and decoding blocks.
+package querystats
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+type contextKey struct{}
+
+// Collector accumulates the statistics of a query. It's safe for concurrent use and all methods
+// are no-ops on a nil Collector, so callers don't need to check if the query is collecting stats.
+type Collector struct {
+	blocksFetched  atomic.Uint32
+	bytesRead      atomic.Uint64
+	cacheHits      atomic.Uint64
+	bloomTests     atomic.Uint64
+	pagesDecoded   atomic.Uint64
+	ioDuration     atomic.Duration
+	decodeDuration atomic.Duration
+}
+
+// NewContext returns a new Collector and a context carrying it.
+func NewContext(ctx context.Context) (*Collector, context.Context) {
+	c := &Collector{}
+	return c, context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext returns the Collector carried by ctx, or nil if there is none.
+func FromContext(ctx context.Context) *Collector {
+	c, _ := ctx.Value(contextKey{}).(*Collector)
+	return c
+}
+
+// AddBlocksFetched adds n blocks opened to execute the query.
+func (c *Collector) AddBlocksFetched(n uint32) {
+	if c == nil {
+		return
+	}
+	c.blocksFetched.Add(n)
+}
+
+// AddRead adds n bytes read from the backend in d.
+func (c *Collector) AddRead(n int, d time.Duration) {
+	if c == nil {
+		return
+	}
+	c.bytesRead.Add(uint64(n))
+	c.ioDuration.Add(d)
+}
+
+// AddCacheHit counts an object or range served by a cache instead of the backend.
+func (c *Collector) AddCacheHit() {
+	if c == nil {
+		return
+	}
+	c.cacheHits.Inc()
+}
+
+// AddBloomTest counts a bloom filter tested for a trace ID.
+func (c *Collector) AddBloomTest() {
+	if c == nil {
+		return
+	}
+	c.bloomTests.Inc()
+}
+
+// AddPagesDecoded adds n pages decoded in d.
+func (c *Collector) AddPagesDecoded(n int, d time.Duration) {
+	if c == nil {
+		return
+	}
+	c.pagesDecoded.Add(uint64(n))
+	c.decodeDuration.Add(d)
+}
+
+// Stats returns the statistics collected so far.
+func (c *Collector) Stats() *tempopb.QueryStats {
+	if c == nil {
+		return nil
+	}
+	return &tempopb.QueryStats{
+		BlocksFetched:       c.blocksFetched.Load(),
+		BytesRead:           c.bytesRead.Load(),
+		CacheHits:           c.cacheHits.Load(),
+		BloomTests:          c.bloomTests.Load(),
+		PagesDecoded:        c.pagesDecoded.Load(),
+		IoDurationNanos:     uint64(c.ioDuration.Load()),
+		DecodeDurationNanos: uint64(c.decodeDuration.Load()),
+	}
+}
+
+// Merge adds the statistics of src to dst. It returns dst, or a copy of src if dst is nil.
+func Merge(dst, src *tempopb.QueryStats) *tempopb.QueryStats {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = &tempopb.QueryStats{}
+	}
+	dst.BlocksFetched += src.BlocksFetched
+	dst.BytesRead += src.BytesRead
+	dst.CacheHits += src.CacheHits
+	dst.BloomTests += src.BloomTests
+	dst.PagesDecoded += src.PagesDecoded
+	dst.IoDurationNanos += src.IoDurationNanos
+	dst.DecodeDurationNanos += src.DecodeDurationNanos
+	return dst
+}
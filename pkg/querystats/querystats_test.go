@@ -0,0 +1,58 @@
+package querystats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestCollector(t *testing.T) {
+	c, ctx := NewContext(context.Background())
+	assert.Equal(t, c, FromContext(ctx))
+
+	c.AddBlocksFetched(2)
+	c.AddRead(100, time.Second)
+	c.AddRead(50, time.Second)
+	c.AddCacheHit()
+	c.AddBloomTest()
+	c.AddPagesDecoded(3, time.Millisecond)
+
+	assert.Equal(t, &tempopb.QueryStats{
+		BlocksFetched:       2,
+		BytesRead:           150,
+		CacheHits:           1,
+		BloomTests:          1,
+		PagesDecoded:        3,
+		IoDurationNanos:     uint64(2 * time.Second),
+		DecodeDurationNanos: uint64(time.Millisecond),
+	}, c.Stats())
+}
+
+func TestCollector_Nil(t *testing.T) {
+	c := FromContext(context.Background())
+	assert.Nil(t, c)
+
+	c.AddBlocksFetched(1)
+	c.AddRead(1, time.Second)
+	c.AddCacheHit()
+	c.AddBloomTest()
+	c.AddPagesDecoded(1, time.Second)
+	assert.Nil(t, c.Stats())
+}
+
+func TestMerge(t *testing.T) {
+	assert.Nil(t, Merge(nil, nil))
+
+	src := &tempopb.QueryStats{BlocksFetched: 1, BytesRead: 10, IoDurationNanos: 5}
+	dst := Merge(nil, src)
+	assert.Equal(t, src, dst)
+	assert.NotSame(t, src, dst)
+
+	dst = Merge(dst, src)
+	assert.Equal(t, &tempopb.QueryStats{BlocksFetched: 2, BytesRead: 20, IoDurationNanos: 10}, dst)
+	assert.Equal(t, uint32(1), src.BlocksFetched)
+}
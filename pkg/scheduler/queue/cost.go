@@ -0,0 +1,16 @@
+package queue
+
+import "context"
+
+type costContextKey struct{}
+
+// ContextWithCost returns a context carrying the estimated cost of the request it's attached to.
+func ContextWithCost(ctx context.Context, cost int64) context.Context {
+	return context.WithValue(ctx, costContextKey{}, cost)
+}
+
+// CostFromContext returns the estimated cost attached to ctx, or 0 if there is none.
+func CostFromContext(ctx context.Context) int64 {
+	cost, _ := ctx.Value(costContextKey{}).(int64)
+	return cost
+}
@@ -78,8 +78,13 @@ func NewRequestQueue(maxOutstandingPerTenant int, forgetDelay time.Duration, que
 // this user use (zero or negative = all queriers). It is passed to each EnqueueRequest, because it can change
 // between calls.
 //
+// Cost is the estimated cost of the request, users are served so they get the same share of cost. Requests with
+// a cost lower than 1 cost 1. MaxConcurrentCost is the user-specific limit of the cost of requests in progress
+// (zero or negative = no limit). Once a request is dequeued, FinishRequest must be called with the same cost when
+// it is done.
+//
 // If request is successfully enqueued, successFn is called with the lock held, before any querier can receive the request.
-func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers int, successFn func()) error {
+func (q *RequestQueue) EnqueueRequest(userID string, req Request, cost int64, maxQueriers int, maxConcurrentCost int64, successFn func()) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
@@ -87,14 +92,18 @@ func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers in
 		return ErrStopped
 	}
 
-	queue := q.queues.getOrAddQueue(userID, maxQueriers)
+	queue := q.queues.getOrAddQueue(userID, maxQueriers, maxConcurrentCost)
 	if queue == nil {
 		// This can only happen if userID is "".
 		return errors.New("no queue found")
 	}
 
+	if cost < 1 {
+		cost = 1
+	}
+
 	select {
-	case queue <- req:
+	case queue <- &queuedRequest{req: req, cost: cost}:
 		q.queueLength.WithLabelValues(userID).Inc()
 		q.cond.Broadcast()
 		// Call this function while holding a lock. This guarantees that no querier can fetch the request before function returns.
@@ -111,6 +120,7 @@ func (q *RequestQueue) EnqueueRequest(userID string, req Request, maxQueriers in
 // GetNextRequestForQuerier find next user queue and takes the next request off of it. Will block if there are no requests.
 // By passing user index from previous call of this method, querier guarantees that it iterates over all users fairly.
 // If querier finds that request from the user is already expired, it can get a request for the same user by using UserIndex.ReuseLastUser.
+// The returned request is in progress until FinishRequest is called.
 func (q *RequestQueue) GetNextRequestForQuerier(ctx context.Context, last UserIndex, querierID string) (Request, UserIndex, error) {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
@@ -142,6 +152,7 @@ FindQueue:
 		// Pick next request from the queue.
 		for {
 			request := <-queue
+			q.queues.startRequest(userID, request.cost)
 			if len(queue) == 0 {
 				q.queues.deleteQueue(userID)
 			}
@@ -151,7 +162,7 @@ FindQueue:
 			// Tell close() we've processed a request.
 			q.cond.Broadcast()
 
-			return request, last, nil
+			return request.req, last, nil
 		}
 	}
 
@@ -161,6 +172,21 @@ FindQueue:
 	goto FindQueue
 }
 
+// FinishRequest releases the cost of a request returned by GetNextRequestForQuerier, the cost must be
+// the one the request was enqueued with.
+func (q *RequestQueue) FinishRequest(userID string, cost int64) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	if cost < 1 {
+		cost = 1
+	}
+	q.queues.finishRequest(userID, cost)
+
+	// Users may be below their concurrent cost limit again.
+	q.cond.Broadcast()
+}
+
 func (q *RequestQueue) forgetDisconnectedQueriers(_ context.Context) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
@@ -0,0 +1,96 @@
+package queue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestQueue() *RequestQueue {
+	return NewRequestQueue(100, 0,
+		prometheus.NewGaugeVec(prometheus.GaugeOpts{}, []string{"user"}),
+		prometheus.NewCounterVec(prometheus.CounterOpts{}, []string{"user"}))
+}
+
+func TestQueue_CostFairness(t *testing.T) {
+	q := newTestQueue()
+	q.RegisterQuerierConnection("querier")
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, q.EnqueueRequest("expensive", "expensive", 10, 0, 0, nil))
+		require.NoError(t, q.EnqueueRequest("cheap", "cheap", 1, 0, 0, nil))
+	}
+
+	var actual []Request
+	last := FirstUser()
+	for i := 0; i < 6; i++ {
+		req, idx, err := q.GetNextRequestForQuerier(context.Background(), last, "querier")
+		require.NoError(t, err)
+		last = idx
+		actual = append(actual, req)
+	}
+
+	// cheap requests are served until their cost catches up with the first expensive one
+	assert.Equal(t, []Request{"expensive", "cheap", "cheap", "cheap", "expensive", "expensive"}, actual)
+}
+
+func TestQueue_MaxConcurrentCost(t *testing.T) {
+	q := newTestQueue()
+	q.RegisterQuerierConnection("querier")
+
+	require.NoError(t, q.EnqueueRequest("limited", "limited-1", 10, 0, 10, nil))
+	require.NoError(t, q.EnqueueRequest("limited", "limited-2", 10, 0, 10, nil))
+	require.NoError(t, q.EnqueueRequest("other", "other", 100, 0, 0, nil))
+
+	req, last, err := q.GetNextRequestForQuerier(context.Background(), FirstUser(), "querier")
+	require.NoError(t, err)
+	assert.Equal(t, "limited-1", req)
+
+	// limited is at its limit until the first request is finished
+	req, last, err = q.GetNextRequestForQuerier(context.Background(), last, "querier")
+	require.NoError(t, err)
+	assert.Equal(t, "other", req)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		q.FinishRequest("limited", 10)
+	}()
+
+	req, _, err = q.GetNextRequestForQuerier(context.Background(), last, "querier")
+	require.NoError(t, err)
+	assert.Equal(t, "limited-2", req)
+}
+
+func TestQueue_ShuffleSharding(t *testing.T) {
+	q := newTestQueue()
+	q.RegisterQuerierConnection("querier-1")
+	q.RegisterQuerierConnection("querier-2")
+
+	require.NoError(t, q.EnqueueRequest("user", "req", 1, 1, 0, nil))
+
+	// exactly one of the queriers handles the user
+	handled := 0
+	for _, querier := range []string{"querier-1", "querier-2"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		go func() {
+			<-ctx.Done()
+			q.QuerierDisconnecting()
+		}()
+
+		_, _, err := q.GetNextRequestForQuerier(ctx, FirstUser(), querier)
+		cancel()
+		if err == nil {
+			handled++
+		}
+	}
+	assert.Equal(t, 1, handled)
+}
+
+func TestCostFromContext(t *testing.T) {
+	assert.Equal(t, int64(0), CostFromContext(context.Background()))
+	assert.Equal(t, int64(5), CostFromContext(ContextWithCost(context.Background(), 5)))
+}
@@ -41,16 +41,38 @@ type queues struct {
 
 	// Sorted list of querier names, used when creating per-user shard.
 	sortedQueriers []string
+
+	// Cost of the requests handed to queriers and not finished yet, per user. Kept separately from
+	// userQueues as requests can still be in progress once the user queue is deleted.
+	inflightCost map[string]int64
+
+	// Virtual time of the last dequeued request, new user queues start from it so idle users can't
+	// accumulate credit.
+	virtualTime int64
+}
+
+type queuedRequest struct {
+	req  Request
+	cost int64
 }
 
 type userQueue struct {
-	ch chan Request
+	ch chan *queuedRequest
 
 	// If not nil, only these queriers can handle user requests. If nil, all queriers can.
 	// We set this to nil if number of available queriers <= maxQueriers.
 	queriers    map[string]struct{}
 	maxQueriers int
 
+	// Requests are not handed to queriers if the cost of the requests of the user in progress
+	// is at least maxConcurrentCost. Zero or negative means no limit.
+	maxConcurrentCost int64
+
+	// Total cost of the requests dequeued, starting from the virtual time the queue was created
+	// at. The user with the smallest virtual time is served next, so users get the same share of
+	// cost instead of the same share of requests.
+	virtualTime int64
+
 	// Seed for shuffle sharding of queriers. This seed is based on userID only and is therefore consistent
 	// between different frontends.
 	seed int64
@@ -67,6 +89,7 @@ func newUserQueues(maxUserQueueSize int, forgetDelay time.Duration) *queues {
 		forgetDelay:      forgetDelay,
 		queriers:         map[string]*querier{},
 		sortedQueriers:   nil,
+		inflightCost:     map[string]int64{},
 	}
 }
 
@@ -93,7 +116,8 @@ func (q *queues) deleteQueue(userID string) {
 // MaxQueriers is used to compute which queriers should handle requests for this user.
 // If maxQueriers is <= 0, all queriers can handle this user's requests.
 // If maxQueriers has changed since the last call, queriers for this are recomputed.
-func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
+// MaxConcurrentCost limits the cost of the requests of this user in progress, <= 0 means no limit.
+func (q *queues) getOrAddQueue(userID string, maxQueriers int, maxConcurrentCost int64) chan *queuedRequest {
 	// Empty user is not allowed, as that would break our users list ("" is used for free spot).
 	if userID == "" {
 		return nil
@@ -107,9 +131,10 @@ func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
 
 	if uq == nil {
 		uq = &userQueue{
-			ch:    make(chan Request, q.maxUserQueueSize),
-			seed:  shard.ShuffleShardSeed(userID, ""),
-			index: -1,
+			ch:          make(chan *queuedRequest, q.maxUserQueueSize),
+			seed:        shard.ShuffleShardSeed(userID, ""),
+			index:       -1,
+			virtualTime: q.virtualTime,
 		}
 		q.userQueues[userID] = uq
 
@@ -133,16 +158,25 @@ func (q *queues) getOrAddQueue(userID string, maxQueriers int) chan Request {
 		uq.maxQueriers = maxQueriers
 		uq.queriers = shuffleQueriersForUser(uq.seed, maxQueriers, q.sortedQueriers, nil)
 	}
+	uq.maxConcurrentCost = maxConcurrentCost
 
 	return uq.ch
 }
 
-// Finds next queue for the querier. To support fair scheduling between users, client is expected
-// to pass last user index returned by this function as argument. Is there was no previous
-// last user index, use -1.
-func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (chan Request, string, int) {
+// Finds next queue for the querier. The user with the smallest virtual time is picked, users with the
+// same virtual time are picked in a round-robin fashion. To support fair scheduling between users,
+// client is expected to pass last user index returned by this function as argument. Is there was no
+// previous last user index, use -1.
+// Users whose requests in progress reached their maximum concurrent cost are skipped.
+func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (chan *queuedRequest, string, int) {
 	uid := lastUserIndex
 
+	var (
+		next      *userQueue
+		nextUser  string
+		nextIndex int
+	)
+
 	for iters := 0; iters < len(q.users); iters++ {
 		uid = uid + 1
 
@@ -157,18 +191,45 @@ func (q *queues) getNextQueueForQuerier(lastUserIndex int, querierID string) (ch
 			continue
 		}
 
-		q := q.userQueues[u]
+		uq := q.userQueues[u]
 
-		if q.queriers != nil {
-			if _, ok := q.queriers[querierID]; !ok {
+		if uq.queriers != nil {
+			if _, ok := uq.queriers[querierID]; !ok {
 				// This querier is not handling the user.
 				continue
 			}
 		}
 
-		return q.ch, u, uid
+		if uq.maxConcurrentCost > 0 && q.inflightCost[u] >= uq.maxConcurrentCost {
+			continue
+		}
+
+		if next == nil || uq.virtualTime < next.virtualTime {
+			next, nextUser, nextIndex = uq, u, uid
+		}
+	}
+
+	if next == nil {
+		return nil, "", uid
+	}
+	return next.ch, nextUser, nextIndex
+}
+
+// startRequest charges the cost of a request dequeued for the user.
+func (q *queues) startRequest(userID string, cost int64) {
+	if uq := q.userQueues[userID]; uq != nil {
+		q.virtualTime = uq.virtualTime
+		uq.virtualTime += cost
+	}
+	q.inflightCost[userID] += cost
+}
+
+// finishRequest releases the cost of a request of the user once it's done.
+func (q *queues) finishRequest(userID string, cost int64) {
+	q.inflightCost[userID] -= cost
+	if q.inflightCost[userID] <= 0 {
+		delete(q.inflightCost, userID)
 	}
-	return nil, "", uid
 }
 
 func (q *queues) addQuerierConnection(querierID string) {
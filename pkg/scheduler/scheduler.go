@@ -143,6 +143,9 @@ func NewScheduler(cfg Config, limits Limits, log log.Logger, registerer promethe
 type Limits interface {
 	// MaxQueriersPerUser returns max queriers to use per tenant, or 0 if shuffle sharding is disabled.
 	MaxQueriersPerUser(user string) int
+	// MaxConcurrentQueryBytes returns the max estimated bytes of requests of the tenant executed at the same time,
+	// or 0 if unlimited.
+	MaxConcurrentQueryBytes(user string) int
 }
 
 type schedulerRequest struct {
@@ -151,6 +154,7 @@ type schedulerRequest struct {
 	queryID         uint64
 	request         *httpgrpc.HTTPRequest
 	statsEnabled    bool
+	cost            int64
 
 	enqueueTime time.Time
 
@@ -290,6 +294,7 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 		queryID:         msg.QueryID,
 		request:         msg.HttpRequest,
 		statsEnabled:    msg.StatsEnabled,
+		cost:            msg.Cost,
 	}
 
 	now := time.Now()
@@ -299,15 +304,17 @@ func (s *Scheduler) enqueueRequest(frontendContext context.Context, frontendAddr
 	req.enqueueTime = now
 	req.ctxCancel = cancel
 
-	// aggregate the max queriers limit in the case of a multi tenant query
+	// aggregate the max queriers and cost limits in the case of a multi tenant query
 	tenantIDs, err := tenant.TenantIDsFromOrgID(userID)
 	if err != nil {
 		return err
 	}
 	maxQueriers := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, s.limits.MaxQueriersPerUser)
+	maxConcurrentCost := validation.SmallestPositiveNonZeroIntPerTenant(tenantIDs, s.limits.MaxConcurrentQueryBytes)
 
 	s.activeUsers.UpdateUserTimestamp(userID, now)
-	return s.requestQueue.EnqueueRequest(userID, req, maxQueriers, func() {
+	// the cost is estimated by the frontend, requests of frontends not sending it are queued with the lowest cost
+	return s.requestQueue.EnqueueRequest(userID, req, req.cost, maxQueriers, int64(maxConcurrentCost), func() {
 		shouldCancel = false
 
 		s.pendingRequestsMu.Lock()
@@ -379,12 +386,16 @@ func (s *Scheduler) QuerierLoop(querier schedulerpb.SchedulerForQuerier_QuerierL
 		if r.ctx.Err() != nil {
 			// Remove from pending requests.
 			s.cancelRequestAndRemoveFromPending(r.frontendAddress, r.queryID)
+			s.requestQueue.FinishRequest(r.userID, r.cost)
 
 			lastUserIndex = lastUserIndex.ReuseLastUser()
 			continue
 		}
 
-		if err := s.forwardRequestToQuerier(querier, r); err != nil {
+		// forwardRequestToQuerier returns once the querier has responded to the frontend, which releases the cost
+		err = s.forwardRequestToQuerier(querier, r)
+		s.requestQueue.FinishRequest(r.userID, r.cost)
+		if err != nil {
 			return err
 		}
 	}
@@ -219,6 +219,8 @@ type FrontendToScheduler struct {
 	UserID       string                `protobuf:"bytes,4,opt,name=userID,proto3" json:"userID,omitempty"`
 	HttpRequest  *httpgrpc.HTTPRequest `protobuf:"bytes,5,opt,name=httpRequest,proto3" json:"httpRequest,omitempty"`
 	StatsEnabled bool                  `protobuf:"varint,6,opt,name=statsEnabled,proto3" json:"statsEnabled,omitempty"`
+	// Estimated cost of the request, in bytes to scan. Requests without a cost are queued with the lowest cost.
+	Cost int64 `protobuf:"varint,7,opt,name=cost,proto3" json:"cost,omitempty"`
 }
 
 func (m *FrontendToScheduler) Reset()      { *m = FrontendToScheduler{} }
@@ -295,6 +297,13 @@ func (m *FrontendToScheduler) GetStatsEnabled() bool {
 	return false
 }
 
+func (m *FrontendToScheduler) GetCost() int64 {
+	if m != nil {
+		return m.Cost
+	}
+	return 0
+}
+
 type SchedulerToFrontend struct {
 	Status SchedulerToFrontendStatus `protobuf:"varint,1,opt,name=status,proto3,enum=schedulerpb.SchedulerToFrontendStatus" json:"status,omitempty"`
 	Error  string                    `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
@@ -438,48 +447,49 @@ func init() {
 func init() { proto.RegisterFile("scheduler.proto", fileDescriptor_2b3fc28395a6d9c5) }
 
 var fileDescriptor_2b3fc28395a6d9c5 = []byte{
-	// 650 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcf, 0x4f, 0x13, 0x41,
-	0x14, 0xde, 0x29, 0x6d, 0x81, 0x57, 0x94, 0x75, 0x00, 0xad, 0x0d, 0x0e, 0x4d, 0x63, 0x4c, 0x25,
-	0xb1, 0x35, 0xd5, 0x44, 0x0f, 0xc4, 0xa4, 0xc2, 0x22, 0x8d, 0xb8, 0x85, 0xe9, 0x34, 0xfe, 0xb8,
-	0x34, 0xb4, 0x1d, 0x5a, 0x02, 0xec, 0x2c, 0xb3, 0xbb, 0x92, 0xde, 0x3c, 0x7a, 0xf4, 0xcf, 0xf0,
-	0x4f, 0xf1, 0x62, 0xc2, 0x91, 0x83, 0x07, 0x59, 0x2e, 0x1e, 0xf9, 0x13, 0x0c, 0xd3, 0x6d, 0xdd,
-	0xd6, 0x16, 0xb8, 0xbd, 0xf7, 0xf6, 0xfb, 0x76, 0xde, 0xf7, 0xbd, 0x37, 0x03, 0xb3, 0x4e, 0xa3,
-	0xcd, 0x9b, 0xde, 0x01, 0x97, 0x39, 0x5b, 0x0a, 0x57, 0xe0, 0x44, 0xbf, 0x60, 0xd7, 0x53, 0x4f,
-	0x5a, 0x7b, 0x6e, 0xdb, 0xab, 0xe7, 0x1a, 0xe2, 0x30, 0xdf, 0x12, 0x2d, 0x91, 0x57, 0x98, 0xba,
-	0xb7, 0xab, 0x32, 0x95, 0xa8, 0xa8, 0xcb, 0x4d, 0x3d, 0x0f, 0xc1, 0x8f, 0xf9, 0xce, 0x67, 0x7e,
-	0x2c, 0xe4, 0xbe, 0x93, 0x6f, 0x88, 0xc3, 0x43, 0x61, 0xe5, 0xdb, 0xae, 0x6b, 0xb7, 0xa4, 0xdd,
-	0xe8, 0x07, 0x5d, 0x56, 0xa6, 0x00, 0x78, 0xdb, 0xe3, 0x72, 0x8f, 0x4b, 0x26, 0x2a, 0xbd, 0xc3,
-	0xf1, 0x22, 0x4c, 0x1f, 0x75, 0xab, 0xa5, 0xb5, 0x24, 0x4a, 0xa3, 0xec, 0x34, 0xfd, 0x57, 0xc8,
-	0xfc, 0x44, 0x80, 0xfb, 0x58, 0x26, 0x02, 0x3e, 0x4e, 0xc2, 0xe4, 0x25, 0xa6, 0x13, 0x50, 0xa2,
-	0xb4, 0x97, 0xe2, 0x17, 0x90, 0xb8, 0x3c, 0x96, 0xf2, 0x23, 0x8f, 0x3b, 0x6e, 0x32, 0x92, 0x46,
-	0xd9, 0x44, 0x61, 0x21, 0xd7, 0x6f, 0x65, 0x83, 0xb1, 0xad, 0xe0, 0x23, 0x0d, 0x23, 0x71, 0x16,
-	0x66, 0x77, 0xa5, 0xb0, 0x5c, 0x6e, 0x35, 0x8b, 0xcd, 0xa6, 0xe4, 0x8e, 0x93, 0x9c, 0x50, 0xdd,
-	0x0c, 0x97, 0xf1, 0x5d, 0x88, 0x7b, 0x8e, 0x6a, 0x37, 0xaa, 0x00, 0x41, 0x86, 0x33, 0x30, 0xe3,
-	0xb8, 0x3b, 0xae, 0x63, 0x58, 0x3b, 0xf5, 0x03, 0xde, 0x4c, 0xc6, 0xd2, 0x28, 0x3b, 0x45, 0x07,
-	0x6a, 0x99, 0xaf, 0x11, 0x98, 0x5b, 0x0f, 0xfe, 0x17, 0x76, 0xe1, 0x25, 0x44, 0xdd, 0x8e, 0xcd,
-	0x95, 0x9a, 0xdb, 0x85, 0x87, 0xb9, 0xd0, 0x70, 0x72, 0x23, 0xf0, 0xac, 0x63, 0x73, 0xaa, 0x18,
-	0xa3, 0xfa, 0x8e, 0x8c, 0xee, 0x3b, 0x64, 0xda, 0xc4, 0xa0, 0x69, 0xe3, 0x14, 0x0d, 0x99, 0x19,
-	0xbb, 0xb1, 0x99, 0xc3, 0x56, 0xc4, 0x47, 0x58, 0xb1, 0x0f, 0x73, 0xa1, 0xc9, 0xf6, 0x44, 0xe2,
-	0x57, 0x10, 0xbf, 0x84, 0x79, 0x4e, 0xe0, 0xc5, 0xa3, 0x01, 0x2f, 0x46, 0x30, 0x2a, 0x0a, 0x4d,
-	0x03, 0x16, 0x9e, 0x87, 0x18, 0x97, 0x52, 0xc8, 0xc0, 0x85, 0x6e, 0x92, 0x59, 0x81, 0x45, 0x53,
-	0xb8, 0x7b, 0xbb, 0x9d, 0x60, 0x83, 0x2a, 0x6d, 0xcf, 0x6d, 0x8a, 0x63, 0xab, 0xd7, 0xf0, 0xd5,
-	0x5b, 0xb8, 0x04, 0x0f, 0xc6, 0xb0, 0x1d, 0x5b, 0x58, 0x0e, 0x5f, 0x5e, 0x81, 0x7b, 0x63, 0xa6,
-	0x84, 0xa7, 0x20, 0x5a, 0x32, 0x4b, 0x4c, 0xd7, 0x70, 0x02, 0x26, 0x0d, 0x73, 0xbb, 0x6a, 0x54,
-	0x0d, 0x1d, 0x61, 0x80, 0xf8, 0x6a, 0xd1, 0x5c, 0x35, 0x36, 0xf5, 0xc8, 0x72, 0x03, 0xee, 0x8f,
-	0xd5, 0x85, 0xe3, 0x10, 0x29, 0xbf, 0xd5, 0x35, 0x9c, 0x86, 0x45, 0x56, 0x2e, 0xd7, 0xde, 0x15,
-	0xcd, 0x8f, 0x35, 0x6a, 0x6c, 0x57, 0x8d, 0x0a, 0xab, 0xd4, 0xb6, 0x0c, 0x5a, 0x63, 0x86, 0x59,
-	0x34, 0x99, 0x8e, 0xf0, 0x34, 0xc4, 0x0c, 0x4a, 0xcb, 0x54, 0x8f, 0xe0, 0x3b, 0x70, 0xab, 0xb2,
-	0x51, 0x65, 0xac, 0x64, 0xbe, 0xa9, 0xad, 0x95, 0xdf, 0x9b, 0xfa, 0x44, 0xe1, 0x17, 0x0a, 0xf9,
-	0xbd, 0x2e, 0x64, 0xef, 0x2a, 0x55, 0x21, 0x11, 0x84, 0x9b, 0x42, 0xd8, 0x78, 0x69, 0xc0, 0xee,
-	0xff, 0xef, 0x6b, 0x6a, 0x69, 0xdc, 0x3c, 0x02, 0x6c, 0x46, 0xcb, 0xa2, 0xa7, 0x08, 0x5b, 0xb0,
-	0x30, 0xd2, 0x32, 0xfc, 0x78, 0x80, 0x7f, 0xd5, 0x50, 0x52, 0xcb, 0x37, 0x81, 0x76, 0x27, 0x50,
-	0xb0, 0x61, 0x3e, 0xac, 0xae, 0xbf, 0x4e, 0x1f, 0x60, 0xa6, 0x17, 0x2b, 0x7d, 0xe9, 0xeb, 0xae,
-	0x56, 0x2a, 0x7d, 0xdd, 0xc2, 0x75, 0x15, 0xbe, 0x2e, 0x9e, 0x9c, 0x11, 0xed, 0xf4, 0x8c, 0x68,
-	0x17, 0x67, 0x04, 0x7d, 0xf1, 0x09, 0xfa, 0xee, 0x13, 0xf4, 0xc3, 0x27, 0xe8, 0xc4, 0x27, 0xe8,
-	0xb7, 0x4f, 0xd0, 0x1f, 0x9f, 0x68, 0x17, 0x3e, 0x41, 0xdf, 0xce, 0x89, 0x76, 0x72, 0x4e, 0xb4,
-	0xd3, 0x73, 0xa2, 0x7d, 0x0a, 0x3f, 0xbb, 0xf5, 0xb8, 0x7a, 0x18, 0x9f, 0xfd, 0x0d, 0x00, 0x00,
-	0xff, 0xff, 0x49, 0x11, 0xf9, 0x34, 0x9d, 0x05, 0x00, 0x00,
+	// 663 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0xcd, 0x4e, 0xdb, 0x4c,
+	0x14, 0xf5, 0xe4, 0x0f, 0xb8, 0xe1, 0xfb, 0x70, 0x07, 0x68, 0xd3, 0x88, 0x0e, 0x96, 0x55, 0x55,
+	0x29, 0x52, 0x93, 0x2a, 0xad, 0xd4, 0x2e, 0x50, 0xa5, 0x14, 0x4c, 0x89, 0x4a, 0x1d, 0x98, 0x38,
+	0xea, 0xcf, 0x26, 0x22, 0xc9, 0x90, 0x20, 0xc0, 0x63, 0xec, 0x71, 0x51, 0x76, 0x7d, 0x84, 0x3e,
+	0x40, 0x1f, 0xa0, 0x8f, 0xd2, 0x4d, 0x25, 0x96, 0x2c, 0xba, 0x28, 0x66, 0xd3, 0x25, 0x8f, 0x50,
+	0x31, 0x71, 0x52, 0x07, 0x12, 0x60, 0x77, 0xef, 0xf5, 0x39, 0x9e, 0x7b, 0xce, 0xbd, 0x33, 0x30,
+	0xe3, 0x35, 0x3b, 0xac, 0xe5, 0xef, 0x33, 0x37, 0xef, 0xb8, 0x5c, 0x70, 0x9c, 0x1e, 0x14, 0x9c,
+	0x46, 0xf6, 0x49, 0x7b, 0x57, 0x74, 0xfc, 0x46, 0xbe, 0xc9, 0x0f, 0x0a, 0x6d, 0xde, 0xe6, 0x05,
+	0x89, 0x69, 0xf8, 0x3b, 0x32, 0x93, 0x89, 0x8c, 0x7a, 0xdc, 0xec, 0xf3, 0x08, 0xfc, 0x88, 0x6d,
+	0x7f, 0x66, 0x47, 0xdc, 0xdd, 0xf3, 0x0a, 0x4d, 0x7e, 0x70, 0xc0, 0xed, 0x42, 0x47, 0x08, 0xa7,
+	0xed, 0x3a, 0xcd, 0x41, 0xd0, 0x63, 0xe9, 0x45, 0xc0, 0x5b, 0x3e, 0x73, 0x77, 0x99, 0x6b, 0xf1,
+	0x6a, 0xff, 0x70, 0xbc, 0x00, 0x53, 0x87, 0xbd, 0x6a, 0x79, 0x35, 0x83, 0x34, 0x94, 0x9b, 0xa2,
+	0xff, 0x0a, 0xfa, 0x4f, 0x04, 0x78, 0x80, 0xb5, 0x78, 0xc8, 0xc7, 0x19, 0x98, 0xb8, 0xc0, 0x74,
+	0x43, 0x4a, 0x82, 0xf6, 0x53, 0xfc, 0x02, 0xd2, 0x17, 0xc7, 0x52, 0x76, 0xe8, 0x33, 0x4f, 0x64,
+	0x62, 0x1a, 0xca, 0xa5, 0x8b, 0xf3, 0xf9, 0x41, 0x2b, 0xeb, 0x96, 0xb5, 0x19, 0x7e, 0xa4, 0x51,
+	0x24, 0xce, 0xc1, 0xcc, 0x8e, 0xcb, 0x6d, 0xc1, 0xec, 0x56, 0xa9, 0xd5, 0x72, 0x99, 0xe7, 0x65,
+	0xe2, 0xb2, 0x9b, 0xcb, 0x65, 0x7c, 0x17, 0x52, 0xbe, 0x27, 0xdb, 0x4d, 0x48, 0x40, 0x98, 0x61,
+	0x1d, 0xa6, 0x3d, 0xb1, 0x2d, 0x3c, 0xc3, 0xde, 0x6e, 0xec, 0xb3, 0x56, 0x26, 0xa9, 0xa1, 0xdc,
+	0x24, 0x1d, 0xaa, 0xe9, 0xdf, 0x62, 0x30, 0xbb, 0x16, 0xfe, 0x2f, 0xea, 0xc2, 0x4b, 0x48, 0x88,
+	0xae, 0xc3, 0xa4, 0x9a, 0xff, 0x8b, 0x0f, 0xf3, 0x91, 0xe1, 0xe4, 0x47, 0xe0, 0xad, 0xae, 0xc3,
+	0xa8, 0x64, 0x8c, 0xea, 0x3b, 0x36, 0xba, 0xef, 0x88, 0x69, 0xf1, 0x61, 0xd3, 0xc6, 0x29, 0xba,
+	0x64, 0x66, 0xf2, 0xd6, 0x66, 0x5e, 0xb6, 0x22, 0x75, 0xd5, 0x0a, 0x8c, 0x21, 0xd1, 0xe4, 0x9e,
+	0xc8, 0x4c, 0x68, 0x28, 0x17, 0xa7, 0x32, 0xd6, 0xf7, 0x60, 0x36, 0x32, 0xed, 0xbe, 0x70, 0xfc,
+	0x0a, 0x52, 0x17, 0x54, 0xdf, 0x0b, 0xfd, 0x79, 0x34, 0xe4, 0xcf, 0x08, 0x46, 0x55, 0xa2, 0x69,
+	0xc8, 0xc2, 0x73, 0x90, 0x64, 0xae, 0xcb, 0xdd, 0xd0, 0x99, 0x5e, 0xa2, 0x2f, 0xc3, 0x82, 0xc9,
+	0xc5, 0xee, 0x4e, 0x37, 0xdc, 0xaa, 0x6a, 0xc7, 0x17, 0x2d, 0x7e, 0x64, 0xf7, 0x45, 0x5c, 0xbf,
+	0x99, 0x8b, 0xf0, 0x60, 0x0c, 0xdb, 0x73, 0xb8, 0xed, 0xb1, 0xa5, 0x65, 0xb8, 0x37, 0x66, 0x72,
+	0x78, 0x12, 0x12, 0x65, 0xb3, 0x6c, 0xa9, 0x0a, 0x4e, 0xc3, 0x84, 0x61, 0x6e, 0xd5, 0x8c, 0x9a,
+	0xa1, 0x22, 0x0c, 0x90, 0x5a, 0x29, 0x99, 0x2b, 0xc6, 0x86, 0x1a, 0x5b, 0x6a, 0xc2, 0xfd, 0xb1,
+	0xba, 0x70, 0x0a, 0x62, 0x95, 0xb7, 0xaa, 0x82, 0x35, 0x58, 0xb0, 0x2a, 0x95, 0xfa, 0xbb, 0x92,
+	0xf9, 0xb1, 0x4e, 0x8d, 0xad, 0x9a, 0x51, 0xb5, 0xaa, 0xf5, 0x4d, 0x83, 0xd6, 0x2d, 0xc3, 0x2c,
+	0x99, 0x96, 0x8a, 0xf0, 0x14, 0x24, 0x0d, 0x4a, 0x2b, 0x54, 0x8d, 0xe1, 0x3b, 0xf0, 0x5f, 0x75,
+	0xbd, 0x66, 0x59, 0x65, 0xf3, 0x4d, 0x7d, 0xb5, 0xf2, 0xde, 0x54, 0xe3, 0xc5, 0x5f, 0x28, 0xe2,
+	0xf7, 0x1a, 0x77, 0xfb, 0xd7, 0xab, 0x06, 0xe9, 0x30, 0xdc, 0xe0, 0xdc, 0xc1, 0x8b, 0x43, 0x76,
+	0x5f, 0xbd, 0xc3, 0xd9, 0xc5, 0x71, 0xf3, 0x08, 0xb1, 0xba, 0x92, 0x43, 0x4f, 0x11, 0xb6, 0x61,
+	0x7e, 0xa4, 0x65, 0xf8, 0xf1, 0x10, 0xff, 0xba, 0xa1, 0x64, 0x97, 0x6e, 0x03, 0xed, 0x4d, 0xa0,
+	0xe8, 0xc0, 0x5c, 0x54, 0xdd, 0x60, 0x9d, 0x3e, 0xc0, 0x74, 0x3f, 0x96, 0xfa, 0xb4, 0x9b, 0xae,
+	0x5b, 0x56, 0xbb, 0x69, 0xe1, 0x7a, 0x0a, 0x5f, 0x97, 0x8e, 0x4f, 0x89, 0x72, 0x72, 0x4a, 0x94,
+	0xf3, 0x53, 0x82, 0xbe, 0x04, 0x04, 0x7d, 0x0f, 0x08, 0xfa, 0x11, 0x10, 0x74, 0x1c, 0x10, 0xf4,
+	0x3b, 0x20, 0xe8, 0x4f, 0x40, 0x94, 0xf3, 0x80, 0xa0, 0xaf, 0x67, 0x44, 0x39, 0x3e, 0x23, 0xca,
+	0xc9, 0x19, 0x51, 0x3e, 0x45, 0x9f, 0xe2, 0x46, 0x4a, 0x3e, 0x96, 0xcf, 0xfe, 0x0e, 0x00, 0x0f,
+	0x87, 0x1d, 0x31, 0xb1, 0x05, 0x00, 0x00,
 }
 
 func (x FrontendToSchedulerType) String() string {
@@ -593,6 +603,9 @@ func (this *FrontendToScheduler) Equal(that interface{}) bool {
 	if this.StatsEnabled != that1.StatsEnabled {
 		return false
 	}
+	if this.Cost != that1.Cost {
+		return false
+	}
 	return true
 }
 func (this *SchedulerToFrontend) Equal(that interface{}) bool {
@@ -697,7 +710,7 @@ func (this *FrontendToScheduler) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 10)
+	s := make([]string, 0, 11)
 	s = append(s, "&schedulerpb.FrontendToScheduler{")
 	s = append(s, "Type: "+fmt.Sprintf("%#v", this.Type)+",\n")
 	s = append(s, "FrontendAddress: "+fmt.Sprintf("%#v", this.FrontendAddress)+",\n")
@@ -707,6 +720,7 @@ func (this *FrontendToScheduler) GoString() string {
 		s = append(s, "HttpRequest: "+fmt.Sprintf("%#v", this.HttpRequest)+",\n")
 	}
 	s = append(s, "StatsEnabled: "+fmt.Sprintf("%#v", this.StatsEnabled)+",\n")
+	s = append(s, "Cost: "+fmt.Sprintf("%#v", this.Cost)+",\n")
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
@@ -1142,6 +1156,11 @@ func (m *FrontendToScheduler) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.Cost != 0 {
+		i = encodeVarintScheduler(dAtA, i, uint64(m.Cost))
+		i--
+		dAtA[i] = 0x38
+	}
 	if m.StatsEnabled {
 		i--
 		if m.StatsEnabled {
@@ -1357,6 +1376,9 @@ func (m *FrontendToScheduler) Size() (n int) {
 	if m.StatsEnabled {
 		n += 2
 	}
+	if m.Cost != 0 {
+		n += 1 + sovScheduler(uint64(m.Cost))
+	}
 	return n
 }
 
@@ -1439,6 +1461,7 @@ func (this *FrontendToScheduler) String() string {
 		`UserID:` + fmt.Sprintf("%v", this.UserID) + `,`,
 		`HttpRequest:` + strings.Replace(fmt.Sprintf("%v", this.HttpRequest), "HTTPRequest", "httpgrpc.HTTPRequest", 1) + `,`,
 		`StatsEnabled:` + fmt.Sprintf("%v", this.StatsEnabled) + `,`,
+		`Cost:` + fmt.Sprintf("%v", this.Cost) + `,`,
 		`}`,
 	}, "")
 	return s
@@ -1945,6 +1968,25 @@ func (m *FrontendToScheduler) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.StatsEnabled = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cost", wireType)
+			}
+			m.Cost = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowScheduler
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Cost |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipScheduler(dAtA[iNdEx:])
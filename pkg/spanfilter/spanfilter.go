@@ -0,0 +1,170 @@
+package spanfilter
+
+import (
+	"fmt"
+	"regexp"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	tempo_util "github.com/grafana/tempo/pkg/util"
+)
+
+// Config selects spans by include and exclude rules. A span is kept if it matches any of the
+// include rules, or if there are no include rules, and it doesn't match any of the exclude rules.
+type Config struct {
+	Include []Match `yaml:"include,omitempty" json:"include,omitempty"`
+	Exclude []Match `yaml:"exclude,omitempty" json:"exclude,omitempty"`
+}
+
+// Match is a rule matching spans. All criteria that are set must match.
+type Match struct {
+	// Services is a list of regular expressions matched against the service name. Matches if any of
+	// them matches.
+	Services []string `yaml:"services,omitempty" json:"services,omitempty"`
+	// SpanKinds is a list of span kinds, e.g. SPAN_KIND_SERVER. Matches if any of them matches.
+	SpanKinds []string `yaml:"span_kinds,omitempty" json:"span_kinds,omitempty"`
+	// Attributes maps attribute keys to regular expressions matched against the attribute value of
+	// the span or its resource. Matches if all attributes match.
+	Attributes map[string]string `yaml:"attributes,omitempty" json:"attributes,omitempty"`
+}
+
+// Filter decides whether a span should be processed.
+type Filter struct {
+	include []*matcher
+	exclude []*matcher
+}
+
+type matcher struct {
+	services   []*regexp.Regexp
+	spanKinds  map[string]struct{}
+	attributes map[string]*regexp.Regexp
+}
+
+// New creates a Filter from the config. Returns an error if a regular expression is invalid.
+func New(cfg Config) (*Filter, error) {
+	include, err := newMatchers(cfg.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include: %w", err)
+	}
+	exclude, err := newMatchers(cfg.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude: %w", err)
+	}
+
+	return &Filter{
+		include: include,
+		exclude: exclude,
+	}, nil
+}
+
+func newMatchers(matches []Match) ([]*matcher, error) {
+	matchers := make([]*matcher, 0, len(matches))
+
+	for _, match := range matches {
+		m := &matcher{}
+
+		for _, service := range match.Services {
+			r, err := compileAnchored(service)
+			if err != nil {
+				return nil, err
+			}
+			m.services = append(m.services, r)
+		}
+
+		if len(match.SpanKinds) > 0 {
+			m.spanKinds = make(map[string]struct{}, len(match.SpanKinds))
+			for _, kind := range match.SpanKinds {
+				if _, ok := v1_trace.Span_SpanKind_value[kind]; !ok {
+					return nil, fmt.Errorf("unknown span kind %s", kind)
+				}
+				m.spanKinds[kind] = struct{}{}
+			}
+		}
+
+		if len(match.Attributes) > 0 {
+			m.attributes = make(map[string]*regexp.Regexp, len(match.Attributes))
+			for key, value := range match.Attributes {
+				r, err := compileAnchored(value)
+				if err != nil {
+					return nil, err
+				}
+				m.attributes[key] = r
+			}
+		}
+
+		matchers = append(matchers, m)
+	}
+
+	return matchers, nil
+}
+
+func compileAnchored(expr string) (*regexp.Regexp, error) {
+	r, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression %s: %w", expr, err)
+	}
+	return r, nil
+}
+
+// Keep returns whether the span of service svcName should be processed. resourceAttributes are
+// the attributes of the resource the span belongs to.
+func (f *Filter) Keep(svcName string, resourceAttributes []*v1_common.KeyValue, span *v1_trace.Span) bool {
+	if f == nil {
+		return true
+	}
+
+	if len(f.include) > 0 && !matchesAny(f.include, svcName, resourceAttributes, span) {
+		return false
+	}
+	return !matchesAny(f.exclude, svcName, resourceAttributes, span)
+}
+
+func matchesAny(matchers []*matcher, svcName string, resourceAttributes []*v1_common.KeyValue, span *v1_trace.Span) bool {
+	for _, m := range matchers {
+		if m.matches(svcName, resourceAttributes, span) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *matcher) matches(svcName string, resourceAttributes []*v1_common.KeyValue, span *v1_trace.Span) bool {
+	if len(m.services) > 0 {
+		found := false
+		for _, r := range m.services {
+			if r.MatchString(svcName) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if m.spanKinds != nil {
+		if _, ok := m.spanKinds[span.Kind.String()]; !ok {
+			return false
+		}
+	}
+
+	for key, r := range m.attributes {
+		value, ok := findAttributeValue(key, span.Attributes, resourceAttributes)
+		if !ok || !r.MatchString(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func findAttributeValue(key string, attributes ...[]*v1_common.KeyValue) (string, bool) {
+	for _, attrs := range attributes {
+		for _, kv := range attrs {
+			if key == kv.Key {
+				return tempo_util.StringifyAnyValue(kv.Value), true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,123 @@
+package spanfilter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestFilter_Keep(t *testing.T) {
+	serverSpan := &v1_trace.Span{
+		Kind:       v1_trace.Span_SPAN_KIND_SERVER,
+		Attributes: []*v1_common.KeyValue{stringKV("http.method", "GET")},
+	}
+	clientSpan := &v1_trace.Span{
+		Kind: v1_trace.Span_SPAN_KIND_CLIENT,
+	}
+	resource := []*v1_common.KeyValue{stringKV("k8s.namespace", "team-a")}
+
+	tests := []struct {
+		name     string
+		cfg      Config
+		svcName  string
+		span     *v1_trace.Span
+		expected bool
+	}{
+		{
+			name:     "empty config",
+			svcName:  "frontend",
+			span:     clientSpan,
+			expected: true,
+		},
+		{
+			name:     "include service",
+			cfg:      Config{Include: []Match{{Services: []string{"front.*"}}}},
+			svcName:  "frontend",
+			span:     clientSpan,
+			expected: true,
+		},
+		{
+			name:     "include service is anchored",
+			cfg:      Config{Include: []Match{{Services: []string{"front"}}}},
+			svcName:  "frontend",
+			span:     clientSpan,
+			expected: false,
+		},
+		{
+			name:     "include any of",
+			cfg:      Config{Include: []Match{{Services: []string{"backend"}}, {SpanKinds: []string{"SPAN_KIND_SERVER"}}}},
+			svcName:  "frontend",
+			span:     serverSpan,
+			expected: true,
+		},
+		{
+			name:     "all criteria must match",
+			cfg:      Config{Include: []Match{{Services: []string{"frontend"}, SpanKinds: []string{"SPAN_KIND_SERVER"}}}},
+			svcName:  "frontend",
+			span:     clientSpan,
+			expected: false,
+		},
+		{
+			name:     "include span attribute",
+			cfg:      Config{Include: []Match{{Attributes: map[string]string{"http.method": "GET|POST"}}}},
+			svcName:  "frontend",
+			span:     serverSpan,
+			expected: true,
+		},
+		{
+			name:     "missing attribute",
+			cfg:      Config{Include: []Match{{Attributes: map[string]string{"http.method": ".*"}}}},
+			svcName:  "frontend",
+			span:     clientSpan,
+			expected: false,
+		},
+		{
+			name:     "exclude resource attribute",
+			cfg:      Config{Exclude: []Match{{Attributes: map[string]string{"k8s.namespace": "team-a"}}}},
+			svcName:  "frontend",
+			span:     serverSpan,
+			expected: false,
+		},
+		{
+			name: "exclude wins over include",
+			cfg: Config{
+				Include: []Match{{Services: []string{"frontend"}}},
+				Exclude: []Match{{SpanKinds: []string{"SPAN_KIND_CLIENT"}}},
+			},
+			svcName:  "frontend",
+			span:     clientSpan,
+			expected: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := New(tc.cfg)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, f.Keep(tc.svcName, resource, tc.span))
+		})
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	_, err := New(Config{Include: []Match{{Services: []string{"("}}}})
+	assert.Error(t, err)
+
+	_, err = New(Config{Exclude: []Match{{SpanKinds: []string{"SERVER"}}}})
+	assert.Error(t, err)
+
+	_, err = New(Config{Exclude: []Match{{Attributes: map[string]string{"foo": "["}}}})
+	assert.Error(t, err)
+}
+
+func stringKV(k, v string) *v1_common.KeyValue {
+	return &v1_common.KeyValue{
+		Key:   k,
+		Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: v}},
+	}
+}
@@ -12,6 +12,9 @@ var (
 // PreallocBytes is a (repeated bytes slices) which preallocs slices on Unmarshal.
 type PreallocBytes struct {
 	Slice []byte
+
+	// buf is the request buffer Slice references if it was unmarshalled with UnmarshalShared
+	buf *Buffer
 }
 
 // Unmarshal implements proto.Message.
@@ -37,6 +40,19 @@ func (r *PreallocBytes) Size() (n int) {
 	return len(r.Slice)
 }
 
+// Release releases the byte slice once it is no longer used. Slices unmarshalled with UnmarshalShared release
+// their reference to the request buffer, all others are put back into bytePool for reuse. The slice must not be
+// used afterwards.
+func (r *PreallocBytes) Release() {
+	if r.buf != nil {
+		r.buf.release()
+		r.buf = nil
+	} else if cap(r.Slice) > 0 {
+		bytePool.Put(r.Slice[:0])
+	}
+	r.Slice = nil
+}
+
 // ReuseByteSlices puts the byte slice back into bytePool for reuse.
 func ReuseByteSlices(buffs [][]byte) {
 	for _, b := range buffs {
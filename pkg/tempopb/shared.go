@@ -0,0 +1,194 @@
+package tempopb
+
+import (
+	"fmt"
+	"io"
+
+	"go.uber.org/atomic"
+)
+
+// sharedBufferBytes is the total size of all buffers that are still referenced
+var sharedBufferBytes = atomic.NewInt64(0)
+
+// Buffer is a reference counted buffer backing the PreallocBytes of a request unmarshalled with UnmarshalShared.
+// The byte slices reference the buffer instead of holding copies of it, so it is retained until all of them have
+// been released.
+type Buffer struct {
+	size int
+	refs atomic.Int32
+}
+
+// newBuffer returns a buffer for b with a single reference held by the caller.
+func newBuffer(b []byte) *Buffer {
+	buf := &Buffer{size: len(b)}
+	buf.refs.Store(1)
+	sharedBufferBytes.Add(int64(buf.size))
+	return buf
+}
+
+func (b *Buffer) ref() {
+	b.refs.Inc()
+}
+
+func (b *Buffer) release() {
+	refs := b.refs.Dec()
+	if refs == 0 {
+		sharedBufferBytes.Sub(int64(b.size))
+	}
+	if refs < 0 {
+		panic("tempopb: buffer released more often than referenced")
+	}
+}
+
+// SharedBufferBytes returns the total size of all request buffers that are still referenced by unreleased
+// byte slices.
+func SharedBufferBytes() int64 {
+	return sharedBufferBytes.Load()
+}
+
+// Ref returns a copy of r that holds its own reference to the request buffer, so that both r and the copy have to be
+// released. Slices that are not backed by a request buffer are copied.
+func (r *PreallocBytes) Ref() PreallocBytes {
+	if r.buf == nil {
+		if r.Slice == nil {
+			return PreallocBytes{}
+		}
+		b := bytePool.Get(len(r.Slice))
+		return PreallocBytes{Slice: append(b[:0], r.Slice...)}
+	}
+
+	r.buf.ref()
+	return PreallocBytes{Slice: r.Slice, buf: r.buf}
+}
+
+// UnmarshalShared unmarshals the request without copying traces and search data. Their byte slices reference data
+// directly and must be released with PreallocBytes.Release once they are no longer used. Trace IDs are copied since
+// they are usually retained much longer than the rest of the request. data must not be modified afterwards.
+func (m *PushBytesRequest) UnmarshalShared(data []byte) error {
+	m.Reset()
+
+	// count the fields first to allocate everything upfront
+	var traces, ids, searchData, idBytes int
+	err := walkPushBytesRequest(data, func(fieldNum int32, b []byte) {
+		switch fieldNum {
+		case 2:
+			traces++
+		case 3:
+			ids++
+			idBytes += len(b)
+		case 4:
+			searchData++
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	buf := newBuffer(data)
+	defer buf.release()
+
+	m.Traces = make([]PreallocBytes, 0, traces)
+	m.Ids = make([]PreallocBytes, 0, ids)
+	m.SearchData = make([]PreallocBytes, 0, searchData)
+	idArena := make([]byte, 0, idBytes)
+
+	return walkPushBytesRequest(data, func(fieldNum int32, b []byte) {
+		switch fieldNum {
+		case 2:
+			buf.ref()
+			m.Traces = append(m.Traces, PreallocBytes{Slice: b, buf: buf})
+		case 3:
+			start := len(idArena)
+			idArena = append(idArena, b...)
+			m.Ids = append(m.Ids, PreallocBytes{Slice: idArena[start:len(idArena):len(idArena)]})
+		case 4:
+			buf.ref()
+			m.SearchData = append(m.SearchData, PreallocBytes{Slice: b, buf: buf})
+		}
+	})
+}
+
+// walkPushBytesRequest calls fn for every traces, ids and searchData field of a marshalled PushBytesRequest. The
+// byte slices passed to fn reference data and are capped to their length so appending can't overwrite data.
+func walkPushBytesRequest(data []byte, fn func(fieldNum int32, b []byte)) error {
+	l := len(data)
+	idx := 0
+	for idx < l {
+		preIndex := idx
+		wire, n, err := decodeVarint(data[idx:])
+		if err != nil {
+			return err
+		}
+		idx += n
+
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: PushBytesRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+
+		switch fieldNum {
+		case 2, 3, 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field %d", wireType, fieldNum)
+			}
+			byteLen, n, err := decodeVarint(data[idx:])
+			if err != nil {
+				return err
+			}
+			idx += n
+
+			end := idx + int(byteLen)
+			if int(byteLen) < 0 || end < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if end > l {
+				return io.ErrUnexpectedEOF
+			}
+			fn(fieldNum, data[idx:end:end])
+			idx = end
+		default:
+			idx = preIndex
+			skippy, err := skipTempo(data[idx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 || idx+skippy < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if idx+skippy > l {
+				return io.ErrUnexpectedEOF
+			}
+			idx += skippy
+		}
+	}
+
+	return nil
+}
+
+// Release releases all traces and search data of the request.
+func (m *PushBytesRequest) Release() {
+	for i := range m.Traces {
+		m.Traces[i].Release()
+	}
+	for i := range m.SearchData {
+		m.SearchData[i].Release()
+	}
+}
+
+func decodeVarint(data []byte) (uint64, int, error) {
+	var v uint64
+	for i, shift := 0, uint(0); ; i, shift = i+1, shift+7 {
+		if shift >= 64 {
+			return 0, 0, ErrIntOverflowTempo
+		}
+		if i >= len(data) {
+			return 0, 0, io.ErrUnexpectedEOF
+		}
+		b := data[i]
+		v |= uint64(b&0x7F) << shift
+		if b < 0x80 {
+			return v, i + 1, nil
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package tempopb
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeSharedTestRequest(traces int, traceSize int) *PushBytesRequest {
+	req := &PushBytesRequest{}
+	for i := 0; i < traces; i++ {
+		id := make([]byte, 16)
+		rand.Read(id)
+		trace := make([]byte, traceSize)
+		rand.Read(trace)
+		searchData := make([]byte, traceSize/10)
+		rand.Read(searchData)
+
+		req.Ids = append(req.Ids, PreallocBytes{Slice: id})
+		req.Traces = append(req.Traces, PreallocBytes{Slice: trace})
+		req.SearchData = append(req.SearchData, PreallocBytes{Slice: searchData})
+	}
+	return req
+}
+
+func TestUnmarshalShared(t *testing.T) {
+	req := makeSharedTestRequest(10, 1000)
+	data, err := req.Marshal()
+	require.NoError(t, err)
+
+	expected := &PushBytesRequest{}
+	require.NoError(t, expected.Unmarshal(data))
+
+	before := SharedBufferBytes()
+
+	actual := &PushBytesRequest{}
+	require.NoError(t, actual.UnmarshalShared(data))
+	require.Len(t, actual.Traces, len(expected.Traces))
+	require.Len(t, actual.Ids, len(expected.Ids))
+	require.Len(t, actual.SearchData, len(expected.SearchData))
+
+	for i := range expected.Traces {
+		assert.Equal(t, expected.Traces[i].Slice, actual.Traces[i].Slice)
+		assert.Equal(t, expected.Ids[i].Slice, actual.Ids[i].Slice)
+		assert.Equal(t, expected.SearchData[i].Slice, actual.SearchData[i].Slice)
+		assert.Nil(t, actual.Ids[i].buf)
+	}
+
+	// traces reference the request buffer, ids are copied
+	assert.True(t, sameBacking(data, actual.Traces[0].Slice))
+	assert.False(t, sameBacking(data, actual.Ids[0].Slice))
+	assert.Equal(t, len(actual.Traces[0].Slice), cap(actual.Traces[0].Slice))
+
+	// the buffer is retained until all slices are released
+	assert.Equal(t, before+int64(len(data)), SharedBufferBytes())
+	actual.Traces[0].Release()
+	assert.Nil(t, actual.Traces[0].Slice)
+	assert.Equal(t, before+int64(len(data)), SharedBufferBytes())
+	actual.Release()
+	assert.Equal(t, before, SharedBufferBytes())
+}
+
+func TestUnmarshalSharedEmpty(t *testing.T) {
+	before := SharedBufferBytes()
+
+	req := &PushBytesRequest{}
+	require.NoError(t, req.UnmarshalShared(nil))
+	assert.Empty(t, req.Traces)
+	assert.Equal(t, before, SharedBufferBytes())
+}
+
+func TestUnmarshalSharedErrors(t *testing.T) {
+	req := makeSharedTestRequest(1, 100)
+	data, err := req.Marshal()
+	require.NoError(t, err)
+
+	before := SharedBufferBytes()
+
+	for _, data := range [][]byte{
+		data[:len(data)-1], // truncated
+		{0x12, 0xff},       // truncated length
+		{0x00},             // illegal tag
+		{0x10, 0x01},       // wrong wire type
+	} {
+		actual := &PushBytesRequest{}
+		assert.Error(t, actual.UnmarshalShared(data))
+		actual.Release()
+	}
+	assert.Equal(t, before, SharedBufferBytes())
+}
+
+func TestReleasePooled(t *testing.T) {
+	b := &PreallocBytes{}
+	require.NoError(t, b.Unmarshal(make([]byte, 10)))
+	b.Release()
+	assert.Nil(t, b.Slice)
+
+	// releasing twice is a noop for pooled slices
+	b.Release()
+}
+
+func TestRef(t *testing.T) {
+	req := makeSharedTestRequest(1, 100)
+	data, err := req.Marshal()
+	require.NoError(t, err)
+
+	before := SharedBufferBytes()
+
+	actual := &PushBytesRequest{}
+	require.NoError(t, actual.UnmarshalShared(data))
+
+	// the copy keeps the buffer alive after the original is released
+	ref := actual.Traces[0].Ref()
+	assert.True(t, sameBacking(data, ref.Slice))
+	actual.Release()
+	assert.Equal(t, before+int64(len(data)), SharedBufferBytes())
+	ref.Release()
+	assert.Equal(t, before, SharedBufferBytes())
+
+	// slices without a buffer are copied
+	pooled := &PreallocBytes{}
+	require.NoError(t, pooled.Unmarshal([]byte{1, 2, 3}))
+	ref = pooled.Ref()
+	assert.Equal(t, pooled.Slice, ref.Slice)
+	assert.False(t, sameBacking(pooled.Slice, ref.Slice))
+	pooled.Release()
+	ref.Release()
+}
+
+// sameBacking returns true if b references memory of a.
+func sameBacking(a, b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	for i := range a {
+		if &a[i] == &b[0] {
+			return true
+		}
+	}
+	return false
+}
+
+func BenchmarkPushBytesRequestUnmarshal(b *testing.B) {
+	req := makeSharedTestRequest(100, 2000)
+	data, err := req.Marshal()
+	require.NoError(b, err)
+
+	b.Run("copy", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			r := &PushBytesRequest{}
+			_ = r.Unmarshal(data)
+			r.Release()
+		}
+	})
+
+	b.Run("shared", func(b *testing.B) {
+		b.ReportAllocs()
+		b.SetBytes(int64(len(data)))
+		for i := 0; i < b.N; i++ {
+			r := &PushBytesRequest{}
+			_ = r.UnmarshalShared(data)
+			r.Release()
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package tempopb
+
+import (
+	"fmt"
+	"io"
+)
+
+// SpanCount returns the number of spans of a marshalled Trace without unmarshalling it. Only the field tags and
+// lengths of batches and instrumentation library spans are read, spans are skipped.
+func SpanCount(data []byte) (int, error) {
+	count := 0
+	err := walkMessageFields(data, 1, func(batch []byte) error {
+		return walkMessageFields(batch, 2, func(ils []byte) error {
+			return walkMessageFields(ils, 2, func([]byte) error {
+				count++
+				return nil
+			})
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// walkMessageFields calls fn for every occurrence of the length delimited field fieldNum of a marshalled message.
+// All other fields are skipped.
+func walkMessageFields(data []byte, fieldNum int32, fn func(b []byte) error) error {
+	l := len(data)
+	idx := 0
+	for idx < l {
+		preIndex := idx
+		wire, n, err := decodeVarint(data[idx:])
+		if err != nil {
+			return err
+		}
+		idx += n
+
+		num := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if num <= 0 {
+			return fmt.Errorf("proto: illegal tag %d (wire type %d)", num, wire)
+		}
+
+		if num != fieldNum {
+			idx = preIndex
+			skippy, err := skipTempo(data[idx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 || idx+skippy < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if idx+skippy > l {
+				return io.ErrUnexpectedEOF
+			}
+			idx += skippy
+			continue
+		}
+
+		if wireType != 2 {
+			return fmt.Errorf("proto: wrong wireType = %d for field %d", wireType, fieldNum)
+		}
+		byteLen, n, err := decodeVarint(data[idx:])
+		if err != nil {
+			return err
+		}
+		idx += n
+
+		end := idx + int(byteLen)
+		if int(byteLen) < 0 || end < 0 {
+			return ErrInvalidLengthTempo
+		}
+		if end > l {
+			return io.ErrUnexpectedEOF
+		}
+		if err := fn(data[idx:end:end]); err != nil {
+			return err
+		}
+		idx = end
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package tempopb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func TestSpanCount(t *testing.T) {
+	tr := &Trace{
+		Batches: []*v1.ResourceSpans{
+			{
+				InstrumentationLibrarySpans: []*v1.InstrumentationLibrarySpans{
+					{Spans: []*v1.Span{{Name: "a"}, {Name: "b"}}},
+					{},
+					{Spans: []*v1.Span{{}}},
+				},
+			},
+			{},
+			{
+				InstrumentationLibrarySpans: []*v1.InstrumentationLibrarySpans{
+					{Spans: []*v1.Span{{Name: "c", TraceId: []byte{1, 2}}}},
+				},
+			},
+		},
+	}
+
+	b, err := tr.Marshal()
+	require.NoError(t, err)
+
+	count, err := SpanCount(b)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+
+	count, err = SpanCount(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	_, err = SpanCount(b[:len(b)-1])
+	assert.Error(t, err)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/tempo/pkg/cache"
 
 	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/querystats"
 	"github.com/grafana/tempo/tempodb/backend"
 )
 
@@ -40,6 +41,7 @@ func (r *readerWriter) Read(ctx context.Context, name string, keypath backend.Ke
 		k = key(keypath, name)
 		found, vals, _ := r.cache.Fetch(ctx, []string{k})
 		if len(found) > 0 {
+			querystats.FromContext(ctx).AddCacheHit()
 			return io.NopCloser(bytes.NewReader(vals[0])), int64(len(vals[0])), nil
 		}
 	}
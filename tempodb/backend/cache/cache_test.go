@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/querystats"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/stretchr/testify/assert"
 )
@@ -101,6 +102,28 @@ func TestReadWrite(t *testing.T) {
 	}
 }
 
+func TestReadStats(t *testing.T) {
+	mockR := &backend.MockRawReader{
+		R: []byte{0x01, 0x02},
+	}
+	r, _, _ := NewCache(backend.NewStatsReader(mockR), &backend.MockRawWriter{}, NewMockClient())
+
+	stats, ctx := querystats.NewContext(context.Background())
+	keypath := backend.KeyPathForBlock(uuid.New(), "test")
+
+	// a miss reads from the backend
+	reader, _, _ := r.Read(ctx, "foo", keypath, true)
+	_, _ = io.ReadAll(reader)
+	assert.Equal(t, uint64(2), stats.Stats().BytesRead)
+	assert.Equal(t, uint64(0), stats.Stats().CacheHits)
+
+	// a hit doesn't
+	reader, _, _ = r.Read(ctx, "foo", keypath, true)
+	_, _ = io.ReadAll(reader)
+	assert.Equal(t, uint64(2), stats.Stats().BytesRead)
+	assert.Equal(t, uint64(1), stats.Stats().CacheHits)
+}
+
 func TestList(t *testing.T) {
 	tenantID := "test"
 	blockID := uuid.New()
@@ -0,0 +1,9 @@
+package diskcache
+
+type Config struct {
+	// Path is the directory ranges are cached in. The disk cache is disabled if empty.
+	Path string `yaml:"path"`
+	// MaxSizeBytes is the maximum size of the cached ranges, least recently used ranges are removed
+	// once it is exceeded.
+	MaxSizeBytes int64 `yaml:"max_size_bytes"`
+}
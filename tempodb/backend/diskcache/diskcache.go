@@ -0,0 +1,293 @@
+package diskcache
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/querystats"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const tmpSuffix = ".tmp"
+
+var (
+	metricHits = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "disk_cache_hits_total",
+		Help:      "Total number of ranges read from the disk cache.",
+	})
+	metricMisses = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "disk_cache_misses_total",
+		Help:      "Total number of ranges missing in the disk cache and read from the backend.",
+	})
+	metricEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "disk_cache_evictions_total",
+		Help:      "Total number of ranges removed from the disk cache to stay below the maximum size.",
+	})
+	metricSizeBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "disk_cache_size_bytes",
+		Help:      "Current size of the ranges in the disk cache.",
+	})
+)
+
+// cachedObjects are the block objects whose ranges are cached. They are never modified once
+// written so cached ranges don't need to be invalidated.
+var cachedObjects = map[string]struct{}{
+	common.NameObjects: {},
+	common.NameIndex:   {},
+	"search":           {},
+	"search-index":     {},
+}
+
+type entry struct {
+	path string
+	size int64
+}
+
+type reader struct {
+	next   backend.RawReader
+	cfg    *Config
+	logger log.Logger
+
+	mtx     sync.Mutex
+	size    int64
+	ll      *list.List
+	entries map[string]*list.Element
+}
+
+var _ backend.RawReader = (*reader)(nil)
+
+// NewReader returns a RawReader caching ranges of block objects read from next in files on
+// local disk. Ranges cached in the same path by a previous process are reused.
+func NewReader(next backend.RawReader, cfg *Config, logger log.Logger) (backend.RawReader, error) {
+	if cfg.MaxSizeBytes <= 0 {
+		return nil, fmt.Errorf("disk cache max_size_bytes should be positive")
+	}
+
+	err := os.MkdirAll(cfg.Path, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reader{
+		next:    next,
+		cfg:     cfg,
+		logger:  logger,
+		ll:      list.New(),
+		entries: map[string]*list.Element{},
+	}
+
+	err = r.load()
+	if err != nil {
+		return nil, fmt.Errorf("error loading disk cache: %w", err)
+	}
+
+	return r, nil
+}
+
+// List implements backend.RawReader
+func (r *reader) List(ctx context.Context, keypath backend.KeyPath) ([]string, error) {
+	return r.next.List(ctx, keypath)
+}
+
+// Read implements backend.RawReader
+func (r *reader) Read(ctx context.Context, name string, keypath backend.KeyPath, shouldCache bool) (io.ReadCloser, int64, error) {
+	return r.next.Read(ctx, name, keypath, shouldCache)
+}
+
+// ReadRange implements backend.RawReader
+func (r *reader) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte) error {
+	if _, ok := cachedObjects[name]; !ok {
+		return r.next.ReadRange(ctx, name, keypath, offset, buffer)
+	}
+
+	path := filepath.Join(r.cfg.Path, filepath.Join(keypath...), fmt.Sprintf("%s-%d-%d", name, offset, len(buffer)))
+	if r.readFile(path, buffer) {
+		metricHits.Inc()
+		querystats.FromContext(ctx).AddCacheHit()
+		return nil
+	}
+	metricMisses.Inc()
+
+	err := r.next.ReadRange(ctx, name, keypath, offset, buffer)
+	if err != nil {
+		return err
+	}
+
+	err = r.writeFile(path, buffer)
+	if err != nil {
+		level.Warn(r.logger).Log("msg", "failed to write range to disk cache", "path", path, "err", err)
+	}
+
+	return nil
+}
+
+// Shutdown implements backend.RawReader
+func (r *reader) Shutdown() {
+	r.next.Shutdown()
+}
+
+// load adds the files found in the cache path, the most recently modified ones are the last to be
+// evicted.
+func (r *reader) load() error {
+	type file struct {
+		entry
+		modTime time.Time
+	}
+	var files []file
+
+	err := filepath.Walk(r.cfg.Path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		// left over by an interrupted write
+		if strings.HasSuffix(path, tmpSuffix) {
+			return os.Remove(path)
+		}
+
+		files = append(files, file{
+			entry:   entry{path: path, size: info.Size()},
+			modTime: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	for _, f := range files {
+		r.add(f.path, f.size)
+	}
+
+	return nil
+}
+
+// readFile reads the cached range at path into buffer. It returns false if the range is not cached.
+func (r *reader) readFile(path string, buffer []byte) bool {
+	r.mtx.Lock()
+	elem, ok := r.entries[path]
+	if ok {
+		r.ll.MoveToFront(elem)
+	}
+	r.mtx.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	f, err := os.Open(path)
+	if err == nil {
+		_, err = io.ReadFull(f, buffer)
+		f.Close()
+	}
+	if err != nil {
+		// the file was evicted or damaged since the lookup
+		r.mtx.Lock()
+		if elem, ok := r.entries[path]; ok {
+			r.remove(elem)
+		}
+		r.mtx.Unlock()
+		return false
+	}
+
+	return true
+}
+
+// writeFile writes buffer to a temporary file renamed to path once complete, so partially written
+// ranges are never read.
+func (r *reader) writeFile(path string, buffer []byte) error {
+	size := int64(len(buffer))
+	if size > r.cfg.MaxSizeBytes {
+		return nil
+	}
+
+	dir := filepath.Dir(path)
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+"-*"+tmpSuffix)
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(buffer)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	r.add(path, size)
+	return nil
+}
+
+// add adds the file at path to the front of the list and evicts the least recently used files
+// until the cache is below the maximum size. Must be called under lock.
+func (r *reader) add(path string, size int64) {
+	if elem, ok := r.entries[path]; ok {
+		r.ll.MoveToFront(elem)
+		return
+	}
+
+	r.entries[path] = r.ll.PushFront(&entry{path: path, size: size})
+	r.size += size
+
+	for r.size > r.cfg.MaxSizeBytes {
+		r.remove(r.ll.Back())
+		metricEvictions.Inc()
+	}
+
+	metricSizeBytes.Set(float64(r.size))
+}
+
+// remove removes the file of elem from the cache. Must be called under lock.
+func (r *reader) remove(elem *list.Element) {
+	e := r.ll.Remove(elem).(*entry)
+	delete(r.entries, e.path)
+	r.size -= e.size
+	metricSizeBytes.Set(float64(r.size))
+
+	err := os.Remove(e.path)
+	if err != nil && !os.IsNotExist(err) {
+		level.Warn(r.logger).Log("msg", "failed to remove range from disk cache", "path", e.path, "err", err)
+	}
+	// removes the block folder once it's empty, fails otherwise
+	_ = os.Remove(filepath.Dir(e.path))
+}
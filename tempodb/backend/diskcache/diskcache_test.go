@@ -0,0 +1,117 @@
+package diskcache
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type mockReader struct {
+	backend.MockRawReader
+	calls int
+}
+
+// ReadRange fills buffer with the offset so ranges can be told apart
+func (m *mockReader) ReadRange(_ context.Context, _ string, _ backend.KeyPath, offset uint64, buffer []byte) error {
+	m.calls++
+	for i := range buffer {
+		buffer[i] = byte(offset)
+	}
+	return nil
+}
+
+func TestReadRange(t *testing.T) {
+	next := &mockReader{}
+	r, err := NewReader(next, &Config{Path: t.TempDir(), MaxSizeBytes: 100}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	keypath := backend.KeyPath{"tenant", "block"}
+	for i := 0; i < 2; i++ {
+		buffer := make([]byte, 10)
+		require.NoError(t, r.ReadRange(context.Background(), "data", keypath, 5, buffer))
+		assert.Equal(t, []byte{5, 5, 5, 5, 5, 5, 5, 5, 5, 5}, buffer)
+	}
+	assert.Equal(t, 1, next.calls)
+
+	// other lengths and offsets are different ranges
+	require.NoError(t, r.ReadRange(context.Background(), "data", keypath, 5, make([]byte, 5)))
+	require.NoError(t, r.ReadRange(context.Background(), "data", keypath, 6, make([]byte, 10)))
+	assert.Equal(t, 3, next.calls)
+
+	// objects other than data, index and search are not cached
+	for i := 0; i < 2; i++ {
+		require.NoError(t, r.ReadRange(context.Background(), "bloom-0", keypath, 0, make([]byte, 10)))
+	}
+	assert.Equal(t, 5, next.calls)
+}
+
+func TestReadRange_Eviction(t *testing.T) {
+	next := &mockReader{}
+	path := t.TempDir()
+	r, err := NewReader(next, &Config{Path: path, MaxSizeBytes: 20}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	keypath := backend.KeyPath{"tenant", "block"}
+	read := func(offset uint64) {
+		require.NoError(t, r.ReadRange(context.Background(), "index", keypath, offset, make([]byte, 10)))
+	}
+
+	read(0)
+	read(1)
+	read(0) // 0 is now the most recently used
+	read(2) // evicts 1
+	assert.Equal(t, 3, next.calls)
+
+	read(0)
+	read(2)
+	assert.Equal(t, 3, next.calls)
+
+	_, err = os.Stat(filepath.Join(path, "tenant", "block", "index-1-10"))
+	assert.True(t, os.IsNotExist(err))
+
+	read(1)
+	assert.Equal(t, 4, next.calls)
+
+	// larger ranges than the cache are not stored
+	require.NoError(t, r.ReadRange(context.Background(), "index", keypath, 0, make([]byte, 30)))
+	require.NoError(t, r.ReadRange(context.Background(), "index", keypath, 0, make([]byte, 30)))
+	assert.Equal(t, 6, next.calls)
+}
+
+func TestReadRange_Reload(t *testing.T) {
+	path := t.TempDir()
+	keypath := backend.KeyPath{"tenant", "block"}
+
+	next := &mockReader{}
+	r, err := NewReader(next, &Config{Path: path, MaxSizeBytes: 100}, log.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, r.ReadRange(context.Background(), "search", keypath, 3, make([]byte, 10)))
+
+	// an interrupted write is cleaned up
+	tmp := filepath.Join(path, "tenant", "block", "search-4-10-1234"+tmpSuffix)
+	require.NoError(t, os.WriteFile(tmp, make([]byte, 10), 0644))
+
+	next = &mockReader{}
+	r, err = NewReader(next, &Config{Path: path, MaxSizeBytes: 100}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	buffer := make([]byte, 10)
+	require.NoError(t, r.ReadRange(context.Background(), "search", keypath, 3, buffer))
+	assert.Equal(t, []byte{3, 3, 3, 3, 3, 3, 3, 3, 3, 3}, buffer)
+	assert.Equal(t, 0, next.calls)
+
+	_, err = os.Stat(tmp)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestNewReader_Invalid(t *testing.T) {
+	_, err := NewReader(&mockReader{}, &Config{Path: t.TempDir()}, log.NewNopLogger())
+	assert.Error(t, err)
+}
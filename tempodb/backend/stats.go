@@ -0,0 +1,72 @@
+package backend
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/grafana/tempo/pkg/querystats"
+)
+
+type statsReader struct {
+	next RawReader
+}
+
+// NewStatsReader returns a RawReader that records the bytes read from next and the time spent reading them in the
+// query statistics of the context. It must wrap the backend itself, below any caches, so that only reads that
+// actually hit the backend are recorded. Caches record their hits separately.
+func NewStatsReader(next RawReader) RawReader {
+	return &statsReader{
+		next: next,
+	}
+}
+
+// List implements RawReader
+func (r *statsReader) List(ctx context.Context, keypath KeyPath) ([]string, error) {
+	return r.next.List(ctx, keypath)
+}
+
+// Read implements RawReader
+func (r *statsReader) Read(ctx context.Context, name string, keypath KeyPath, shouldCache bool) (io.ReadCloser, int64, error) {
+	stats := querystats.FromContext(ctx)
+	if stats == nil {
+		return r.next.Read(ctx, name, keypath, shouldCache)
+	}
+
+	start := time.Now()
+	object, size, err := r.next.Read(ctx, name, keypath, shouldCache)
+	if err != nil {
+		return nil, 0, err
+	}
+	stats.AddRead(0, time.Since(start))
+
+	return &statsReadCloser{ReadCloser: object, stats: stats}, size, nil
+}
+
+// ReadRange implements RawReader
+func (r *statsReader) ReadRange(ctx context.Context, name string, keypath KeyPath, offset uint64, buffer []byte) error {
+	start := time.Now()
+	err := r.next.ReadRange(ctx, name, keypath, offset, buffer)
+	if err == nil {
+		querystats.FromContext(ctx).AddRead(len(buffer), time.Since(start))
+	}
+	return err
+}
+
+// Shutdown implements RawReader
+func (r *statsReader) Shutdown() {
+	r.next.Shutdown()
+}
+
+// statsReadCloser records the bytes read from an object stream and the time spent reading them.
+type statsReadCloser struct {
+	io.ReadCloser
+	stats *querystats.Collector
+}
+
+func (r *statsReadCloser) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := r.ReadCloser.Read(p)
+	r.stats.AddRead(n, time.Since(start))
+	return n, err
+}
@@ -0,0 +1,43 @@
+package backend
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/querystats"
+)
+
+func TestStatsReader(t *testing.T) {
+	m := &MockRawReader{
+		R:     []byte{0x01, 0x02, 0x03},
+		Range: []byte{0x01, 0x02},
+	}
+	r := NewStatsReader(m)
+
+	stats, ctx := querystats.NewContext(context.Background())
+
+	obj, size, err := r.Read(ctx, "test", KeyPath{"test"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), size)
+	b, err := io.ReadAll(obj)
+	require.NoError(t, err)
+	assert.Equal(t, m.R, b)
+	require.NoError(t, obj.Close())
+	assert.Equal(t, uint64(3), stats.Stats().BytesRead)
+
+	buffer := make([]byte, 2)
+	require.NoError(t, r.ReadRange(ctx, "test", KeyPath{"test"}, 0, buffer))
+	assert.Equal(t, uint64(5), stats.Stats().BytesRead)
+	assert.NotZero(t, stats.Stats().IoDurationNanos)
+
+	// reads without a collector are not recorded
+	obj, _, err = r.Read(context.Background(), "test", KeyPath{"test"}, false)
+	require.NoError(t, err)
+	_, err = io.ReadAll(obj)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), stats.Stats().BytesRead)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/azure"
 	"github.com/grafana/tempo/tempodb/backend/cache/memcached"
 	"github.com/grafana/tempo/tempodb/backend/cache/redis"
+	"github.com/grafana/tempo/tempodb/backend/diskcache"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
@@ -48,13 +49,18 @@ type Config struct {
 	S3      *s3.Config    `yaml:"s3"`
 	Azure   *azure.Config `yaml:"azure"`
 
-	// caches
+	// caches, Cache is a comma separated list of lru, memcached and redis ordered from the first
+	// cache to query to the last
 	Cache                   string                  `yaml:"cache"`
 	CacheMinCompactionLevel uint8                   `yaml:"cache_min_compaction_level"`
 	CacheMaxBlockAge        time.Duration           `yaml:"cache_max_block_age"`
 	BackgroundCache         *cache.BackgroundConfig `yaml:"background_cache"`
 	Memcached               *memcached.Config       `yaml:"memcached"`
 	Redis                   *redis.Config           `yaml:"redis"`
+	LRU                     *cache.LRUConfig        `yaml:"lru"`
+
+	// DiskCache caches ranges of blocks read by queries on local disk
+	DiskCache *diskcache.Config `yaml:"disk_cache"`
 }
 
 type SearchConfig struct {
@@ -10,6 +10,7 @@ import (
 	willf_bloom "github.com/willf/bloom"
 
 	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/querystats"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
@@ -63,6 +64,7 @@ func (b *BackendBlock) find(ctx context.Context, id common.ID) ([]byte, error) {
 		return nil, fmt.Errorf("error parsing bloom (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
 	}
 
+	querystats.FromContext(ctx).AddBloomTest()
 	if !filter.Test(id) {
 		return nil, nil
 	}
@@ -91,6 +93,96 @@ func (b *BackendBlock) find(ctx context.Context, id common.ID) ([]byte, error) {
 	return objectBytes, nil
 }
 
+// FindTracesByID looks up a batch of trace IDs in the block. Each bloom filter shard is fetched once for all IDs
+// and only the IDs passing it are looked up in the index. The returned traces are aligned with ids, nil if the
+// trace is not in the block.
+func (b *BackendBlock) FindTracesByID(ctx context.Context, ids []common.ID) ([]*tempopb.Trace, error) {
+	var err error
+	span, ctx := opentracing.StartSpanFromContext(ctx, "BackendBlock.FindTracesByID")
+	defer func() {
+		if err != nil {
+			span.SetTag("error", true)
+		}
+		span.Finish()
+	}()
+
+	span.SetTag("block", b.meta.BlockID.String())
+	span.SetTag("ids", len(ids))
+
+	// group the ids by bloom shard
+	shards := map[int][]int{}
+	for i, id := range ids {
+		shardKey := common.ShardKeyForTraceID(id, int(b.meta.BloomShardCount))
+		shards[shardKey] = append(shards[shardKey], i)
+	}
+
+	stats := querystats.FromContext(ctx)
+	var candidates []int
+	for shardKey, idxs := range shards {
+		var bloomBytes []byte
+		bloomBytes, err = b.reader.Read(ctx, common.BloomName(shardKey), b.meta.BlockID, b.meta.TenantID, true)
+		if err != nil {
+			return nil, fmt.Errorf("error retrieving bloom (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+		}
+
+		filter := &willf_bloom.BloomFilter{}
+		_, err = filter.ReadFrom(bytes.NewReader(bloomBytes))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing bloom (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+		}
+
+		for _, i := range idxs {
+			stats.AddBloomTest()
+			if filter.Test(ids[i]) {
+				candidates = append(candidates, i)
+			}
+		}
+	}
+
+	traces := make([]*tempopb.Trace, len(ids))
+	if len(candidates) == 0 {
+		return traces, nil
+	}
+
+	indexReaderAt := backend.NewContextReader(b.meta, common.NameIndex, b.reader, false)
+	indexReader, err := NewIndexReader(indexReaderAt, int(b.meta.IndexPageSize), int(b.meta.TotalRecords))
+	if err != nil {
+		return nil, fmt.Errorf("error building index reader (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+	}
+
+	ra := backend.NewContextReader(b.meta, common.NameObjects, b.reader, false)
+	dataReader, err := NewDataReader(ra, b.meta.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("error building page reader (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+	}
+	defer dataReader.Close()
+
+	dec, err := model.NewObjectDecoder(b.meta.DataEncoding)
+	if err != nil {
+		return nil, err
+	}
+
+	// passing nil for objectCombiner here.  this is fine b/c a backend block should never have dupes
+	finder := NewPagedFinder(indexReader, dataReader, nil, NewObjectReaderWriter(), b.meta.DataEncoding)
+	for _, i := range candidates {
+		var obj []byte
+		obj, err = finder.Find(ctx, ids[i])
+		if err != nil {
+			return nil, fmt.Errorf("error using pageFinder (%s, %s): %w", b.meta.TenantID, b.meta.BlockID, err)
+		}
+		if obj == nil {
+			continue
+		}
+
+		traces[i], err = dec.PrepareForRead(obj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return traces, nil
+}
+
 // Iterator returns an Iterator that iterates over the objects in the block from the backend
 func (b *BackendBlock) Iterator(chunkSizeBytes uint32) (Iterator, error) {
 	// read index
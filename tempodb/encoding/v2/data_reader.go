@@ -5,8 +5,10 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/querystats"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/klauspost/compress/zstd"
@@ -102,6 +104,7 @@ func (r *dataReader) Read(ctx context.Context, records []common.Record, pagesBuf
 	}
 
 	// now decompress
+	decodeStart := time.Now()
 	for i, page := range compressedPages {
 		reader, err := r.getCompressedReader(page)
 		if err != nil {
@@ -120,6 +123,7 @@ func (r *dataReader) Read(ctx context.Context, records []common.Record, pagesBuf
 			return nil, nil, err
 		}
 	}
+	querystats.FromContext(ctx).AddPagesDecoded(len(compressedPages), time.Since(decodeStart))
 
 	return pagesBuffer, buffer, nil
 }
@@ -6,6 +6,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"strings"
 	"time"
 
 	gkLog "github.com/go-kit/log"
@@ -18,6 +19,7 @@ import (
 
 	pkg_cache "github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/querystats"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -25,6 +27,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/cache"
 	"github.com/grafana/tempo/tempodb/backend/cache/memcached"
 	"github.com/grafana/tempo/tempodb/backend/cache/redis"
+	"github.com/grafana/tempo/tempodb/backend/diskcache"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
@@ -75,11 +78,18 @@ type Writer interface {
 	WAL() *wal.WAL
 }
 
+// IterateObjectCallback is called for every object of a block. Returning false stops the iteration.
 type IterateObjectCallback func(id common.ID, obj []byte) bool
 
 type Reader interface {
-	Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string) ([]*tempopb.Trace, []error, error)
+	// Find returns the partial traces of id found in the blocks between blockStart and blockEnd. timeStart and timeEnd are
+	// optional hints in unix epoch seconds, blocks not overlapping them are skipped. Pass 0 to leave them unbounded.
+	Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64) ([]*tempopb.Trace, []error, error)
+	// FindMany is Find for a batch of ids. Each block is searched once for all the ids it may contain. The partial
+	// traces and the errors of the blocks searched are aligned with ids.
+	FindMany(ctx context.Context, tenantID string, ids []common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64) ([][]*tempopb.Trace, [][]error, error)
 	Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error)
+	IterateObjects(ctx context.Context, metas []*backend.BlockMeta, chunkSizeBytes uint32, callback IterateObjectCallback) error
 	BlockMetas(tenantID string) []*backend.BlockMeta
 	EnablePolling(sharder blocklist.JobSharder)
 
@@ -112,6 +122,10 @@ type readerWriter struct {
 	uncachedReader backend.Reader
 	uncachedWriter backend.Writer
 
+	// queryReader is r with ranges additionally cached on disk if configured. It is used to find
+	// and search blocks that qualify for caching.
+	queryReader backend.Reader
+
 	wal  *wal.WAL
 	pool *pool.Pool
 
@@ -155,16 +169,15 @@ func New(cfg *Config, logger gkLog.Logger) (Reader, Writer, Compactor, error) {
 		return nil, nil, nil, err
 	}
 
+	// query statistics only count the reads that miss all caches
+	rawR = backend.NewStatsReader(rawR)
+
 	uncachedReader := backend.NewReader(rawR)
 	uncachedWriter := backend.NewWriter(rawW)
 
-	var cacheBackend pkg_cache.Cache
-
-	switch cfg.Cache {
-	case "redis":
-		cacheBackend = redis.NewClient(cfg.Redis, cfg.BackgroundCache, logger)
-	case "memcached":
-		cacheBackend = memcached.NewClient(cfg.Memcached, cfg.BackgroundCache, logger)
+	cacheBackend, err := newCacheBackend(cfg, prometheus.DefaultRegisterer, logger)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
 	if cacheBackend != nil {
@@ -176,11 +189,22 @@ func New(cfg *Config, logger gkLog.Logger) (Reader, Writer, Compactor, error) {
 
 	r := backend.NewReader(rawR)
 	w := backend.NewWriter(rawW)
+
+	queryReader := r
+	if cfg.DiskCache != nil && cfg.DiskCache.Path != "" {
+		diskR, err := diskcache.NewReader(rawR, cfg.DiskCache, logger)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		queryReader = backend.NewReader(diskR)
+	}
+
 	rw := &readerWriter{
 		c:              c,
 		r:              r,
 		uncachedReader: uncachedReader,
 		uncachedWriter: uncachedWriter,
+		queryReader:    queryReader,
 		w:              w,
 		cfg:            cfg,
 		logger:         logger,
@@ -285,25 +309,13 @@ func (rw *readerWriter) BlockMetas(tenantID string) []*backend.BlockMeta {
 	return rw.blocklist.Metas(tenantID)
 }
 
-func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string) ([]*tempopb.Trace, []error, error) {
+func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64) ([]*tempopb.Trace, []error, error) {
 	// tracing instrumentation
 	logger := log.WithContext(ctx, log.Logger)
 	span, ctx := opentracing.StartSpanFromContext(ctx, "store.Find")
 	defer span.Finish()
 
-	blockStartUUID, err := uuid.Parse(blockStart)
-	if err != nil {
-		return nil, nil, err
-	}
-	blockStartBytes, err := blockStartUUID.MarshalBinary()
-	if err != nil {
-		return nil, nil, err
-	}
-	blockEndUUID, err := uuid.Parse(blockEnd)
-	if err != nil {
-		return nil, nil, err
-	}
-	blockEndBytes, err := blockEndUUID.MarshalBinary()
+	blockStartBytes, blockEndBytes, err := parseBlockBoundaries(blockStart, blockEnd)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -316,13 +328,13 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 	compactedBlocksSearched := 0
 
 	for _, b := range blocklist {
-		if includeBlock(b, id, blockStartBytes, blockEndBytes) {
+		if includeBlock(b, id, blockStartBytes, blockEndBytes, timeStart, timeEnd) {
 			copiedBlocklist = append(copiedBlocklist, b)
 			blocksSearched++
 		}
 	}
 	for _, c := range compactedBlocklist {
-		if includeCompactedBlock(c, id, blockStartBytes, blockEndBytes, rw.cfg.BlocklistPoll) {
+		if includeCompactedBlock(c, id, blockStartBytes, blockEndBytes, timeStart, timeEnd, rw.cfg.BlocklistPoll) {
 			copiedBlocklist = append(copiedBlocklist, &c.BlockMeta)
 			compactedBlocksSearched++
 		}
@@ -334,6 +346,7 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 	curTime := time.Now()
 	partialTraces, funcErrs, err := rw.pool.RunJobs(ctx, copiedBlocklist, func(ctx context.Context, payload interface{}) (interface{}, error) {
 		meta := payload.(*backend.BlockMeta)
+		querystats.FromContext(ctx).AddBlocksFetched(1)
 		r := rw.getReaderForBlock(meta, curTime)
 		block, err := v2.NewBackendBlock(meta, r)
 		if err != nil {
@@ -363,21 +376,186 @@ func (rw *readerWriter) Find(ctx context.Context, tenantID string, id common.ID,
 	return partialTraceObjs, funcErrs, err
 }
 
+// findManyJob is a block to search for the ids at idxs of a FindMany call.
+type findManyJob struct {
+	meta *backend.BlockMeta
+	idxs []int
+}
+
+// findManyResult holds the traces found by a findManyJob, aligned with its idxs, or the error
+// searching the block.
+type findManyResult struct {
+	idxs   []int
+	traces []*tempopb.Trace
+	err    error
+}
+
+func (rw *readerWriter) FindMany(ctx context.Context, tenantID string, ids []common.ID, blockStart string, blockEnd string, timeStart int64, timeEnd int64) ([][]*tempopb.Trace, [][]error, error) {
+	// tracing instrumentation
+	logger := log.WithContext(ctx, log.Logger)
+	span, ctx := opentracing.StartSpanFromContext(ctx, "store.FindMany")
+	defer span.Finish()
+
+	blockStartBytes, blockEndBytes, err := parseBlockBoundaries(blockStart, blockEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	partialTraces := make([][]*tempopb.Trace, len(ids))
+	blockErrs := make([][]error, len(ids))
+
+	// gather appropriate blocks along with the ids each of them may contain
+	blocklist := rw.blocklist.Metas(tenantID)
+	compactedBlocklist := rw.blocklist.CompactedMetas(tenantID)
+	jobs := make([]interface{}, 0, len(blocklist))
+
+	for _, b := range blocklist {
+		var idxs []int
+		for i, id := range ids {
+			if includeBlock(b, id, blockStartBytes, blockEndBytes, timeStart, timeEnd) {
+				idxs = append(idxs, i)
+			}
+		}
+		if len(idxs) > 0 {
+			jobs = append(jobs, &findManyJob{meta: b, idxs: idxs})
+		}
+	}
+	for _, c := range compactedBlocklist {
+		var idxs []int
+		for i, id := range ids {
+			if includeCompactedBlock(c, id, blockStartBytes, blockEndBytes, timeStart, timeEnd, rw.cfg.BlocklistPoll) {
+				idxs = append(idxs, i)
+			}
+		}
+		if len(idxs) > 0 {
+			jobs = append(jobs, &findManyJob{meta: &c.BlockMeta, idxs: idxs})
+		}
+	}
+	if len(jobs) == 0 {
+		return partialTraces, blockErrs, nil
+	}
+
+	curTime := time.Now()
+	results, _, err := rw.pool.RunJobs(ctx, jobs, func(ctx context.Context, payload interface{}) (interface{}, error) {
+		job := payload.(*findManyJob)
+		querystats.FromContext(ctx).AddBlocksFetched(1)
+
+		// errors are returned in the result so they can be attributed to the ids of the job
+		block, err := v2.NewBackendBlock(job.meta, rw.getReaderForBlock(job.meta, curTime))
+		if err != nil {
+			return &findManyResult{idxs: job.idxs, err: err}, nil
+		}
+
+		blockIDs := make([]common.ID, len(job.idxs))
+		for i, idx := range job.idxs {
+			blockIDs[i] = ids[idx]
+		}
+
+		traces, err := block.FindTracesByID(ctx, blockIDs)
+		if err != nil {
+			return &findManyResult{idxs: job.idxs, err: err}, nil
+		}
+
+		level.Info(logger).Log("msg", "searching for traces in block", "ids", len(blockIDs), "block", job.meta.BlockID)
+		return &findManyResult{idxs: job.idxs, traces: traces}, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, r := range results {
+		result := r.(*findManyResult)
+		for i, idx := range result.idxs {
+			if result.err != nil {
+				blockErrs[idx] = append(blockErrs[idx], result.err)
+				continue
+			}
+			if result.traces[i] != nil {
+				partialTraces[idx] = append(partialTraces[idx], result.traces[i])
+			}
+		}
+	}
+
+	span.SetTag("ids", len(ids))
+	span.SetTag("blocksSearched", len(jobs))
+
+	return partialTraces, blockErrs, nil
+}
+
 // Search the given block.  This method takes the pre-loaded block meta instead of a block ID, which
 // eliminates a read per search request.
 func (rw *readerWriter) Search(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error) {
-	block, err := v2.NewBackendBlock(meta, rw.r)
+	// blocks that don't qualify for caching are still read through the memcached or redis cache, as
+	// the search objects were before, only the disk cache is limited to blocks qualifying for caching
+	r := rw.r
+	if rw.shouldCache(meta, time.Now()) {
+		r = rw.queryReader
+	}
+
+	block, err := v2.NewBackendBlock(meta, r)
 	if err != nil {
 		return nil, err
 	}
 
+	querystats.FromContext(ctx).AddBlocksFetched(1)
 	return block.Search(ctx, req, opts)
 }
 
+// IterateObjects calls callback for every object in the given blocks, in ID order. Objects found in more
+// than one block are combined and passed once. All blocks must have the same data encoding.
+func (rw *readerWriter) IterateObjects(ctx context.Context, metas []*backend.BlockMeta, chunkSizeBytes uint32, callback IterateObjectCallback) error {
+	if len(metas) == 0 {
+		return nil
+	}
+
+	iters := make([]v2.Iterator, 0, len(metas))
+	defer func() {
+		for _, iter := range iters {
+			iter.Close()
+		}
+	}()
+
+	dataEncoding := metas[0].DataEncoding
+	for _, meta := range metas {
+		if meta.DataEncoding != dataEncoding {
+			return fmt.Errorf("block %s has data encoding %s, expected %s", meta.BlockID, meta.DataEncoding, dataEncoding)
+		}
+
+		block, err := v2.NewBackendBlock(meta, rw.r)
+		if err != nil {
+			return err
+		}
+
+		iter, err := block.Iterator(chunkSizeBytes)
+		if err != nil {
+			return err
+		}
+		iters = append(iters, iter)
+	}
+
+	iter := v2.NewMultiblockIterator(ctx, iters, DefaultIteratorBufferSize, model.StaticCombiner, dataEncoding, rw.logger)
+	defer iter.Close()
+
+	for {
+		id, obj, err := iter.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error iterating blocks: %w", err)
+		}
+
+		if !callback(id, obj) {
+			return nil
+		}
+	}
+}
+
 func (rw *readerWriter) Shutdown() {
 	// todo: stop blocklist poll
 	rw.pool.Shutdown()
-	rw.r.Shutdown()
+	// the query reader wraps r
+	rw.queryReader.Shutdown()
 }
 
 // EnableCompaction activates the compaction/retention loops
@@ -458,6 +636,58 @@ func (rw *readerWriter) pollBlocklist() {
 	rw.blocklist.ApplyPollResults(blocklist, compactedBlocklist)
 }
 
+// newCacheBackend returns the caches configured in cfg.Cache, combined in a tiered cache if more
+// than one is configured. nil is returned if no cache is configured. A single cache is named tempo,
+// the tiers of a tiered cache are named tempo-<cache> so their metrics don't collide.
+func newCacheBackend(cfg *Config, reg prometheus.Registerer, logger gkLog.Logger) (pkg_cache.Cache, error) {
+	if cfg.Cache == "" {
+		return nil, nil
+	}
+
+	names := strings.Split(cfg.Cache, ",")
+	seen := map[string]bool{}
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+		if seen[name] {
+			return nil, fmt.Errorf("cache %s is configured more than once", name)
+		}
+		seen[name] = true
+		names[i] = name
+	}
+
+	var caches []pkg_cache.Cache
+	for _, name := range names {
+		metricsName := "tempo"
+		if len(names) > 1 {
+			metricsName = "tempo-" + name
+		}
+
+		switch name {
+		case "lru":
+			if cfg.LRU == nil {
+				stopCaches(caches)
+				return nil, errors.New("lru config should be non-nil")
+			}
+			caches = append(caches, pkg_cache.NewLRU(*cfg.LRU, metricsName, reg))
+		case "redis":
+			caches = append(caches, redis.NewClient(cfg.Redis, cfg.BackgroundCache, metricsName, reg, logger))
+		case "memcached":
+			caches = append(caches, memcached.NewClient(cfg.Memcached, cfg.BackgroundCache, metricsName, reg, logger))
+		default:
+			stopCaches(caches)
+			return nil, fmt.Errorf("unknown cache %s, expected lru, memcached or redis", name)
+		}
+	}
+
+	return pkg_cache.NewTiered(caches), nil
+}
+
+func stopCaches(caches []pkg_cache.Cache) {
+	for _, c := range caches {
+		c.Stop()
+	}
+}
+
 func (rw *readerWriter) shouldCache(meta *backend.BlockMeta, curTime time.Time) bool {
 	// compaction level is _atleast_ CacheMinCompactionLevel
 	if rw.cfg.CacheMinCompactionLevel > 0 && meta.CompactionLevel < rw.cfg.CacheMinCompactionLevel {
@@ -474,7 +704,7 @@ func (rw *readerWriter) shouldCache(meta *backend.BlockMeta, curTime time.Time)
 
 func (rw *readerWriter) getReaderForBlock(meta *backend.BlockMeta, curTime time.Time) backend.Reader {
 	if rw.shouldCache(meta, curTime) {
-		return rw.r
+		return rw.queryReader
 	}
 
 	return rw.uncachedReader
@@ -488,12 +718,41 @@ func (rw *readerWriter) getWriterForBlock(meta *backend.BlockMeta, curTime time.
 	return rw.uncachedWriter
 }
 
+// parseBlockBoundaries parses the blockStart and blockEnd uuids bounding a search.
+func parseBlockBoundaries(blockStart string, blockEnd string) ([]byte, []byte, error) {
+	blockStartUUID, err := uuid.Parse(blockStart)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockStartBytes, err := blockStartUUID.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	blockEndUUID, err := uuid.Parse(blockEnd)
+	if err != nil {
+		return nil, nil, err
+	}
+	blockEndBytes, err := blockEndUUID.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	return blockStartBytes, blockEndBytes, nil
+}
+
 // includeBlock indicates whether a given block should be included in a backend search
-func includeBlock(b *backend.BlockMeta, id common.ID, blockStart []byte, blockEnd []byte) bool {
+func includeBlock(b *backend.BlockMeta, id common.ID, blockStart []byte, blockEnd []byte, timeStart int64, timeEnd int64) bool {
 	if bytes.Compare(id, b.MinID) == -1 || bytes.Compare(id, b.MaxID) == 1 {
 		return false
 	}
 
+	// check block overlaps the time range hints, 0 is unbounded
+	if timeStart != 0 && b.EndTime.Unix() < timeStart {
+		return false
+	}
+	if timeEnd != 0 && b.StartTime.Unix() > timeEnd {
+		return false
+	}
+
 	blockIDBytes, _ := b.BlockID.MarshalBinary()
 	// check block is in shard boundaries
 	// blockStartBytes <= blockIDBytes <= blockEndBytes
@@ -505,10 +764,10 @@ func includeBlock(b *backend.BlockMeta, id common.ID, blockStart []byte, blockEn
 }
 
 // if block is compacted within lookback period, and is within shard ranges, include it in search
-func includeCompactedBlock(c *backend.CompactedBlockMeta, id common.ID, blockStart []byte, blockEnd []byte, poll time.Duration) bool {
+func includeCompactedBlock(c *backend.CompactedBlockMeta, id common.ID, blockStart []byte, blockEnd []byte, timeStart int64, timeEnd int64, poll time.Duration) bool {
 	lookback := time.Now().Add(-(2 * poll))
 	if c.CompactedTime.Before(lookback) {
 		return false
 	}
-	return includeBlock(&c.BlockMeta, id, blockStart, blockEnd)
+	return includeBlock(&c.BlockMeta, id, blockStart, blockEnd, timeStart, timeEnd)
 }
@@ -11,13 +11,17 @@ import (
 	"github.com/go-kit/log"
 	"github.com/golang/protobuf/proto"
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	pkg_cache "github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/querystats"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/cache/redis"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/wal"
@@ -91,11 +95,69 @@ func TestDB(t *testing.T) {
 
 	// read
 	for i, id := range ids {
-		bFound, failedBlocks, err := r.Find(context.Background(), testTenantID, id, BlockIDMin, BlockIDMax)
+		stats, ctx := querystats.NewContext(context.Background())
+		bFound, failedBlocks, err := r.Find(ctx, testTenantID, id, BlockIDMin, BlockIDMax, 0, 0)
 		assert.NoError(t, err)
 		assert.Nil(t, failedBlocks)
 		assert.True(t, proto.Equal(bFound[0], reqs[i]))
+
+		queryStats := stats.Stats()
+		assert.Equal(t, uint32(1), queryStats.BlocksFetched)
+		assert.Equal(t, uint64(1), queryStats.BloomTests)
+		assert.NotZero(t, queryStats.BytesRead)
+		assert.NotZero(t, queryStats.PagesDecoded)
+	}
+}
+
+func TestDBFindMany(t *testing.T) {
+	r, w, _, _ := testConfig(t, backend.EncGZIP, 0)
+
+	r.EnablePolling(&mockJobSharder{})
+
+	wal := w.WAL()
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	// write two blocks
+	numMsgs := 5
+	var ids []common.ID
+	var reqs []*tempopb.Trace
+	for b := 0; b < 2; b++ {
+		head, err := wal.NewBlock(uuid.New(), testTenantID, model.CurrentEncoding)
+		require.NoError(t, err)
+
+		for i := 0; i < numMsgs; i++ {
+			id := test.ValidTraceID(nil)
+			req := test.MakeTrace(10, id)
+			writeTraceToWal(t, head, dec, id, req, 0, 0)
+			ids = append(ids, id)
+			reqs = append(reqs, req)
+		}
+
+		_, err = w.CompleteBlock(head, &mockCombiner{})
+		require.NoError(t, err)
 	}
+
+	// poll
+	r.(*readerWriter).pollBlocklist()
+
+	// read all ids and one missing
+	missingID := test.ValidTraceID(nil)
+	stats, ctx := querystats.NewContext(context.Background())
+	found, failedBlocks, err := r.FindMany(ctx, testTenantID, append(ids, missingID), BlockIDMin, BlockIDMax, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, found, len(ids)+1)
+	require.Len(t, failedBlocks, len(ids)+1)
+
+	for i := range ids {
+		assert.Nil(t, failedBlocks[i])
+		require.Len(t, found[i], 1)
+		assert.True(t, proto.Equal(found[i][0], reqs[i]))
+	}
+	assert.Nil(t, found[len(ids)])
+	assert.Nil(t, failedBlocks[len(ids)])
+
+	// each block is fetched once for all ids
+	assert.Equal(t, uint32(2), stats.Stats().BlocksFetched)
 }
 
 func TestBlockSharding(t *testing.T) {
@@ -133,7 +195,7 @@ func TestBlockSharding(t *testing.T) {
 	// check if it respects the blockstart/blockend params - case1: hit
 	blockStart := uuid.MustParse(BlockIDMin).String()
 	blockEnd := uuid.MustParse(BlockIDMax).String()
-	bFound, failedBlocks, err := r.Find(context.Background(), testTenantID, id, blockStart, blockEnd)
+	bFound, failedBlocks, err := r.Find(context.Background(), testTenantID, id, blockStart, blockEnd, 0, 0)
 	assert.NoError(t, err)
 	assert.Nil(t, failedBlocks)
 	assert.Greater(t, len(bFound), 0)
@@ -142,7 +204,7 @@ func TestBlockSharding(t *testing.T) {
 	// check if it respects the blockstart/blockend params - case2: miss
 	blockStart = uuid.MustParse(BlockIDMin).String()
 	blockEnd = uuid.MustParse(BlockIDMin).String()
-	bFound, failedBlocks, err = r.Find(context.Background(), testTenantID, id, blockStart, blockEnd)
+	bFound, failedBlocks, err = r.Find(context.Background(), testTenantID, id, blockStart, blockEnd, 0, 0)
 	assert.NoError(t, err)
 	assert.Nil(t, failedBlocks)
 	assert.Len(t, bFound, 0)
@@ -151,7 +213,7 @@ func TestBlockSharding(t *testing.T) {
 func TestNilOnUnknownTenantID(t *testing.T) {
 	r, _, _, _ := testConfig(t, backend.EncLZ4_256k, 0)
 
-	buff, failedBlocks, err := r.Find(context.Background(), "unknown", []byte{0x01}, BlockIDMin, BlockIDMax)
+	buff, failedBlocks, err := r.Find(context.Background(), "unknown", []byte{0x01}, BlockIDMin, BlockIDMax, 0, 0)
 	assert.Nil(t, buff)
 	assert.Nil(t, err)
 	assert.Nil(t, failedBlocks)
@@ -230,6 +292,8 @@ func TestIncludeBlock(t *testing.T) {
 		searchID   common.ID
 		blockStart uuid.UUID
 		blockEnd   uuid.UUID
+		timeStart  int64
+		timeEnd    int64
 		meta       *backend.BlockMeta
 		expected   bool
 	}{
@@ -306,6 +370,37 @@ func TestIncludeBlock(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name:       "include - time range",
+			searchID:   []byte{0x05},
+			blockStart: uuid.MustParse(BlockIDMin),
+			blockEnd:   uuid.MustParse(BlockIDMax),
+			timeStart:  20,
+			timeEnd:    30,
+			meta: &backend.BlockMeta{
+				BlockID:   uuid.MustParse("50000000-0000-0000-0000-000000000000"),
+				MinID:     []byte{0x00},
+				MaxID:     []byte{0x10},
+				StartTime: time.Unix(10, 0),
+				EndTime:   time.Unix(20, 0),
+			},
+			expected: true,
+		},
+		{
+			name:       "include - open time range",
+			searchID:   []byte{0x05},
+			blockStart: uuid.MustParse(BlockIDMin),
+			blockEnd:   uuid.MustParse(BlockIDMax),
+			timeStart:  15,
+			meta: &backend.BlockMeta{
+				BlockID:   uuid.MustParse("50000000-0000-0000-0000-000000000000"),
+				MinID:     []byte{0x00},
+				MaxID:     []byte{0x10},
+				StartTime: time.Unix(10, 0),
+				EndTime:   time.Unix(20, 0),
+			},
+			expected: true,
+		},
 		// excludes
 		{
 			name:       "exclude - duh",
@@ -362,6 +457,35 @@ func TestIncludeBlock(t *testing.T) {
 				MaxID:   []byte{0x10},
 			},
 		},
+		{
+			name:       "exclude - block before time range",
+			searchID:   []byte{0x05},
+			blockStart: uuid.MustParse(BlockIDMin),
+			blockEnd:   uuid.MustParse(BlockIDMax),
+			timeStart:  21,
+			timeEnd:    30,
+			meta: &backend.BlockMeta{
+				BlockID:   uuid.MustParse("50000000-0000-0000-0000-000000000000"),
+				MinID:     []byte{0x00},
+				MaxID:     []byte{0x10},
+				StartTime: time.Unix(10, 0),
+				EndTime:   time.Unix(20, 0),
+			},
+		},
+		{
+			name:       "exclude - block after time range",
+			searchID:   []byte{0x05},
+			blockStart: uuid.MustParse(BlockIDMin),
+			blockEnd:   uuid.MustParse(BlockIDMax),
+			timeEnd:    9,
+			meta: &backend.BlockMeta{
+				BlockID:   uuid.MustParse("50000000-0000-0000-0000-000000000000"),
+				MinID:     []byte{0x00},
+				MaxID:     []byte{0x10},
+				StartTime: time.Unix(10, 0),
+				EndTime:   time.Unix(20, 0),
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -371,7 +495,7 @@ func TestIncludeBlock(t *testing.T) {
 			e, err := tc.blockEnd.MarshalBinary()
 			require.NoError(t, err)
 
-			assert.Equal(t, tc.expected, includeBlock(tc.meta, tc.searchID, s, e))
+			assert.Equal(t, tc.expected, includeBlock(tc.meta, tc.searchID, s, e, tc.timeStart, tc.timeEnd))
 		})
 	}
 }
@@ -440,7 +564,7 @@ func TestIncludeCompactedBlock(t *testing.T) {
 			e, err := tc.blockEnd.MarshalBinary()
 			require.NoError(t, err)
 
-			assert.Equal(t, tc.expected, includeCompactedBlock(tc.meta, tc.searchID, s, e, blocklistPoll))
+			assert.Equal(t, tc.expected, includeCompactedBlock(tc.meta, tc.searchID, s, e, 0, 0, blocklistPoll))
 		})
 	}
 
@@ -489,7 +613,7 @@ func TestSearchCompactedBlocks(t *testing.T) {
 
 	// read
 	for i, id := range ids {
-		bFound, failedBlocks, err := r.Find(context.Background(), testTenantID, id, blockID, blockID)
+		bFound, failedBlocks, err := r.Find(context.Background(), testTenantID, id, blockID, blockID, 0, 0)
 		require.NoError(t, err)
 		require.Nil(t, failedBlocks)
 		require.True(t, proto.Equal(bFound[0], reqs[i]))
@@ -513,13 +637,64 @@ func TestSearchCompactedBlocks(t *testing.T) {
 
 	// find should succeed with old block range
 	for i, id := range ids {
-		bFound, failedBlocks, err := r.Find(context.Background(), testTenantID, id, blockID, blockID)
+		bFound, failedBlocks, err := r.Find(context.Background(), testTenantID, id, blockID, blockID, 0, 0)
 		require.NoError(t, err)
 		require.Nil(t, failedBlocks)
 		require.True(t, proto.Equal(bFound[0], reqs[i]))
 	}
 }
 
+func TestIterateObjects(t *testing.T) {
+	r, w, _, _ := testConfig(t, backend.EncLZ4_256k, time.Minute)
+
+	head, err := w.WAL().NewBlock(uuid.New(), testTenantID, model.CurrentEncoding)
+	require.NoError(t, err)
+
+	dec := model.MustNewSegmentDecoder(model.CurrentEncoding)
+
+	numMsgs := 10
+	ids := make(map[string]struct{}, numMsgs)
+	for i := 0; i < numMsgs; i++ {
+		id := test.ValidTraceID(nil)
+		writeTraceToWal(t, head, dec, id, test.MakeTrace(rand.Int()%10+1, id), 0, 0)
+		ids[string(id)] = struct{}{}
+	}
+
+	complete, err := w.CompleteBlock(head, &mockCombiner{})
+	require.NoError(t, err)
+
+	objDecoder := model.MustNewObjectDecoder(complete.BlockMeta().DataEncoding)
+
+	found := map[string]struct{}{}
+	err = r.IterateObjects(context.Background(), []*backend.BlockMeta{complete.BlockMeta()}, 1_000_000, func(id common.ID, obj []byte) bool {
+		tr, err := objDecoder.PrepareForRead(obj)
+		require.NoError(t, err)
+		require.NotEmpty(t, tr.Batches)
+		found[string(id)] = struct{}{}
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ids, found)
+
+	// objects found in multiple blocks are passed once
+	count := 0
+	err = r.IterateObjects(context.Background(), []*backend.BlockMeta{complete.BlockMeta(), complete.BlockMeta()}, 1_000_000, func(id common.ID, obj []byte) bool {
+		count++
+		return true
+	})
+	require.NoError(t, err)
+	assert.Equal(t, numMsgs, count)
+
+	// returning false stops the iteration
+	count = 0
+	err = r.IterateObjects(context.Background(), []*backend.BlockMeta{complete.BlockMeta()}, 1_000_000, func(id common.ID, obj []byte) bool {
+		count++
+		return false
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
 func TestCompleteBlock(t *testing.T) {
 	_, w, _, _ := testConfig(t, backend.EncLZ4_256k, time.Minute)
 
@@ -628,3 +803,51 @@ func writeTraceToWal(t require.TestingT, b *wal.AppendBlock, dec model.SegmentDe
 	err = b.Append(id, b2, start, end)
 	require.NoError(t, err, "unexpected error writing req")
 }
+
+func TestNewCacheBackend(t *testing.T) {
+	c, err := newCacheBackend(&Config{}, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	assert.Nil(t, c)
+
+	_, err = newCacheBackend(&Config{Cache: "nope"}, prometheus.NewRegistry(), log.NewNopLogger())
+	assert.Error(t, err)
+
+	_, err = newCacheBackend(&Config{Cache: "lru"}, prometheus.NewRegistry(), log.NewNopLogger())
+	assert.Error(t, err)
+
+	c, err = newCacheBackend(&Config{Cache: "lru", LRU: &pkg_cache.LRUConfig{MaxSizeBytes: 100}}, prometheus.NewRegistry(), log.NewNopLogger())
+	require.NoError(t, err)
+	assert.IsType(t, &pkg_cache.LRU{}, c)
+
+	_, err = newCacheBackend(&Config{Cache: "lru, lru", LRU: &pkg_cache.LRUConfig{MaxSizeBytes: 100}}, prometheus.NewRegistry(), log.NewNopLogger())
+	assert.EqualError(t, err, "cache lru is configured more than once")
+}
+
+func TestNewCacheBackendTiered(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	cfg := &Config{
+		Cache:           "lru,redis",
+		LRU:             &pkg_cache.LRUConfig{MaxSizeBytes: 100},
+		Redis:           &redis.Config{},
+		BackgroundCache: &pkg_cache.BackgroundConfig{WriteBackGoroutines: 1, WriteBackBuffer: 1},
+	}
+
+	c, err := newCacheBackend(cfg, reg, log.NewNopLogger())
+	require.NoError(t, err)
+	defer c.Stop()
+
+	// every tier registers its metrics under its own name
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	names := map[string]bool{}
+	for _, f := range families {
+		for _, m := range f.Metric {
+			for _, l := range m.Label {
+				if l.GetName() == "name" {
+					names[l.GetValue()] = true
+				}
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{"tempo-lru": true, "tempo-redis": true}, names)
+}